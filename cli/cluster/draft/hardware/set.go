@@ -0,0 +1,73 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package hardware
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/esx/settings/clusters"
+)
+
+type set struct {
+	*flags.ClientFlag
+
+	clusterId string
+	draftId   string
+	manager   string
+	pkg       string
+	version   string
+}
+
+func init() {
+	cli.Register("cluster.draft.hardware.set", &set{})
+}
+
+func (cmd *set) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.clusterId, "cluster-id", "", "The identifier of the cluster.")
+	f.StringVar(&cmd.draftId, "draft-id", "", "The identifier of the software draft.")
+	f.StringVar(&cmd.manager, "manager", "", "The identifier of the hardware support manager.")
+	f.StringVar(&cmd.pkg, "package", "", "The name of the hardware support package.")
+	f.StringVar(&cmd.version, "version", "", "The version of the hardware support package.")
+}
+
+func (cmd *set) Process(ctx context.Context) error {
+	return cmd.ClientFlag.Process(ctx)
+}
+
+func (cmd *set) Usage() string {
+	return "CLUSTER"
+}
+
+func (cmd *set) Description() string {
+	return `Sets the hardware support package of a hardware support manager on the software draft.
+
+The available managers and packages are listed by 'govc cluster.vlcm.hsm.ls'.
+
+Examples:
+  govc cluster.draft.hardware.set -cluster-id=domain-c21 -draft-id=13 -manager=com.dell.OMIVV -package=DellFirmware -version=1.0.0`
+}
+
+func (cmd *set) Run(ctx context.Context, f *flag.FlagSet) error {
+	rc, err := cmd.RestClient()
+
+	if err != nil {
+		return err
+	}
+
+	dm := clusters.NewManager(rc)
+
+	spec := clusters.SettingsHardwareSupportPackageInfo{
+		Pkg:     cmd.pkg,
+		Version: cmd.version,
+	}
+
+	return dm.SetSoftwareDraftHardwareSupport(cmd.clusterId, cmd.draftId, cmd.manager, spec)
+}
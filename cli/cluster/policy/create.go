@@ -0,0 +1,95 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vapi/vcenter/compute/policies"
+)
+
+type create struct {
+	*flags.ClientFlag
+
+	spec       policies.CreateSpec
+	capability string
+	cat        string
+}
+
+func init() {
+	cli.Register("cluster.policy.create", &create{})
+}
+
+func (cmd *create) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.capability, "capability", "", "Policy capability: "+capabilityNames())
+	f.StringVar(&cmd.spec.Description, "d", "", "Description of policy")
+	f.StringVar(&cmd.spec.VMTag, "vm-tag", "", "Tag of the VMs the policy applies to")
+	f.StringVar(&cmd.spec.HostTag, "host-tag", "", "Tag of the hosts for the vm-host-affinity and vm-host-anti-affinity capabilities")
+	f.StringVar(&cmd.cat, "c", "", "Tag category of -vm-tag and -host-tag")
+}
+
+func (cmd *create) Usage() string {
+	return "NAME"
+}
+
+func (cmd *create) Description() string {
+	return `Create compute policy NAME.
+
+Compute policies constrain the placement of the VMs with the VM tag attached.
+The ID of the new policy is printed on success.
+
+Examples:
+  govc cluster.policy.create -capability vm-host-affinity -vm-tag gold-vms -host-tag gold-hosts gold
+  govc cluster.policy.create -capability vm-vm-anti-affinity -c k8s -vm-tag control-plane spread-control-plane
+  govc cluster.policy.create -capability disable-drs-vmotion -vm-tag pinned -d "Do not migrate" pinned`
+}
+
+func (cmd *create) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	capability, ok := capabilities[cmd.capability]
+	if !ok {
+		return fmt.Errorf("invalid capability %q, must be one of: %s", cmd.capability, capabilityNames())
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	cmd.spec.Name = f.Arg(0)
+	cmd.spec.Capability = capability
+
+	t := tags.NewManager(c)
+	for _, name := range []*string{&cmd.spec.VMTag, &cmd.spec.HostTag} {
+		if *name == "" {
+			continue
+		}
+		tag, err := t.GetTagForCategory(ctx, *name, cmd.cat)
+		if err != nil {
+			return err
+		}
+		*name = tag.ID
+	}
+
+	id, err := policies.NewManager(c).Create(ctx, cmd.spec)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(id)
+
+	return nil
+}
@@ -0,0 +1,144 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vapi/vcenter/compute/policies"
+)
+
+type info struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("cluster.policy.info", &info{})
+}
+
+func (cmd *info) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *info) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *info) Usage() string {
+	return "[NAME]..."
+}
+
+func (cmd *info) Description() string {
+	return `Display compute policy info.
+
+NAME can be the name or ID of a policy. If NAME is not specified, info for all policies is displayed.
+
+Examples:
+  govc cluster.policy.info
+  govc cluster.policy.info gold
+  govc cluster.policy.info -json gold | jq -r .[].vm_tag`
+}
+
+type policyInfo struct {
+	Policy string `json:"policy"`
+	policies.Info
+
+	vmTag   string
+	hostTag string
+}
+
+type infoResult []policyInfo
+
+func (r infoResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, p := range r {
+		fmt.Fprintf(tw, "Name:\t%s\n", p.Name)
+		fmt.Fprintf(tw, "  ID:\t%s\n", p.Policy)
+		fmt.Fprintf(tw, "  Capability:\t%s\n", capabilityName(p.Capability))
+		fmt.Fprintf(tw, "  Description:\t%s\n", p.Description)
+		fmt.Fprintf(tw, "  VM Tag:\t%s\n", p.vmTag)
+		if p.HostTag != "" {
+			fmt.Fprintf(tw, "  Host Tag:\t%s\n", p.hostTag)
+		}
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *info) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := policies.NewManager(c)
+
+	var ids []string
+	if f.NArg() == 0 {
+		list, err := m.List(ctx)
+		if err != nil {
+			return err
+		}
+		for _, p := range list {
+			ids = append(ids, p.Policy)
+		}
+	} else {
+		for _, name := range f.Args() {
+			p, err := lookup(ctx, m, name)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, p.Policy)
+		}
+	}
+
+	t := tags.NewManager(c)
+	names := make(map[string]string)
+	tagName := func(id string) string {
+		if id == "" {
+			return ""
+		}
+		if name, ok := names[id]; ok {
+			return name
+		}
+		names[id] = id
+		if tag, err := t.GetTag(ctx, id); err == nil {
+			names[id] = tag.Name
+		}
+		return names[id]
+	}
+
+	res := infoResult{}
+	for _, id := range ids {
+		p, err := m.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		res = append(res, policyInfo{
+			Policy:  id,
+			Info:    *p,
+			vmTag:   tagName(p.VMTag),
+			hostTag: tagName(p.HostTag),
+		})
+	}
+
+	return cmd.WriteResult(res)
+}
@@ -0,0 +1,106 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/compute/policies"
+)
+
+type ls struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+
+	capabilities bool
+}
+
+func init() {
+	cli.Register("cluster.policy.ls", &ls{})
+}
+
+func (cmd *ls) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.capabilities, "C", false, "List policy capabilities")
+}
+
+func (cmd *ls) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *ls) Description() string {
+	return `List compute policies.
+
+Examples:
+  govc cluster.policy.ls
+  govc cluster.policy.ls -C
+  govc cluster.policy.ls -json | jq -r .[].policy`
+}
+
+type lsResult []policies.Summary
+
+func (r lsResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, p := range r {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", p.Policy, p.Name, capabilityName(p.Capability))
+	}
+
+	return tw.Flush()
+}
+
+type lsCapabilityResult []policies.CapabilitySummary
+
+func (r lsCapabilityResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, c := range r {
+		fmt.Fprintf(tw, "%s\t%s\n", capabilityName(c.Capability), c.Description)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *ls) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 0 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := policies.NewManager(c)
+
+	if cmd.capabilities {
+		res, err := m.ListCapabilities(ctx)
+		if err != nil {
+			return err
+		}
+		return cmd.WriteResult(lsCapabilityResult(res))
+	}
+
+	res, err := m.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(lsResult(res))
+}
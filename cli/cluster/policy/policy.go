@@ -0,0 +1,56 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/vmware/govmomi/vapi/vcenter/compute/policies"
+)
+
+// capabilities maps the command line names of compute policy capabilities to their IDs
+var capabilities = map[string]string{
+	"vm-host-affinity":      policies.CapabilityVMHostAffinity,
+	"vm-host-anti-affinity": policies.CapabilityVMHostAntiAffinity,
+	"vm-vm-affinity":        policies.CapabilityVMVMAffinity,
+	"vm-vm-anti-affinity":   policies.CapabilityVMVMAntiAffinity,
+	"disable-drs-vmotion":   policies.CapabilityDisableDRSVMotion,
+	"vm-evacuation":         policies.CapabilityVMEvacuation,
+}
+
+// capabilityNames returns the sorted command line names of compute policy capabilities
+func capabilityNames() string {
+	return strings.Join(slices.Sorted(maps.Keys(capabilities)), ", ")
+}
+
+// capabilityName returns the command line name of the given capability ID
+func capabilityName(id string) string {
+	for name, capability := range capabilities {
+		if capability == id {
+			return name
+		}
+	}
+	return id
+}
+
+// lookup returns the summary of the compute policy with the given name or ID
+func lookup(ctx context.Context, m *policies.Manager, name string) (*policies.Summary, error) {
+	list, err := m.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range list {
+		if list[i].Policy == name || list[i].Name == name {
+			return &list[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("compute policy %q not found", name)
+}
@@ -0,0 +1,66 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/compute/policies"
+)
+
+type rm struct {
+	*flags.ClientFlag
+}
+
+func init() {
+	cli.Register("cluster.policy.rm", &rm{})
+}
+
+func (cmd *rm) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+}
+
+func (cmd *rm) Usage() string {
+	return "NAME..."
+}
+
+func (cmd *rm) Description() string {
+	return `Delete compute policies.
+
+NAME can be the name or ID of a policy.
+
+Examples:
+  govc cluster.policy.rm gold
+  govc cluster.policy.rm $(govc cluster.policy.ls -json | jq -r .[].policy)`
+}
+
+func (cmd *rm) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() == 0 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := policies.NewManager(c)
+
+	for _, name := range f.Args() {
+		p, err := lookup(ctx, m, name)
+		if err != nil {
+			return err
+		}
+		if err = m.Delete(ctx, p.Policy); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vapi/vcenter/compute/policies"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+type status struct {
+	*flags.SearchFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("cluster.policy.status", &status{})
+}
+
+func (cmd *status) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.SearchFlag, ctx = flags.NewSearchFlag(ctx, flags.SearchVirtualMachines)
+	cmd.SearchFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *status) Process(ctx context.Context) error {
+	if err := cmd.SearchFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *status) Usage() string {
+	return "NAME [VM]..."
+}
+
+func (cmd *status) Description() string {
+	return `Display compliance of VMs with compute policy NAME.
+
+NAME can be the name or ID of a policy.
+If VM is not specified, compliance of all VMs with the policy's VM tag attached is displayed.
+
+Examples:
+  govc cluster.policy.status gold
+  govc cluster.policy.status gold my-vm
+  govc cluster.policy.status -json gold | jq -r '.[] | select(.status == "NOT_COMPLIANT") | .vm.value'`
+}
+
+type vmStatus struct {
+	VM     types.ManagedObjectReference `json:"vm"`
+	Status policies.ComplianceStatus    `json:"status"`
+
+	path string
+}
+
+type statusResult []vmStatus
+
+func (r statusResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, s := range r {
+		fmt.Fprintf(tw, "%s\t%s\n", s.path, s.Status)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *status) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() == 0 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	vc, err := cmd.Client()
+	if err != nil {
+		return err
+	}
+
+	m := policies.NewManager(c)
+
+	p, err := lookup(ctx, m, f.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var refs []types.ManagedObjectReference
+	if f.NArg() == 1 {
+		info, err := m.Get(ctx, p.Policy)
+		if err != nil {
+			return err
+		}
+		objs, err := tags.NewManager(c).ListAttachedObjects(ctx, info.VMTag)
+		if err != nil {
+			return err
+		}
+		for _, obj := range objs {
+			if ref := obj.Reference(); ref.Type == "VirtualMachine" {
+				refs = append(refs, ref)
+			}
+		}
+	} else {
+		vms, err := cmd.VirtualMachines(f.Args()[1:])
+		if err != nil {
+			return err
+		}
+		for _, vm := range vms {
+			refs = append(refs, vm.Reference())
+		}
+	}
+
+	res := statusResult{}
+	for _, ref := range refs {
+		s, err := m.GetVMCompliance(ctx, ref.Value, p.Policy)
+		if err != nil {
+			return err
+		}
+		path, err := find.InventoryPath(ctx, vc, ref)
+		if err != nil {
+			path = ref.Value
+		}
+		res = append(res, vmStatus{VM: ref, Status: s.Status, path: path})
+	}
+
+	slices.SortFunc(res, func(a, b vmStatus) int {
+		return strings.Compare(a.path, b.path)
+	})
+
+	return cmd.WriteResult(res)
+}
@@ -0,0 +1,113 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package vlcm
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/esx/settings/clusters"
+)
+
+type apply struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+
+	clusterId string
+	hosts     flags.StringList
+	spec      clusters.SettingsClustersSoftwareApplySpec
+}
+
+func init() {
+	cli.Register("cluster.vlcm.apply", &apply{})
+}
+
+func (cmd *apply) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.clusterId, "cluster-id", "", "The identifier of the cluster.")
+	f.StringVar(&cmd.spec.Commit, "commit", "", "The identifier of the commit to apply (defaults to the latest commit).")
+	f.Var(&cmd.hosts, "host-id", "The identifier of a host to remediate (defaults to all hosts in the cluster).")
+	f.BoolVar(&cmd.spec.AcceptEula, "accept-eula", false, "Accept the VMware End User License Agreement")
+}
+
+func (cmd *apply) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *apply) Usage() string {
+	return "CLUSTER"
+}
+
+func (cmd *apply) Description() string {
+	return `Remediates the hosts of a cluster to the desired software specification.
+
+Execution will block the terminal for the duration of the task.
+
+Examples:
+  govc cluster.vlcm.apply -cluster-id=domain-c21 -accept-eula
+  govc cluster.vlcm.apply -cluster-id=domain-c21 -host-id=host-42 -host-id=host-43
+  govc cluster.vlcm.apply -cluster-id=domain-c21 -commit=2 -json | jq -r .successful_hosts[]`
+}
+
+type applyResult clusters.SettingsClustersSoftwareApplyResult
+
+func (r applyResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Status:\t%s\n", r.Status.Status)
+	fmt.Fprintf(tw, "Commit:\t%s\n", r.Commit)
+
+	for _, id := range slices.Sorted(maps.Keys(r.HostStatus)) {
+		fmt.Fprintf(tw, "%s:\t%s\n", id, r.HostStatus[id].Status)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *apply) Run(ctx context.Context, f *flag.FlagSet) error {
+	rc, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	dm := clusters.NewManager(rc)
+
+	cmd.spec.Hosts = cmd.hosts
+
+	taskId, err := dm.ApplySoftware(cmd.clusterId, cmd.spec)
+	if err != nil {
+		return err
+	}
+
+	info, err := waitForTask(ctx, rc, taskId)
+	if err != nil {
+		return err
+	}
+
+	var res clusters.SettingsClustersSoftwareApplyResult
+	if len(info.Result) != 0 {
+		if err = json.Unmarshal(info.Result, &res); err != nil {
+			return err
+		}
+	}
+
+	return cmd.WriteResult(applyResult(res))
+}
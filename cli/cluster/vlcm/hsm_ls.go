@@ -0,0 +1,101 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package vlcm
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/esx/settings/clusters"
+)
+
+type hsmLs struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+
+	manager string
+}
+
+func init() {
+	cli.Register("cluster.vlcm.hsm.ls", &hsmLs{})
+}
+
+func (cmd *hsmLs) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.manager, "manager", "", "List the packages of the given hardware support manager")
+}
+
+func (cmd *hsmLs) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *hsmLs) Description() string {
+	return `Lists the registered hardware support managers, or the packages of a hardware support manager.
+
+Examples:
+  govc cluster.vlcm.hsm.ls
+  govc cluster.vlcm.hsm.ls -manager=com.dell.OMIVV`
+}
+
+type hsmManagers []clusters.SettingsHardwareSupportManagerInfo
+
+func (r hsmManagers) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, m := range r {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", m.Manager, m.DisplayName, m.Vendor)
+	}
+
+	return tw.Flush()
+}
+
+type hsmPackages []clusters.SettingsHardwareSupportManagersPackagesSummary
+
+func (r hsmPackages) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, p := range r {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", p.Pkg, p.Version, strings.Join(p.SupportedReleases, ","))
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *hsmLs) Run(ctx context.Context, f *flag.FlagSet) error {
+	rc, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	dm := clusters.NewManager(rc)
+
+	if cmd.manager == "" {
+		res, err := dm.ListHardwareSupportManagers()
+		if err != nil {
+			return err
+		}
+		return cmd.WriteResult(hsmManagers(res))
+	}
+
+	res, err := dm.ListHardwareSupportPackages(cmd.manager)
+	if err != nil {
+		return err
+	}
+	return cmd.WriteResult(hsmPackages(res))
+}
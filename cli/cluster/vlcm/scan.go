@@ -0,0 +1,160 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package vlcm
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/cis/tasks"
+	"github.com/vmware/govmomi/vapi/esx/settings/clusters"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+type scan struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+
+	clusterId string
+	last      bool
+}
+
+func init() {
+	cli.Register("cluster.vlcm.scan", &scan{})
+}
+
+func (cmd *scan) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.clusterId, "cluster-id", "", "The identifier of the cluster.")
+	f.BoolVar(&cmd.last, "last", false, "Display the result of the last scan, without scanning")
+}
+
+func (cmd *scan) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *scan) Usage() string {
+	return "CLUSTER"
+}
+
+func (cmd *scan) Description() string {
+	return `Scans the hosts of a cluster for compliance with the desired software specification.
+
+Execution will block the terminal for the duration of the task.
+
+Examples:
+  govc cluster.vlcm.scan -cluster-id=domain-c21
+  govc cluster.vlcm.scan -cluster-id=domain-c21 -last
+  govc cluster.vlcm.scan -cluster-id=domain-c21 -json | jq -r .non_compliant_hosts[]`
+}
+
+type scanResult clusters.SettingsClusterCompliance
+
+func (r scanResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Status:\t%s\n", r.Status)
+	fmt.Fprintf(tw, "Scan Time:\t%s\n", r.ScanTime)
+	fmt.Fprintf(tw, "Commit:\t%s\n", r.Commit)
+
+	for _, id := range slices.Sorted(maps.Keys(r.Hosts)) {
+		host := r.Hosts[id]
+		fmt.Fprintf(tw, "%s:\t%s\n", id, host.Status)
+		if host.BaseImage.Status == clusters.ComplianceStatusNonCompliant {
+			fmt.Fprintf(tw, "  base-image:\t%s -> %s\n", host.BaseImage.Current.Version, host.BaseImage.Target.Version)
+		}
+		for _, name := range slices.Sorted(maps.Keys(host.Components)) {
+			c := host.Components[name]
+			if c.Status == clusters.ComplianceStatusCompliant {
+				continue
+			}
+			current, target := "-", "-"
+			if c.Current != nil {
+				current = c.Current.Version
+			}
+			if c.Target != nil {
+				target = c.Target.Version
+			}
+			fmt.Fprintf(tw, "  %s:\t%s -> %s\n", name, current, target)
+		}
+		for _, name := range slices.Sorted(maps.Keys(host.HardwareSupport)) {
+			p := host.HardwareSupport[name]
+			if p.Status == clusters.ComplianceStatusCompliant {
+				continue
+			}
+			current, target := "-", "-"
+			if p.Current != nil {
+				current = p.Current.Pkg + " " + p.Current.Version
+			}
+			if p.Target != nil {
+				target = p.Target.Pkg + " " + p.Target.Version
+			}
+			fmt.Fprintf(tw, "  %s:\t%s -> %s\n", name, current, target)
+		}
+	}
+
+	return tw.Flush()
+}
+
+// waitForTask waits for the given task to complete, returning the task error if it failed.
+func waitForTask(ctx context.Context, rc *rest.Client, taskId string) (*tasks.Info, error) {
+	m := tasks.NewManagerWithCustomInterval(rc, 1)
+
+	if _, err := m.WaitForCompletion(ctx, taskId); err != nil {
+		return nil, err
+	}
+
+	info, err := m.Get(ctx, taskId)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Error != nil {
+		return nil, info.Error
+	}
+
+	return info, nil
+}
+
+func (cmd *scan) Run(ctx context.Context, f *flag.FlagSet) error {
+	rc, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	dm := clusters.NewManager(rc)
+
+	if !cmd.last {
+		taskId, err := dm.ScanCluster(cmd.clusterId)
+		if err != nil {
+			return err
+		}
+		if _, err = waitForTask(ctx, rc, taskId); err != nil {
+			return err
+		}
+	}
+
+	res, err := dm.CheckCompliance(cmd.clusterId)
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(scanResult(res))
+}
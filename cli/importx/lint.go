@@ -0,0 +1,112 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package importx
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"path"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/ovf"
+	"github.com/vmware/govmomi/ovf/importer"
+)
+
+type lint struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+
+	ovf.ValidateOptions
+}
+
+func init() {
+	cli.Register("import.lint", &lint{})
+}
+
+func (cmd *lint) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.Strict, "strict", false, "Report unsupported hardware items as errors")
+}
+
+func (cmd *lint) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *lint) Usage() string {
+	return "PATH_TO_OVF_OR_OVA"
+}
+
+func (cmd *lint) Description() string {
+	return `Validate OVF or OVA offline.
+
+Checks the descriptor structure, references between the References, DiskSection,
+NetworkSection and VirtualHardwareSection, hardware items, vApp property types and
+the digests listed in the manifest (.mf) if present.
+The command fails if any errors are found, warnings are reported only.
+
+Examples:
+  govc import.lint vm.ova
+  govc import.lint -strict vm.ovf
+  govc import.lint -json vm.ova | jq -r '.[] | select(.severity == "error") | .message'`
+}
+
+func (cmd *lint) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	fpath := f.Arg(0)
+
+	var archive importer.Archive
+
+	switch path.Ext(fpath) {
+	case ".ovf":
+		archive = &importer.FileArchive{Path: fpath}
+	case "", ".ova":
+		archive = &importer.TapeArchive{Path: fpath}
+		fpath = "*.ovf"
+	default:
+		return fmt.Errorf("invalid file extension %s", path.Ext(fpath))
+	}
+
+	if importer.IsRemotePath(f.Arg(0)) {
+		client, err := cmd.Client()
+		if err != nil {
+			return err
+		}
+		switch archive := archive.(type) {
+		case *importer.FileArchive:
+			archive.Client = client
+		case *importer.TapeArchive:
+			archive.Client = client
+		}
+	}
+
+	res, err := importer.Lint(fpath, archive, cmd.ValidateOptions)
+	if err != nil {
+		return err
+	}
+
+	if err = cmd.WriteResult(res); err != nil {
+		return err
+	}
+
+	if res.HasErrors() {
+		return errors.New("validation failed")
+	}
+
+	return nil
+}
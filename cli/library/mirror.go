@@ -0,0 +1,124 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package library
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/library/mirror"
+	"github.com/vmware/govmomi/vim25/progress"
+)
+
+type mirrorCmd struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+
+	mirror.Mirror
+
+	dryRun bool
+}
+
+func init() {
+	cli.Register("library.mirror", &mirrorCmd{})
+}
+
+func (cmd *mirrorCmd) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.Delete, "delete", false, "Delete library items and item files that do not exist in DIR")
+	f.BoolVar(&cmd.dryRun, "n", false, "Dry run, print the changes without applying them")
+	f.StringVar(&cmd.Algorithm, "a", "SHA256", "Checksum algorithm used to verify uploaded files: SHA1, MD5, SHA256, SHA512")
+}
+
+func (cmd *mirrorCmd) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *mirrorCmd) Usage() string {
+	return "DIR LIBRARY"
+}
+
+func (cmd *mirrorCmd) Description() string {
+	return `Mirror local directory DIR to LIBRARY.
+
+Each subdirectory of DIR corresponds to a library item of the same name, containing the files within.
+Each regular file in DIR corresponds to a library item named by the file name without extension.
+Hidden files are ignored. OVA files must be unpacked first, see 'govc ovf.unpack'.
+
+Files are compared by checksum, using the algorithm reported by the library for each item file.
+Only new or changed files are uploaded.
+Library items and item files that no longer exist in DIR are deleted only when the '-delete' flag is given.
+Uploaded files are verified using their checksum.
+
+Examples:
+  govc library.mirror -n ~/images my-content # print the changes only
+  govc library.mirror ~/images my-content
+  govc library.mirror -delete ~/images my-content
+  govc library.mirror -n -json ~/images my-content | jq -r '.[] | select(.op == "upload") | .path'`
+}
+
+type mirrorResult []mirror.Change
+
+func (r mirrorResult) Write(w io.Writer) error {
+	for _, c := range r {
+		if _, err := fmt.Fprintln(w, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cmd *mirrorCmd) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 2 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+	cmd.KeepAlive(c)
+
+	lib, err := flags.ContentLibrary(ctx, c, f.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	cmd.Manager = library.NewManager(c)
+
+	changes, err := cmd.Plan(ctx, lib, f.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if cmd.dryRun {
+		return cmd.WriteResult(mirrorResult(changes))
+	}
+
+	if cmd.TTY {
+		cmd.Progress = func(c mirror.Change) *progress.ProgressLogger {
+			return cmd.ProgressLogger(fmt.Sprintf("Uploading %s/%s... ", c.Item, c.File))
+		}
+	}
+
+	if err = cmd.Apply(ctx, lib, changes); err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(mirrorResult(changes))
+}
@@ -0,0 +1,120 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package library
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/library/vcsp"
+)
+
+type serve struct {
+	*flags.EmptyFlag
+
+	vcsp.Directory
+
+	listen string
+	cert   string
+	key    string
+}
+
+func init() {
+	cli.Register("library.serve", &serve{})
+}
+
+func (cmd *serve) Register(ctx context.Context, f *flag.FlagSet) {
+	f.StringVar(&cmd.Name, "name", "", "Library name (default: base name of DIR)")
+	f.StringVar(&cmd.listen, "l", "127.0.0.1:0", "Listen address for the HTTP server")
+	f.StringVar(&cmd.Username, "user", vcsp.DefaultUsername, "User name for basic authentication")
+	f.StringVar(&cmd.Password, "password", "", "Enable basic authentication with the given password")
+	f.StringVar(&cmd.cert, "tlscert", "", "TLS certificate file, enables HTTPS")
+	f.StringVar(&cmd.key, "tlskey", "", "TLS private key file")
+}
+
+func (cmd *serve) Usage() string {
+	return "DIR"
+}
+
+func (cmd *serve) Description() string {
+	return `Publish DIR as a content library.
+
+Serves DIR over HTTP using the vCenter Content Subscription Protocol, such that
+a subscribed library can be created with the printed lib.json URL as its subscription URL.
+
+Each subdirectory of DIR is published as a library item with the files it contains.
+Subdirectories containing an .ovf file are published as OVF items, those containing
+a single .iso file as ISO items. Regular files in DIR are published as single-file items.
+Changes to DIR are seen by subscribers on their next sync.
+
+The server runs until interrupted.
+
+Examples:
+  govc library.serve -l :8080 ~/images
+  govc library.serve -l :8443 -tlscert cert.pem -tlskey key.pem -password secret ~/images
+  govc library.create -sub http://127.0.0.1:8080/lib.json images
+  govc library.create -sub https://127.0.0.1:8443/lib.json -sub-password secret -thumbprint ... images`
+}
+
+func (cmd *serve) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	dir, err := filepath.Abs(f.Arg(0))
+	if err != nil {
+		return err
+	}
+	cmd.Path = dir
+
+	if _, err = cmd.Library(); err != nil {
+		return err
+	}
+
+	l, err := net.Listen("tcp", cmd.listen)
+	if err != nil {
+		return err
+	}
+
+	u := url.URL{Scheme: "http", Host: l.Addr().String(), Path: "/" + vcsp.LibraryFile}
+	if cmd.cert != "" {
+		u.Scheme = "https"
+	}
+	fmt.Println(u.String())
+
+	s := &http.Server{Handler: &cmd.Directory}
+
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+		select {
+		case <-sig:
+		case <-ctx.Done():
+		}
+		_ = s.Close()
+	}()
+
+	if cmd.cert != "" {
+		err = s.ServeTLS(l, cmd.cert, cmd.key)
+	} else {
+		err = s.Serve(l)
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
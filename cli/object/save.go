@@ -6,6 +6,7 @@ package object
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
@@ -16,6 +17,9 @@ import (
 	"github.com/vmware/govmomi/cli/flags"
 	"github.com/vmware/govmomi/fault"
 	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/simulator/model"
+	"github.com/vmware/govmomi/vapi/tags"
 	"github.com/vmware/govmomi/view"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/methods"
@@ -34,6 +38,7 @@ type save struct {
 	recurse bool
 	one     bool
 	license bool
+	vapi    bool
 	kind    kinds
 	summary map[string]int
 }
@@ -53,6 +58,7 @@ func (cmd *save) Register(ctx context.Context, f *flag.FlagSet) {
 	f.BoolVar(&cmd.recurse, "r", true, "Include children of the container view root")
 	f.Var(&cmd.kind, "type", "Resource types to save.  Defaults to all types")
 	f.BoolVar(&cmd.verbose, "v", false, "Verbose output")
+	f.BoolVar(&cmd.vapi, "vapi", false, "Include tags and content library metadata (vCenter only)")
 }
 
 func (cmd *save) Usage() string {
@@ -64,14 +70,115 @@ func (cmd *save) Description() string {
 
 By default, the object tree and all properties are saved, starting at PATH.
 PATH defaults to ServiceContent, but can be specified to save a subset of objects.
+With '-vapi', when connected to vCenter and PATH is not specified, tag categories, tags, tag associations
+and content library metadata are saved to the 'vapi' subdirectory. Library item files are not saved.
 The primary use case for this command is to save inventory from a live vCenter and
 load it into a vcsim instance.
 
 Examples:
   govc object.save -d my-vcenter
+  govc object.save -vapi -d my-vcenter
   vcsim -load my-vcenter`
 }
 
+// writeJSON encodes data to file name in the vapi subdirectory
+func (cmd *save) writeJSON(name string, data any) error {
+	f, err := os.Create(filepath.Join(cmd.dir, model.Subdir, name))
+	if err != nil {
+		return err
+	}
+	e := json.NewEncoder(f)
+	e.SetIndent("", "  ")
+	if err = e.Encode(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// saveVAPI saves tags and content library metadata, for use by vcsim's vapi simulator
+func (cmd *save) saveVAPI(ctx context.Context) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Join(cmd.dir, model.Subdir), 0755); err != nil {
+		return err
+	}
+
+	m := tags.NewManager(c)
+
+	categories, err := m.GetCategories(ctx)
+	if err != nil {
+		return err
+	}
+
+	attached, err := m.GetTags(ctx)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(attached))
+	for i := range attached {
+		ids[i] = attached[i].ID
+	}
+
+	var associations []tags.AttachedObjects
+	if len(ids) != 0 {
+		associations, err = m.ListAttachedObjectsOnTags(ctx, ids)
+		if err != nil {
+			return err
+		}
+	}
+
+	l := library.NewManager(c)
+
+	libraries, err := l.GetLibraries(ctx)
+	if err != nil {
+		return err
+	}
+
+	var items []library.Item
+	for _, lib := range libraries {
+		res, err := l.GetLibraryItems(ctx, lib.ID)
+		if err != nil {
+			return err
+		}
+		items = append(items, res...)
+	}
+
+	files := []struct {
+		name string
+		kind string
+		data any
+		n    int
+	}{
+		{model.CategoriesFile, "Category", categories, len(categories)},
+		{model.TagsFile, "Tag", attached, len(attached)},
+		{model.AssociationsFile, "TagAssociation", associations, len(associations)},
+		{model.LibrariesFile, "Library", libraries, len(libraries)},
+		{model.LibraryItemsFile, "LibraryItem", items, len(items)},
+	}
+
+	for _, file := range files {
+		if cmd.verbose {
+			fmt.Printf("Saving %s...", filepath.Join(model.Subdir, file.name))
+		}
+		if err = cmd.writeJSON(file.name, file.data); err != nil {
+			return err
+		}
+		if cmd.verbose {
+			fmt.Println("ok")
+		}
+		if file.n != 0 {
+			cmd.summary[file.kind] = file.n
+		}
+	}
+
+	return nil
+}
+
 // write encodes data to file name
 func (cmd *save) write(name string, data any) error {
 	f, err := os.Create(filepath.Join(cmd.dir, name) + ".xml")
@@ -462,6 +569,12 @@ func (cmd *save) Run(ctx context.Context, f *flag.FlagSet) error {
 		}
 	}
 
+	if cmd.vapi && f.NArg() == 0 && c.IsVC() {
+		if err = cmd.saveVAPI(ctx); err != nil {
+			return err
+		}
+	}
+
 	var summary []string
 	for k, v := range cmd.summary {
 		if v == 1 && !cmd.verbose {
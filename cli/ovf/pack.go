@@ -0,0 +1,104 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package ovf
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/ovf"
+	"github.com/vmware/govmomi/units"
+)
+
+type pack struct {
+	*flags.EmptyFlag
+
+	force bool
+	sha   int
+	chunk units.ByteSize
+	cert  string
+	key   string
+}
+
+func init() {
+	cli.Register("ovf.pack", &pack{})
+}
+
+func (cmd *pack) Register(ctx context.Context, f *flag.FlagSet) {
+	f.BoolVar(&cmd.force, "f", false, "Overwrite existing")
+	f.IntVar(&cmd.sha, "sha", 256, "Generate manifest using SHA 1, 256 or 512")
+	f.Var(&cmd.chunk, "chunk-size", "Split files larger than this size into chunks")
+	f.StringVar(&cmd.cert, "sign-cert", "", "Sign manifest with this certificate file")
+	f.StringVar(&cmd.key, "sign-key", "", "Private key file for -sign-cert")
+}
+
+func (cmd *pack) Usage() string {
+	return "PATH_TO_OVF PATH_TO_OVA"
+}
+
+func (cmd *pack) Description() string {
+	return `Package OVF and the files it references as OVA.
+
+The OVA entries are ordered as the descriptor, manifest, certificate and then the
+referenced files. The descriptor File sizes are updated to match the files on disk
+and a new manifest is generated, any existing manifest or certificate is not included.
+Files split into chunks by 'ovf.unpack' of a chunked OVA are joined, unless -chunk-size is set.
+
+Examples:
+  govc ovf.unpack vm.ova vm
+  vi vm/vm.ovf
+  govc ovf.pack vm/vm.ovf vm-new.ova
+  govc ovf.pack -chunk-size 1GB -sha 512 vm/vm.ovf vm-new.ova
+  govc ovf.pack -sign-cert cert.pem -sign-key key.pem vm/vm.ovf vm-signed.ova`
+}
+
+func (cmd *pack) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 2 {
+		return flag.ErrHelp
+	}
+
+	opts := ovf.PackOptions{
+		SHA:       cmd.sha,
+		ChunkSize: int64(cmd.chunk),
+	}
+
+	if cmd.cert != "" || cmd.key != "" {
+		if cmd.cert == "" || cmd.key == "" {
+			return errors.New("-sign-cert and -sign-key must be specified together")
+		}
+		cert, err := tls.LoadX509KeyPair(cmd.cert, cmd.key)
+		if err != nil {
+			return err
+		}
+		opts.Certificate = &cert
+	}
+
+	target := f.Arg(1)
+
+	if !cmd.force {
+		if _, err := os.Stat(target); err == nil {
+			return fmt.Errorf("file already exists: %s", target)
+		}
+	}
+
+	file, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+
+	if err = ovf.Pack(file, f.Arg(0), opts); err != nil {
+		_ = file.Close()
+		_ = os.Remove(target)
+		return err
+	}
+
+	return file.Close()
+}
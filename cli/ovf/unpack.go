@@ -0,0 +1,59 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package ovf
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/ovf"
+)
+
+type unpack struct {
+	*flags.EmptyFlag
+}
+
+func init() {
+	cli.Register("ovf.unpack", &unpack{})
+}
+
+func (cmd *unpack) Usage() string {
+	return "PATH_TO_OVA DIR"
+}
+
+func (cmd *unpack) Description() string {
+	return `Extract OVA to DIR.
+
+The path of the extracted OVF descriptor is written to stdout.
+
+Examples:
+  govc ovf.unpack vm.ova vm
+  govc import.ovf $(govc ovf.unpack vm.ova vm)`
+}
+
+func (cmd *unpack) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 2 {
+		return flag.ErrHelp
+	}
+
+	file, err := os.Open(f.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	desc, err := ovf.Unpack(file, f.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(desc)
+
+	return nil
+}
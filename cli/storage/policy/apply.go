@@ -0,0 +1,168 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/pbm"
+)
+
+type apply struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+
+	file string
+}
+
+func init() {
+	cli.Register("storage.policy.apply", &apply{})
+}
+
+func (cmd *apply) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.file, "f", "", "Policy file (YAML or JSON), use '-' for STDIN")
+}
+
+func (cmd *apply) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *apply) Description() string {
+	return `Create or update VM Storage Policies from a policy file.
+
+Each policy in the file is matched to an existing policy by name.
+A policy that does not exist is created, an existing policy is updated only if
+its description or rules differ from the file.
+An existing policy's description is left unchanged if the file does not specify one.
+The file may contain multiple YAML documents separated by "---".
+
+Policy fields:
+  name:        Policy name (required)
+  description: Policy description
+  vsan:        vSAN rules, such as hostFailuresToTolerate, stripeWidth, forceProvisioning,
+               proportionalCapacity, cacheReservation or replicaPreference
+  tags:        Tag based placement, map of tag category name to a list of tag names
+  encryption:  Enable VM encryption
+  ioFilters:   IDs of host based data services (IO filters)
+  zonal:       Enable Zonal topology for multi-zone Supervisor
+  rules:       Other rules, list of namespace, id, operator and properties;
+               the "NOT" operator excludes datastores with the given tags
+
+Examples:
+  cat <<EOF | govc storage.policy.apply -f -
+  name: gold
+  description: Mirrored and encrypted, placed on gold datastores
+  vsan:
+    hostFailuresToTolerate: 1
+    stripeWidth: 2
+  tags:
+    tier: [gold]
+  encryption: true
+  EOF
+  govc storage.policy.info -export gold > gold.yaml
+  govc storage.policy.apply -f gold.yaml`
+}
+
+// readPolicies decodes the policy documents from the given YAML or JSON stream.
+// YAML streams may contain multiple documents separated by "---".
+func readPolicies(r io.Reader) ([]pbm.Policy, error) {
+	var policies []pbm.Policy
+
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+
+	for {
+		var p pbm.Policy
+		err := dec.Decode(&p)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		if p.Name == "" {
+			return nil, fmt.Errorf("policy %d: name is required", len(policies)+1)
+		}
+		policies = append(policies, p)
+	}
+
+	return policies, nil
+}
+
+type applyResult struct {
+	Name   string           `json:"name"`
+	ID     string           `json:"id"`
+	Action pbm.PolicyAction `json:"action"`
+}
+
+type applyResults []applyResult
+
+func (r applyResults) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, res := range r {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", res.Name, res.Action, res.ID)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *apply) Run(ctx context.Context, f *flag.FlagSet) error {
+	if cmd.file == "" || f.NArg() != 0 {
+		return flag.ErrHelp
+	}
+
+	var r io.Reader = os.Stdin
+	if cmd.file != "-" {
+		file, err := os.Open(cmd.file)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	policies, err := readPolicies(r)
+	if err != nil {
+		return fmt.Errorf("%s: %s", cmd.file, err)
+	}
+
+	c, err := cmd.PbmClient()
+	if err != nil {
+		return err
+	}
+
+	var res applyResults
+
+	for i := range policies {
+		p := &policies[i]
+		id, action, err := c.ApplyPolicy(ctx, p)
+		if err != nil {
+			return fmt.Errorf("policy %q: %s", p.Name, err)
+		}
+		res = append(res, applyResult{Name: p.Name, ID: id.UniqueId, Action: action})
+	}
+
+	return cmd.WriteResult(res)
+}
@@ -0,0 +1,54 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicies = `
+name: gold
+description: Mirrored and encrypted
+vsan:
+  hostFailuresToTolerate: 1
+  forceProvisioning: false
+tags:
+  tier: [gold, platinum]
+encryption: true
+zonal: true
+rules:
+  - namespace: PMem
+    id: PMemType
+    properties:
+      PMemType: [NVDIMM]
+---
+{"name": "silver", "vsan": {"stripeWidth": 2}}
+`
+
+func TestReadPolicies(t *testing.T) {
+	policies, err := readPolicies(strings.NewReader(testPolicies))
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+
+	gold := policies[0]
+	assert.Equal(t, "Mirrored and encrypted", gold.Description)
+	assert.Equal(t, 1, gold.VSAN["hostFailuresToTolerate"])
+	assert.Equal(t, false, gold.VSAN["forceProvisioning"])
+	assert.Equal(t, []string{"gold", "platinum"}, gold.Tags["tier"])
+	assert.True(t, gold.Encryption)
+	assert.True(t, gold.Zonal)
+	assert.Equal(t, []any{"NVDIMM"}, gold.Rules[0].Properties["PMemType"])
+	assert.Equal(t, map[string]any{"stripeWidth": 2}, policies[1].VSAN)
+
+	_, err = readPolicies(strings.NewReader("name: gold\nvsan_rules: {}"))
+	assert.Error(t, err, "unknown field")
+
+	_, err = readPolicies(strings.NewReader("description: no name"))
+	assert.Error(t, err, "name is required")
+}
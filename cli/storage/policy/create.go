@@ -49,7 +49,9 @@ func (cmd *create) Description() string {
 
 Examples:
   govc storage.policy.create -category my_cat -tag my_tag MyStoragePolicy # Tag based placement
-  govc storage.policy.create -z MyZonalPolicy # Zonal topology`
+  govc storage.policy.create -z MyZonalPolicy # Zonal topology
+
+See also storage.policy.apply for vSAN rules and other policy options.`
 }
 
 func (cmd *create) Run(ctx context.Context, f *flag.FlagSet) error {
@@ -67,7 +69,7 @@ func (cmd *create) Run(ctx context.Context, f *flag.FlagSet) error {
 	if cmd.tag != "" {
 		cmd.spec.CapabilityList = append(cmd.spec.CapabilityList, pbm.Capability{
 			ID:        cmd.cat,
-			Namespace: "http://www.vmware.com/storage/tag",
+			Namespace: pbm.NamespaceTag,
 			PropertyList: []pbm.Property{{
 				ID:       fmt.Sprintf("com.vmware.storage.tag.%s.property", cmd.cat),
 				Value:    cmd.tag,
@@ -78,8 +80,8 @@ func (cmd *create) Run(ctx context.Context, f *flag.FlagSet) error {
 
 	if cmd.zone {
 		cmd.spec.CapabilityList = append(cmd.spec.CapabilityList, pbm.Capability{
-			ID:        "StorageTopology",
-			Namespace: "com.vmware.storage.consumptiondomain",
+			ID:        pbm.TopologyCapabilityID,
+			Namespace: pbm.NamespaceTopology,
 			PropertyList: []pbm.Property{{
 				ID:       "StorageTopologyType",
 				Value:    "Zonal",
@@ -89,14 +91,12 @@ func (cmd *create) Run(ctx context.Context, f *flag.FlagSet) error {
 	}
 
 	if cmd.enc {
-		const encryptionCapabilityID = "ad5a249d-cbc2-43af-9366-694d7664fa52"
-
 		cmd.spec.CapabilityList = append(cmd.spec.CapabilityList, pbm.Capability{
-			ID:        encryptionCapabilityID,
-			Namespace: "com.vmware.storageprofile.dataservice",
+			ID:        pbm.EncryptionCapabilityID,
+			Namespace: pbm.NamespaceDataService,
 			PropertyList: []pbm.Property{{
-				ID:       encryptionCapabilityID,
-				Value:    encryptionCapabilityID,
+				ID:       pbm.EncryptionCapabilityID,
+				Value:    pbm.EncryptionCapabilityID,
 				DataType: "string",
 			}},
 		})
@@ -13,8 +13,11 @@ import (
 	"strings"
 	"text/tabwriter"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/vmware/govmomi/cli"
 	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/pbm"
 	"github.com/vmware/govmomi/pbm/types"
 	"github.com/vmware/govmomi/property"
 	vim "github.com/vmware/govmomi/vim25/types"
@@ -27,6 +30,7 @@ type info struct {
 	compliance bool
 	storage    bool
 	iofilters  bool
+	export     bool
 }
 
 func init() {
@@ -42,6 +46,7 @@ func (cmd *info) Register(ctx context.Context, f *flag.FlagSet) {
 
 	f.BoolVar(&cmd.storage, "s", false, "Check Storage Compatibility")
 	f.BoolVar(&cmd.compliance, "c", false, "Check VM Compliance")
+	f.BoolVar(&cmd.export, "export", false, "Output policy file, see storage.policy.apply")
 
 	if cli.ShowUnreleased() {
 		f.BoolVar(&cmd.iofilters, "i", false, "Query IO Filters")
@@ -68,7 +73,8 @@ func (cmd *info) Description() string {
 Examples:
   govc storage.policy.info
   govc storage.policy.info "vSAN Default Storage Policy"
-  govc storage.policy.info -c -s`
+  govc storage.policy.info -c -s
+  govc storage.policy.info -export "vSAN Default Storage Policy"`
 }
 
 type Policy struct {
@@ -120,6 +126,10 @@ func (cmd *info) Run(ctx context.Context, f *flag.FlagSet) error {
 		return err
 	}
 
+	if cmd.export {
+		return cmd.exportPolicies(profiles)
+	}
+
 	ds, err := c.DatastoreMap(ctx, vc, vc.ServiceContent.RootFolder)
 	if err != nil {
 		return err
@@ -196,3 +206,21 @@ func (cmd *info) Run(ctx context.Context, f *flag.FlagSet) error {
 
 	return cmd.WriteResult(&infoResult{policies, cmd})
 }
+
+// exportPolicies writes the given profiles as policy file YAML documents.
+func (cmd *info) exportPolicies(profiles []types.BasePbmProfile) error {
+	enc := yaml.NewEncoder(cmd.Out)
+	enc.SetIndent(2)
+
+	for _, profile := range profiles {
+		p, err := pbm.NewPolicy(profile)
+		if err != nil {
+			return err
+		}
+		if err = enc.Encode(p); err != nil {
+			return err
+		}
+	}
+
+	return enc.Close()
+}
@@ -19,7 +19,9 @@ import (
 
 type attach struct {
 	*flags.DatacenterFlag
-	cat string
+	cat   string
+	query string
+	kind  flags.StringList
 }
 
 func init() {
@@ -31,18 +33,23 @@ func (cmd *attach) Register(ctx context.Context, f *flag.FlagSet) {
 	cmd.DatacenterFlag.Register(ctx, f)
 
 	f.StringVar(&cmd.cat, "c", "", "Tag category")
+	f.StringVar(&cmd.query, "query", "", "Attach tag to objects matching tags query EXPR, instead of PATH (see tags.find)")
+	f.Var(&cmd.kind, "type", "Limit -query results to objects of TYPE, such as VirtualMachine")
 }
 
 func (cmd *attach) Usage() string {
-	return "NAME PATH"
+	return "NAME [PATH]"
 }
 
 func (cmd *attach) Description() string {
 	return `Attach tag NAME to object PATH.
 
+If the '-query' flag is specified, tag NAME is attached to all objects matching the tags query (see tags.find).
+
 Examples:
   govc tags.attach k8s-region-us /dc1
-  govc tags.attach -c k8s-region us-ca1 /dc1/host/cluster1`
+  govc tags.attach -c k8s-region us-ca1 /dc1/host/cluster1
+  govc tags.attach -query 'env:prod && !tier:db' -type VirtualMachine backup-daily`
 }
 
 func convertPath(ctx context.Context, c *rest.Client, cmd *flags.DatacenterFlag, managedObj string) (*types.ManagedObjectReference, error) {
@@ -77,6 +84,13 @@ func convertPath(ctx context.Context, c *rest.Client, cmd *flags.DatacenterFlag,
 }
 
 func (cmd *attach) Run(ctx context.Context, f *flag.FlagSet) error {
+	if cmd.query != "" {
+		if f.NArg() != 1 {
+			return flag.ErrHelp
+		}
+		return cmd.attachQuery(ctx, f.Arg(0))
+	}
+
 	if f.NArg() != 2 {
 		return flag.ErrHelp
 	}
@@ -100,3 +114,23 @@ func (cmd *attach) Run(ctx context.Context, f *flag.FlagSet) error {
 	}
 	return m.AttachTag(ctx, tag.ID, ref)
 }
+
+func (cmd *attach) attachQuery(ctx context.Context, tagID string) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := tags.NewManager(c)
+	tag, err := m.GetTagForCategory(ctx, tagID, cmd.cat)
+	if err != nil {
+		return err
+	}
+
+	refs, err := queryObjects(ctx, m, cmd.query, cmd.kind)
+	if err != nil || len(refs) == 0 {
+		return err
+	}
+
+	return m.AttachTagToMultipleObjects(ctx, tag.ID, refs)
+}
@@ -15,7 +15,9 @@ import (
 
 type detach struct {
 	*flags.DatacenterFlag
-	cat string
+	cat   string
+	query string
+	kind  flags.StringList
 }
 
 func init() {
@@ -27,21 +29,33 @@ func (cmd *detach) Register(ctx context.Context, f *flag.FlagSet) {
 	cmd.DatacenterFlag.Register(ctx, f)
 
 	f.StringVar(&cmd.cat, "c", "", "Tag category")
+	f.StringVar(&cmd.query, "query", "", "Detach tag from objects matching tags query EXPR, instead of PATH (see tags.find)")
+	f.Var(&cmd.kind, "type", "Limit -query results to objects of TYPE, such as VirtualMachine")
 }
 
 func (cmd *detach) Usage() string {
-	return "NAME PATH"
+	return "NAME [PATH]"
 }
 
 func (cmd *detach) Description() string {
 	return `Detach tag NAME from object PATH.
 
+If the '-query' flag is specified, tag NAME is detached from all objects matching the tags query (see tags.find).
+
 Examples:
   govc tags.detach k8s-region-us /dc1
-  govc tags.detach -c k8s-region us-ca1 /dc1/host/cluster1`
+  govc tags.detach -c k8s-region us-ca1 /dc1/host/cluster1
+  govc tags.detach -query 'env:prod && !tier:db' -type VirtualMachine backup-daily`
 }
 
 func (cmd *detach) Run(ctx context.Context, f *flag.FlagSet) error {
+	if cmd.query != "" {
+		if f.NArg() != 1 {
+			return flag.ErrHelp
+		}
+		return cmd.detachQuery(ctx, f.Arg(0))
+	}
+
 	if f.NArg() != 2 {
 		return flag.ErrHelp
 	}
@@ -65,3 +79,23 @@ func (cmd *detach) Run(ctx context.Context, f *flag.FlagSet) error {
 	}
 	return m.DetachTag(ctx, tag.ID, ref)
 }
+
+func (cmd *detach) detachQuery(ctx context.Context, tagID string) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := tags.NewManager(c)
+	tag, err := m.GetTagForCategory(ctx, tagID, cmd.cat)
+	if err != nil {
+		return err
+	}
+
+	refs, err := queryObjects(ctx, m, cmd.query, cmd.kind)
+	if err != nil || len(refs) == 0 {
+		return err
+	}
+
+	return m.DetachTagFromMultipleObjects(ctx, tag.ID, refs)
+}
@@ -0,0 +1,136 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package association
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+type search struct {
+	*flags.DatacenterFlag
+
+	kind flags.StringList
+	ref  bool
+}
+
+func init() {
+	cli.Register("tags.find", &search{})
+}
+
+func (cmd *search) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.DatacenterFlag, ctx = flags.NewDatacenterFlag(ctx)
+	cmd.DatacenterFlag.Register(ctx, f)
+
+	f.Var(&cmd.kind, "type", "Limit results to objects of TYPE, such as VirtualMachine")
+	f.BoolVar(&cmd.ref, "i", false, "Print the managed object reference instead of the inventory path")
+}
+
+func (cmd *search) Usage() string {
+	return "EXPR"
+}
+
+func (cmd *search) Description() string {
+	return `Find objects with tags matching the boolean expression EXPR.
+
+A term is a tag name or ID, optionally qualified by a category name or ID in the form CATEGORY:TAG.
+A TAG of "*" matches any tag in the category.
+Terms are combined using "!" (or "not"), "&&" (or "and"), "||" (or "or") and parentheses.
+Names that contain spaces, colons, parentheses or operator characters must be double quoted.
+Negation is relative to the objects with at least one tag attached.
+
+Examples:
+  govc tags.find k8s-region-us
+  govc tags.find -type VirtualMachine 'env:prod && !(env:test || deprecated)'
+  govc tags.find -i '"k8s-region":* and not "k8s-zone":us-west-1a'
+  govc tags.find -json 'tier:db' | jq -r .[].value`
+}
+
+type findResult struct {
+	refs  []types.ManagedObjectReference
+	paths []string
+}
+
+func (r *findResult) Write(w io.Writer) error {
+	for i := range r.refs {
+		if r.paths != nil {
+			fmt.Fprintln(w, r.paths[i])
+		} else {
+			fmt.Fprintln(w, r.refs[i])
+		}
+	}
+	return nil
+}
+
+func (r *findResult) Dump() any {
+	return r.refs
+}
+
+func (r *findResult) MarshalJSON() ([]byte, error) {
+	if r.refs == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(r.refs)
+}
+
+// queryObjects returns the objects matching the given tags query expression.
+func queryObjects(ctx context.Context, m *tags.Manager, expr string, kind []string) ([]mo.Reference, error) {
+	res, err := m.Query(ctx, tags.Query{Expr: expr, Types: kind})
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]mo.Reference, len(res))
+	for i := range res {
+		refs[i] = res[i]
+	}
+
+	return refs, nil
+}
+
+func (cmd *search) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	refs, err := tags.NewManager(c).Query(ctx, tags.Query{Expr: f.Arg(0), Types: cmd.kind})
+	if err != nil {
+		return err
+	}
+
+	res := &findResult{refs: refs}
+
+	if !cmd.ref && !cmd.All() {
+		vc, err := cmd.Client()
+		if err != nil {
+			return err
+		}
+
+		for _, ref := range refs {
+			path, err := find.InventoryPath(ctx, vc, ref)
+			if err != nil {
+				path = ref.String() // not an inventory object, such as a content library
+			}
+			res.paths = append(res.paths, path)
+		}
+	}
+
+	return cmd.WriteResult(res)
+}
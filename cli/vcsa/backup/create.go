@@ -0,0 +1,101 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/recovery/backup"
+)
+
+type create struct {
+	*flags.ClientFlag
+
+	spec  backup.JobCreateSpec
+	kind  string
+	parts flags.StringList
+	async bool
+}
+
+func init() {
+	cli.Register("vcsa.backup.create", &create{})
+}
+
+func (cmd *create) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.Var(&cmd.parts, "part", "Backup part ID (default parts if not specified)")
+	f.StringVar(&cmd.kind, "type", "", "Location type (defaults to LOCATION scheme)")
+	f.StringVar(&cmd.spec.BackupPassword, "backup-password", "", "Password used to encrypt the backup")
+	f.StringVar(&cmd.spec.LocationUser, "location-user", "", "Location user")
+	f.StringVar(&cmd.spec.LocationPassword, "location-password", "", "Location password")
+	f.StringVar(&cmd.spec.Comment, "comment", "", "Backup comment")
+	f.BoolVar(&cmd.async, "async", false, "Do not wait for the backup job to complete")
+}
+
+func (cmd *create) Usage() string {
+	return "LOCATION"
+}
+
+func (cmd *create) Description() string {
+	return `Start appliance backup job to LOCATION.
+
+The job ID is written to stdout.
+Unless the '-async' flag is specified, wait for the backup job to complete.
+The vcsim backup writes to "file" locations on the local file system and to "http" or "https" locations using PUT.
+
+Examples:
+  govc vcsa.backup.parts
+  govc vcsa.backup.create -location-user backup -location-password pass sftp://10.0.0.42/backups
+  govc vcsa.backup.create -part seat -comment "before upgrade" -type SFTP file:///var/tmp/backups
+  id=$(govc vcsa.backup.create -async https://backup.example.com/vcsa)
+  govc vcsa.backup.info $id`
+}
+
+func (cmd *create) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	cmd.spec.Location = f.Arg(0)
+	u, err := url.Parse(cmd.spec.Location)
+	if err != nil {
+		return err
+	}
+
+	cmd.spec.LocationType = backup.LocationType(strings.ToUpper(u.Scheme))
+	if cmd.kind != "" {
+		cmd.spec.LocationType = backup.LocationType(cmd.kind)
+	}
+	cmd.spec.Parts = cmd.parts
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := backup.NewManager(c)
+
+	id, err := m.CreateJob(ctx, cmd.spec)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(id)
+
+	if cmd.async {
+		return nil
+	}
+
+	_, err = m.WaitForJob(ctx, id)
+	return err
+}
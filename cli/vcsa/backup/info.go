@@ -0,0 +1,110 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/recovery/backup"
+)
+
+type info struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("vcsa.backup.info", &info{})
+}
+
+func (cmd *info) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *info) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *info) Usage() string {
+	return "ID"
+}
+
+func (cmd *info) Description() string {
+	return `Display appliance backup job info.
+
+Examples:
+  govc vcsa.backup.info $id
+  govc vcsa.backup.info -json $id | jq -r .state`
+}
+
+type infoResult struct {
+	info *backup.JobInfo
+}
+
+func (r *infoResult) Dump() any {
+	return r.info
+}
+
+func (r *infoResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.info)
+}
+
+func (r *infoResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+	job := r.info
+
+	fmt.Fprintf(tw, "ID:\t%s\n", job.ID)
+	fmt.Fprintf(tw, "State:\t%s\n", job.State)
+	fmt.Fprintf(tw, "Type:\t%s\n", job.Type)
+	fmt.Fprintf(tw, "Parts:\t%s\n", strings.Join(job.Parts, ","))
+	fmt.Fprintf(tw, "Location:\t%s\n", job.Location)
+	fmt.Fprintf(tw, "Location Type:\t%s\n", job.LocationType)
+	fmt.Fprintf(tw, "Comment:\t%s\n", job.Comment)
+	fmt.Fprintf(tw, "Progress:\t%d/%d\n", job.Progress.Completed, job.Progress.Total)
+	fmt.Fprintf(tw, "Size:\t%d\n", job.Size)
+	fmt.Fprintf(tw, "Start Time:\t%s\n", job.StartTime.Format(time.RFC3339))
+	if job.EndTime != nil {
+		fmt.Fprintf(tw, "End Time:\t%s\n", job.EndTime.Format(time.RFC3339))
+	}
+	if job.Error != nil {
+		fmt.Fprintf(tw, "Error:\t%s\n", job.Error.DefaultMessage)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *info) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	job, err := backup.NewManager(c).GetJob(ctx, f.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(&infoResult{job})
+}
@@ -0,0 +1,95 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/recovery/backup"
+)
+
+type ls struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("vcsa.backup.ls", &ls{})
+}
+
+func (cmd *ls) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *ls) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *ls) Usage() string {
+	return "[ID]..."
+}
+
+func (cmd *ls) Description() string {
+	return `List appliance backup jobs.
+
+If no ID is specified, all backup jobs are listed.
+
+Examples:
+  govc vcsa.backup.ls
+  govc vcsa.backup.ls -json | jq -r '.[] | select(.state == "SUCCEEDED") | .location'`
+}
+
+type lsResult []*backup.JobInfo
+
+func (r lsResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, job := range r {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			job.ID, job.State, job.Type, job.StartTime.Format(time.RFC3339), job.Location)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *ls) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := backup.NewManager(c)
+
+	ids := f.Args()
+	if len(ids) == 0 {
+		ids, err = m.ListJobs(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	res := make(lsResult, len(ids))
+	for i, id := range ids {
+		if res[i], err = m.GetJob(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return cmd.WriteResult(res)
+}
@@ -0,0 +1,73 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package schedule
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/recovery/backup"
+)
+
+type create struct {
+	*flags.ClientFlag
+	scheduleFlags
+
+	id string
+}
+
+func init() {
+	cli.Register("vcsa.backup.schedule.create", &create{})
+}
+
+func (cmd *create) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.scheduleFlags.register(f)
+	f.StringVar(&cmd.id, "id", "default", "Schedule ID")
+}
+
+func (cmd *create) Usage() string {
+	return "LOCATION"
+}
+
+func (cmd *create) Description() string {
+	return `Create appliance backup schedule to LOCATION.
+
+vCenter supports a single schedule with the ID "default".
+The vcsim schedules do not run on their recurrence, use 'vcsa.backup.schedule.run' to start a backup job.
+
+Examples:
+  govc vcsa.backup.schedule.create -hour 2 -days sunday,wednesday -keep 7 sftp://10.0.0.42/backups
+  govc vcsa.backup.schedule.create -hour 23 -minute 30 -location-user backup -location-password pass https://backup.example.com/vcsa`
+}
+
+func (cmd *create) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	update := cmd.updateSpec(nil)
+	spec := backup.ScheduleCreateSpec{
+		Parts:            update.Parts,
+		BackupPassword:   update.BackupPassword,
+		Location:         f.Arg(0),
+		LocationUser:     update.LocationUser,
+		LocationPassword: update.LocationPassword,
+		Enable:           update.Enable,
+		RecurrenceInfo:   update.RecurrenceInfo,
+		RetentionInfo:    update.RetentionInfo,
+	}
+
+	return backup.NewManager(c).CreateSchedule(ctx, cmd.id, spec)
+}
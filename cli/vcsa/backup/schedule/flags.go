@@ -0,0 +1,71 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package schedule
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/recovery/backup"
+)
+
+// scheduleFlags are the flags shared by schedule.create and schedule.update
+type scheduleFlags struct {
+	spec backup.ScheduleUpdateSpec
+
+	parts  flags.StringList
+	enable *bool
+	hour   int64
+	minute int64
+	days   string
+	keep   int64
+}
+
+func (cmd *scheduleFlags) register(f *flag.FlagSet) {
+	f.Var(&cmd.parts, "part", "Backup part ID (default parts if not specified)")
+	f.StringVar(&cmd.spec.BackupPassword, "backup-password", "", "Password used to encrypt the backup")
+	f.StringVar(&cmd.spec.LocationUser, "location-user", "", "Location user")
+	f.StringVar(&cmd.spec.LocationPassword, "location-password", "", "Location password")
+	f.Var(flags.NewOptionalBool(&cmd.enable), "enable", "Enable schedule")
+	f.Int64Var(&cmd.hour, "hour", -1, "Hour (0-23) of the backup")
+	f.Int64Var(&cmd.minute, "minute", -1, "Minute (0-59) of the backup")
+	f.StringVar(&cmd.days, "days", "", "Comma separated days of the week (daily if not specified)")
+	f.Int64Var(&cmd.keep, "keep", -1, "Number of backups to keep (0 keeps all)")
+}
+
+// updateSpec returns the spec with only the specified flags set.
+// Recurrence fields that are not specified are copied from current, if any.
+func (cmd *scheduleFlags) updateSpec(current *backup.RecurrenceInfo) backup.ScheduleUpdateSpec {
+	spec := cmd.spec
+	spec.Parts = cmd.parts
+	spec.Enable = cmd.enable
+
+	if cmd.hour >= 0 || cmd.minute >= 0 || cmd.days != "" {
+		r := backup.RecurrenceInfo{}
+		if current != nil {
+			r = *current
+		}
+		if cmd.hour >= 0 {
+			r.Hour = cmd.hour
+		}
+		if cmd.minute >= 0 {
+			r.Minute = cmd.minute
+		}
+		if cmd.days != "" {
+			r.Days = nil
+			for _, day := range strings.Split(cmd.days, ",") {
+				r.Days = append(r.Days, backup.Weekday(strings.ToUpper(day)))
+			}
+		}
+		spec.RecurrenceInfo = &r
+	}
+
+	if cmd.keep >= 0 {
+		spec.RetentionInfo = &backup.RetentionInfo{MaxCount: cmd.keep}
+	}
+
+	return spec
+}
@@ -0,0 +1,103 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package schedule
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/recovery/backup"
+)
+
+type ls struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("vcsa.backup.schedule.ls", &ls{})
+}
+
+func (cmd *ls) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *ls) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *ls) Description() string {
+	return `List appliance backup schedules.
+
+Examples:
+  govc vcsa.backup.schedule.ls
+  govc vcsa.backup.schedule.ls -json | jq .default.recurrence_info`
+}
+
+type lsResult map[string]backup.ScheduleInfo
+
+func (r lsResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	ids := make([]string, 0, len(r))
+	for id := range r {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		s := r[id]
+
+		when := "-"
+		if s.RecurrenceInfo != nil {
+			days := "daily"
+			if len(s.RecurrenceInfo.Days) != 0 {
+				names := make([]string, len(s.RecurrenceInfo.Days))
+				for i, day := range s.RecurrenceInfo.Days {
+					names[i] = string(day)
+				}
+				days = strings.Join(names, ",")
+			}
+			when = fmt.Sprintf("%02d:%02d %s", s.RecurrenceInfo.Hour, s.RecurrenceInfo.Minute, days)
+		}
+
+		keep := "all"
+		if s.RetentionInfo != nil && s.RetentionInfo.MaxCount > 0 {
+			keep = fmt.Sprintf("%d", s.RetentionInfo.MaxCount)
+		}
+
+		fmt.Fprintf(tw, "%s\t%t\t%s\t%s\t%s\n", id, s.Enable, when, keep, s.Location)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *ls) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	res, err := backup.NewManager(c).ListSchedules(ctx)
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(lsResult(res))
+}
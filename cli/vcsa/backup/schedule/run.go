@@ -0,0 +1,81 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package schedule
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/recovery/backup"
+)
+
+type run struct {
+	*flags.ClientFlag
+
+	comment string
+	async   bool
+}
+
+func init() {
+	cli.Register("vcsa.backup.schedule.run", &run{})
+}
+
+func (cmd *run) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.comment, "comment", "", "Backup comment")
+	f.BoolVar(&cmd.async, "async", false, "Do not wait for the backup job to complete")
+}
+
+func (cmd *run) Usage() string {
+	return "[ID]"
+}
+
+func (cmd *run) Description() string {
+	return `Start backup job using appliance backup schedule ID.
+
+ID defaults to "default".
+The job ID is written to stdout.
+Unless the '-async' flag is specified, wait for the backup job to complete.
+
+Examples:
+  govc vcsa.backup.schedule.run -comment "before upgrade"`
+}
+
+func (cmd *run) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() > 1 {
+		return flag.ErrHelp
+	}
+
+	id := "default"
+	if f.NArg() == 1 {
+		id = f.Arg(0)
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := backup.NewManager(c)
+
+	job, err := m.RunSchedule(ctx, id, cmd.comment)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(job)
+
+	if cmd.async {
+		return nil
+	}
+
+	_, err = m.WaitForJob(ctx, job)
+	return err
+}
@@ -0,0 +1,76 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package schedule
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/recovery/backup"
+)
+
+type update struct {
+	*flags.ClientFlag
+	scheduleFlags
+
+	location string
+}
+
+func init() {
+	cli.Register("vcsa.backup.schedule.update", &update{})
+}
+
+func (cmd *update) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.scheduleFlags.register(f)
+	f.StringVar(&cmd.location, "location", "", "Backup location")
+}
+
+func (cmd *update) Usage() string {
+	return "[ID]"
+}
+
+func (cmd *update) Description() string {
+	return `Update appliance backup schedule ID.
+
+ID defaults to "default".
+Only the specified options are changed.
+
+Examples:
+  govc vcsa.backup.schedule.update -enable=false
+  govc vcsa.backup.schedule.update -hour 4 -keep 3`
+}
+
+func (cmd *update) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() > 1 {
+		return flag.ErrHelp
+	}
+
+	id := "default"
+	if f.NArg() == 1 {
+		id = f.Arg(0)
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := backup.NewManager(c)
+
+	info, err := m.GetSchedule(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	spec := cmd.updateSpec(info.RecurrenceInfo)
+	spec.Location = cmd.location
+
+	return m.UpdateSchedule(ctx, id, spec)
+}
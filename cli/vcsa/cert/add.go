@@ -0,0 +1,87 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package cert
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/certificate"
+)
+
+type add struct {
+	*flags.ClientFlag
+
+	id string
+}
+
+func init() {
+	cli.Register("vcsa.cert.add", &add{})
+}
+
+func (cmd *add) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.id, "id", "", "Chain ID (generated if not specified)")
+}
+
+func (cmd *add) Usage() string {
+	return "FILE"
+}
+
+func (cmd *add) Description() string {
+	return `Add certificate chain to vCenter trusted root chains.
+
+FILE is a PEM encoded certificate chain, ordered from leaf to root.
+If FILE name is "-", read certificate chain from stdin.
+The chain ID is written to stdout.
+
+Examples:
+  govc vcsa.cert.add root.pem
+  govc about.cert -k -show -u ldap.example.com | govc vcsa.cert.add -`
+}
+
+func (cmd *add) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	var b []byte
+	name := f.Arg(0)
+	if name == "-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(filepath.Clean(name))
+	}
+	if err != nil {
+		return err
+	}
+
+	spec := certificate.TrustedRootChainCreateSpec{
+		CertChain: certificate.X509CertChain{CertChain: []string{string(b)}},
+		Chain:     cmd.id,
+	}
+
+	id, err := certificate.NewManager(c).CreateTrustedRootChain(ctx, spec)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(id)
+
+	return nil
+}
@@ -0,0 +1,108 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package cert
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/certificate"
+)
+
+type ls struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("vcsa.cert.ls", &ls{})
+}
+
+func (cmd *ls) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *ls) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *ls) Description() string {
+	return `List vCenter trusted root certificate chains.
+
+Examples:
+  govc vcsa.cert.ls
+  govc vcsa.cert.ls -json | jq -r '.[].chain'`
+}
+
+type chain struct {
+	Chain     string                    `json:"chain"`
+	CertChain certificate.X509CertChain `json:"cert_chain"`
+}
+
+type lsResult []chain
+
+func (r lsResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, c := range r {
+		var subject string
+
+		certs := c.CertChain.CertChain
+		if len(certs) != 0 {
+			block, _ := pem.Decode([]byte(certs[len(certs)-1]))
+			if block != nil {
+				x, err := x509.ParseCertificate(block.Bytes)
+				if err != nil {
+					return err
+				}
+				x.Subject.Names = nil // trim x.Subject.String() output
+				subject = x.Subject.String()
+			}
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\n", c.Chain, subject)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *ls) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := certificate.NewManager(c)
+
+	chains, err := m.ListTrustedRootChains(ctx)
+	if err != nil {
+		return err
+	}
+
+	res := make(lsResult, len(chains))
+	for i, c := range chains {
+		info, err := m.GetTrustedRootChain(ctx, c.Chain)
+		if err != nil {
+			return err
+		}
+		res[i] = chain{Chain: c.Chain, CertChain: info.CertChain}
+	}
+
+	return cmd.WriteResult(res)
+}
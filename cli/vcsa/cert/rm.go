@@ -0,0 +1,61 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package cert
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/certificate"
+)
+
+type rm struct {
+	*flags.ClientFlag
+}
+
+func init() {
+	cli.Register("vcsa.cert.rm", &rm{})
+}
+
+func (cmd *rm) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+}
+
+func (cmd *rm) Usage() string {
+	return "ID..."
+}
+
+func (cmd *rm) Description() string {
+	return `Remove certificate chain ID from vCenter trusted root chains.
+
+The chain of the active VMCA signing certificate cannot be removed.
+
+Examples:
+  govc vcsa.cert.rm $id`
+}
+
+func (cmd *rm) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() == 0 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := certificate.NewManager(c)
+
+	for _, id := range f.Args() {
+		if err := m.DeleteTrustedRootChain(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
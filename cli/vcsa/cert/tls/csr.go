@@ -0,0 +1,69 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package tls
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/certificate"
+)
+
+type csr struct {
+	*flags.ClientFlag
+
+	spec certificate.CSRSpec
+	san  flags.StringList
+}
+
+func init() {
+	cli.Register("vcsa.cert.tls.csr", &csr{})
+}
+
+func (cmd *csr) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.Int64Var(&cmd.spec.KeySize, "size", 0, "Key size")
+	f.StringVar(&cmd.spec.CommonName, "cn", "", "Common name")
+	f.StringVar(&cmd.spec.Organization, "o", "", "Organization")
+	f.StringVar(&cmd.spec.OrganizationUnit, "ou", "", "Organization unit")
+	f.StringVar(&cmd.spec.Locality, "l", "", "Locality")
+	f.StringVar(&cmd.spec.StateOrProvince, "st", "", "State or province")
+	f.StringVar(&cmd.spec.Country, "c", "", "Country")
+	f.StringVar(&cmd.spec.EmailAddress, "email", "", "Email address")
+	f.Var(&cmd.san, "san", "Subject alternative name")
+}
+
+func (cmd *csr) Description() string {
+	return `Generate a CSR for the vCenter machine SSL certificate.
+
+The PEM encoded CSR is written to stdout.
+The private key is kept by vCenter and used by 'vcsa.cert.tls.replace' when no key is specified.
+
+Examples:
+  govc vcsa.cert.tls.csr -cn vcsa.example.com -o Example -san vcsa.example.com -san 10.0.0.10 > machine.csr`
+}
+
+func (cmd *csr) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	cmd.spec.SubjectAltName = cmd.san
+
+	res, err := certificate.NewManager(c).CreateTLSCSR(ctx, cmd.spec)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(res)
+
+	return nil
+}
@@ -0,0 +1,102 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package tls
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/certificate"
+)
+
+type info struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+
+	show bool
+}
+
+func init() {
+	cli.Register("vcsa.cert.tls.info", &info{})
+}
+
+func (cmd *info) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.show, "show", false, "Show PEM encoded certificate only")
+}
+
+func (cmd *info) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *info) Description() string {
+	return `Display vCenter machine SSL certificate info.
+
+Examples:
+  govc vcsa.cert.tls.info
+  govc vcsa.cert.tls.info -show > machine.pem
+  govc vcsa.cert.tls.info -json | jq -r .valid_to`
+}
+
+type infoResult struct {
+	cmd  *info
+	info *certificate.TLSInfo
+}
+
+func (r *infoResult) Dump() any {
+	return r.info
+}
+
+func (r *infoResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.info)
+}
+
+func (r *infoResult) Write(w io.Writer) error {
+	if r.cmd.show {
+		_, err := io.WriteString(w, r.info.Cert)
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Subject:\t%s\n", r.info.SubjectDN)
+	fmt.Fprintf(tw, "Issuer:\t%s\n", r.info.IssuerDN)
+	fmt.Fprintf(tw, "Serial Number:\t%s\n", r.info.SerialNumber)
+	fmt.Fprintf(tw, "Valid From:\t%s\n", r.info.ValidFrom)
+	fmt.Fprintf(tw, "Valid To:\t%s\n", r.info.ValidTo)
+	fmt.Fprintf(tw, "Thumbprint:\t%s\n", r.info.Thumbprint)
+	fmt.Fprintf(tw, "Subject Alternative Name:\t%s\n", strings.Join(r.info.SubjectAlternativeName, ","))
+
+	return tw.Flush()
+}
+
+func (cmd *info) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	res, err := certificate.NewManager(c).GetTLS(ctx)
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(&infoResult{cmd, res})
+}
@@ -0,0 +1,48 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package tls
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/certificate"
+)
+
+type renew struct {
+	*flags.ClientFlag
+
+	duration int64
+}
+
+func init() {
+	cli.Register("vcsa.cert.tls.renew", &renew{})
+}
+
+func (cmd *renew) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.Int64Var(&cmd.duration, "days", 0, "Certificate validity in days (vCenter default if 0)")
+}
+
+func (cmd *renew) Description() string {
+	return `Renew vCenter machine SSL certificate using the VMCA signing certificate.
+
+Examples:
+  govc vcsa.cert.tls.renew
+  govc vcsa.cert.tls.renew -days 365`
+}
+
+func (cmd *renew) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	return certificate.NewManager(c).RenewTLS(ctx, cmd.duration)
+}
@@ -0,0 +1,99 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package tls
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/certificate"
+)
+
+type replace struct {
+	*flags.ClientFlag
+
+	key  string
+	root string
+}
+
+func init() {
+	cli.Register("vcsa.cert.tls.replace", &replace{})
+}
+
+func (cmd *replace) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.key, "private-key", "", "PEM encoded private key file")
+	f.StringVar(&cmd.root, "root", "", "PEM encoded root certificate file, added to trusted root chains")
+}
+
+func (cmd *replace) Usage() string {
+	return "FILE"
+}
+
+func (cmd *replace) Description() string {
+	return `Replace vCenter machine SSL certificate.
+
+FILE is the PEM encoded certificate, optionally followed by intermediate certificates.
+If FILE name is "-", read certificate from stdin.
+If the '-private-key' flag is not specified, the private key generated by 'vcsa.cert.tls.csr' is used.
+The certificate must chain to a trusted root, see 'vcsa.cert.add' or the '-root' flag.
+vCenter services are restarted once the certificate is replaced.
+
+Examples:
+  govc vcsa.cert.tls.replace -private-key machine.key -root root.pem machine.pem
+  govc vcsa.cert.tls.csr -cn vcsa.example.com > machine.csr # signed by an external CA
+  govc vcsa.cert.tls.replace machine.pem`
+}
+
+func readFile(name string) (string, error) {
+	var b []byte
+	var err error
+
+	if name == "-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(filepath.Clean(name))
+	}
+
+	return string(b), err
+}
+
+func (cmd *replace) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	var spec certificate.TLSSpec
+
+	if spec.Cert, err = readFile(f.Arg(0)); err != nil {
+		return err
+	}
+
+	if cmd.key != "" {
+		if spec.Key, err = readFile(cmd.key); err != nil {
+			return err
+		}
+	}
+
+	if cmd.root != "" {
+		if spec.RootCert, err = readFile(cmd.root); err != nil {
+			return err
+		}
+	}
+
+	return certificate.NewManager(c).SetTLS(ctx, spec)
+}
@@ -0,0 +1,111 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/health"
+)
+
+type status struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("vcsa.health", &status{})
+}
+
+func (cmd *status) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *status) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *status) Usage() string {
+	return "[ITEM]..."
+}
+
+func (cmd *status) Description() string {
+	return `Display appliance health levels.
+
+If no ITEM is specified, the level of all health items is displayed.
+Health levels are one of: green, yellow, orange, red or gray.
+The vcsim health levels default to green and can be changed using "vcsim.appliance.health.$ITEM" options.
+
+Examples:
+  govc vcsa.health
+  govc vcsa.health storage swap
+  govc vcsa.health -json | jq -r '.items[] | select(.level != "green") | .item'
+  govc option.set vcsim.appliance.health.storage red # vcsim only`
+}
+
+type itemLevel struct {
+	Item  string       `json:"item"`
+	Level health.Level `json:"level"`
+}
+
+type statusResult struct {
+	Items     []itemLevel `json:"items"`
+	LastCheck time.Time   `json:"last_check"`
+}
+
+func (r *statusResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, item := range r.Items {
+		fmt.Fprintf(tw, "%s:\t%s\n", item.Item, item.Level)
+	}
+	fmt.Fprintf(tw, "Last Check:\t%s\n", r.LastCheck.Format(time.RFC3339))
+
+	return tw.Flush()
+}
+
+func (cmd *status) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := health.NewManager(c)
+
+	items := f.Args()
+	if len(items) == 0 {
+		items = health.Items
+	}
+
+	var res statusResult
+
+	for _, item := range items {
+		level, err := m.Get(ctx, item)
+		if err != nil {
+			return fmt.Errorf("%s: %s", item, err)
+		}
+		res.Items = append(res.Items, itemLevel{item, level})
+	}
+
+	if res.LastCheck, err = m.LastCheck(ctx); err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(&res)
+}
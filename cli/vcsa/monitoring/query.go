@@ -0,0 +1,104 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package monitoring
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/monitoring"
+)
+
+type query struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+
+	interval string
+	function string
+	since    time.Duration
+}
+
+func init() {
+	cli.Register("vcsa.monitoring.query", &query{})
+}
+
+func (cmd *query) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.interval, "i", string(monitoring.IntervalMinutes5), "Interval between data points")
+	f.StringVar(&cmd.function, "f", string(monitoring.FunctionAvg), "Aggregation function (COUNT|MAX|AVG|MIN)")
+	f.DurationVar(&cmd.since, "since", time.Hour, "Query data points since the given duration")
+}
+
+func (cmd *query) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *query) Usage() string {
+	return "ID..."
+}
+
+func (cmd *query) Description() string {
+	return `Query appliance monitoring statistics.
+
+Intervals are one of: MINUTES1, MINUTES5, MINUTES30, HOURS2, HOURS6 or DAY1.
+
+Examples:
+  govc vcsa.monitoring.query cpu.util mem.util
+  govc vcsa.monitoring.query -i HOURS2 -f MAX -since 24h storage.util.filesystem.root
+  govc vcsa.monitoring.query -json mem.usage | jq -r '.[].data[]'`
+}
+
+type queryResult []monitoring.ItemData
+
+func (r queryResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, item := range r {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", item.Name, item.Interval, item.Function, strings.Join(item.Data, ","))
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *query) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() == 0 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	data, err := monitoring.NewManager(c).Query(ctx, monitoring.Query{
+		Names:     f.Args(),
+		Interval:  monitoring.Interval(cmd.interval),
+		Function:  monitoring.Function(cmd.function),
+		StartTime: now.Add(-cmd.since),
+		EndTime:   now,
+	})
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(queryResult(data))
+}
@@ -0,0 +1,103 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/update"
+)
+
+type info struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("vcsa.update.info", &info{})
+}
+
+func (cmd *info) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *info) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *info) Description() string {
+	return `Display appliance update state.
+
+Examples:
+  govc vcsa.update.info
+  govc vcsa.update.info -json | jq -r .state`
+}
+
+type infoResult struct {
+	info   *update.Info
+	staged *update.StagedInfo
+}
+
+func (r *infoResult) Dump() any {
+	return r.info
+}
+
+func (r *infoResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		*update.Info
+		Staged *update.StagedInfo `json:"staged,omitempty"`
+	}{r.info, r.staged})
+}
+
+func (r *infoResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "State:\t%s\n", r.info.State)
+	fmt.Fprintf(tw, "Version:\t%s\n", r.info.Version)
+	if r.info.LatestQueryTime != nil {
+		fmt.Fprintf(tw, "Latest Query Time:\t%s\n", r.info.LatestQueryTime.Format(time.RFC3339))
+	}
+	if r.staged != nil {
+		fmt.Fprintf(tw, "Staged:\t%s (complete=%t)\n", r.staged.Version, r.staged.StagingComplete)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *info) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := update.NewManager(c)
+
+	res := &infoResult{}
+	if res.info, err = m.Get(ctx); err != nil {
+		return err
+	}
+
+	// GetStaged fails if no update is staged
+	if staged, err := m.GetStaged(ctx); err == nil {
+		res.staged = staged
+	}
+
+	return cmd.WriteResult(res)
+}
@@ -0,0 +1,103 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package update
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/update"
+)
+
+type install struct {
+	*flags.ClientFlag
+
+	data  flags.StringList
+	async bool
+}
+
+func init() {
+	cli.Register("vcsa.update.install", &install{})
+}
+
+func (cmd *install) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.Var(&cmd.data, "data", "Answer to a precheck question in the form KEY=VALUE")
+	f.BoolVar(&cmd.async, "async", false, "Do not wait for the install task to complete")
+}
+
+func (cmd *install) Usage() string {
+	return "VERSION"
+}
+
+func (cmd *install) Description() string {
+	return `Install appliance update VERSION.
+
+If VERSION is not staged, it is staged before installing.
+The task ID is written to stdout.
+Unless the '-async' flag is specified, wait for the install task to complete.
+
+Examples:
+  govc vcsa.update.precheck 8.0.3.00200
+  govc vcsa.update.install 8.0.3.00200
+  govc vcsa.update.install -data vmdir.password=secret 8.0.3.00200`
+}
+
+func (cmd *install) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+	version := f.Arg(0)
+
+	data := make(map[string]string)
+	for _, kv := range cmd.data {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return fmt.Errorf("invalid -data %q", kv)
+		}
+		data[k] = v
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := update.NewManager(c)
+
+	pending, err := m.GetPending(ctx, version)
+	if err != nil {
+		return err
+	}
+
+	if !pending.Staged {
+		id, err := m.Stage(ctx, version)
+		if err != nil {
+			return err
+		}
+		if err = wait(ctx, c, id); err != nil {
+			return err
+		}
+	}
+
+	id, err := m.Install(ctx, version, data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(id)
+
+	if cmd.async {
+		return nil
+	}
+
+	return wait(ctx, c, id)
+}
@@ -0,0 +1,105 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/update"
+)
+
+type precheck struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("vcsa.update.precheck", &precheck{})
+}
+
+func (cmd *precheck) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *precheck) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *precheck) Usage() string {
+	return "VERSION"
+}
+
+func (cmd *precheck) Description() string {
+	return `Run appliance update precheck for VERSION.
+
+Examples:
+  govc vcsa.update.precheck 8.0.3.00200
+  govc vcsa.update.precheck -json 8.0.3.00200 | jq .issues.errors`
+}
+
+type precheckResult struct {
+	res *update.PrecheckResult
+}
+
+func (r *precheckResult) Dump() any {
+	return r.res
+}
+
+func (r *precheckResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.res)
+}
+
+func (r *precheckResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Estimated Time To Install:\t%dm\n", r.res.EstimatedTimeToInstall)
+	fmt.Fprintf(tw, "Reboot Required:\t%t\n", r.res.RebootRequired)
+
+	if issues := r.res.Issues; issues != nil {
+		for _, n := range issues.Errors {
+			fmt.Fprintf(tw, "Error:\t%s\n", n.Message.DefaultMessage)
+		}
+		for _, n := range issues.Warnings {
+			fmt.Fprintf(tw, "Warning:\t%s\n", n.Message.DefaultMessage)
+		}
+		for _, n := range issues.Info {
+			fmt.Fprintf(tw, "Info:\t%s\n", n.Message.DefaultMessage)
+		}
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *precheck) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	res, err := update.NewManager(c).Precheck(ctx, f.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(&precheckResult{res})
+}
@@ -0,0 +1,71 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package update
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/update"
+)
+
+type stage struct {
+	*flags.ClientFlag
+
+	async bool
+}
+
+func init() {
+	cli.Register("vcsa.update.stage", &stage{})
+}
+
+func (cmd *stage) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.async, "async", false, "Do not wait for the stage task to complete")
+}
+
+func (cmd *stage) Usage() string {
+	return "VERSION"
+}
+
+func (cmd *stage) Description() string {
+	return `Stage appliance update VERSION.
+
+The task ID is written to stdout.
+Unless the '-async' flag is specified, wait for the stage task to complete.
+
+Examples:
+  govc vcsa.update.stage 8.0.3.00200
+  govc vcsa.update.info`
+}
+
+func (cmd *stage) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	id, err := update.NewManager(c).Stage(ctx, f.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(id)
+
+	if cmd.async {
+		return nil
+	}
+
+	return wait(ctx, c, id)
+}
@@ -0,0 +1,32 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package update
+
+import (
+	"context"
+
+	"github.com/vmware/govmomi/vapi/cis/tasks"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// wait for the given update task to complete, returning the task error if it failed.
+func wait(ctx context.Context, c *rest.Client, id string) error {
+	m := tasks.NewManagerWithCustomInterval(c, 1)
+
+	if _, err := m.WaitForCompletion(ctx, id); err != nil {
+		return err
+	}
+
+	info, err := m.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if info.Error != nil {
+		return info.Error
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package guest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/guest/toolbox"
+)
+
+type shell struct {
+	*GuestFlag
+
+	dir       string
+	vars      env
+	processIO bool
+}
+
+func init() {
+	cli.Register("guest.shell", &shell{})
+}
+
+func (cmd *shell) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.GuestFlag, ctx = newGuestProcessFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.dir, "C", "", "The absolute path of the initial working directory")
+	f.Var(&cmd.vars, "e", "Set environment variables")
+	f.BoolVar(&cmd.processIO, "toolbox", false, "Use toolbox Process I/O, only supported by the govmomi toolbox")
+}
+
+func (cmd *shell) Usage() string {
+	return "[COMMAND]..."
+}
+
+func (cmd *shell) Description() string {
+	return `Interactive shell session in VM, without network connectivity to the VM.
+
+Each command line read from stdin is run as a guest process, with output streamed to stdout and stderr.
+The "cd" command changes the working directory of the commands that follow, other shell state such as
+variables does not carry over between commands. The "exit [N]" command ends the session.
+
+If COMMAND is given, it is run as a single command with stdin forwarded when stdin is not a terminal.
+The exit code of the last command is propagated to the govc process exit code.
+
+Examples:
+  govc guest.shell -vm $name
+  govc guest.shell -vm $name -C /var/log ls -l
+  govc guest.shell -vm $name 'cat > /tmp/motd' < motd.txt
+  govc guest.shell -vm $name < script.sh`
+}
+
+type exitCode int
+
+func (rc exitCode) Error() string {
+	return fmt.Sprintf("exit %d", rc)
+}
+
+func (rc exitCode) ExitCode() int {
+	return int(rc)
+}
+
+func (cmd *shell) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.Toolbox(ctx)
+	if err != nil {
+		return err
+	}
+
+	sh := toolbox.NewShell(c)
+	sh.Stdout = os.Stdout
+	sh.Stderr = os.Stderr
+	sh.Dir = cmd.dir
+	sh.Env = cmd.vars
+	sh.ProcessIO = cmd.processIO
+
+	defer func() {
+		_ = sh.Close(ctx)
+	}()
+
+	tty := false
+	if info, serr := os.Stdin.Stat(); serr == nil {
+		tty = info.Mode()&os.ModeCharDevice != 0
+	}
+
+	var rc int
+
+	if f.NArg() == 0 {
+		if tty {
+			vm, _ := cmd.VirtualMachine()
+			sh.Prompt = vm.Name() + "$ "
+		}
+
+		rc, err = sh.Run(ctx, os.Stdin)
+	} else {
+		var stdin io.Reader
+		if !tty {
+			stdin = os.Stdin
+		}
+
+		rc, err = sh.Exec(ctx, strings.Join(f.Args(), " "), stdin)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if rc != 0 {
+		return exitCode(rc)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package guest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/guest/toolbox"
+)
+
+type syncCmd struct {
+	*GuestFlag
+
+	download bool
+	opts     toolbox.SyncOptions
+}
+
+func init() {
+	cli.Register("guest.sync", &syncCmd{})
+}
+
+func (cmd *syncCmd) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.GuestFlag, ctx = newGuestFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.download, "download", false, "Sync SOURCE directory in the guest to local DEST directory")
+	f.BoolVar(&cmd.opts.Delete, "delete", false, "Delete files in DEST that do not exist in SOURCE")
+	f.BoolVar(&cmd.opts.DryRun, "n", false, "Dry run, print changes without making them")
+	f.IntVar(&cmd.opts.Parallel, "p", 4, "Number of concurrent file transfers")
+}
+
+func (cmd *syncCmd) Process(ctx context.Context) error {
+	if err := cmd.GuestFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *syncCmd) Usage() string {
+	return "SOURCE DEST"
+}
+
+func (cmd *syncCmd) Description() string {
+	return `Sync local SOURCE directory to DEST directory in the guest VM.
+
+Files are transferred if they do not exist in DEST, differ in size or have a newer modification time in SOURCE.
+Directories are created as needed. Each change to DEST is printed, where the file name is relative to DEST.
+
+Examples:
+  govc guest.sync -vm $name ./site /var/www/html
+  govc guest.sync -vm $name -delete -n ./site /var/www/html # print changes only
+  govc guest.sync -vm $name -download /var/log/app ./logs`
+}
+
+func (cmd *syncCmd) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 2 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.Toolbox(ctx)
+	if err != nil {
+		return err
+	}
+
+	opts := cmd.opts
+	opts.Log = func(action toolbox.SyncAction, name string) {
+		fmt.Printf("%s\t%s\n", action, name)
+	}
+
+	src := f.Arg(0)
+	dst := f.Arg(1)
+
+	if cmd.download {
+		return c.SyncFromGuest(ctx, src, dst, opts)
+	}
+
+	return c.SyncToGuest(ctx, src, dst, opts)
+}
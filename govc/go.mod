@@ -14,4 +14,5 @@ require (
 	github.com/rogpeppe/go-internal v1.12.0 // indirect
 	github.com/xlab/treeprint v1.2.0 // indirect
 	golang.org/x/text v0.26.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -14,9 +14,11 @@ import (
 	_ "github.com/vmware/govmomi/cli/cluster/draft"
 	_ "github.com/vmware/govmomi/cli/cluster/draft/baseimage"
 	_ "github.com/vmware/govmomi/cli/cluster/draft/component"
+	_ "github.com/vmware/govmomi/cli/cluster/draft/hardware"
 	_ "github.com/vmware/govmomi/cli/cluster/group"
 	_ "github.com/vmware/govmomi/cli/cluster/module"
 	_ "github.com/vmware/govmomi/cli/cluster/override"
+	_ "github.com/vmware/govmomi/cli/cluster/policy"
 	_ "github.com/vmware/govmomi/cli/cluster/rule"
 	_ "github.com/vmware/govmomi/cli/cluster/vlcm"
 	_ "github.com/vmware/govmomi/cli/datacenter"
@@ -81,6 +83,7 @@ import (
 	_ "github.com/vmware/govmomi/cli/namespace/vmclass"
 	_ "github.com/vmware/govmomi/cli/object"
 	_ "github.com/vmware/govmomi/cli/option"
+	_ "github.com/vmware/govmomi/cli/ovf"
 	_ "github.com/vmware/govmomi/cli/permissions"
 	_ "github.com/vmware/govmomi/cli/pool"
 	_ "github.com/vmware/govmomi/cli/role"
@@ -100,9 +103,16 @@ import (
 	_ "github.com/vmware/govmomi/cli/vcsa/access/dcui"
 	_ "github.com/vmware/govmomi/cli/vcsa/access/shell"
 	_ "github.com/vmware/govmomi/cli/vcsa/access/ssh"
+	_ "github.com/vmware/govmomi/cli/vcsa/backup"
+	_ "github.com/vmware/govmomi/cli/vcsa/backup/schedule"
+	_ "github.com/vmware/govmomi/cli/vcsa/cert"
+	_ "github.com/vmware/govmomi/cli/vcsa/cert/tls"
+	_ "github.com/vmware/govmomi/cli/vcsa/health"
 	_ "github.com/vmware/govmomi/cli/vcsa/log"
+	_ "github.com/vmware/govmomi/cli/vcsa/monitoring"
 	_ "github.com/vmware/govmomi/cli/vcsa/proxy"
 	_ "github.com/vmware/govmomi/cli/vcsa/shutdown"
+	_ "github.com/vmware/govmomi/cli/vcsa/update"
 	_ "github.com/vmware/govmomi/cli/version"
 	_ "github.com/vmware/govmomi/cli/vlcm/depot/content/baseimages"
 	_ "github.com/vmware/govmomi/cli/vlcm/depot/offline"
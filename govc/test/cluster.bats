@@ -422,3 +422,71 @@ _EOF_
   assert_equal true "$(jq -r .dasConfig.enabled <<<"$config")"
   assert_equal false "$(jq -r .dasConfig.admissionControlEnabled <<<"$config")"
 }
+
+@test "cluster.policy" {
+  vcsim_env
+
+  run govc cluster.policy.ls -C
+  assert_success
+  assert_output_lines 6
+
+  run govc cluster.policy.ls
+  assert_success ""
+
+  govc tags.category.create placement
+  govc tags.create -c placement gold-vms
+  govc tags.create -c placement gold-hosts
+
+  govc tags.attach -c placement gold-hosts /DC0/host/DC0_C0/DC0_C0_H2
+  govc tags.attach -c placement gold-vms /DC0/vm/DC0_C0_RP0_VM0
+
+  run govc cluster.policy.create -capability enoent -vm-tag gold-vms gold
+  assert_failure
+
+  run govc cluster.policy.create -capability vm-host-affinity -vm-tag gold-vms gold
+  assert_failure # -host-tag is required
+
+  run govc cluster.policy.create -capability vm-host-affinity -c placement -vm-tag gold-vms -host-tag gold-hosts -d "Gold tier" gold
+  assert_success
+  id="$output"
+
+  run govc cluster.policy.create -capability vm-host-affinity -vm-tag gold-vms -host-tag gold-hosts gold
+  assert_failure # duplicate name
+
+  run govc cluster.policy.ls
+  assert_success
+  assert_matches "$id"
+  assert_matches vm-host-affinity
+
+  run govc cluster.policy.info gold
+  assert_success
+  assert_matches "Gold tier"
+  assert_matches gold-hosts
+
+  run govc cluster.policy.info -json "$id"
+  assert_success
+  assert_equal "$id" "$(jq -r .[].policy <<<"$output")"
+
+  govc vm.migrate -host DC0_C0_H0 DC0_C0_RP0_VM0
+
+  run govc cluster.policy.status gold
+  assert_success "/DC0/vm/DC0_C0_RP0_VM0  NOT_COMPLIANT"
+
+  run govc cluster.policy.status gold DC0_C0_RP0_VM1
+  assert_success "/DC0/vm/DC0_C0_RP0_VM1  NOT_APPLICABLE"
+
+  govc vm.migrate -host DC0_C0_H2 DC0_C0_RP0_VM0
+
+  run govc cluster.policy.status -json gold
+  assert_success
+  assert_equal COMPLIANT "$(jq -r .[].status <<<"$output")"
+
+  run govc cluster.policy.rm gold
+  assert_success
+
+  run govc cluster.policy.rm gold
+  assert_failure
+
+  run govc cluster.policy.ls
+  assert_success ""
+}
@@ -181,3 +181,63 @@ vcsim_guest() {
   run govc guest.run uname -a
   assert_failure # powered off
 }
+
+@test "guest.sync" {
+  root=$BATS_TMPDIR/$(new_id)
+  mkdir -p "$root"
+
+  vcsim_env -toolbox-root "$root"
+
+  export GOVC_VM=DC0_H0_VM0 GOVC_GUEST_LOGIN=user:pass
+
+  run govc vm.change -e RUN.toolbox=true
+  assert_success
+
+  src=$BATS_TMPDIR/$(new_id)
+  dst=/$(new_id) # guest path within $root
+  mkdir -p "$src/sub"
+  echo foo > "$src/foo"
+  echo bar > "$src/sub/bar"
+
+  run govc guest.sync "$src" "$dst"
+  assert_success
+  assert_matches "copy	sub/bar"
+
+  run govc guest.sync "$src" "$dst"
+  assert_success "" # no changes
+
+  rm "$src/foo"
+
+  run govc guest.sync -delete -n "$src" "$dst"
+  assert_success "delete	foo"
+  assert [ -e "$root$dst/foo" ]
+
+  run govc guest.sync -delete "$src" "$dst"
+  assert_success "delete	foo"
+  assert [ ! -e "$root$dst/foo" ]
+
+  run govc guest.sync -download "$dst" "$src.local"
+  assert_success
+  assert [ -e "$src.local/sub/bar" ]
+
+  rm -rf "$src" "$root" "$src.local"
+}
+
+@test "guest.shell" {
+  vcsim_env -toolbox-root / # run guest programs on the local system
+
+  export GOVC_VM=DC0_H0_VM0 GOVC_GUEST_LOGIN=user:pass
+
+  run govc vm.change -e RUN.toolbox=true
+  assert_success
+
+  run govc guest.shell -C /etc pwd
+  assert_success /etc
+
+  run govc guest.shell 'exit 3'
+  assert_equal 3 "$status"
+
+  run govc guest.shell <<<$'cd /tmp\npwd\nexit 2'
+  assert_equal 2 "$status"
+  assert_output /tmp
+}
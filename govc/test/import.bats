@@ -100,6 +100,31 @@ load test_helper
   rm -rf "$dir"
 }
 
+@test "import.lint" {
+  export GOVC_URL="unused"
+
+  run govc import.lint "$GOVC_IMAGES/$TTYLINUX_NAME.ova"
+  assert_success
+
+  run govc import.lint -json "$GOVC_IMAGES/$TTYLINUX_NAME.ovf"
+  assert_success
+
+  run jq . <<<"$output"
+  assert_success
+
+  run govc import.lint "$GOVC_IMAGES/$TTYLINUX_NAME-bad-checksum.ova"
+  assert_failure
+  assert_matches DigestMismatch
+
+  dir=$($mktemp --tmpdir -d govc-test-XXXXX 2>/dev/null || $mktemp -d -t govc-test-XXXXX)
+  sed -e 's/ovf:diskId="vmdisk1"/ovf:diskId="vmdisk2"/' "$GOVC_IMAGES/$TTYLINUX_NAME.ovf" > "$dir/$TTYLINUX_NAME.ovf"
+  touch "$dir/$TTYLINUX_NAME-disk1.vmdk"
+  run govc import.lint "$dir/$TTYLINUX_NAME.ovf"
+  assert_failure
+  assert_matches InvalidReference
+  rm -rf "$dir"
+}
+
 @test "import.ovf -host.ipath" {
   vcsim_env
 
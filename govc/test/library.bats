@@ -461,6 +461,121 @@ EOF
   assert_success
 }
 
+@test "library.serve" {
+  vcsim_env
+
+  dir=$BATS_TMPDIR/library-serve
+  rm -rf "$dir"
+  mkdir -p "$dir/iso"
+
+  run govc ovf.unpack "$GOVC_IMAGES/ttylinux-latest.ova" "$dir/ttylinux"
+  assert_success
+
+  cp "$GOVC_IMAGES/$TTYLINUX_NAME.iso" "$dir/iso/"
+  echo "hello" > "$dir/README.txt"
+
+  log=$BATS_TMPDIR/library-serve.log
+  govc library.serve -password secret "$dir" > "$log" &
+  pid=$!
+
+  while [ ! -s "$log" ] ; do sleep 0.1 ; done
+  url=$(cat "$log")
+
+  run govc library.create -sub "$url" -sub-password wrong my-content
+  assert_success
+
+  run govc library.ls my-content/
+  assert_success ""
+
+  run govc library.rm my-content
+  assert_success
+
+  run govc library.create -sub "$url" -sub-password secret -sub-ondemand=true my-content
+  assert_success
+
+  run govc library.ls my-content/
+  assert_success
+  assert_matches /my-content/ttylinux
+  assert_matches /my-content/iso
+  assert_matches /my-content/README.txt
+
+  run govc library.ls -json my-content/iso
+  assert_success
+  [ "$(jq -r .[].type <<<"$output")" = "iso" ]
+
+  run govc library.ls my-content/ttylinux/
+  assert_success
+  assert_matches "/my-content/ttylinux/$TTYLINUX_NAME.ovf"
+
+  rm "$dir/README.txt"
+
+  run govc library.sync my-content
+  assert_success
+
+  run govc library.ls my-content/README.txt
+  assert_success ""
+
+  kill "$pid"
+}
+
+@test "library.mirror" {
+  vcsim_env
+
+  dir=$BATS_TMPDIR/library-mirror
+  rm -rf "$dir"
+  mkdir -p "$dir"
+
+  run govc ovf.unpack "$GOVC_IMAGES/ttylinux-latest.ova" "$dir/ttylinux"
+  assert_success
+
+  cp "$GOVC_IMAGES/$TTYLINUX_NAME.iso" "$dir/"
+  echo "hello" > "$dir/README.txt"
+
+  run govc library.create my-content
+  assert_success
+
+  run govc library.mirror "$dir"
+  assert_failure
+
+  run govc library.mirror -n "$dir" my-content
+  assert_success
+  assert_matches "create ttylinux"
+  assert_matches "upload README/README.txt"
+
+  run govc library.ls my-content/
+  assert_success ""
+
+  run govc library.mirror "$dir" my-content
+  assert_success
+
+  run govc library.ls my-content/
+  assert_success
+  assert_matches /my-content/ttylinux
+  assert_matches /my-content/README
+  assert_matches "/my-content/$TTYLINUX_NAME"
+
+  run govc library.mirror -n "$dir" my-content
+  assert_success "" # no changes
+
+  echo "world" >> "$dir/README.txt"
+  rm "$dir/$TTYLINUX_NAME.iso"
+
+  run govc library.mirror -n -json "$dir" my-content
+  assert_success
+  [ "$(jq -r '.[].op' <<<"$output")" = "upload" ]
+
+  run govc library.mirror -delete "$dir" my-content
+  assert_success
+  assert_matches "upload README/README.txt"
+  assert_matches "delete $TTYLINUX_NAME"
+
+  run govc library.ls "my-content/$TTYLINUX_NAME"
+  assert_success ""
+
+  run govc library.mirror -n "$dir" my-content
+  assert_success ""
+}
+
 @test "library.subscriber example" {
   vcsim_start -ds 3
 
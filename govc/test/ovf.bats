@@ -0,0 +1,47 @@
+#!/usr/bin/env bats
+
+load test_helper
+
+@test "ovf.pack" {
+  vcsim_env
+
+  dir=$($mktemp --tmpdir -d govc-test-XXXXX 2>/dev/null || $mktemp -d -t govc-test-XXXXX)
+
+  run govc ovf.unpack "$GOVC_IMAGES/$TTYLINUX_NAME.ova" "$dir/src"
+  assert_success "$dir/src/$TTYLINUX_NAME.ovf"
+
+  run govc ovf.pack "$dir/src/$TTYLINUX_NAME.ovf" "$dir/vm.ova"
+  assert_success
+
+  run govc ovf.pack "$dir/src/$TTYLINUX_NAME.ovf" "$dir/vm.ova"
+  assert_failure # exists
+
+  run tar -tf "$dir/vm.ova"
+  assert_success
+  assert_equal "$TTYLINUX_NAME.ovf" "${lines[0]}"
+  assert_equal "$TTYLINUX_NAME.mf" "${lines[1]}"
+
+  run govc import.lint "$dir/vm.ova"
+  assert_success
+
+  run govc ovf.pack -f -chunk-size 1MB "$dir/src/$TTYLINUX_NAME.ovf" "$dir/vm.ova"
+  assert_success
+
+  run tar -tf "$dir/vm.ova"
+  assert_success
+  assert_matches "$TTYLINUX_NAME-disk1.vmdk.000000001"
+
+  run govc import.lint "$dir/vm.ova"
+  assert_success
+
+  run govc ovf.unpack "$dir/vm.ova" "$dir/chunked"
+  assert_success
+
+  run govc ovf.pack "$dir/chunked/$TTYLINUX_NAME.ovf" "$dir/joined.ova"
+  assert_success
+
+  run govc import.ova -name joined "$dir/joined.ova"
+  assert_success
+
+  rm -rf "$dir"
+}
@@ -64,6 +64,51 @@ load test_helper
   assert_success
 }
 
+@test "storage.policy.apply" {
+  vcsim_env
+
+  run govc storage.policy.apply
+  assert_failure # -f is required
+
+  run govc storage.policy.apply -f - <<<"vsan: {stripeWidth: 1}"
+  assert_failure # name is required
+
+  run govc storage.policy.apply -f - <<<"name: gold
+vsan_rules: {}"
+  assert_failure # unknown field
+
+  policy="name: gold
+description: gold tier
+vsan:
+  hostFailuresToTolerate: 1
+tags:
+  tier: [gold]
+encryption: true"
+
+  run govc storage.policy.apply -f - <<<"$policy"
+  assert_success
+  assert_matches "gold *created"
+
+  run govc storage.policy.apply -f - <<<"$policy"
+  assert_success
+  assert_matches "gold *unchanged"
+
+  run govc storage.policy.apply -json -f - <<<"${policy/: 1/: 2}"
+  assert_success
+  assert_equal updated "$(jq -r .[].action <<<"$output")"
+
+  run govc storage.policy.info -export gold
+  assert_success
+  assert_matches "hostFailuresToTolerate: 2"
+
+  # round trip of all policies is a no-op
+  govc storage.policy.info -export > "$BATS_TMPDIR/policies.yaml"
+  run govc storage.policy.apply -f "$BATS_TMPDIR/policies.yaml"
+  assert_success
+  run grep -v unchanged <<<"$output"
+  assert_failure
+}
+
 @test "vm.policy.ls" {
   vcsim_env
 
@@ -231,3 +231,70 @@ load test_helper
   govc tags.attached.ls -r /DC1
   govc tags.attached.ls -r /DC1/host/DC1_C0
 }
+
+@test "tags.find" {
+  vcsim_env
+
+  govc tags.category.create env
+  govc tags.category.create tier
+
+  for tag in prod test ; do
+    govc tags.create -c env $tag
+  done
+
+  for tag in web db ; do
+    govc tags.create -c tier $tag
+  done
+
+  govc tags.attach -c env prod /DC0/vm/DC0_H0_VM0
+  govc tags.attach -c env prod /DC0/vm/DC0_H0_VM1
+  govc tags.attach -c env prod /DC0/datastore/LocalDS_0
+  govc tags.attach -c env test /DC0/vm/DC0_C0_RP0_VM0
+  govc tags.attach -c tier web /DC0/vm/DC0_H0_VM0
+  govc tags.attach -c tier db /DC0/vm/DC0_H0_VM1
+
+  run govc tags.find env:prod
+  assert_success
+  assert_output_lines 3
+
+  run govc tags.find -type VirtualMachine 'env:prod && !tier:db'
+  assert_success /DC0/vm/DC0_H0_VM0
+
+  run govc tags.find -i 'tier:db or env:test'
+  assert_success
+  assert_output_lines 2
+  assert_matches VirtualMachine:vm-
+
+  run govc tags.find -json 'env:* and not tier:*'
+  assert_success
+  assert_equal 2 "$(jq length <<<"$output")"
+
+  run govc tags.find -json 'env:prod && env:test'
+  assert_success "[]"
+
+  run govc tags.find 'env:prod &&'
+  assert_failure
+
+  run govc tags.find env:enoent
+  assert_failure
+
+  # bulk retag
+  govc tags.category.create backup
+  govc tags.create -c backup daily
+
+  run govc tags.attach -query 'env:prod' -type VirtualMachine daily
+  assert_success
+
+  run govc tags.find daily
+  assert_success
+  assert_output_lines 2
+
+  run govc tags.attach -query 'env:prod' daily /DC0/vm/DC0_H0_VM0
+  assert_failure # PATH not allowed with -query
+
+  run govc tags.detach -query 'daily && tier:web' daily
+  assert_success
+
+  run govc tags.find daily
+  assert_success /DC0/vm/DC0_H0_VM1
+}
@@ -0,0 +1,93 @@
+#!/usr/bin/env bats
+
+load test_helper
+
+@test "vcsa.backup" {
+  vcsim_env
+
+  run govc vcsa.backup.parts
+  assert_success
+  assert_matches seat
+
+  dir=$(mktemp -d)
+
+  run govc vcsa.backup.create -part enoent "file://$dir"
+  assert_failure
+
+  run govc vcsa.backup.create -comment test "file://$dir"
+  assert_success
+  id="$output"
+
+  [ -e "$dir/$id/backup-metadata.json" ]
+  [ -e "$dir/$id/common.tar.gz" ]
+  [ -e "$dir/$id/seat.tar.gz" ]
+
+  run govc vcsa.backup.info "$id"
+  assert_success
+  assert_matches SUCCEEDED
+
+  run govc vcsa.backup.info -json "$id"
+  assert_success
+  assert_equal test "$(jq -r .comment <<<"$output")"
+
+  run govc vcsa.backup.cancel "$id"
+  assert_failure # not running
+
+  run govc vcsa.backup.create enoent:///backups
+  assert_failure # unsupported location
+
+  run govc vcsa.backup.ls
+  assert_success
+  assert_output_lines 2
+
+  run govc vcsa.backup.info enoent
+  assert_failure
+
+  rm -rf "$dir"
+}
+
+@test "vcsa.backup.schedule" {
+  vcsim_env
+
+  dir=$(mktemp -d)
+
+  run govc vcsa.backup.schedule.ls
+  assert_success ""
+
+  run govc vcsa.backup.schedule.create -hour 25 "file://$dir"
+  assert_failure
+
+  run govc vcsa.backup.schedule.create -hour 2 -days sunday,wednesday -keep 1 "file://$dir"
+  assert_success
+
+  run govc vcsa.backup.schedule.ls
+  assert_success
+  assert_matches "02:00 SUNDAY,WEDNESDAY"
+
+  run govc vcsa.backup.schedule.update -minute 30 -enable=false
+  assert_success
+
+  run govc vcsa.backup.schedule.ls -json
+  assert_success
+  assert_equal false "$(jq -r .default.enable <<<"$output")"
+  assert_equal 2 "$(jq -r .default.recurrence_info.hour <<<"$output")"
+  assert_equal 30 "$(jq -r .default.recurrence_info.minute <<<"$output")"
+
+  run govc vcsa.backup.schedule.run
+  assert_success
+
+  run govc vcsa.backup.schedule.run
+  assert_success
+  id="$output"
+
+  run ls "$dir"
+  assert_success "$id" # -keep 1
+
+  run govc vcsa.backup.schedule.rm
+  assert_success
+
+  run govc vcsa.backup.schedule.run
+  assert_failure
+
+  rm -rf "$dir"
+}
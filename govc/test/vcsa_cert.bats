@@ -0,0 +1,92 @@
+#!/usr/bin/env bats
+
+load test_helper
+
+@test "vcsa.cert" {
+  vcsim_env
+
+  run govc vcsa.cert.ls
+  assert_success
+  [ ${#lines[@]} -eq 1 ]
+  vmca=$(govc vcsa.cert.ls | awk '{print $1}')
+
+  run govc vcsa.cert.rm "$vmca"
+  assert_failure # active signing certificate
+
+  run govc vcsa.cert.rm enoent
+  assert_failure
+
+  pem=$(new_id)
+  run govc extension.setcert -cert-pem ++ -org govc-vcsa-cert "$pem" # generate a cert for testing
+  assert_success
+
+  run govc vcsa.cert.add "$pem.crt"
+  assert_success
+  id="$output"
+
+  run govc vcsa.cert.add -id "$id" "$pem.crt"
+  assert_failure # id exists
+
+  run govc vcsa.cert.ls
+  assert_success
+  assert_matches O=govc-vcsa-cert
+
+  run govc vcsa.cert.ls -json
+  assert_success
+
+  run govc vcsa.cert.rm "$id"
+  assert_success
+
+  run govc vcsa.cert.ls
+  assert_success
+  [ ${#lines[@]} -eq 1 ]
+
+  date > "$id.crt"
+  run govc vcsa.cert.add "$id.crt"
+  assert_failure # invalid cert
+  rm "$id.crt"
+
+  rm "$pem".{crt,key}
+}
+
+@test "vcsa.cert.tls" {
+  vcsim_env
+
+  run govc vcsa.cert.tls.info
+  assert_success
+
+  run govc vcsa.cert.tls.info -show
+  assert_success
+  assert_matches "BEGIN CERTIFICATE"
+
+  pem=$(new_id)
+  run govc extension.setcert -cert-pem ++ -org govc-vcsa-tls "$pem"
+  assert_success
+
+  run govc vcsa.cert.tls.replace "$pem.crt"
+  assert_failure # no key
+
+  run govc vcsa.cert.tls.replace -private-key "$pem.key" "$pem.crt"
+  assert_failure # untrusted
+
+  run govc vcsa.cert.tls.replace -private-key "$pem.key" -root "$pem.crt" "$pem.crt"
+  assert_success
+
+  run govc vcsa.cert.tls.info
+  assert_success
+  assert_matches O=govc-vcsa-tls
+
+  run govc vcsa.cert.tls.csr -cn vcsa.example.com -san vcsa.example.com
+  assert_success
+  assert_matches "BEGIN CERTIFICATE REQUEST"
+
+  run govc vcsa.cert.tls.renew -days 30
+  assert_success
+
+  run govc vcsa.cert.tls.info -json
+  assert_success
+  assert_matches O=govc-vcsa-tls "$(jq -r .subject_dn <<<"$output")"
+  [ "$(jq -r .issuer_dn <<<"$output")" != "$(jq -r .subject_dn <<<"$output")" ] # issued by VMCA
+
+  rm "$pem".{crt,key}
+}
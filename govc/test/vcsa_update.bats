@@ -0,0 +1,105 @@
+#!/usr/bin/env bats
+
+load test_helper
+
+@test "vcsa.health" {
+  vcsim_env
+
+  run govc vcsa.health
+  assert_success
+  assert_matches "system:"
+  assert_matches "Last Check:"
+
+  run govc vcsa.health -json
+  assert_success
+  assert_equal 8 "$(jq '.items | length' <<<"$output")"
+  assert_equal green "$(jq -r '.items[0].level' <<<"$output")"
+
+  run govc vcsa.health enoent
+  assert_failure
+
+  run govc option.set vcsim.appliance.health.storage red
+  assert_success
+
+  run govc vcsa.health -json storage system swap
+  assert_success
+  assert_equal "red red green" "$(jq -r '[.items[].level] | join(" ")' <<<"$output")"
+}
+
+@test "vcsa.monitoring" {
+  vcsim_env
+
+  run govc vcsa.monitoring.ls
+  assert_success
+  assert_matches cpu.util
+
+  run govc vcsa.monitoring.query -json -i MINUTES30 -since 3h cpu.util mem.total
+  assert_success
+  assert_equal 2 "$(jq length <<<"$output")"
+  assert_equal MINUTES30 "$(jq -r .[0].interval <<<"$output")"
+
+  run govc vcsa.monitoring.query -i enoent cpu.util
+  assert_failure
+
+  run govc vcsa.monitoring.query enoent
+  assert_failure
+}
+
+@test "vcsa.update" {
+  vcsim_env
+
+  run govc vcsa.update.info -json
+  assert_success
+  assert_equal UPDATES_PENDING "$(jq -r .state <<<"$output")"
+  current=$(jq -r .version <<<"$output")
+
+  run govc vcsa.update.ls -json
+  assert_success
+  version=$(jq -r .[0].version <<<"$output")
+
+  run govc vcsa.update.ls -source enoent
+  assert_failure
+
+  run govc vcsa.update.precheck "$version"
+  assert_success
+  assert_matches "Reboot Required:"
+
+  run govc vcsa.update.precheck enoent
+  assert_failure
+
+  run govc vcsa.update.stage "$version"
+  assert_success
+
+  run govc vcsa.update.info -json
+  assert_success
+  assert_equal "$version" "$(jq -r .staged.version <<<"$output")"
+
+  run govc option.set vcsim.appliance.update.fail true
+  assert_success
+
+  run govc vcsa.update.precheck -json "$version"
+  assert_success
+  assert_equal 1 "$(jq '.issues.errors | length' <<<"$output")"
+
+  run govc vcsa.update.install "$version"
+  assert_failure
+
+  run govc vcsa.update.info -json
+  assert_success
+  assert_equal INSTALL_FAILED "$(jq -r .state <<<"$output")"
+  assert_equal "$current" "$(jq -r .version <<<"$output")"
+
+  run govc option.set vcsim.appliance.update.fail false
+  assert_success
+
+  run govc vcsa.update.install -data enoent "$version"
+  assert_failure
+
+  run govc vcsa.update.install -data vmdir.password=secret "$version"
+  assert_success
+
+  run govc vcsa.update.info -json
+  assert_success
+  assert_equal "$version" "$(jq -r .version <<<"$output")"
+  assert_equal null "$(jq -r .staged <<<"$output")"
+}
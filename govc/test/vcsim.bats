@@ -591,6 +591,66 @@ EOF
   assert_success
 }
 
+@test "vcsim model load vapi" {
+  vcsim_start
+  dir="$BATS_TMPDIR/$(new_id)"
+
+  govc tags.category.create -m region
+  govc tags.create -c region us-west
+  govc tags.create -c region us-east
+  govc tags.attach -c region us-west /DC0/vm/DC0_H0_VM0
+  govc tags.attach -c region us-west /DC0/host/DC0_C0
+  govc tags.attach -c region us-east /DC0/vm/DC0_H0_VM1
+  govc library.create -d "library description" my-content
+  govc library.import -n my-file my-content vcsim.bats # any file will do
+
+  run govc object.save -vapi -v -d "$dir"
+  assert_success
+  assert_matches "Tag: 2"
+  assert_matches "LibraryItem: 1"
+  vcsim_stop
+
+  run jq length "$dir/vapi/tags.json"
+  assert_success 2
+
+  vcsim_env -load "$dir"
+  rm -rf "$dir"
+
+  run govc tags.category.info -json region
+  assert_success
+  assert_equal MULTIPLE "$(jq -r .[].cardinality <<<"$output")"
+
+  run govc tags.ls -c region
+  assert_success
+  assert_output_lines 2
+
+  run govc tags.attached.ls us-west
+  assert_success
+  assert_output_lines 2
+
+  run govc tags.find 'region:us-east'
+  assert_success /DC0/vm/DC0_H0_VM1
+
+  run govc library.info -json my-content
+  assert_success
+  assert_equal "library description" "$(jq -r .[].description <<<"$output")"
+
+  run govc library.ls my-content/
+  assert_success /my-content/my-file
+
+  # loaded state is writable
+  run govc tags.create -c region us-central
+  assert_success
+
+  run govc library.import -n my-file2 my-content vcsim.bats
+  assert_success
+
+  run govc object.save -d "$dir"
+  assert_success
+  [ ! -e "$dir/vapi" ]
+  rm -rf "$dir"
+}
+
 @test "vcsim trace file" {
   file="$BATS_TMPDIR/$(new_id).trace"
 
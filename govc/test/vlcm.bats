@@ -188,4 +188,135 @@ location="http://127.0.0.1:9999/files/custom_driver.zip"
 
   res=$(govc cluster.vlcm.info -cluster-id=domain-c21)
   assert_equal "true" $(echo $res | jq -r '.enabled')
-}
\ No newline at end of file
+}
+
+@test "cluster.vlcm.scan" {
+  vcsim_env
+
+  id=$(govc find -i -type c / | cut -d: -f2)
+
+  run govc cluster.vlcm.scan -cluster-id=domain-c0
+  assert_failure
+
+  run govc cluster.vlcm.scan -cluster-id=$id -last
+  assert_failure # no scan yet
+
+  run govc cluster.vlcm.scan -cluster-id=$id
+  assert_success
+  assert_matches "Status: *COMPLIANT"
+
+  run govc cluster.draft.create -cluster-id=$id
+  assert_success
+
+  run govc cluster.draft.component.add -cluster-id=$id -draft-id=1 -component-id=comp-id -component-version=1.2.3.4
+  assert_success
+
+  run govc cluster.draft.commit -cluster-id=$id -draft-id=1
+  assert_success
+
+  run govc cluster.vlcm.scan -cluster-id=$id -json
+  assert_success
+  assert_equal NON_COMPLIANT "$(jq -r .status <<<"$output")"
+  assert_equal 3 "$(jq '.non_compliant_hosts | length' <<<"$output")"
+
+  run govc cluster.vlcm.scan -cluster-id=$id
+  assert_success
+  assert_matches "comp-id: *- -> 1.2.3.4"
+}
+
+@test "cluster.vlcm.apply" {
+  vcsim_env
+
+  id=$(govc find -i -type c / | cut -d: -f2)
+
+  run govc cluster.draft.create -cluster-id=$id
+  assert_success
+
+  run govc cluster.draft.component.add -cluster-id=$id -draft-id=1 -component-id=comp-id -component-version=1.2.3.4
+  assert_success
+
+  run govc cluster.draft.commit -cluster-id=$id -draft-id=1
+  assert_success
+
+  run govc cluster.vlcm.apply -cluster-id=$id
+  assert_failure # vLCM not enabled
+
+  run govc cluster.vlcm.enable -cluster-id=$id
+  assert_success
+
+  host=$(govc find -i -type h "$(govc find -type c /)" | head -1 | cut -d: -f2)
+
+  run govc cluster.vlcm.apply -cluster-id=$id -host-id=$host -json
+  assert_success
+  assert_equal "$host" "$(jq -r .successful_hosts[] <<<"$output")"
+
+  run govc cluster.vlcm.scan -cluster-id=$id -last -json
+  assert_success
+  assert_equal "$host" "$(jq -r .compliant_hosts[] <<<"$output")"
+
+  run govc cluster.vlcm.apply -cluster-id=$id -commit=enoent
+  assert_failure
+
+  run govc cluster.vlcm.apply -cluster-id=$id -accept-eula
+  assert_success
+  assert_matches "Status: *OK"
+
+  run govc cluster.vlcm.scan -cluster-id=$id -json
+  assert_success
+  assert_equal COMPLIANT "$(jq -r .status <<<"$output")"
+}
+
+@test "cluster.vlcm.hsm" {
+  vcsim_env
+
+  id=$(govc find -i -type c / | cut -d: -f2)
+
+  manager=$(govc cluster.vlcm.hsm.ls -json | jq -r .[0].manager)
+
+  run govc cluster.vlcm.hsm.ls -manager=enoent
+  assert_failure
+
+  run govc cluster.vlcm.hsm.ls -manager="$manager" -json
+  assert_success
+  pkg=$(jq -r .[0].pkg <<<"$output")
+  version=$(jq -r .[0].version <<<"$output")
+
+  run govc cluster.draft.create -cluster-id=$id
+  assert_success
+
+  run govc cluster.draft.hardware.set -cluster-id=$id -draft-id=1 -manager="$manager" -package="$pkg" -version=enoent
+  assert_failure
+
+  run govc cluster.draft.hardware.set -cluster-id=$id -draft-id=1 -manager="$manager" -package="$pkg" -version="$version"
+  assert_success
+
+  run govc cluster.draft.hardware.info -cluster-id=$id -draft-id=1
+  assert_success
+  assert_equal "$version" "$(jq -r ".packages[\"$manager\"].version" <<<"$output")"
+
+  run govc cluster.draft.commit -cluster-id=$id -draft-id=1
+  assert_success
+
+  run govc cluster.vlcm.scan -cluster-id=$id
+  assert_success
+  assert_matches "$manager: *- -> $pkg $version"
+
+  run govc cluster.vlcm.enable -cluster-id=$id
+  assert_success
+
+  run govc cluster.vlcm.apply -cluster-id=$id
+  assert_success
+
+  run govc cluster.vlcm.scan -cluster-id=$id -last -json
+  assert_success
+  assert_equal COMPLIANT "$(jq -r .status <<<"$output")"
+
+  run govc cluster.draft.create -cluster-id=$id
+  assert_success
+
+  run govc cluster.draft.hardware.rm -cluster-id=$id -draft-id=2 -manager="$manager"
+  assert_success
+
+  run govc cluster.draft.hardware.rm -cluster-id=$id -draft-id=2 -manager="$manager"
+  assert_failure
+}
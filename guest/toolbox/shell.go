@@ -0,0 +1,302 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// Shell emulates an interactive shell session in the guest, without network connectivity to the VM,
+// using the GuestProcessManager and GuestFileManager.
+// Each command line runs as a separate guest process, where the "cd" command changes the working directory
+// of the commands that follow. Other shell state, such as variables, does not carry over between commands.
+type Shell struct {
+	*Client
+
+	// Stdout and Stderr receive the output of each command, output is discarded if nil
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// Dir is the working directory of each command, the guest tools default if empty
+	Dir string
+	// Env is the environment of each command, in "key=value" form
+	Env []string
+	// Prompt, if set, is written to Stderr by Run before reading each command line
+	Prompt string
+	// Poll is the interval for checking process state and streaming output, defaults to 500ms.
+	// Guest file transfers do not support range requests, so the output files are downloaded in full
+	// on each poll while a command runs. The interval grows by Poll for each MiB of output, up to 10 times Poll.
+	Poll time.Duration
+	// ProcessIO uses the toolbox Process I/O redirection rather than temporary files in the guest.
+	// This is only supported when the guest tools is the govmomi toolbox, where output is written once the command exits.
+	ProcessIO bool
+
+	tmp string
+}
+
+const (
+	pollBackoffSize = 1 << 20 // output size per Poll interval increase
+	pollBackoffMax  = 10      // maximum multiple of Poll
+)
+
+// NewShell creates a Shell using the given Client
+func NewShell(c *Client) *Shell {
+	return &Shell{Client: c}
+}
+
+func (s *Shell) windows() bool {
+	return s.GuestFamily == types.VirtualMachineGuestOsFamilyWindowsGuest
+}
+
+func (s *Shell) join(name string) string {
+	if s.windows() {
+		return s.tmp + `\` + name
+	}
+	return s.tmp + "/" + name
+}
+
+// shellQuote quotes s as a single posix shell word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// spec returns the GuestProgramSpec to run the given command line, with i/o redirected to the given stdin, stdout and stderr
+func (s *Shell) spec(line, stdin, stdout, stderr string) *types.GuestProgramSpec {
+	spec := &types.GuestProgramSpec{
+		EnvVariables:     s.Env,
+		WorkingDirectory: s.Dir,
+	}
+
+	switch {
+	case s.ProcessIO:
+		// A ProgramPath that is not absolute enables toolbox Process I/O
+		spec.ProgramPath = "sh"
+		spec.Arguments = "-c " + shellQuote(line)
+	case s.windows():
+		spec.ProgramPath = `c:\Windows\System32\cmd.exe`
+		spec.Arguments = fmt.Sprintf(`/c (%s) <"%s" >"%s" 2>"%s"`, line, stdin, stdout, stderr)
+	default:
+		spec.ProgramPath = "/bin/sh"
+		spec.Arguments = "-c " + shellQuote(fmt.Sprintf("{ %s\n} <%s >%s 2>%s", line, shellQuote(stdin), shellQuote(stdout), shellQuote(stderr)))
+	}
+
+	return spec
+}
+
+// tempDir returns the guest directory used for command i/o redirection, creating it on first use
+func (s *Shell) tempDir(ctx context.Context) (string, error) {
+	if s.tmp != "" {
+		return s.tmp, nil
+	}
+
+	dir, err := s.FileManager.CreateTemporaryDirectory(ctx, s.Authentication, "govmomi-shell-", "", "")
+	if err != nil {
+		return "", err
+	}
+
+	s.tmp = dir
+
+	return dir, nil
+}
+
+// Close removes the guest directory used for command i/o redirection, if any
+func (s *Shell) Close(ctx context.Context) error {
+	if s.tmp == "" {
+		return nil
+	}
+
+	err := s.FileManager.DeleteDirectory(ctx, s.Authentication, s.tmp, true)
+	s.tmp = ""
+
+	return err
+}
+
+// tail writes the contents of the guest file name after offset to w, returning the new offset
+func (s *Shell) tail(ctx context.Context, name string, offset int64, w io.Writer) (int64, error) {
+	if w == nil {
+		return offset, nil
+	}
+
+	f, n, err := s.Download(ctx, name)
+	if err != nil {
+		return offset, err
+	}
+	defer f.Close()
+
+	if n <= offset {
+		return offset, nil
+	}
+
+	if _, err = io.CopyN(io.Discard, f, offset); err != nil {
+		return offset, err
+	}
+
+	m, err := io.CopyN(w, f, n-offset)
+
+	return offset + m, err
+}
+
+func (s *Shell) upload(ctx context.Context, r io.Reader, name string) error {
+	if r == nil {
+		r = new(bytes.Buffer)
+	}
+
+	var attr types.BaseGuestFileAttributes = new(types.GuestPosixFileAttributes)
+	if s.windows() {
+		attr = new(types.GuestWindowsFileAttributes)
+	}
+
+	return s.Upload(ctx, r, name, soap.DefaultUpload, attr, true)
+}
+
+// Exec runs the given command line in the guest, returning its exit code.
+// If stdin is not nil, it is uploaded and redirected as the input of the command.
+// Output is streamed to Stdout and Stderr while the command runs, unless ProcessIO is enabled.
+// If ctx is done before the command exits, the guest process is terminated.
+func (s *Shell) Exec(ctx context.Context, line string, stdin io.Reader) (int, error) {
+	var in, out, errs string
+
+	// With ProcessIO, the i/o paths are known once the process is started
+	if !s.ProcessIO {
+		if _, err := s.tempDir(ctx); err != nil {
+			return -1, err
+		}
+
+		in, out, errs = s.join("stdin"), s.join("stdout"), s.join("stderr")
+
+		if stdin == nil {
+			in = "/dev/null"
+			if s.windows() {
+				in = "NUL"
+			}
+		} else if err := s.upload(ctx, stdin, in); err != nil {
+			return -1, err
+		}
+	}
+
+	pid, err := s.ProcessManager.StartProgram(ctx, s.Authentication, s.spec(line, in, out, errs))
+	if err != nil {
+		return -1, err
+	}
+
+	if s.ProcessIO {
+		proc := fmt.Sprintf("/proc:/%d/", pid)
+		in, out, errs = proc+"stdin", proc+"stdout", proc+"stderr"
+
+		// Closes the process stdin once uploaded, even if empty.
+		// Without stdin, an error is ignored as the process may have exited already.
+		if err = s.upload(ctx, stdin, in); err != nil && stdin != nil {
+			return -1, err
+		}
+	}
+
+	poll := s.Poll
+	if poll == 0 {
+		poll = time.Second / 2
+	}
+
+	var nout, nerr int64
+
+	for {
+		procs, err := s.ProcessManager.ListProcesses(ctx, s.Authentication, []int64{pid})
+		if err != nil {
+			return -1, err
+		}
+
+		done := len(procs) == 1 && procs[0].EndTime != nil
+
+		if done || !s.ProcessIO {
+			if nout, err = s.tail(ctx, out, nout, s.Stdout); err != nil {
+				return -1, err
+			}
+			if nerr, err = s.tail(ctx, errs, nerr, s.Stderr); err != nil {
+				return -1, err
+			}
+		}
+
+		if done {
+			return int(procs[0].ExitCode), nil
+		}
+
+		backoff := min(1+(nout+nerr)/pollBackoffSize, pollBackoffMax)
+
+		select {
+		case <-time.After(poll * time.Duration(backoff)):
+		case <-ctx.Done():
+			_ = s.ProcessManager.TerminateProcess(context.Background(), s.Authentication, pid)
+			return -1, ctx.Err()
+		}
+	}
+}
+
+// cd runs the given cd command line in the guest, changing Dir if the command succeeds
+func (s *Shell) cd(ctx context.Context, line string) (int, error) {
+	pwd := " && pwd"
+	if s.windows() {
+		line = strings.Replace(line, "cd", "cd /d", 1)
+		pwd = " && cd"
+	}
+
+	var buf bytes.Buffer
+	stdout := s.Stdout
+	s.Stdout = &buf
+	rc, err := s.Exec(ctx, line+pwd, nil)
+	s.Stdout = stdout
+
+	if err == nil && rc == 0 {
+		s.Dir = strings.TrimSpace(buf.String())
+	}
+
+	return rc, err
+}
+
+// Run reads command lines from r, running each with Exec until r returns EOF or the "exit [N]" command is read.
+// The exit code of the last command, or N, is returned.
+func (s *Shell) Run(ctx context.Context, r io.Reader) (int, error) {
+	scanner := bufio.NewScanner(r)
+	rc := 0
+
+	for {
+		if s.Prompt != "" && s.Stderr != nil {
+			_, _ = io.WriteString(s.Stderr, s.Prompt)
+		}
+
+		if !scanner.Scan() {
+			return rc, scanner.Err()
+		}
+
+		var err error
+		line := strings.TrimSpace(scanner.Text())
+		cmd, arg, _ := strings.Cut(line, " ")
+
+		switch cmd {
+		case "":
+			continue
+		case "exit":
+			if arg = strings.TrimSpace(arg); arg != "" {
+				rc, err = strconv.Atoi(arg)
+			}
+			return rc, err
+		case "cd":
+			rc, err = s.cd(ctx, line)
+		default:
+			rc, err = s.Exec(ctx, line, nil)
+		}
+
+		if err != nil {
+			return rc, err
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/guest/toolbox"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a posix guest")
+	}
+
+	// The simulator runs guest operations against the local file system
+	model := simulator.VPX()
+	model.ToolboxRoot = "/"
+
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		task, err := vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{
+			ExtraConfig: []types.BaseOptionValue{
+				&types.OptionValue{Key: simulator.ToolboxBackingOptionKey, Value: "true"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		tools, err := toolbox.NewClient(ctx, c, vm, &types.NamePasswordAuthentication{
+			Username: "user",
+			Password: "pass",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, processIO := range []bool{false, true} {
+			var stdout, stderr bytes.Buffer
+
+			sh := toolbox.NewShell(tools)
+			sh.Stdout = &stdout
+			sh.Stderr = &stderr
+			sh.Poll = 10 * time.Millisecond
+			sh.ProcessIO = processIO
+
+			rc, err := sh.Exec(ctx, "echo hello; echo oops >&2; exit 3", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rc != 3 {
+				t.Errorf("rc=%d", rc)
+			}
+			if stdout.String() != "hello\n" || stderr.String() != "oops\n" {
+				t.Errorf("stdout=%q, stderr=%q", stdout.String(), stderr.String())
+			}
+
+			stdout.Reset()
+			rc, err = sh.Exec(ctx, "tr a-z A-Z", strings.NewReader("stdin"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rc != 0 || stdout.String() != "STDIN" {
+				t.Errorf("rc=%d, stdout=%q", rc, stdout.String())
+			}
+
+			dir := t.TempDir()
+			stdout.Reset()
+			script := strings.Join([]string{"cd " + dir, "touch file", "ls", "cd enoent", "exit 2"}, "\n")
+			rc, err = sh.Run(ctx, strings.NewReader(script))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rc != 2 {
+				t.Errorf("rc=%d", rc)
+			}
+			if sh.Dir != dir {
+				t.Errorf("dir=%s", sh.Dir)
+			}
+			if stdout.String() != "file\n" {
+				t.Errorf("stdout=%q", stdout.String())
+			}
+
+			if _, err = os.Stat(dir + "/file"); err != nil {
+				t.Error(err)
+			}
+
+			if err = sh.Close(ctx); err != nil {
+				t.Error(err)
+			}
+		}
+	}, model)
+}
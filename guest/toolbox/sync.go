@@ -0,0 +1,447 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/fault"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// SyncAction is a change made to the destination tree by Client.SyncToGuest or Client.SyncFromGuest
+type SyncAction string
+
+const (
+	SyncMkdir  = SyncAction("mkdir")
+	SyncCopy   = SyncAction("copy")
+	SyncDelete = SyncAction("delete")
+)
+
+// SyncOptions for Client.SyncToGuest and Client.SyncFromGuest
+type SyncOptions struct {
+	// Delete files and directories in the destination that do not exist in the source
+	Delete bool
+	// Parallel is the maximum number of concurrent file transfers, defaults to 1
+	Parallel int
+	// DryRun reports the changes via Log, without making them
+	DryRun bool
+	// Log is called with each change made to the destination, where name is relative to the destination directory
+	Log func(action SyncAction, name string)
+}
+
+// syncEntry is a file or directory in a sync tree, keyed by its slash separated path relative to the tree root
+type syncEntry struct {
+	dir   bool
+	size  int64
+	mtime time.Time
+}
+
+type syncTree map[string]syncEntry
+
+// changed reports whether dst needs to be updated with src.
+// As with rsync's --update flag, a destination file modified after the source is not replaced,
+// since guest tools may not apply the source modification time on upload.
+func (src syncEntry) changed(dst syncEntry) bool {
+	if src.size != dst.size {
+		return true
+	}
+	return src.mtime.Truncate(time.Second).After(dst.mtime.Truncate(time.Second))
+}
+
+// syncer is implemented by both ends of a sync, the local file system and the guest file system
+type syncer interface {
+	list(ctx context.Context, root string) (syncTree, error)
+	join(root, name string) string
+	mkdir(ctx context.Context, name string) error
+	remove(ctx context.Context, name string, dir bool) error
+}
+
+// localSyncer implements syncer for the local file system
+type localSyncer struct{}
+
+func (localSyncer) list(_ context.Context, root string) (syncTree, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s: not a directory", root)
+	}
+
+	tree := make(syncTree)
+
+	err = filepath.WalkDir(root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, name)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil // symlinks, devices, etc are not synced
+		}
+
+		tree[filepath.ToSlash(rel)] = syncEntry{dir: info.IsDir(), size: info.Size(), mtime: info.ModTime()}
+
+		return nil
+	})
+
+	return tree, err
+}
+
+func (localSyncer) join(root, name string) string {
+	return filepath.Join(root, filepath.FromSlash(name))
+}
+
+func (localSyncer) mkdir(_ context.Context, name string) error {
+	return os.MkdirAll(name, 0750)
+}
+
+func (localSyncer) remove(_ context.Context, name string, dir bool) error {
+	if dir {
+		return os.RemoveAll(name)
+	}
+	return os.Remove(name)
+}
+
+// guestSyncer implements syncer for the guest file system, using the GuestFileManager
+type guestSyncer struct {
+	*Client
+}
+
+func (c guestSyncer) windows() bool {
+	return c.GuestFamily == types.VirtualMachineGuestOsFamilyWindowsGuest
+}
+
+func (c guestSyncer) join(root, name string) string {
+	if c.windows() {
+		return strings.TrimRight(root, `\`) + `\` + strings.ReplaceAll(name, "/", `\`)
+	}
+	return path.Join(root, name)
+}
+
+func (c guestSyncer) list(ctx context.Context, root string) (syncTree, error) {
+	tree := make(syncTree)
+
+	dirs := []string{""}
+
+	for len(dirs) != 0 {
+		dir := dirs[0]
+		dirs = dirs[1:]
+
+		var name string
+		if dir == "" {
+			// Listing "root/." fails if root is not a directory, rather than listing the file itself
+			sep := "/"
+			if c.windows() {
+				sep = `\`
+			}
+			name = strings.TrimRight(root, sep) + sep + "."
+		} else {
+			name = c.join(root, dir)
+		}
+
+		var offset int32
+
+		for {
+			info, err := c.FileManager.ListFiles(ctx, c.Authentication, name, offset, 0, "")
+			if err != nil {
+				if dir == "" && fault.Is(err, &types.FileNotFound{}) {
+					return nil, nil
+				}
+				return nil, err
+			}
+
+			for _, f := range info.Files {
+				if f.Path == "." || f.Path == ".." {
+					continue
+				}
+
+				rel := path.Join(dir, f.Path)
+				entry := syncEntry{size: f.Size}
+				if attr := f.Attributes.GetGuestFileAttributes(); attr.ModificationTime != nil {
+					entry.mtime = *attr.ModificationTime
+				}
+
+				switch types.GuestFileType(f.Type) {
+				case types.GuestFileTypeDirectory:
+					entry.dir = true
+					dirs = append(dirs, rel)
+				case types.GuestFileTypeFile:
+				default:
+					continue // symlinks are not synced
+				}
+
+				tree[rel] = entry
+			}
+
+			if info.Remaining == 0 {
+				break
+			}
+			offset += int32(len(info.Files))
+		}
+	}
+
+	return tree, nil
+}
+
+func (c guestSyncer) mkdir(ctx context.Context, name string) error {
+	return c.FileManager.MakeDirectory(ctx, c.Authentication, name, true)
+}
+
+func (c guestSyncer) remove(ctx context.Context, name string, dir bool) error {
+	if dir {
+		return c.FileManager.DeleteDirectory(ctx, c.Authentication, name, true)
+	}
+	return c.FileManager.DeleteFile(ctx, c.Authentication, name)
+}
+
+// syncCopyFunc transfers the src file to dst, where entry describes the src file
+type syncCopyFunc func(ctx context.Context, src, dst string, entry syncEntry) error
+
+// upload the local src file to the guest dst file
+func (c *Client) upload(ctx context.Context, src, dst string, entry syncEntry) error {
+	f, err := os.Open(filepath.Clean(src))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	mtime := entry.mtime
+	var attr types.BaseGuestFileAttributes
+
+	if c.GuestFamily == types.VirtualMachineGuestOsFamilyWindowsGuest {
+		attr = &types.GuestWindowsFileAttributes{
+			GuestFileAttributes: types.GuestFileAttributes{ModificationTime: &mtime},
+		}
+	} else {
+		attr = &types.GuestPosixFileAttributes{
+			GuestFileAttributes: types.GuestFileAttributes{ModificationTime: &mtime},
+			Permissions:         int64(info.Mode().Perm()),
+		}
+	}
+
+	p := soap.DefaultUpload
+	p.ContentLength = info.Size()
+
+	return c.Upload(ctx, f, dst, p, attr, true)
+}
+
+// download the guest src file to the local dst file, setting the local modification time to that of the guest file
+func (c *Client) download(ctx context.Context, src, dst string, entry syncEntry) error {
+	r, _, err := c.Download(ctx, src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	f, err := os.Create(filepath.Clean(dst))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil || entry.mtime.IsZero() {
+		return err
+	}
+
+	return os.Chtimes(dst, entry.mtime, entry.mtime)
+}
+
+// SyncToGuest recursively copies the local src directory to the guest dst directory.
+// Only files that do not exist in the guest, differ in size, or have a newer local modification time are transferred.
+// Guest directories are created as needed. See SyncOptions for removing extraneous guest files and parallel transfers.
+// Only the GuestFileManager is used, such that any guest tools implementation is supported.
+func (c *Client) SyncToGuest(ctx context.Context, src, dst string, opts SyncOptions) error {
+	return syncTrees(ctx, localSyncer{}, guestSyncer{c}, src, dst, c.upload, opts)
+}
+
+// SyncFromGuest recursively copies the guest src directory to the local dst directory.
+// Only files that do not exist locally, differ in size, or have a newer guest modification time are transferred.
+// Local directories are created as needed. See SyncOptions for removing extraneous local files and parallel transfers.
+// Only the GuestFileManager is used, such that any guest tools implementation is supported.
+func (c *Client) SyncFromGuest(ctx context.Context, src, dst string, opts SyncOptions) error {
+	return syncTrees(ctx, guestSyncer{c}, localSyncer{}, src, dst, c.download, opts)
+}
+
+func syncTrees(ctx context.Context, s, d syncer, src, dst string, copy syncCopyFunc, opts SyncOptions) error {
+	log := opts.Log
+	if log == nil {
+		log = func(SyncAction, string) {}
+	}
+
+	stree, err := s.list(ctx, src)
+	if err != nil {
+		return err
+	}
+	if stree == nil {
+		return fmt.Errorf("%s: %w", src, os.ErrNotExist)
+	}
+
+	dtree, err := d.list(ctx, dst)
+	if err != nil {
+		return err
+	}
+
+	if dtree == nil {
+		log(SyncMkdir, ".")
+		if !opts.DryRun {
+			if err = d.mkdir(ctx, dst); err != nil {
+				return err
+			}
+		}
+	}
+
+	names := make([]string, 0, len(stree))
+	for name := range stree {
+		names = append(names, name)
+	}
+	sort.Strings(names) // parent directories are created before their children
+
+	if opts.Delete {
+		var extra []string
+		for name, entry := range dtree {
+			if sentry, ok := stree[name]; !ok || sentry.dir != entry.dir {
+				extra = append(extra, name)
+			}
+		}
+		sort.Strings(extra)
+
+		var removed string
+		for _, name := range extra {
+			if removed != "" && strings.HasPrefix(name, removed+"/") {
+				continue // removed along with its parent directory
+			}
+
+			dir := dtree[name].dir
+
+			log(SyncDelete, name)
+			if !opts.DryRun {
+				if err = d.remove(ctx, d.join(dst, name), dir); err != nil {
+					return err
+				}
+			}
+			delete(dtree, name)
+
+			if dir {
+				removed = name
+			}
+		}
+	}
+
+	var files []string
+
+	for _, name := range names {
+		sentry := stree[name]
+		dentry, ok := dtree[name]
+
+		if ok && dentry.dir != sentry.dir {
+			return fmt.Errorf("%s: destination type differs from source, see the Delete option", d.join(dst, name))
+		}
+
+		if sentry.dir {
+			if !ok {
+				log(SyncMkdir, name)
+				if !opts.DryRun {
+					if err = d.mkdir(ctx, d.join(dst, name)); err != nil {
+						return err
+					}
+				}
+			}
+			continue
+		}
+
+		if !ok || sentry.changed(dentry) {
+			files = append(files, name)
+		}
+	}
+
+	parallel := opts.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		once sync.Once
+		ferr error
+	)
+
+	queue := make(chan string)
+
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for name := range queue {
+				err := copy(ctx, s.join(src, name), d.join(dst, name), stree[name])
+				if err != nil {
+					once.Do(func() {
+						ferr = fmt.Errorf("%s: %w", name, err)
+						cancel() // the first error stops the remaining transfers
+					})
+				}
+			}
+		}()
+	}
+
+send:
+	for _, name := range files {
+		log(SyncCopy, name)
+		if opts.DryRun {
+			continue
+		}
+
+		select {
+		case queue <- name:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if ferr != nil {
+		return ferr
+	}
+
+	return ctx.Err()
+}
@@ -0,0 +1,189 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/guest/toolbox"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+type syncLog map[toolbox.SyncAction][]string
+
+func (l syncLog) opts(opts toolbox.SyncOptions) toolbox.SyncOptions {
+	opts.Log = func(action toolbox.SyncAction, name string) {
+		l[action] = append(l[action], name)
+	}
+	return opts
+}
+
+func (l syncLog) check(t *testing.T, action toolbox.SyncAction, expect ...string) {
+	t.Helper()
+
+	names := l[action]
+	sort.Strings(names)
+
+	if len(names) != len(expect) {
+		t.Fatalf("%s: %v, expected %v", action, names, expect)
+	}
+	for i := range names {
+		if names[i] != expect[i] {
+			t.Errorf("%s: %v, expected %v", action, names, expect)
+		}
+	}
+}
+
+func TestSync(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a posix guest")
+	}
+
+	// The simulator runs guest operations against the local file system
+	model := simulator.VPX()
+	model.ToolboxRoot = "/"
+
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		task, err := vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{
+			ExtraConfig: []types.BaseOptionValue{
+				&types.OptionValue{Key: simulator.ToolboxBackingOptionKey, Value: "true"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		tools, err := toolbox.NewClient(ctx, c, vm, &types.NamePasswordAuthentication{
+			Username: "user",
+			Password: "pass",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		src := t.TempDir()
+		guest := filepath.Join(t.TempDir(), "guest")
+		dst := filepath.Join(t.TempDir(), "local")
+
+		writeFiles(t, src, map[string]string{
+			"a.txt":     "a",
+			"sub/b.txt": "bb",
+			"sub/c/d":   "ddd",
+		})
+
+		log := syncLog{}
+		err = tools.SyncToGuest(ctx, src, guest, log.opts(toolbox.SyncOptions{Parallel: 2}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		log.check(t, toolbox.SyncMkdir, ".", "sub", "sub/c")
+		log.check(t, toolbox.SyncCopy, "a.txt", "sub/b.txt", "sub/c/d")
+
+		// Unchanged
+		log = syncLog{}
+		if err = tools.SyncToGuest(ctx, src, guest, log.opts(toolbox.SyncOptions{})); err != nil {
+			t.Fatal(err)
+		}
+		log.check(t, toolbox.SyncMkdir)
+		log.check(t, toolbox.SyncCopy)
+
+		log = syncLog{}
+		if err = tools.SyncFromGuest(ctx, guest, dst, log.opts(toolbox.SyncOptions{Parallel: 4})); err != nil {
+			t.Fatal(err)
+		}
+		log.check(t, toolbox.SyncCopy, "a.txt", "sub/b.txt", "sub/c/d")
+
+		b, err := os.ReadFile(filepath.Join(dst, "sub", "c", "d"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != "ddd" {
+			t.Errorf("d=%q", b)
+		}
+
+		// Changed file, new file and removed directory
+		future := time.Now().Add(time.Hour)
+		writeFiles(t, src, map[string]string{"a.txt": "A", "e": "eeeee"})
+		if err = os.Chtimes(filepath.Join(src, "a.txt"), future, future); err != nil {
+			t.Fatal(err)
+		}
+		if err = os.RemoveAll(filepath.Join(src, "sub", "c")); err != nil {
+			t.Fatal(err)
+		}
+
+		log = syncLog{}
+		opts := log.opts(toolbox.SyncOptions{Delete: true, DryRun: true})
+		if err = tools.SyncToGuest(ctx, src, guest, opts); err != nil {
+			t.Fatal(err)
+		}
+		log.check(t, toolbox.SyncCopy, "a.txt", "e")
+		log.check(t, toolbox.SyncDelete, "sub/c")
+
+		if _, err = os.Stat(filepath.Join(guest, "e")); !os.IsNotExist(err) {
+			t.Errorf("DryRun: err=%v", err)
+		}
+
+		log = syncLog{}
+		opts = log.opts(toolbox.SyncOptions{Delete: true})
+		if err = tools.SyncToGuest(ctx, src, guest, opts); err != nil {
+			t.Fatal(err)
+		}
+		log.check(t, toolbox.SyncCopy, "a.txt", "e")
+		log.check(t, toolbox.SyncDelete, "sub/c")
+
+		if _, err = os.Stat(filepath.Join(guest, "sub", "c")); !os.IsNotExist(err) {
+			t.Errorf("Delete: err=%v", err)
+		}
+
+		b, err = os.ReadFile(filepath.Join(guest, "a.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != "A" {
+			t.Errorf("a.txt=%q", b)
+		}
+
+		// Not a directory
+		err = tools.SyncFromGuest(ctx, filepath.Join(guest, "e"), dst, toolbox.SyncOptions{})
+		if err == nil {
+			t.Error("expected error")
+		}
+
+		err = tools.SyncFromGuest(ctx, filepath.Join(guest, "enoent"), dst, toolbox.SyncOptions{})
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("err=%v", err)
+		}
+	}, model)
+}
@@ -215,8 +215,10 @@ func (e Envelope) toHardware(
 	hw = vs.VirtualHardware[0]
 
 	// Set the hardware version.
-	if vmx := hw.System.VirtualSystemType; vmx != nil {
-		dst.Version = *vmx
+	if hw.System != nil {
+		if vmx := hw.System.VirtualSystemType; vmx != nil {
+			dst.Version = *vmx
+		}
 	}
 
 	// Parse the config
@@ -481,6 +483,9 @@ func (e Envelope) setPCISlotNumber(
 }
 
 func (e Envelope) ovfDisk(diskID string) *VirtualDiskDesc {
+	if e.Disk == nil {
+		return nil
+	}
 	for _, disk := range e.Disk.Disks {
 		if strings.HasSuffix(diskID, disk.DiskID) {
 			return &disk
@@ -0,0 +1,29 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package ovf
+
+import (
+	"crypto"
+	_ "crypto/md5" // register crypto.Hash implementations
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"strings"
+)
+
+var digestAlgorithms = map[string]crypto.Hash{
+	"MD5":    crypto.MD5,
+	"SHA1":   crypto.SHA1,
+	"SHA256": crypto.SHA256,
+	"SHA512": crypto.SHA512,
+}
+
+// DigestAlgorithm returns the hash function for the given digest algorithm name,
+// as used by manifest entries such as "SHA256(disk1.vmdk)= ..." and content library
+// checksums. The name is case insensitive.
+func DigestAlgorithm(name string) (crypto.Hash, bool) {
+	h, ok := digestAlgorithms[strings.ToUpper(name)]
+	return h, ok
+}
@@ -47,6 +47,10 @@ type Importer struct {
 }
 
 func (imp *Importer) manifestPath(fpath string) string {
+	return manifestPath(fpath)
+}
+
+func manifestPath(fpath string) string {
 	base := filepath.Base(fpath)
 	ext := filepath.Ext(base)
 	return filepath.Join(filepath.Dir(fpath), strings.Replace(base, ext, ".mf", 1))
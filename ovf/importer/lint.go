@@ -0,0 +1,191 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package importer
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vmware/govmomi/ovf"
+	"github.com/vmware/govmomi/vapi/library"
+)
+
+// Lint validates the OVF descriptor fpath within the given Archive, along with
+// the files it references and the manifest (.mf) if present.
+// See ovf.Validate for the checks applied to the descriptor itself.
+func Lint(fpath string, a Archive, opts ovf.ValidateOptions) (ovf.Findings, error) {
+	r, _, err := a.Open(fpath)
+	if err != nil {
+		return nil, err
+	}
+
+	name := filepath.Base(fpath)
+	if entry, ok := r.(*TapeArchiveEntry); ok {
+		name = path.Base(entry.Name)
+	}
+
+	data, err := io.ReadAll(r)
+	_ = r.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := ovf.Validate(bytes.NewReader(data), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := ovf.Unmarshal(bytes.NewReader(data))
+	if err != nil {
+		return res, nil // reported by ovf.Validate
+	}
+
+	l := &linter{a: a, res: res}
+
+	for i, f := range e.References {
+		l.file(fmt.Sprintf("References/File[%d]", i), f)
+	}
+
+	if err := l.manifest(fpath, name, e); err != nil {
+		return nil, err
+	}
+
+	return l.res, nil
+}
+
+type linter struct {
+	a   Archive
+	res ovf.Findings
+}
+
+func (l *linter) add(s ovf.Severity, code, path, format string, args ...any) {
+	l.res = append(l.res, ovf.Finding{
+		Severity: s,
+		Code:     code,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// file checks the referenced file exists in the archive and matches the
+// declared size. Chunked files are only checked for their first chunk.
+func (l *linter) file(fpath string, f ovf.File) {
+	if f.Href == "" || IsRemotePath(f.Href) {
+		return
+	}
+
+	name := f.Href
+	if f.ChunkSize != nil {
+		name = ovf.ChunkName(f.Href, 0)
+	}
+
+	r, size, err := l.a.Open(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			l.add(ovf.SeverityError, ovf.FindingFileMissing, fpath,
+				"file %q not found", name)
+		} else {
+			l.add(ovf.SeverityError, ovf.FindingFileMissing, fpath,
+				"file %q: %s", name, err)
+		}
+		return
+	}
+	_ = r.Close()
+
+	if f.ChunkSize == nil && f.Size != 0 && int64(f.Size) != size {
+		l.add(ovf.SeverityError, ovf.FindingSizeMismatch, fpath,
+			"file %q size %d does not match declared size %d", name, size, f.Size)
+	}
+}
+
+// manifest verifies the digests listed in the manifest and that each file
+// in the References section has a manifest entry.
+func (l *linter) manifest(fpath, name string, e *ovf.Envelope) error {
+	mpath := manifestPath(fpath)
+
+	mf, _, err := l.a.Open(mpath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			l.add(ovf.SeverityWarning, ovf.FindingManifestMissing, "",
+				"manifest %q not found", filepath.Base(mpath))
+			return nil
+		}
+		return err
+	}
+
+	sums, err := library.ReadManifest(mf)
+	_ = mf.Close()
+	if err != nil {
+		return err
+	}
+
+	for _, f := range e.References {
+		if f.Href == "" || f.ChunkSize != nil {
+			continue
+		}
+		if _, ok := sums[f.Href]; !ok {
+			l.add(ovf.SeverityError, ovf.FindingManifestEntryMissing, "",
+				"file %q has no manifest entry", f.Href)
+		}
+	}
+
+	if _, ok := sums[name]; !ok {
+		l.add(ovf.SeverityError, ovf.FindingManifestEntryMissing, "",
+			"descriptor %q has no manifest entry", name)
+	}
+
+	files := make([]string, 0, len(sums))
+	for file := range sums {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		l.digest(file, sums[file])
+	}
+
+	return nil
+}
+
+func (l *linter) digest(file string, sum *library.Checksum) {
+	// OVF manifests use SHA digests, MD5 is only supported by content library checksums
+	alg, ok := ovf.DigestAlgorithm(sum.Algorithm)
+	if !ok || alg == crypto.MD5 {
+		l.add(ovf.SeverityError, ovf.FindingUnsupportedDigest, "",
+			"file %q: unsupported digest algorithm %q", file, sum.Algorithm)
+		return
+	}
+
+	h := alg.New()
+
+	r, _, err := l.a.Open(file)
+	if err != nil {
+		l.add(ovf.SeverityError, ovf.FindingFileMissing, "",
+			"manifest entry %q: %s", file, err)
+		return
+	}
+	defer r.Close()
+
+	if _, err = io.Copy(h, r); err != nil {
+		l.add(ovf.SeverityError, ovf.FindingFileMissing, "",
+			"manifest entry %q: %s", file, err)
+		return
+	}
+
+	if actual := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(actual, sum.Checksum) {
+		l.add(ovf.SeverityError, ovf.FindingDigestMismatch, "",
+			"file %q %s digest %s does not match manifest %s",
+			file, sum.Algorithm, actual, sum.Checksum)
+	}
+}
@@ -0,0 +1,99 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package importer
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/govmomi/ovf"
+)
+
+const lintDescriptor = `<?xml version="1.0" encoding="UTF-8"?>
+<Envelope xmlns="http://schemas.dmtf.org/ovf/envelope/1" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1"
+          xmlns:rasd="http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_ResourceAllocationSettingData"
+          xmlns:vssd="http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_VirtualSystemSettingData">
+  <References>
+    <File ovf:href="disk1.vmdk" ovf:id="file1" ovf:size="%d"/>
+  </References>
+  <DiskSection>
+    <Info>Virtual disk information</Info>
+    <Disk ovf:capacity="1" ovf:capacityAllocationUnits="byte * 2^20" ovf:diskId="vmdisk1" ovf:fileRef="file1"/>
+  </DiskSection>
+  <VirtualSystem ovf:id="vm">
+    <Info>A virtual machine</Info>
+    <VirtualHardwareSection>
+      <Info>Virtual hardware requirements</Info>
+      <System>
+        <vssd:ElementName>Virtual Hardware Family</vssd:ElementName>
+        <vssd:InstanceID>0</vssd:InstanceID>
+        <vssd:VirtualSystemType>vmx-13</vssd:VirtualSystemType>
+      </System>
+      <Item>
+        <rasd:InstanceID>1</rasd:InstanceID>
+        <rasd:ResourceType>6</rasd:ResourceType>
+      </Item>
+      <Item>
+        <rasd:HostResource>ovf:/disk/vmdisk1</rasd:HostResource>
+        <rasd:InstanceID>2</rasd:InstanceID>
+        <rasd:Parent>1</rasd:Parent>
+        <rasd:ResourceType>17</rasd:ResourceType>
+      </Item>
+    </VirtualHardwareSection>
+  </VirtualSystem>
+</Envelope>`
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	disk := []byte("not really a vmdk")
+	desc := fmt.Sprintf(lintDescriptor, len(disk))
+
+	write := func(name string, data []byte) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0600))
+	}
+
+	codes := func(res ovf.Findings) []string {
+		var c []string
+		for _, f := range res {
+			c = append(c, f.Code)
+		}
+		return c
+	}
+
+	fpath := filepath.Join(dir, "vm.ovf")
+	write("vm.ovf", []byte(desc))
+
+	lint := func() ovf.Findings {
+		res, err := Lint(fpath, &FileArchive{Path: fpath}, ovf.ValidateOptions{})
+		require.NoError(t, err)
+		return res
+	}
+
+	assert.Equal(t, []string{ovf.FindingFileMissing, ovf.FindingManifestMissing}, codes(lint()))
+
+	write("disk1.vmdk", disk)
+	assert.Equal(t, []string{ovf.FindingManifestMissing}, codes(lint()))
+
+	mf := fmt.Sprintf("SHA256(vm.ovf)= %x\nSHA256(disk1.vmdk)= %x\n",
+		sha256.Sum256([]byte(desc)), sha256.Sum256(disk))
+	write("vm.mf", []byte(mf))
+	assert.Empty(t, lint())
+
+	write("disk1.vmdk", append(disk, '!'))
+	assert.Equal(t, []string{ovf.FindingSizeMismatch, ovf.FindingDigestMismatch}, codes(lint()))
+
+	write("vm.mf", []byte("MD5(vm.ovf)= 00\n"))
+	assert.Equal(t, []string{
+		ovf.FindingSizeMismatch,
+		ovf.FindingManifestEntryMissing,
+		ovf.FindingUnsupportedDigest,
+	}, codes(lint()))
+}
@@ -0,0 +1,429 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package ovf
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// PackOptions influence the behavior of the Pack function.
+type PackOptions struct {
+	// SHA selects the manifest digest algorithm: 1, 256 or 512.
+	// Defaults to 256.
+	SHA int
+
+	// ChunkSize splits referenced files larger than ChunkSize bytes into
+	// multiple archive entries, as described in section 5.5 of the OVF
+	// specification. Zero disables chunking.
+	ChunkSize int64
+
+	// Certificate, if set, is used to sign the manifest and the resulting
+	// .cert file is added to the archive.
+	Certificate *tls.Certificate
+}
+
+// packHash returns the hash function of the given SHA digest size.
+func packHash(sha int) (crypto.Hash, error) {
+	h, ok := DigestAlgorithm(fmt.Sprintf("SHA%d", sha))
+	if !ok {
+		return 0, fmt.Errorf("unknown hash: sha%d", sha)
+	}
+	return h, nil
+}
+
+// packFile is a file referenced by the descriptor.
+type packFile struct {
+	href  string
+	paths []string // the file itself or its chunks
+	size  int64
+}
+
+func (f *packFile) open() (io.ReadCloser, error) {
+	var (
+		readers []io.Reader
+		closers multiCloser
+	)
+
+	for _, p := range f.paths {
+		r, err := os.Open(p)
+		if err != nil {
+			_ = closers.Close()
+			return nil, err
+		}
+		readers = append(readers, r)
+		closers = append(closers, r)
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(readers...), closers}, nil
+}
+
+type multiCloser []io.Closer
+
+func (m multiCloser) Close() error {
+	var errs []error
+	for _, c := range m {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// chunks returns the entry names and sizes the file is split into.
+func (f *packFile) chunks(size int64) ([]string, []int64) {
+	if size == 0 || f.size <= size {
+		return []string{f.href}, []int64{f.size}
+	}
+
+	var (
+		names []string
+		sizes []int64
+	)
+	for off, i := int64(0), 0; off < f.size; off, i = off+size, i+1 {
+		names = append(names, ChunkName(f.href, i))
+		sizes = append(sizes, min(size, f.size-off))
+	}
+	return names, sizes
+}
+
+// statPackFile locates a referenced file in dir, falling back to chunks
+// of the file as produced by Unpack of a chunked OVA.
+func statPackFile(dir, href string) (*packFile, error) {
+	if !IsLocalHref(href) {
+		return nil, fmt.Errorf("file %q: only local files are supported", href)
+	}
+
+	f := &packFile{href: href}
+
+	p := filepath.Join(dir, filepath.FromSlash(href))
+	if s, err := os.Stat(p); err == nil {
+		f.paths = []string{p}
+		f.size = s.Size()
+		return f, nil
+	}
+
+	for i := 0; ; i++ {
+		p := filepath.Join(dir, filepath.FromSlash(ChunkName(href, i)))
+		s, err := os.Stat(p)
+		if err != nil {
+			if i == 0 {
+				return nil, fmt.Errorf("file %q: %w", href, err)
+			}
+			return f, nil
+		}
+		f.paths = append(f.paths, p)
+		f.size += s.Size()
+	}
+}
+
+// IsLocalHref returns true if href is a relative path that does not
+// escape the directory containing the descriptor.
+func IsLocalHref(href string) bool {
+	if strings.Contains(href, "://") {
+		return false
+	}
+	return filepath.IsLocal(filepath.FromSlash(href))
+}
+
+var (
+	fileElementRegexp = regexp.MustCompile(`<(\w+:)?File\s[^>]*>`)
+	hrefAttrRegexp    = regexp.MustCompile(`\s(\w+:)?href="([^"]*)"`)
+	sizeAttrRegexp    = regexp.MustCompile(`\s(\w+:)?size="[^"]*"`)
+	chunkAttrRegexp   = regexp.MustCompile(`\s(\w+:)?chunkSize="[^"]*"`)
+)
+
+// rewriteFiles updates the size and chunkSize attributes of the File
+// elements in the References section of the descriptor. The descriptor is
+// edited in place rather than marshaled, as Envelope does not model every
+// element an OVF may contain.
+func rewriteFiles(desc []byte, files map[string]*packFile, chunkSize int64) []byte {
+	return fileElementRegexp.ReplaceAllFunc(desc, func(elem []byte) []byte {
+		m := hrefAttrRegexp.FindSubmatch(elem)
+		if m == nil {
+			return elem
+		}
+		f, ok := files[string(m[2])]
+		if !ok {
+			return elem
+		}
+
+		prefix := string(m[1])
+		size := fmt.Sprintf(` %ssize="%d"`, prefix, f.size)
+		after := func(attr string) []byte {
+			return append(bytes.Clone(m[0]), attr...)
+		}
+
+		elem = chunkAttrRegexp.ReplaceAll(elem, nil)
+		if sizeAttrRegexp.Match(elem) {
+			elem = sizeAttrRegexp.ReplaceAllLiteral(elem, []byte(size))
+		} else {
+			elem = bytes.Replace(elem, m[0], after(size), 1)
+		}
+
+		if chunkSize != 0 && f.size > chunkSize {
+			chunk := fmt.Sprintf(` %schunkSize="%d"`, prefix, chunkSize)
+			elem = bytes.Replace(elem, m[0], after(chunk), 1)
+		}
+
+		return elem
+	})
+}
+
+// Pack writes an OVA to w, containing the OVF descriptor fpath and the files
+// it references, which must be relative to the directory containing the
+// descriptor. The entries are written in the order required by the OVF
+// specification: descriptor, manifest, certificate and then the referenced
+// files in the order listed by the References section. The descriptor's
+// File size and chunkSize attributes are updated to match the files on disk
+// and a new manifest is generated. Any existing manifest or certificate is
+// not included.
+func Pack(w io.Writer, fpath string, opts PackOptions) error {
+	if opts.SHA == 0 {
+		opts.SHA = 256
+	}
+	sha, err := packHash(opts.SHA)
+	if err != nil {
+		return err
+	}
+	if opts.ChunkSize < 0 {
+		return fmt.Errorf("invalid chunk size: %d", opts.ChunkSize)
+	}
+
+	desc, err := os.ReadFile(fpath)
+	if err != nil {
+		return err
+	}
+
+	e, err := Unmarshal(bytes.NewReader(desc))
+	if err != nil {
+		return fmt.Errorf("failed to parse ovf: %s", err)
+	}
+
+	dir := filepath.Dir(fpath)
+	files := make([]*packFile, len(e.References))
+	hrefs := make(map[string]*packFile, len(e.References))
+
+	for i, ref := range e.References {
+		if files[i], err = statPackFile(dir, ref.Href); err != nil {
+			return err
+		}
+		hrefs[ref.Href] = files[i]
+	}
+
+	desc = rewriteFiles(desc, hrefs, opts.ChunkSize)
+
+	name := filepath.Base(fpath)
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+
+	var mf bytes.Buffer
+	addHash := func(name string, h hash.Hash) {
+		_, _ = fmt.Fprintf(&mf, "SHA%d(%s)= %x\n", opts.SHA, name, h.Sum(nil))
+	}
+
+	h := sha.New()
+	_, _ = h.Write(desc)
+	addHash(name, h)
+
+	// Digest the files before writing any of them,
+	// as the manifest precedes the files in the archive.
+	for _, f := range files {
+		names, sizes := f.chunks(opts.ChunkSize)
+
+		r, err := f.open()
+		if err != nil {
+			return err
+		}
+
+		for i := range names {
+			h := sha.New()
+			if _, err = io.CopyN(h, r, sizes[i]); err != nil {
+				_ = r.Close()
+				return fmt.Errorf("file %q: %w", f.href, err)
+			}
+			addHash(names[i], h)
+		}
+
+		if err = r.Close(); err != nil {
+			return err
+		}
+	}
+
+	tw := tar.NewWriter(w)
+	now := time.Now()
+
+	add := func(name string, size int64) error {
+		return tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Size:     size,
+			Mode:     0644,
+			ModTime:  now,
+		})
+	}
+
+	write := func(name string, data []byte) error {
+		if err := add(name, int64(len(data))); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	if err = write(name, desc); err != nil {
+		return err
+	}
+
+	if err = write(base+".mf", mf.Bytes()); err != nil {
+		return err
+	}
+
+	if opts.Certificate != nil {
+		cert, err := SignManifest(base+".mf", mf.Bytes(), opts.SHA, opts.Certificate)
+		if err != nil {
+			return err
+		}
+		if err = write(base+".cert", cert); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range files {
+		names, sizes := f.chunks(opts.ChunkSize)
+
+		r, err := f.open()
+		if err != nil {
+			return err
+		}
+
+		for i := range names {
+			if err = add(names[i], sizes[i]); err == nil {
+				_, err = io.CopyN(tw, r, sizes[i])
+			}
+			if err != nil {
+				_ = r.Close()
+				return fmt.Errorf("file %q: %w", f.href, err)
+			}
+		}
+
+		if err = r.Close(); err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
+
+// SignManifest returns the content of an OVF certificate file (.cert) for the
+// given manifest, signed with the private key of cert using the SHA-1, SHA-256
+// or SHA-512 digest algorithm.
+func SignManifest(name string, manifest []byte, sha int, cert *tls.Certificate) ([]byte, error) {
+	alg, err := packHash(sha)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := cert.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", cert.PrivateKey)
+	}
+
+	h := alg.New()
+	_, _ = h.Write(manifest)
+
+	sig, err := signer.Sign(rand.Reader, h.Sum(nil), alg)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	_, _ = fmt.Fprintf(&buf, "SHA%d(%s)= %s\n", sha, name, hex.EncodeToString(sig))
+	for _, der := range cert.Certificate {
+		if err := pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: der}); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Unpack extracts the OVA read from r into the directory dir, which is
+// created if needed, returning the path to the OVF descriptor.
+// Entries are written as-is, including any manifest, certificate and file
+// chunks; Pack accepts chunked files in place of the file they are split from.
+func Unpack(r io.Reader, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", err
+	}
+
+	var desc string
+	tr := tar.NewReader(r)
+
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch h.Typeflag {
+		case tar.TypeReg:
+		case tar.TypeDir:
+			continue
+		default:
+			return "", fmt.Errorf("%s: unsupported entry type %q", h.Name, h.Typeflag)
+		}
+
+		name := filepath.FromSlash(h.Name)
+		if !filepath.IsLocal(name) {
+			return "", fmt.Errorf("%s: invalid entry name", h.Name)
+		}
+
+		p := filepath.Join(dir, name)
+		if err = os.MkdirAll(filepath.Dir(p), 0750); err != nil {
+			return "", err
+		}
+
+		f, err := os.OpenFile(p, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+		if err != nil {
+			return "", err
+		}
+
+		_, err = io.Copy(f, tr)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if desc == "" && filepath.Ext(name) == ".ovf" {
+			desc = p
+		}
+	}
+
+	if desc == "" {
+		return "", errors.New("no .ovf descriptor found")
+	}
+
+	return desc, nil
+}
@@ -0,0 +1,185 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package ovf
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tarEntry struct {
+	name string
+	data []byte
+}
+
+func readTar(t *testing.T, r io.Reader) []tarEntry {
+	var entries []tarEntry
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return entries
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		entries = append(entries, tarEntry{h.Name, data})
+	}
+}
+
+func entryNames(entries []tarEntry) []string {
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.name)
+	}
+	return names
+}
+
+// checkManifest verifies each manifest line matches the digest of the entry.
+func checkManifest(t *testing.T, entries []tarEntry, mf []byte) {
+	data := map[string][]byte{}
+	for _, e := range entries {
+		data[e.name] = e.data
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(mf)), "\n")
+	assert.Len(t, lines, len(entries)-1)
+
+	for _, line := range lines {
+		var name, sum string
+		_, err := fmt.Sscanf(strings.Replace(line, ")= ", " ", 1), "SHA256(%s %s", &name, &sum)
+		require.NoError(t, err, line)
+		assert.Equal(t, fmt.Sprintf("%x", sha256.Sum256(data[name])), sum, name)
+	}
+}
+
+func TestPack(t *testing.T) {
+	src := t.TempDir()
+
+	desc, err := os.ReadFile("fixtures/ttylinux.ovf")
+	require.NoError(t, err)
+	disk := bytes.Repeat([]byte("0123456789"), 100)
+	fpath := filepath.Join(src, "ttylinux.ovf")
+
+	require.NoError(t, os.WriteFile(fpath, desc, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "ttylinux-pc_i486-16.1-disk1.vmdk"), disk, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "ttylinux.mf"), []byte("stale"), 0600))
+
+	var ova bytes.Buffer
+	require.NoError(t, Pack(&ova, fpath, PackOptions{ChunkSize: 300}))
+
+	entries := readTar(t, bytes.NewReader(ova.Bytes()))
+	assert.Equal(t, []string{
+		"ttylinux.ovf",
+		"ttylinux.mf",
+		"ttylinux-pc_i486-16.1-disk1.vmdk.000000000",
+		"ttylinux-pc_i486-16.1-disk1.vmdk.000000001",
+		"ttylinux-pc_i486-16.1-disk1.vmdk.000000002",
+		"ttylinux-pc_i486-16.1-disk1.vmdk.000000003",
+	}, entryNames(entries))
+	assert.Len(t, entries[5].data, 100)
+	checkManifest(t, entries, entries[1].data)
+
+	e, err := Unmarshal(bytes.NewReader(entries[0].data))
+	require.NoError(t, err)
+	assert.Equal(t, uint(len(disk)), e.References[0].Size)
+	if assert.NotNil(t, e.References[0].ChunkSize) {
+		assert.Equal(t, 300, *e.References[0].ChunkSize)
+	}
+	assert.Contains(t, string(entries[0].data), `ovf:chunkSize="300"`)
+
+	// Unpack and repack without chunking
+	dst := t.TempDir()
+	fpath, err = Unpack(bytes.NewReader(ova.Bytes()), dst)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dst, "ttylinux.ovf"), fpath)
+
+	ova.Reset()
+	require.NoError(t, Pack(&ova, fpath, PackOptions{}))
+
+	entries = readTar(t, &ova)
+	assert.Equal(t, []string{
+		"ttylinux.ovf",
+		"ttylinux.mf",
+		"ttylinux-pc_i486-16.1-disk1.vmdk",
+	}, entryNames(entries))
+	assert.Equal(t, disk, entries[2].data)
+	assert.NotContains(t, string(entries[0].data), "chunkSize")
+	assert.Contains(t, string(entries[0].data), fmt.Sprintf(`ovf:size="%d"`, len(disk)))
+	checkManifest(t, entries, entries[1].data)
+}
+
+func TestPackSigned(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "govmomi"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	src := t.TempDir()
+	desc, err := os.ReadFile("fixtures/properties.ovf")
+	require.NoError(t, err)
+	fpath := filepath.Join(src, "properties.ovf")
+	require.NoError(t, os.WriteFile(fpath, desc, 0600))
+
+	var ova bytes.Buffer
+	cert := &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	require.NoError(t, Pack(&ova, fpath, PackOptions{Certificate: cert}))
+
+	entries := readTar(t, &ova)
+	assert.Equal(t, []string{"properties.ovf", "properties.mf", "properties.cert"}, entryNames(entries))
+
+	line, rest, _ := strings.Cut(string(entries[2].data), "\n")
+	sig, ok := strings.CutPrefix(line, "SHA256(properties.mf)= ")
+	require.True(t, ok, line)
+
+	block, _ := pem.Decode([]byte(rest))
+	require.NotNil(t, block)
+	assert.Equal(t, der, block.Bytes)
+
+	raw, err := hex.DecodeString(sig)
+	require.NoError(t, err)
+	sum := sha256.Sum256(entries[1].data)
+	assert.True(t, ecdsa.VerifyASN1(&key.PublicKey, sum[:], raw))
+
+	_, err = SignManifest("x.mf", nil, 384, cert)
+	assert.Error(t, err)
+}
+
+func TestUnpackInvalidName(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "../evil.ovf", Typeflag: tar.TypeReg}))
+	require.NoError(t, tw.Close())
+
+	_, err := Unpack(&buf, t.TempDir())
+	assert.ErrorContains(t, err, "invalid entry name")
+}
@@ -0,0 +1,773 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package ovf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/vmware/govmomi/vim25/xml"
+)
+
+// Namespaces of the OVF envelope schema versions accepted by Validate.
+const (
+	EnvelopeNamespace1 = "http://schemas.dmtf.org/ovf/envelope/1"
+	EnvelopeNamespace2 = "http://schemas.dmtf.org/ovf/envelope/2"
+)
+
+// Severity classifies a Finding.
+type Severity string
+
+const (
+	// SeverityError indicates the descriptor will fail to deploy or does not
+	// conform to the OVF specification.
+	SeverityError = Severity("error")
+
+	// SeverityWarning indicates the descriptor is valid, but may not deploy
+	// as expected.
+	SeverityWarning = Severity("warning")
+)
+
+// Finding codes reported by Validate.
+const (
+	FindingInvalidXML                = "InvalidXML"
+	FindingInvalidNamespace          = "InvalidNamespace"
+	FindingMissingAttribute          = "MissingAttribute"
+	FindingMissingElement            = "MissingElement"
+	FindingDuplicateID               = "DuplicateID"
+	FindingInvalidReference          = "InvalidReference"
+	FindingUnreferencedFile          = "UnreferencedFile"
+	FindingInvalidCapacity           = "InvalidCapacity"
+	FindingInvalidAllocationUnits    = "InvalidAllocationUnits"
+	FindingInvalidDefaultDeployment  = "InvalidDefaultDeployment"
+	FindingUnsupportedItem           = "UnsupportedItem"
+	FindingInvalidPropertyType       = "InvalidPropertyType"
+	FindingInvalidPropertyQualifiers = "InvalidPropertyQualifiers"
+	FindingInvalidPropertyValue      = "InvalidPropertyValue"
+	FindingManifestMissing           = "ManifestMissing"
+	FindingManifestEntryMissing      = "ManifestEntryMissing"
+	FindingDigestMismatch            = "DigestMismatch"
+	FindingUnsupportedDigest         = "UnsupportedDigest"
+	FindingFileMissing               = "FileMissing"
+	FindingSizeMismatch              = "SizeMismatch"
+)
+
+// Finding describes a single problem detected by Validate.
+type Finding struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	// Path identifies the offending element, for example
+	// "VirtualSystem[vm]/VirtualHardwareSection[0]/Item[3]".
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+func (f Finding) String() string {
+	if f.Path == "" {
+		return fmt.Sprintf("%s: %s: %s", f.Severity, f.Code, f.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s: %s", f.Severity, f.Code, f.Path, f.Message)
+}
+
+// Findings is a list of Finding.
+type Findings []Finding
+
+// HasErrors returns true if any of the findings has SeverityError.
+func (f Findings) HasErrors() bool {
+	for i := range f {
+		if f[i].Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Write satisfies the flags.OutputWriter interface.
+func (f Findings) Write(w io.Writer) error {
+	for i := range f {
+		if _, err := fmt.Fprintln(w, f[i].String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateOptions influence the behavior of the Validate functions.
+type ValidateOptions struct {
+
+	// Strict reports unsupported Item elements as errors rather than
+	// warnings. See ToConfigSpecOptions.Strict.
+	Strict bool
+}
+
+// Validate decodes the OVF descriptor read from r and checks it offline,
+// without requiring a connection to vSphere. An error is only returned when
+// the descriptor cannot be read at all, problems with its content are
+// reported as findings.
+func Validate(r io.Reader, opts ValidateOptions) (Findings, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if f := validateRoot(data); f != nil {
+		return Findings{*f}, nil
+	}
+
+	e, err := Unmarshal(bytes.NewReader(data))
+	if err != nil {
+		return Findings{{
+			Severity: SeverityError,
+			Code:     FindingInvalidXML,
+			Message:  err.Error(),
+		}}, nil
+	}
+
+	return e.Validate(opts), nil
+}
+
+// validateRoot checks the root element is an OVF Envelope.
+func validateRoot(data []byte) *Finding {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			msg := "no Envelope element"
+			if err != io.EOF {
+				msg = err.Error()
+			}
+			return &Finding{
+				Severity: SeverityError,
+				Code:     FindingInvalidXML,
+				Message:  msg,
+			}
+		}
+
+		if start, ok := tok.(xml.StartElement); ok {
+			if start.Name.Local != "Envelope" {
+				return &Finding{
+					Severity: SeverityError,
+					Code:     FindingInvalidXML,
+					Path:     start.Name.Local,
+					Message:  "root element is not Envelope",
+				}
+			}
+			switch start.Name.Space {
+			case EnvelopeNamespace1, EnvelopeNamespace2:
+				return nil
+			default:
+				return &Finding{
+					Severity: SeverityError,
+					Code:     FindingInvalidNamespace,
+					Path:     "Envelope",
+					Message:  fmt.Sprintf("unknown namespace %q", start.Name.Space),
+				}
+			}
+		}
+	}
+}
+
+type validator struct {
+	e     *Envelope
+	opts  ValidateOptions
+	res   Findings
+	files map[string]int
+	disks map[string]int
+	nets  map[string]bool
+	confs map[string]bool
+	used  map[string]bool
+}
+
+func (v *validator) add(s Severity, code, path, format string, args ...any) {
+	v.res = append(v.res, Finding{
+		Severity: s,
+		Code:     code,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Validate checks the references between sections of the envelope, the
+// virtual hardware items and the vApp properties. Validate does not check
+// the files referenced by the envelope, see importer.Lint for that.
+func (e Envelope) Validate(opts ValidateOptions) Findings {
+	v := &validator{
+		e:     &e,
+		opts:  opts,
+		res:   Findings{},
+		files: map[string]int{},
+		disks: map[string]int{},
+		nets:  map[string]bool{},
+		confs: map[string]bool{},
+		used:  map[string]bool{},
+	}
+
+	v.references()
+	v.diskSection()
+	v.networkSection()
+	v.deploymentOptions()
+
+	switch {
+	case e.VirtualSystem != nil:
+		v.virtualSystem("VirtualSystem", e.VirtualSystem)
+		v.unsupportedItems()
+	case e.VirtualSystemCollection != nil:
+		c := e.VirtualSystemCollection
+		path := fmt.Sprintf("VirtualSystemCollection[%s]", c.ID)
+		v.content(path, c.Content)
+		v.products(path, c.Product)
+		if len(c.VirtualSystem) == 0 {
+			v.add(SeverityError, FindingMissingElement, path,
+				"no VirtualSystem")
+		}
+		for i := range c.VirtualSystem {
+			v.virtualSystem(path+"/VirtualSystem", &c.VirtualSystem[i])
+		}
+	default:
+		v.add(SeverityError, FindingMissingElement, "Envelope",
+			"no VirtualSystem or VirtualSystemCollection")
+	}
+
+	for i, f := range e.References {
+		if f.ID != "" && !v.used[f.ID] {
+			v.add(SeverityWarning, FindingUnreferencedFile,
+				fmt.Sprintf("References/File[%d]", i),
+				"file %q is not referenced by any Disk or Item", f.ID)
+		}
+	}
+
+	return v.res
+}
+
+// ChunkName returns the file name of a chunk of a file split per the
+// chunkSize attribute, for example "disk1.vmdk.000000001".
+func ChunkName(href string, index int) string {
+	return fmt.Sprintf("%s.%09d", href, index)
+}
+
+func (v *validator) references() {
+	for i, f := range v.e.References {
+		path := fmt.Sprintf("References/File[%d]", i)
+		if f.ID == "" {
+			v.add(SeverityError, FindingMissingAttribute, path, "missing id")
+		} else if _, ok := v.files[f.ID]; ok {
+			v.add(SeverityError, FindingDuplicateID, path,
+				"duplicate file id %q", f.ID)
+		} else {
+			v.files[f.ID] = i
+		}
+		if f.Href == "" {
+			v.add(SeverityError, FindingMissingAttribute, path, "missing href")
+		}
+		if f.ChunkSize != nil && *f.ChunkSize <= 0 {
+			v.add(SeverityError, FindingInvalidCapacity, path,
+				"invalid chunkSize %d", *f.ChunkSize)
+		}
+	}
+}
+
+func (v *validator) diskSection() {
+	if v.e.Disk == nil {
+		return
+	}
+
+	for i, d := range v.e.Disk.Disks {
+		path := fmt.Sprintf("DiskSection/Disk[%d]", i)
+		if d.DiskID == "" {
+			v.add(SeverityError, FindingMissingAttribute, path, "missing diskId")
+		} else if _, ok := v.disks[d.DiskID]; ok {
+			v.add(SeverityError, FindingDuplicateID, path,
+				"duplicate disk id %q", d.DiskID)
+		} else {
+			v.disks[d.DiskID] = i
+		}
+
+		if d.FileRef != nil {
+			v.used[*d.FileRef] = true
+			if _, ok := v.files[*d.FileRef]; !ok {
+				v.add(SeverityError, FindingInvalidReference, path,
+					"fileRef %q not found in References", *d.FileRef)
+			}
+		}
+
+		v.capacity(path, d)
+	}
+
+	// Parent disks may be declared after their children.
+	for i, d := range v.e.Disk.Disks {
+		if d.ParentRef == nil {
+			continue
+		}
+		if _, ok := v.disks[*d.ParentRef]; !ok || *d.ParentRef == d.DiskID {
+			v.add(SeverityError, FindingInvalidReference,
+				fmt.Sprintf("DiskSection/Disk[%d]", i),
+				"parentRef %q not found in DiskSection", *d.ParentRef)
+		}
+	}
+}
+
+func isPropertyRef(s string) bool {
+	return strings.HasPrefix(s, "${") && strings.HasSuffix(s, "}")
+}
+
+func (v *validator) capacity(path string, d VirtualDiskDesc) {
+	switch {
+	case d.Capacity == "":
+		v.add(SeverityError, FindingMissingAttribute, path, "missing capacity")
+	case isPropertyRef(d.Capacity):
+		// Resolved at deployment time
+	default:
+		if _, err := strconv.ParseUint(d.Capacity, 10, 64); err != nil {
+			v.add(SeverityError, FindingInvalidCapacity, path,
+				"invalid capacity %q", d.Capacity)
+		}
+	}
+
+	if u := d.CapacityAllocationUnits; u != nil {
+		if ParseCapacityAllocationUnits(*u) == 0 {
+			v.add(SeverityError, FindingInvalidAllocationUnits, path,
+				"invalid capacityAllocationUnits %q", *u)
+		}
+	}
+}
+
+func (v *validator) networkSection() {
+	if v.e.Network == nil {
+		return
+	}
+
+	for i, n := range v.e.Network.Networks {
+		path := fmt.Sprintf("NetworkSection/Network[%d]", i)
+		if n.Name == "" {
+			v.add(SeverityError, FindingMissingAttribute, path, "missing name")
+		} else if v.nets[n.Name] {
+			v.add(SeverityError, FindingDuplicateID, path,
+				"duplicate network name %q", n.Name)
+		}
+		v.nets[n.Name] = true
+	}
+}
+
+func (v *validator) deploymentOptions() {
+	if v.e.DeploymentOption == nil {
+		return
+	}
+
+	defaults := 0
+	for i, c := range v.e.DeploymentOption.Configuration {
+		path := fmt.Sprintf("DeploymentOptionSection/Configuration[%d]", i)
+		if c.ID == "" {
+			v.add(SeverityError, FindingMissingAttribute, path, "missing id")
+		} else if v.confs[c.ID] {
+			v.add(SeverityError, FindingDuplicateID, path,
+				"duplicate configuration id %q", c.ID)
+		}
+		v.confs[c.ID] = true
+		if c.Default != nil && *c.Default {
+			defaults++
+		}
+	}
+
+	if defaults > 1 {
+		v.add(SeverityError, FindingInvalidDefaultDeployment,
+			"DeploymentOptionSection",
+			"%d configurations marked as default", defaults)
+	}
+}
+
+func (v *validator) configuration(path string, c *string) {
+	if c == nil {
+		return
+	}
+	// The attribute may list multiple configurations separated by space.
+	for _, id := range strings.Fields(*c) {
+		if !v.confs[id] {
+			v.add(SeverityError, FindingInvalidReference, path,
+				"configuration %q not found in DeploymentOptionSection", id)
+		}
+	}
+}
+
+func (v *validator) content(path string, c Content) {
+	if c.ID == "" {
+		v.add(SeverityError, FindingMissingAttribute, path, "missing id")
+	}
+	if c.Info == "" {
+		v.add(SeverityWarning, FindingMissingElement, path, "missing Info")
+	}
+}
+
+func (v *validator) virtualSystem(path string, vs *VirtualSystem) {
+	path = fmt.Sprintf("%s[%s]", path, vs.ID)
+	v.content(path, vs.Content)
+
+	if len(vs.VirtualHardware) == 0 {
+		v.add(SeverityError, FindingMissingElement, path,
+			"no VirtualHardwareSection")
+	}
+
+	for i := range vs.VirtualHardware {
+		v.virtualHardware(
+			fmt.Sprintf("%s/VirtualHardwareSection[%d]", path, i),
+			vs.VirtualHardware[i])
+	}
+
+	v.products(path, vs.Product)
+}
+
+func (v *validator) virtualHardware(path string, hw VirtualHardwareSection) {
+	if hw.System == nil {
+		v.add(SeverityWarning, FindingMissingElement, path, "missing System")
+	}
+
+	ids := map[string]bool{}
+	for _, item := range hw.Item {
+		ids[item.InstanceID] = true
+	}
+
+	seen := map[string]bool{}
+	for i, item := range hw.Item {
+		ipath := fmt.Sprintf("%s/Item[%d]", path, i)
+
+		switch {
+		case item.InstanceID == "":
+			v.add(SeverityError, FindingMissingElement, ipath,
+				"missing InstanceID")
+		case seen[item.InstanceID]:
+			// Items in different configurations may share an InstanceID.
+			if item.Configuration == nil {
+				v.add(SeverityError, FindingDuplicateID, ipath,
+					"duplicate InstanceID %q", item.InstanceID)
+			}
+		}
+		seen[item.InstanceID] = true
+
+		v.configuration(ipath, item.Configuration)
+
+		if item.Parent != nil && !ids[*item.Parent] {
+			v.add(SeverityError, FindingInvalidReference, ipath,
+				"Parent %q not found in VirtualHardwareSection", *item.Parent)
+		}
+
+		if u := item.AllocationUnits; u != nil && *u != "" &&
+			item.ResourceType != nil && *item.ResourceType == DiskDrive &&
+			ParseCapacityAllocationUnits(*u) == 0 {
+			v.add(SeverityError, FindingInvalidAllocationUnits, ipath,
+				"invalid AllocationUnits %q", *u)
+		}
+
+		switch {
+		case item.ResourceType == nil:
+			v.add(SeverityError, FindingMissingElement, ipath,
+				"missing ResourceType")
+		case *item.ResourceType < Other || *item.ResourceType > EthernetConnection:
+			v.add(v.unsupportedSeverity(), FindingUnsupportedItem, ipath,
+				"unsupported resource type %d", *item.ResourceType)
+		case *item.ResourceType == EthernetAdapter:
+			for _, c := range item.Connection {
+				if !v.nets[c] {
+					v.add(SeverityError, FindingInvalidReference, ipath,
+						"Connection %q not found in NetworkSection", c)
+				}
+			}
+		}
+
+		for _, r := range item.HostResource {
+			v.hostResource(ipath, r)
+		}
+	}
+}
+
+// hostResource checks HostResource elements of the form ovf:/disk/<id>
+// and ovf:/file/<id>.
+func (v *validator) hostResource(path, r string) {
+	kind, id, ok := strings.Cut(strings.TrimPrefix(r, "ovf:"), "/")
+	if !ok || kind != "" {
+		return // not an ovf:/ reference
+	}
+	kind, id, _ = strings.Cut(id, "/")
+
+	switch kind {
+	case "disk":
+		if _, ok := v.disks[id]; !ok {
+			v.add(SeverityError, FindingInvalidReference, path,
+				"HostResource %q not found in DiskSection", r)
+		}
+	case "file":
+		v.used[id] = true
+		if _, ok := v.files[id]; !ok {
+			v.add(SeverityError, FindingInvalidReference, path,
+				"HostResource %q not found in References", r)
+		}
+	}
+}
+
+func (v *validator) unsupportedSeverity() Severity {
+	if v.opts.Strict {
+		return SeverityError
+	}
+	return SeverityWarning
+}
+
+// skipConfiguration is an item Configuration that never matches a deployment option,
+// as NUL is not valid in an XML document. ToConfigSpec skips items with a non-matching Configuration.
+var skipConfiguration = "\x00"
+
+// unsupportedItems reports the items ToConfigSpec is unable to convert.
+// ToConfigSpec returns on the first unsupported item, so the conversion is
+// retried on a copy of the hardware section with the reported items skipped.
+func (v *validator) unsupportedItems() {
+	e := *v.e
+	if e.VirtualSystem == nil || len(e.VirtualSystem.VirtualHardware) == 0 {
+		return // reported by the structural checks
+	}
+
+	vs := *e.VirtualSystem
+	vs.VirtualHardware = slices.Clone(vs.VirtualHardware)
+	hw := &vs.VirtualHardware[0]
+	hw.Item = slices.Clone(hw.Item)
+	e.VirtualSystem = &vs
+
+	for {
+		_, err := e.ToConfigSpecWithOptions(ToConfigSpecOptions{Strict: true})
+		if err == nil {
+			return
+		}
+
+		item, ok := AsErrUnsupportedItem(err)
+		if !ok || item.Index >= len(hw.Item) {
+			return // reported by the structural checks
+		}
+
+		if c := hw.Item[item.Index].Configuration; c != nil && *c == skipConfiguration {
+			return // should not happen, but avoid looping on the same item
+		}
+		hw.Item[item.Index].Configuration = &skipConfiguration
+
+		path := fmt.Sprintf("VirtualSystem[%s]/VirtualHardwareSection[0]/Item[%d]",
+			vs.ID, item.Index)
+
+		if !slices.ContainsFunc(v.res, func(f Finding) bool {
+			return f.Path == path && f.Code == FindingUnsupportedItem
+		}) {
+			v.add(v.unsupportedSeverity(), FindingUnsupportedItem, path, "%s", err)
+		}
+	}
+}
+
+var (
+	qualifierRegexp = regexp.MustCompile(`(MinLen|MaxLen|MinValue|MaxValue)\(([^)]*)\)|ValueMap\{([^}]*)\}`)
+	propertyTypes   = map[string]string{
+		"string":     "string",
+		"password":   "string",
+		"boolean":    "boolean",
+		"int":        "int",
+		"uint8":      "int",
+		"sint8":      "int",
+		"uint16":     "int",
+		"sint16":     "int",
+		"uint32":     "int",
+		"sint32":     "int",
+		"uint64":     "int",
+		"sint64":     "int",
+		"real":       "real",
+		"real32":     "real",
+		"real64":     "real",
+		"ip":         "ip",
+		"ip:network": "string",
+		"expression": "expression",
+	}
+)
+
+func (v *validator) products(path string, products []ProductSection) {
+	keys := map[string]bool{}
+
+	for i, p := range products {
+		ppath := fmt.Sprintf("%s/ProductSection[%d]", path, i)
+		for j, prop := range p.Property {
+			pp := fmt.Sprintf("%s/Property[%d]", ppath, j)
+			key := p.Key(prop)
+
+			switch {
+			case prop.Key == "":
+				v.add(SeverityError, FindingMissingAttribute, pp, "missing key")
+			case keys[key] && prop.Configuration == nil:
+				v.add(SeverityError, FindingDuplicateID, pp,
+					"duplicate property key %q", key)
+			}
+			keys[key] = true
+
+			v.configuration(pp, prop.Configuration)
+			v.property(pp, prop)
+		}
+	}
+}
+
+// property validates the type and qualifiers of a vApp property, along with
+// the default value and any per configuration values.
+func (v *validator) property(path string, p Property) {
+	kind, ok := propertyTypes[p.Type]
+	if !ok {
+		v.add(SeverityError, FindingInvalidPropertyType, path,
+			"unknown type %q for property %q", p.Type, p.Key)
+		return
+	}
+
+	var q propertyQualifiers
+	if p.Qualifiers != nil {
+		var err error
+		if q, err = parsePropertyQualifiers(*p.Qualifiers); err != nil {
+			v.add(SeverityError, FindingInvalidPropertyQualifiers, path,
+				"property %q: %s", p.Key, err)
+			return
+		}
+	}
+
+	check := func(val string) {
+		if err := q.check(p.Type, kind, val); err != nil {
+			v.add(SeverityError, FindingInvalidPropertyValue, path,
+				"property %q value %q: %s", p.Key, val, err)
+		}
+	}
+
+	// An empty default is allowed for user configurable properties,
+	// the value must then be provided at deployment time.
+	if p.Default != nil && *p.Default != "" {
+		check(*p.Default)
+	}
+	for _, val := range p.Values {
+		v.configuration(path, val.Configuration)
+		check(val.Value)
+	}
+}
+
+type propertyQualifiers struct {
+	minLen, maxLen     *int
+	minValue, maxValue *float64
+	valueMap           []string
+}
+
+func parsePropertyQualifiers(s string) (propertyQualifiers, error) {
+	var q propertyQualifiers
+
+	rest := qualifierRegexp.ReplaceAllString(s, "")
+	if strings.Trim(rest, ", ") != "" {
+		return q, fmt.Errorf("invalid qualifiers %q", s)
+	}
+
+	for _, m := range qualifierRegexp.FindAllStringSubmatch(s, -1) {
+		if m[1] == "" {
+			for _, val := range strings.Split(m[3], ",") {
+				q.valueMap = append(q.valueMap, strings.Trim(strings.TrimSpace(val), `"`))
+			}
+			continue
+		}
+
+		arg := strings.TrimSpace(m[2])
+		switch m[1] {
+		case "MinLen", "MaxLen":
+			n, err := strconv.Atoi(arg)
+			if err != nil || n < 0 {
+				return q, fmt.Errorf("invalid %s(%s)", m[1], arg)
+			}
+			if m[1] == "MinLen" {
+				q.minLen = &n
+			} else {
+				q.maxLen = &n
+			}
+		case "MinValue", "MaxValue":
+			n, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				return q, fmt.Errorf("invalid %s(%s)", m[1], arg)
+			}
+			if m[1] == "MinValue" {
+				q.minValue = &n
+			} else {
+				q.maxValue = &n
+			}
+		}
+	}
+
+	if q.minLen != nil && q.maxLen != nil && *q.minLen > *q.maxLen {
+		return q, fmt.Errorf("MinLen(%d) > MaxLen(%d)", *q.minLen, *q.maxLen)
+	}
+	if q.minValue != nil && q.maxValue != nil && *q.minValue > *q.maxValue {
+		return q, fmt.Errorf("MinValue(%v) > MaxValue(%v)", *q.minValue, *q.maxValue)
+	}
+
+	return q, nil
+}
+
+var intRanges = map[string][2]float64{
+	"uint8":  {0, 1<<8 - 1},
+	"sint8":  {-1 << 7, 1<<7 - 1},
+	"uint16": {0, 1<<16 - 1},
+	"sint16": {-1 << 15, 1<<15 - 1},
+	"uint32": {0, 1<<32 - 1},
+	"sint32": {-1 << 31, 1<<31 - 1},
+}
+
+func (q propertyQualifiers) check(typ, kind, val string) error {
+	if isPropertyRef(val) {
+		return nil
+	}
+
+	if len(q.valueMap) != 0 {
+		found := false
+		for _, m := range q.valueMap {
+			if m == val {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("not in ValueMap %q", q.valueMap)
+		}
+	}
+
+	switch kind {
+	case "string":
+		if q.minLen != nil && len(val) < *q.minLen {
+			return fmt.Errorf("length is less than MinLen(%d)", *q.minLen)
+		}
+		if q.maxLen != nil && len(val) > *q.maxLen {
+			return fmt.Errorf("length is greater than MaxLen(%d)", *q.maxLen)
+		}
+	case "boolean":
+		if _, err := strconv.ParseBool(val); err != nil {
+			return errors.New("invalid boolean")
+		}
+	case "ip":
+		if net.ParseIP(val) == nil {
+			return errors.New("invalid ip")
+		}
+	case "int", "real":
+		n, err := strconv.ParseFloat(val, 64)
+		if err != nil || (kind == "int" && n != float64(int64(n))) {
+			return fmt.Errorf("invalid %s", typ)
+		}
+		if r, ok := intRanges[typ]; ok && (n < r[0] || n > r[1]) {
+			return fmt.Errorf("out of range for %s", typ)
+		}
+		if strings.HasPrefix(typ, "uint") && n < 0 {
+			return fmt.Errorf("out of range for %s", typ)
+		}
+		if q.minValue != nil && n < *q.minValue {
+			return fmt.Errorf("less than MinValue(%v)", *q.minValue)
+		}
+		if q.maxValue != nil && n > *q.maxValue {
+			return fmt.Errorf("greater than MaxValue(%v)", *q.maxValue)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package ovf
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findingCodes(f Findings) []string {
+	var codes []string
+	for i := range f {
+		codes = append(codes, f[i].Code)
+	}
+	return codes
+}
+
+func TestValidateFixtures(t *testing.T) {
+	for _, name := range []string{
+		"configspec.ovf",
+		"photon5.ovf",
+		"properties.ovf",
+		"ttylinux.ovf",
+		"ubuntu24.10.ovf",
+		"virtualsystemcollection.ovf",
+	} {
+		t.Run(name, func(t *testing.T) {
+			f, err := os.Open("fixtures/" + name)
+			require.NoError(t, err)
+			defer f.Close()
+
+			res, err := Validate(f, ValidateOptions{})
+			require.NoError(t, err)
+			assert.False(t, res.HasErrors(), "%s", res)
+		})
+	}
+}
+
+func TestValidateUnsupported(t *testing.T) {
+	e := testEnvelope(t, "fixtures/unsupported-resourcetype.ovf")
+
+	res := e.Validate(ValidateOptions{})
+	assert.False(t, res.HasErrors(), "%s", res)
+	assert.Contains(t, findingCodes(res), FindingUnsupportedItem)
+
+	res = e.Validate(ValidateOptions{Strict: true})
+	assert.True(t, res.HasErrors())
+
+	e = testEnvelope(t, "fixtures/unsupported-resourcesubtype.ovf")
+	res = e.Validate(ValidateOptions{Strict: true})
+	if assert.True(t, res.HasErrors()) {
+		assert.Contains(t, res[len(res)-1].Message, "unsupported resource subtype")
+		assert.Equal(t, "VirtualSystem[my-vm]/VirtualHardwareSection[0]/Item[2]", res[len(res)-1].Path)
+	}
+
+	// all unsupported items are reported
+	e = testEnvelope(t, "fixtures/unsupported-resourcetype.ovf")
+	hw := &e.VirtualSystem.VirtualHardware[0]
+	hw.Item = append(hw.Item, hw.Item...)
+	res = e.Validate(ValidateOptions{Strict: true})
+	var paths []string
+	for _, f := range res {
+		if f.Code == FindingUnsupportedItem {
+			paths = append(paths, f.Path)
+		}
+	}
+	assert.Equal(t, []string{
+		"VirtualSystem[my-vm]/VirtualHardwareSection[0]/Item[2]",
+		"VirtualSystem[my-vm]/VirtualHardwareSection[0]/Item[5]",
+	}, paths)
+}
+
+func TestValidateDescriptor(t *testing.T) {
+	const descriptor = `<?xml version="1.0" encoding="UTF-8"?>
+<Envelope xmlns="http://schemas.dmtf.org/ovf/envelope/1" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1"
+          xmlns:rasd="http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_ResourceAllocationSettingData">
+  <References>
+    <File ovf:href="disk1.vmdk" ovf:id="file1"/>
+    <File ovf:href="disk1.vmdk" ovf:id="file1"/>
+    <File ovf:href="extra.iso" ovf:id="file2"/>
+  </References>
+  <DiskSection>
+    <Info>Virtual disk information</Info>
+    <Disk ovf:capacity="30" ovf:capacityAllocationUnits="byte * 2^20" ovf:diskId="vmdisk1" ovf:fileRef="file1"/>
+    <Disk ovf:capacity="ten" ovf:capacityAllocationUnits="parsecs" ovf:diskId="vmdisk2" ovf:fileRef="file3" ovf:parentRef="vmdisk9"/>
+  </DiskSection>
+  <NetworkSection>
+    <Info>The list of logical networks</Info>
+    <Network ovf:name="nat"/>
+  </NetworkSection>
+  <VirtualSystem ovf:id="vm">
+    <Info>A virtual machine</Info>
+    <ProductSection>
+      <Info>Properties</Info>
+      <Property ovf:key="mode" ovf:type="string" ovf:qualifiers="ValueMap{&quot;a&quot;,&quot;b&quot;}" ovf:value="c"/>
+      <Property ovf:key="name" ovf:type="string" ovf:qualifiers="MinLen(1),MaxLen(3)" ovf:value="toolong"/>
+      <Property ovf:key="port" ovf:type="uint8" ovf:value="300"/>
+      <Property ovf:key="addr" ovf:type="ip" ovf:value="10.0.0.1"/>
+      <Property ovf:key="flag" ovf:type="boolean" ovf:value="maybe"/>
+      <Property ovf:key="other" ovf:type="complex"/>
+      <Property ovf:key="bad" ovf:type="string" ovf:qualifiers="MinLen(x)"/>
+    </ProductSection>
+    <VirtualHardwareSection>
+      <Info>Virtual hardware requirements</Info>
+      <Item>
+        <rasd:InstanceID>1</rasd:InstanceID>
+        <rasd:ResourceType>6</rasd:ResourceType>
+      </Item>
+      <Item>
+        <rasd:HostResource>ovf:/disk/vmdisk3</rasd:HostResource>
+        <rasd:InstanceID>2</rasd:InstanceID>
+        <rasd:Parent>4</rasd:Parent>
+        <rasd:ResourceType>17</rasd:ResourceType>
+      </Item>
+      <Item>
+        <rasd:Connection>bridged</rasd:Connection>
+        <rasd:InstanceID>2</rasd:InstanceID>
+        <rasd:ResourceType>10</rasd:ResourceType>
+      </Item>
+    </VirtualHardwareSection>
+  </VirtualSystem>
+</Envelope>`
+
+	res, err := Validate(strings.NewReader(descriptor), ValidateOptions{})
+	require.NoError(t, err)
+
+	expect := map[string][]string{
+		"References/File[1]":                                  {FindingDuplicateID},
+		"References/File[2]":                                  {FindingUnreferencedFile},
+		"DiskSection/Disk[1]":                                 {FindingInvalidReference, FindingInvalidCapacity, FindingInvalidAllocationUnits, FindingInvalidReference},
+		"VirtualSystem[vm]":                                   nil,
+		"VirtualSystem[vm]/VirtualHardwareSection[0]":         {FindingMissingElement},
+		"VirtualSystem[vm]/VirtualHardwareSection[0]/Item[1]": {FindingInvalidReference, FindingInvalidReference},
+		"VirtualSystem[vm]/VirtualHardwareSection[0]/Item[2]": {FindingDuplicateID, FindingInvalidReference},
+		"VirtualSystem[vm]/ProductSection[0]/Property[0]":     {FindingInvalidPropertyValue},
+		"VirtualSystem[vm]/ProductSection[0]/Property[1]":     {FindingInvalidPropertyValue},
+		"VirtualSystem[vm]/ProductSection[0]/Property[2]":     {FindingInvalidPropertyValue},
+		"VirtualSystem[vm]/ProductSection[0]/Property[4]":     {FindingInvalidPropertyValue},
+		"VirtualSystem[vm]/ProductSection[0]/Property[5]":     {FindingInvalidPropertyType},
+		"VirtualSystem[vm]/ProductSection[0]/Property[6]":     {FindingInvalidPropertyQualifiers},
+		"VirtualSystem[vm]/VirtualHardwareSection[0]/Item[0]": nil,
+		"VirtualSystem[vm]/ProductSection[0]/Property[3]":     nil,
+	}
+
+	actual := map[string][]string{}
+	for _, f := range res {
+		actual[f.Path] = append(actual[f.Path], f.Code)
+	}
+
+	for path, codes := range expect {
+		assert.Equal(t, codes, actual[path], path)
+	}
+}
+
+func TestValidateRoot(t *testing.T) {
+	tests := []struct {
+		descriptor string
+		code       string
+	}{
+		{"", FindingInvalidXML},
+		{"<Environment/>", FindingInvalidXML},
+		{`<Envelope xmlns="urn:example"/>`, FindingInvalidNamespace},
+		{`<Envelope xmlns="http://schemas.dmtf.org/ovf/envelope/1"><References>`, FindingInvalidXML},
+	}
+
+	for _, test := range tests {
+		res, err := Validate(strings.NewReader(test.descriptor), ValidateOptions{})
+		require.NoError(t, err)
+		if assert.Len(t, res, 1, test.descriptor) {
+			assert.Equal(t, test.code, res[0].Code, test.descriptor)
+		}
+	}
+}
@@ -0,0 +1,478 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package pbm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/vmware/govmomi/pbm/types"
+)
+
+// Capability namespaces and IDs used by Policy rules.
+const (
+	// NamespaceVSAN is the namespace of vSAN capabilities, such as "hostFailuresToTolerate".
+	NamespaceVSAN = "VSAN"
+	// NamespaceTag is the namespace of tag based placement capabilities, the capability ID is the tag category name.
+	NamespaceTag = "http://www.vmware.com/storage/tag"
+	// NamespaceTopology is the namespace of the storage topology capability.
+	NamespaceTopology = "com.vmware.storage.consumptiondomain"
+	// NamespaceDataService is the namespace of host based data service (IO filter) capabilities.
+	NamespaceDataService = "com.vmware.storageprofile.dataservice"
+
+	// EncryptionCapabilityID is the ID of the default encryption data service capability.
+	EncryptionCapabilityID = "ad5a249d-cbc2-43af-9366-694d7664fa52"
+	// TopologyCapabilityID is the ID of the storage topology capability.
+	TopologyCapabilityID = "StorageTopology"
+)
+
+// Policy is a storage policy document, which can be encoded as YAML or JSON.
+// A Policy describes the rules of a storage profile with a single rule set (sub profile).
+//
+// Example:
+//
+//	name: gold
+//	description: Mirrored, encrypted and placed on gold datastores
+//	vsan:
+//	  hostFailuresToTolerate: 1
+//	  stripeWidth: 2
+//	tags:
+//	  tier: [gold]
+//	encryption: true
+type Policy struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// VSAN rules by capability ID, values are integers, booleans or strings.
+	VSAN map[string]any `json:"vsan,omitempty" yaml:"vsan,omitempty"`
+	// Tags for tag based placement, by category name.
+	Tags map[string][]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Encryption enables the default encryption data service.
+	Encryption bool `json:"encryption,omitempty" yaml:"encryption,omitempty"`
+	// IOFilters are the IDs of host based data services other than encryption.
+	IOFilters []string `json:"ioFilters,omitempty" yaml:"ioFilters,omitempty"`
+	// Zonal enables the Zonal storage topology for multi-zone Supervisor.
+	Zonal bool `json:"zonal,omitempty" yaml:"zonal,omitempty"`
+	// Rules that are not covered by the fields above.
+	Rules []Rule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// Rule is a generic capability rule of a Policy.
+// Property values are integers, booleans, strings or lists of those.
+type Rule struct {
+	Namespace string `json:"namespace" yaml:"namespace"`
+	ID        string `json:"id" yaml:"id"`
+	// Operator applied to each property value, such as "NOT" to exclude datastores with the given tags.
+	// See types.PbmCapabilityOperator.
+	Operator   string         `json:"operator,omitempty" yaml:"operator,omitempty"`
+	Properties map[string]any `json:"properties,omitempty" yaml:"properties,omitempty"`
+}
+
+// PolicyAction is the outcome of Client.ApplyPolicy.
+type PolicyAction string
+
+const (
+	PolicyCreated   = PolicyAction("created")
+	PolicyUpdated   = PolicyAction("updated")
+	PolicyUnchanged = PolicyAction("unchanged")
+)
+
+// NewPolicy converts the given storage profile to a Policy.
+// An error is returned if the profile is not a capability profile, has more than one rule set
+// or contains property values that cannot be represented by a Policy.
+func NewPolicy(profile types.BasePbmProfile) (*Policy, error) {
+	p, ok := profile.(*types.PbmCapabilityProfile)
+	if !ok {
+		return nil, fmt.Errorf("profile %q: unsupported type %T", profile.GetPbmProfile().Name, profile)
+	}
+
+	policy := &Policy{
+		Name:        p.Name,
+		Description: p.Description,
+	}
+
+	c, ok := p.Constraints.(*types.PbmCapabilitySubProfileConstraints)
+	if !ok || len(c.SubProfiles) == 0 {
+		return policy, nil
+	}
+	if n := len(c.SubProfiles); n != 1 {
+		return nil, fmt.Errorf("profile %q: %d rule sets, only 1 is supported", p.Name, n)
+	}
+
+	for _, capability := range c.SubProfiles[0].Capability {
+		if err := policy.addCapability(capability); err != nil {
+			return nil, fmt.Errorf("profile %q: %s", p.Name, err)
+		}
+	}
+
+	return policy, nil
+}
+
+func (p *Policy) addCapability(capability types.PbmCapabilityInstance) error {
+	id := capability.Id
+
+	var props []types.PbmCapabilityPropertyInstance
+	switch len(capability.Constraint) {
+	case 0:
+	case 1:
+		props = capability.Constraint[0].PropertyInstance
+	default:
+		return fmt.Errorf("capability %s.%s: multiple constraints are not supported", id.Namespace, id.Id)
+	}
+
+	values := make(map[string]any, len(props))
+	for _, prop := range props {
+		if prop.Operator != props[0].Operator {
+			return fmt.Errorf("capability %s.%s: mixed operators are not supported", id.Namespace, id.Id)
+		}
+		val, err := policyValue(prop.Value)
+		if err != nil {
+			return fmt.Errorf("capability %s.%s property %s: %s", id.Namespace, id.Id, prop.Id, err)
+		}
+		values[prop.Id] = val
+	}
+
+	single := func(key string) (any, bool) {
+		val, ok := values[key]
+		return val, ok && len(props) == 1 && props[0].Operator == ""
+	}
+
+	switch id.Namespace {
+	case NamespaceVSAN:
+		if val, ok := single(id.Id); ok {
+			if p.VSAN == nil {
+				p.VSAN = make(map[string]any)
+			}
+			p.VSAN[id.Id] = val
+			return nil
+		}
+	case NamespaceTag:
+		if val, ok := single(tagPropertyID(id.Id)); ok {
+			if set, ok := val.([]any); ok {
+				var tags []string
+				for _, tag := range set {
+					if s, ok := tag.(string); ok {
+						tags = append(tags, s)
+					}
+				}
+				if len(tags) == len(set) {
+					if p.Tags == nil {
+						p.Tags = make(map[string][]string)
+					}
+					p.Tags[id.Id] = tags
+					return nil
+				}
+			}
+		}
+	case NamespaceTopology:
+		if val, ok := single("StorageTopologyType"); ok && id.Id == TopologyCapabilityID && val == "Zonal" {
+			p.Zonal = true
+			return nil
+		}
+	case NamespaceDataService:
+		if val, ok := single(id.Id); ok && val == id.Id {
+			if id.Id == EncryptionCapabilityID {
+				p.Encryption = true
+			} else {
+				p.IOFilters = append(p.IOFilters, id.Id)
+			}
+			return nil
+		}
+	}
+
+	rule := Rule{Namespace: id.Namespace, ID: id.Id}
+	if len(props) != 0 {
+		rule.Operator = props[0].Operator
+	}
+	if len(values) != 0 {
+		rule.Properties = values
+	}
+	p.Rules = append(p.Rules, rule)
+
+	return nil
+}
+
+func tagPropertyID(category string) string {
+	return fmt.Sprintf("com.vmware.storage.tag.%s.property", category)
+}
+
+// sortedKeys returns the keys of the given map in sorted order, such that specs are built deterministically.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func capabilityInstance(namespace, id string, props ...types.PbmCapabilityPropertyInstance) types.PbmCapabilityInstance {
+	return types.PbmCapabilityInstance{
+		Id: types.PbmCapabilityMetadataUniqueId{
+			Namespace: namespace,
+			Id:        id,
+		},
+		Constraint: []types.PbmCapabilityConstraintInstance{{
+			PropertyInstance: props,
+		}},
+	}
+}
+
+func (p *Policy) capabilities() ([]types.PbmCapabilityInstance, error) {
+	var caps []types.PbmCapabilityInstance
+
+	for _, id := range sortedKeys(p.VSAN) {
+		val, err := propertyValue(p.VSAN[id])
+		if err != nil {
+			return nil, fmt.Errorf("vsan %s: %s", id, err)
+		}
+		caps = append(caps, capabilityInstance(NamespaceVSAN, id, types.PbmCapabilityPropertyInstance{
+			Id:    id,
+			Value: val,
+		}))
+	}
+
+	for _, category := range sortedKeys(p.Tags) {
+		tags := p.Tags[category]
+		if len(tags) == 0 {
+			return nil, fmt.Errorf("tags %s: no tags specified", category)
+		}
+		set := types.PbmCapabilityDiscreteSet{}
+		for _, tag := range tags {
+			set.Values = append(set.Values, tag)
+		}
+		caps = append(caps, capabilityInstance(NamespaceTag, category, types.PbmCapabilityPropertyInstance{
+			Id:    tagPropertyID(category),
+			Value: set,
+		}))
+	}
+
+	if p.Zonal {
+		caps = append(caps, capabilityInstance(NamespaceTopology, TopologyCapabilityID, types.PbmCapabilityPropertyInstance{
+			Id:    "StorageTopologyType",
+			Value: "Zonal",
+		}))
+	}
+
+	services := p.IOFilters
+	if p.Encryption {
+		services = append([]string{EncryptionCapabilityID}, services...)
+	}
+	for _, id := range services {
+		caps = append(caps, capabilityInstance(NamespaceDataService, id, types.PbmCapabilityPropertyInstance{
+			Id:    id,
+			Value: id,
+		}))
+	}
+
+	for _, rule := range p.Rules {
+		if rule.Operator != "" && len(rule.Properties) == 0 {
+			return nil, fmt.Errorf("rule %s.%s: operator %s requires properties", rule.Namespace, rule.ID, rule.Operator)
+		}
+		var props []types.PbmCapabilityPropertyInstance
+		for _, id := range sortedKeys(rule.Properties) {
+			val, err := propertyValue(rule.Properties[id])
+			if err != nil {
+				return nil, fmt.Errorf("rule %s.%s property %s: %s", rule.Namespace, rule.ID, id, err)
+			}
+			props = append(props, types.PbmCapabilityPropertyInstance{
+				Id:       id,
+				Operator: rule.Operator,
+				Value:    val,
+			})
+		}
+		caps = append(caps, capabilityInstance(rule.Namespace, rule.ID, props...))
+	}
+
+	return caps, nil
+}
+
+// Constraints returns the single rule set storage profile constraints described by the Policy.
+func (p *Policy) Constraints() (*types.PbmCapabilitySubProfileConstraints, error) {
+	caps, err := p.capabilities()
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.PbmCapabilitySubProfileConstraints{
+		SubProfiles: []types.PbmCapabilitySubProfile{{
+			Capability: caps,
+		}},
+	}, nil
+}
+
+// CreateSpec returns the spec used to create a storage profile described by the Policy.
+func (p *Policy) CreateSpec() (*types.PbmCapabilityProfileCreateSpec, error) {
+	constraints, err := p.Constraints()
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.PbmCapabilityProfileCreateSpec{
+		Name:        p.Name,
+		Description: p.Description,
+		Category:    string(types.PbmProfileCategoryEnumREQUIREMENT),
+		ResourceType: types.PbmProfileResourceType{
+			ResourceType: string(types.PbmProfileResourceTypeEnumSTORAGE),
+		},
+		Constraints: constraints,
+	}, nil
+}
+
+// UpdateSpec returns the spec used to update a storage profile to match the Policy.
+// An empty Description leaves the description of the profile unchanged.
+func (p *Policy) UpdateSpec() (*types.PbmCapabilityProfileUpdateSpec, error) {
+	constraints, err := p.Constraints()
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.PbmCapabilityProfileUpdateSpec{
+		Description: p.Description,
+		Constraints: constraints,
+	}, nil
+}
+
+// propertyValue converts a Policy value to a capability property value.
+func propertyValue(val any) (any, error) {
+	switch v := val.(type) {
+	case bool, string:
+		return v, nil
+	case int:
+		if v < math.MinInt32 || v > math.MaxInt32 {
+			return nil, fmt.Errorf("value %d out of range", v)
+		}
+		// Go int32 is marshalled to xsi:int, as expected by pbm
+		return int32(v), nil
+	case int32:
+		return v, nil
+	case float64: // encoding/json
+		if v != math.Trunc(v) {
+			return nil, fmt.Errorf("value %v is not an integer", v)
+		}
+		return propertyValue(int(v))
+	case []string:
+		set := types.PbmCapabilityDiscreteSet{}
+		for _, s := range v {
+			set.Values = append(set.Values, s)
+		}
+		return set, nil
+	case []any:
+		set := types.PbmCapabilityDiscreteSet{}
+		for _, item := range v {
+			item, err := propertyValue(item)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := item.(types.PbmCapabilityDiscreteSet); ok {
+				return nil, errors.New("nested lists are not supported")
+			}
+			set.Values = append(set.Values, item)
+		}
+		return set, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", val)
+	}
+}
+
+// policyValue converts a capability property value to a Policy value.
+func policyValue(val any) (any, error) {
+	switch v := val.(type) {
+	case bool, string:
+		return v, nil
+	case int32:
+		return int(v), nil
+	case int64:
+		return int(v), nil
+	case *types.PbmCapabilityDiscreteSet:
+		return policyValue(*v)
+	case types.PbmCapabilityDiscreteSet:
+		set := []any{}
+		for _, item := range v.Values {
+			item, err := policyValue(item)
+			if err != nil {
+				return nil, err
+			}
+			set = append(set, item)
+		}
+		return set, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", val)
+	}
+}
+
+// normalize returns the Policy as converted from the profile it describes,
+// such that it can be compared with a Policy converted from an existing profile.
+func (p *Policy) normalize() (*Policy, error) {
+	constraints, err := p.Constraints()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPolicy(&types.PbmCapabilityProfile{
+		PbmProfile: types.PbmProfile{
+			Name:        p.Name,
+			Description: p.Description,
+		},
+		Constraints: constraints,
+	})
+}
+
+// ApplyPolicy creates the storage profile described by the given Policy if no profile with the same name exists,
+// otherwise the existing profile is updated if its description or rules differ from the Policy.
+// An empty Policy Description does not change the description of an existing profile.
+func (c *Client) ApplyPolicy(ctx context.Context, p *Policy) (*types.PbmProfileId, PolicyAction, error) {
+	m, err := c.ProfileMap(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	idx := slices.IndexFunc(m.Profile, func(profile types.BasePbmProfile) bool {
+		return profile.GetPbmProfile().Name == p.Name
+	})
+
+	if idx == -1 {
+		spec, err := p.CreateSpec()
+		if err != nil {
+			return nil, "", err
+		}
+		id, err := c.CreateProfile(ctx, *spec)
+		if err != nil {
+			return nil, "", err
+		}
+		return id, PolicyCreated, nil
+	}
+
+	profile := m.Profile[idx]
+	id := profile.GetPbmProfile().ProfileId
+
+	desired, err := p.normalize()
+	if err != nil {
+		return nil, "", err
+	}
+
+	// a profile that cannot be converted to a Policy is replaced by the update
+	current, _ := NewPolicy(profile)
+	if current != nil && p.Description == "" {
+		desired.Description = current.Description
+	}
+	if reflect.DeepEqual(current, desired) {
+		return &id, PolicyUnchanged, nil
+	}
+
+	spec, err := p.UpdateSpec()
+	if err != nil {
+		return nil, "", err
+	}
+	if err = c.UpdateProfile(ctx, id, *spec); err != nil {
+		return nil, "", err
+	}
+
+	return &id, PolicyUpdated, nil
+}
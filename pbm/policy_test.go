@@ -0,0 +1,173 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package pbm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/govmomi/pbm"
+	"github.com/vmware/govmomi/pbm/types"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	vim "github.com/vmware/govmomi/vim25/types"
+
+	_ "github.com/vmware/govmomi/pbm/simulator"
+)
+
+// testPolicies returns the Policy documents used by the tests, as decoded by govc storage.policy.apply.
+func testPolicies() []pbm.Policy {
+	return []pbm.Policy{
+		{
+			Name:        "gold",
+			Description: "Mirrored and encrypted",
+			VSAN: map[string]any{
+				"hostFailuresToTolerate": 1,
+				"forceProvisioning":      false,
+				"replicaPreference":      "RAID-1 (Mirroring) - Performance",
+			},
+			Tags:       map[string][]string{"tier": {"gold", "platinum"}},
+			Encryption: true,
+			IOFilters:  []string{"b7a9d5a2-3f33-4c86-8e3c-4d1bbc2a0d2e"},
+			Zonal:      true,
+			Rules: []pbm.Rule{{
+				Namespace:  "PMem",
+				ID:         "PMemType",
+				Properties: map[string]any{"PMemType": []any{"NVDIMM"}},
+			}},
+		},
+		{
+			Name: "silver",
+			VSAN: map[string]any{"stripeWidth": 2},
+		},
+	}
+}
+
+func TestPolicyRoundTrip(t *testing.T) {
+	for _, p := range testPolicies() {
+		spec, err := p.CreateSpec()
+		require.NoError(t, err)
+
+		profile := &types.PbmCapabilityProfile{
+			PbmProfile: types.PbmProfile{
+				Name:        spec.Name,
+				Description: spec.Description,
+			},
+			Constraints: spec.Constraints,
+		}
+
+		res, err := pbm.NewPolicy(profile)
+		require.NoError(t, err)
+		assert.Equal(t, p, *res)
+	}
+
+	_, err := (&pbm.Policy{Name: "bad", VSAN: map[string]any{"stripeWidth": 1.5}}).CreateSpec()
+	assert.Error(t, err, "not an integer")
+
+	_, err = (&pbm.Policy{Name: "bad", Rules: []pbm.Rule{{Namespace: "PMem", ID: "PMemType", Operator: "NOT"}}}).CreateSpec()
+	assert.Error(t, err, "operator requires properties")
+}
+
+func TestPolicyRoundTripOperator(t *testing.T) {
+	// "do not use datastores tagged gold"
+	profile := &types.PbmCapabilityProfile{
+		PbmProfile: types.PbmProfile{Name: "not-gold"},
+		Constraints: &types.PbmCapabilitySubProfileConstraints{
+			SubProfiles: []types.PbmCapabilitySubProfile{{
+				Capability: []types.PbmCapabilityInstance{{
+					Id: types.PbmCapabilityMetadataUniqueId{
+						Namespace: pbm.NamespaceTag,
+						Id:        "tier",
+					},
+					Constraint: []types.PbmCapabilityConstraintInstance{{
+						PropertyInstance: []types.PbmCapabilityPropertyInstance{{
+							Id:       "com.vmware.storage.tag.tier.property",
+							Operator: string(types.PbmCapabilityOperatorNOT),
+							Value:    types.PbmCapabilityDiscreteSet{Values: []vim.AnyType{"gold"}},
+						}},
+					}},
+				}},
+			}},
+		},
+	}
+
+	p, err := pbm.NewPolicy(profile)
+	require.NoError(t, err)
+	assert.Empty(t, p.Tags, "NOT tag rule must not be converted to a tag placement rule")
+	require.Len(t, p.Rules, 1)
+	assert.Equal(t, "NOT", p.Rules[0].Operator)
+
+	spec, err := p.CreateSpec()
+	require.NoError(t, err)
+	assert.Equal(t, profile.Constraints, spec.Constraints)
+
+	// mixed operators cannot be represented by a Rule
+	c := profile.Constraints.(*types.PbmCapabilitySubProfileConstraints).SubProfiles[0].Capability[0].Constraint
+	c[0].PropertyInstance = append(c[0].PropertyInstance, types.PbmCapabilityPropertyInstance{
+		Id:    "other",
+		Value: "value",
+	})
+	_, err = pbm.NewPolicy(profile)
+	assert.Error(t, err)
+}
+
+func TestApplyPolicy(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		pc, err := pbm.NewClient(ctx, c)
+		require.NoError(t, err)
+
+		gold := &testPolicies()[0]
+
+		id, action, err := pc.ApplyPolicy(ctx, gold)
+		require.NoError(t, err)
+		assert.Equal(t, pbm.PolicyCreated, action)
+
+		id2, action, err := pc.ApplyPolicy(ctx, gold)
+		require.NoError(t, err)
+		assert.Equal(t, pbm.PolicyUnchanged, action)
+		assert.Equal(t, id, id2)
+
+		// a Policy without a description leaves the existing description unchanged
+		gold.Description = ""
+		for range 2 {
+			_, action, err = pc.ApplyPolicy(ctx, gold)
+			require.NoError(t, err)
+			assert.Equal(t, pbm.PolicyUnchanged, action)
+		}
+
+		gold.VSAN["hostFailuresToTolerate"] = 2
+		gold.Zonal = false
+		_, action, err = pc.ApplyPolicy(ctx, gold)
+		require.NoError(t, err)
+		assert.Equal(t, pbm.PolicyUpdated, action)
+
+		profiles, err := pc.RetrieveContent(ctx, []types.PbmProfileId{*id})
+		require.NoError(t, err)
+		res, err := pbm.NewPolicy(profiles[0])
+		require.NoError(t, err)
+		assert.Equal(t, 2, res.VSAN["hostFailuresToTolerate"])
+		assert.False(t, res.Zonal)
+		assert.Equal(t, "Mirrored and encrypted", res.Description)
+
+		// default profiles convert to a Policy and are unchanged when applied
+		m, err := pc.ProfileMap(ctx)
+		require.NoError(t, err)
+		vsan, err := pbm.NewPolicy(m.Name["vSAN Default Storage Policy"])
+		require.NoError(t, err)
+		assert.Equal(t, 1, vsan.VSAN["stripeWidth"])
+
+		_, action, err = pc.ApplyPolicy(ctx, vsan)
+		require.NoError(t, err)
+		assert.Equal(t, pbm.PolicyUnchanged, action)
+
+		enc, err := pbm.NewPolicy(m.Name["VM Encryption Policy"])
+		require.NoError(t, err)
+		assert.True(t, enc.Encryption)
+		assert.Empty(t, enc.Rules)
+	})
+}
@@ -278,6 +278,42 @@ func (m *ProfileManager) PbmCreate(ctx *simulator.Context, req *types.PbmCreate)
 	return body
 }
 
+func (m *ProfileManager) PbmUpdate(ctx *simulator.Context, req *types.PbmUpdate) soap.HasFault {
+	body := new(methods.PbmUpdateBody)
+
+	for i, p := range m.profiles {
+		current, ok := p.(*types.PbmCapabilityProfile)
+		if !ok || current.ProfileId != req.ProfileId {
+			continue
+		}
+
+		// the default profiles are shared by simulator instances
+		profile := *current
+		m.profiles[i] = &profile
+
+		spec := req.UpdateSpec
+		if spec.Name != "" {
+			profile.Name = spec.Name
+		}
+		if spec.Description != "" {
+			profile.Description = spec.Description
+		}
+		if spec.Constraints != nil {
+			profile.Constraints = spec.Constraints
+		}
+		profile.LastUpdatedTime = time.Now()
+		profile.LastUpdatedBy = ctx.Session.UserName
+		profile.GenerationId++
+
+		body.Res = new(types.PbmUpdateResponse)
+		return body
+	}
+
+	body.Fault_ = simulator.Fault("", &vim.InvalidArgument{InvalidProperty: "profileId"})
+
+	return body
+}
+
 func (m *ProfileManager) PbmDelete(req *types.PbmDelete) soap.HasFault {
 	body := new(methods.PbmDeleteBody)
 
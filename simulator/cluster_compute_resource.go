@@ -7,6 +7,7 @@ package simulator
 import (
 	"log"
 	"math/rand"
+	"slices"
 	"sync/atomic"
 	"time"
 
@@ -481,8 +482,8 @@ func (c *ClusterComputeResource) PlaceVm(ctx *Context, req *types.PlaceVm) soap.
 	switch types.PlacementSpecPlacementType(req.PlacementSpec.PlacementType) {
 	case types.PlacementSpecPlacementTypeClone, types.PlacementSpecPlacementTypeCreate:
 		spec := &types.VirtualMachineRelocateSpec{
-			Datastore: &datastores[rand.Intn(len(c.Datastore))],
-			Host:      &hosts[rand.Intn(len(c.Host))],
+			Datastore: &datastores[rand.Intn(len(datastores))],
+			Host:      &hosts[rand.Intn(len(hosts))],
 			Pool:      c.ResourcePool,
 		}
 		res.Action = append(res.Action, &types.PlacementAction{
@@ -521,6 +522,11 @@ func (c *ClusterComputeResource) PlaceVm(ctx *Context, req *types.PlaceVm) soap.
 		// After validating req.PlacementSpec, we must have a valid req.PlacementSpec.Vm.
 		vmObj := ctx.Map.Get(*req.PlacementSpec.Vm).(*VirtualMachine)
 
+		// Choose a host that satisfies compute policies, if not explicitly provided.
+		if !placeVmByPolicy(ctx, &req.PlacementSpec, vmObj, hosts, body) {
+			return body
+		}
+
 		// Populate RelocateSpec's common fields, if not explicitly provided.
 		populateRelocateSpecForPlaceVmRelocate(&req.PlacementSpec.RelocateSpec, vmObj)
 
@@ -545,6 +551,36 @@ func (c *ClusterComputeResource) PlaceVm(ctx *Context, req *types.PlaceVm) soap.
 	return body
 }
 
+// placeVmByPolicy sets spec.RelocateSpec.Host when the VM's current host violates compute policies (see vapi/simulator),
+// choosing the first of the given hosts that satisfies the policies.
+// Returns false if no host satisfies the policies.
+func placeVmByPolicy(ctx *Context, spec *types.PlacementSpec, vmObj *VirtualMachine, hosts []types.ManagedObjectReference, body *methods.PlaceVmBody) bool {
+	if ctx.Map.policyManager == nil {
+		return true
+	}
+
+	if spec.RelocateSpec != nil && spec.RelocateSpec.Host != nil {
+		return true
+	}
+
+	allowed := ctx.Map.policyManager.PlacementHosts(vmObj.Self, hosts)
+	if len(allowed) == 0 {
+		body.Fault_ = Fault("", &types.NoCompatibleHost{})
+		return false
+	}
+
+	if vmObj.Runtime.Host != nil && slices.Contains(allowed, *vmObj.Runtime.Host) {
+		return true
+	}
+
+	if spec.RelocateSpec == nil {
+		spec.RelocateSpec = new(types.VirtualMachineRelocateSpec)
+	}
+	spec.RelocateSpec.Host = &allowed[0]
+
+	return true
+}
+
 // validatePlacementSpecForPlaceVmRelocate validates the fields of req.PlacementSpec for a relocate placement type.
 // Returns true if the fields are valid, false otherwise.
 func validatePlacementSpecForPlaceVmRelocate(ctx *Context, req *types.PlaceVm, body *methods.PlaceVmBody) bool {
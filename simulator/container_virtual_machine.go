@@ -39,11 +39,12 @@ var (
 type simVM struct {
 	vm *VirtualMachine
 	c  *container
+	t  *guestToolbox
 }
 
 // createSimulationVM inspects the provided VirtualMachine and creates a simVM binding for it if
-// the vm.Config.ExtraConfig set contains a key "RUN.container".
-// If the ExtraConfig set does not contain that key, this returns nil.
+// the vm.Config.ExtraConfig set contains a key "RUN.container" or "RUN.toolbox".
+// If the ExtraConfig set does not contain either key, this returns nil.
 // Methods on the simVM type are written to check for nil object so the return from this call can be blindly
 // assigned and invoked without the caller caring about whether a binding for a backing container was warranted.
 func createSimulationVM(vm *VirtualMachine) *simVM {
@@ -53,7 +54,7 @@ func createSimulationVM(vm *VirtualMachine) *simVM {
 
 	for _, opt := range vm.Config.ExtraConfig {
 		val := opt.GetOptionValue()
-		if val.Key == ContainerBackingOptionKey {
+		if val.Key == ContainerBackingOptionKey || val.Key == ToolboxBackingOptionKey {
 			return svm
 		}
 	}
@@ -142,7 +143,7 @@ func (svm *simVM) syncNetworkConfigToVMGuestProperties() error {
 }
 
 func (svm *simVM) prepareGuestOperation(auth types.BaseGuestAuthentication) types.BaseMethodFault {
-	if svm == nil || svm.c == nil || svm.c.id == "" {
+	if svm == nil || (svm.t == nil && (svm.c == nil || svm.c.id == "")) {
 		return new(types.GuestOperationsUnavailable)
 	}
 
@@ -153,6 +154,11 @@ func (svm *simVM) prepareGuestOperation(auth types.BaseGuestAuthentication) type
 		}
 	}
 
+	return guestAuthFault(auth)
+}
+
+// guestAuthFault validates the given guest credentials
+func guestAuthFault(auth types.BaseGuestAuthentication) types.BaseMethodFault {
 	switch creds := auth.(type) {
 	case *types.NamePasswordAuthentication:
 		if creds.Username == "" || creds.Password == "" {
@@ -199,6 +205,10 @@ func (svm *simVM) start(ctx *Context) error {
 		return nil
 	}
 
+	if toolboxEnabled(ctx, svm.vm) {
+		return svm.startToolbox(ctx)
+	}
+
 	if svm.c != nil && svm.c.id != "" {
 		err := svm.c.start(ctx)
 		if err != nil {
@@ -341,9 +351,15 @@ func (svm *simVM) start(ctx *Context) error {
 	return err
 }
 
-// stop the container (if any) for the given vm.
+// stop the container or toolbox (if any) for the given vm.
 func (svm *simVM) stop(ctx *Context) error {
-	if svm == nil || svm.c == nil {
+	if svm == nil {
+		return nil
+	}
+
+	svm.stopToolbox(ctx)
+
+	if svm.c == nil {
 		return nil
 	}
 
@@ -359,9 +375,15 @@ func (svm *simVM) stop(ctx *Context) error {
 	return nil
 }
 
-// pause the container (if any) for the given vm.
+// pause the container or toolbox (if any) for the given vm.
 func (svm *simVM) pause(ctx *Context) error {
-	if svm == nil || svm.c == nil {
+	if svm == nil {
+		return nil
+	}
+
+	svm.stopToolbox(ctx)
+
+	if svm.c == nil {
 		return nil
 	}
 
@@ -377,9 +399,18 @@ func (svm *simVM) pause(ctx *Context) error {
 	return nil
 }
 
-// restart the container (if any) for the given vm.
+// restart the container or toolbox (if any) for the given vm.
 func (svm *simVM) restart(ctx *Context) error {
-	if svm == nil || svm.c == nil {
+	if svm == nil {
+		return nil
+	}
+
+	if svm.t != nil {
+		svm.stopToolbox(ctx)
+		return svm.startToolbox(ctx)
+	}
+
+	if svm.c == nil {
 		return nil
 	}
 
@@ -395,9 +426,19 @@ func (svm *simVM) restart(ctx *Context) error {
 	return nil
 }
 
-// remove the container (if any) for the given vm.
+// remove the container or toolbox (if any) for the given vm.
 func (svm *simVM) remove(ctx *Context) error {
-	if svm == nil || svm.c == nil {
+	if svm == nil {
+		return nil
+	}
+
+	// Called with the Map lock held, vm properties are not updated
+	if svm.t != nil {
+		svm.t.stop()
+		svm.t = nil
+	}
+
+	if svm.c == nil {
 		return nil
 	}
 
@@ -451,7 +492,7 @@ func guestDownload(id string, file string, w http.ResponseWriter) error {
 
 const guestPrefix = "/guestFile/"
 
-// ServeGuest handles container guest file upload/download
+// ServeGuest handles container and toolbox guest file upload/download
 func ServeGuest(w http.ResponseWriter, r *http.Request) {
 	// Real vCenter form: /guestFile?id=139&token=...
 	// vcsim form:        /guestFile/tmp/foo/bar?id=ebc8837b8cb6&token=...
@@ -460,6 +501,10 @@ func ServeGuest(w http.ResponseWriter, r *http.Request) {
 	file := strings.TrimPrefix(r.URL.Path, guestPrefix[:len(guestPrefix)-1])
 	var err error
 
+	if serveToolboxGuest(w, r, id, file) {
+		return
+	}
+
 	switch r.Method {
 	case http.MethodPut:
 		err = guestUpload(id, file, r)
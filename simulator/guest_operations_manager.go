@@ -52,12 +52,19 @@ type GuestFileManager struct {
 }
 
 func guestURL(ctx *Context, vm *VirtualMachine, path string) string {
+	id := ""
+	if t := vm.svm.toolbox(); t != nil {
+		id = t.id
+	} else {
+		id = vm.svm.c.id
+	}
+
 	return (&url.URL{
 		Scheme: ctx.svc.Listen.Scheme,
 		Host:   "*", // See guest.FileManager.TransferURL
 		Path:   guestPrefix + strings.TrimPrefix(path, "/"),
 		RawQuery: url.Values{
-			"id":    []string{vm.svm.c.id},
+			"id":    []string{id},
 			"token": []string{ctx.Session.Key},
 		}.Encode(),
 	}).String()
@@ -73,6 +80,14 @@ func (m *GuestFileManager) InitiateFileTransferToGuest(ctx *Context, req *types.
 		return body
 	}
 
+	if t := vm.svm.toolbox(); t != nil {
+		if fault := t.initiateUpload(ctx, req); fault != nil {
+			body.Fault_ = Fault("", fault)
+			return body
+		}
+		t.transfer(req.Auth, req.GuestFilePath)
+	}
+
 	body.Res = &types.InitiateFileTransferToGuestResponse{
 		Returnval: guestURL(ctx, vm, req.GuestFilePath),
 	}
@@ -90,6 +105,25 @@ func (m *GuestFileManager) InitiateFileTransferFromGuest(ctx *Context, req *type
 		return body
 	}
 
+	if t := vm.svm.toolbox(); t != nil {
+		info, fault := t.stat(ctx, req.Auth, req.GuestFilePath)
+		if fault != nil {
+			body.Fault_ = Fault("", fault)
+			return body
+		}
+		t.transfer(req.Auth, req.GuestFilePath)
+
+		body.Res = &types.InitiateFileTransferFromGuestResponse{
+			Returnval: types.FileTransferInformation{
+				Attributes: info.Attributes,
+				Size:       info.Size,
+				Url:        guestURL(ctx, vm, req.GuestFilePath),
+			},
+		}
+
+		return body
+	}
+
 	body.Res = &types.InitiateFileTransferFromGuestResponse{
 		Returnval: types.FileTransferInformation{
 			Attributes: nil, // TODO
@@ -119,6 +153,24 @@ func (m *GuestProcessManager) StartProgramInGuest(ctx *Context, req *types.Start
 		body.Fault_ = Fault("", fault)
 	}
 
+	if t := vm.svm.toolbox(); t != nil {
+		if fault != nil {
+			return body
+		}
+
+		pid, fault := t.startProgram(ctx, auth, spec)
+		if fault != nil {
+			body.Fault_ = Fault("", fault)
+			return body
+		}
+
+		body.Res = &types.StartProgramInGuestResponse{
+			Returnval: pid,
+		}
+
+		return body
+	}
+
 	args := []string{"exec"}
 
 	if spec.WorkingDirectory != "" {
@@ -159,6 +211,26 @@ func (m *GuestProcessManager) ListProcessesInGuest(ctx *Context, req *types.List
 		Res: new(types.ListProcessesInGuestResponse),
 	}
 
+	vm, ok := ctx.Map.Get(req.Vm).(*VirtualMachine)
+	if !ok {
+		body.Res = nil
+		body.Fault_ = Fault("", &types.ManagedObjectNotFound{Obj: req.Vm})
+		return body
+	}
+
+	if t := vm.svm.toolbox(); t != nil {
+		procs, fault := t.listProcesses(ctx, req.Auth, req.Pids)
+		if fault != nil {
+			body.Res = nil
+			body.Fault_ = Fault("", fault)
+			return body
+		}
+
+		body.Res.Returnval = procs
+
+		return body
+	}
+
 	procs := m.List(req.Pids)
 
 	for _, proc := range procs {
@@ -184,6 +256,22 @@ func (m *GuestProcessManager) ListProcessesInGuest(ctx *Context, req *types.List
 func (m *GuestProcessManager) TerminateProcessInGuest(ctx *Context, req *types.TerminateProcessInGuest) soap.HasFault {
 	body := new(methods.TerminateProcessInGuestBody)
 
+	vm, ok := ctx.Map.Get(req.Vm).(*VirtualMachine)
+	if !ok {
+		body.Fault_ = Fault("", &types.ManagedObjectNotFound{Obj: req.Vm})
+		return body
+	}
+
+	if t := vm.svm.toolbox(); t != nil {
+		if fault := t.terminateProcess(ctx, req.Auth, req.Pid); fault != nil {
+			body.Fault_ = Fault("", fault)
+		} else {
+			body.Res = new(types.TerminateProcessInGuestResponse)
+		}
+
+		return body
+	}
+
 	if m.Kill(req.Pid) {
 		body.Res = new(types.TerminateProcessInGuestResponse)
 	} else {
@@ -201,6 +289,10 @@ func (m *GuestFileManager) mktemp(ctx *Context, req *types.CreateTemporaryFileIn
 
 	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
 
+	if t := vm.svm.toolbox(); t != nil {
+		return t.mktemp(ctx, req, dir)
+	}
+
 	return vm.svm.exec(ctx, req.Auth, args)
 }
 
@@ -286,6 +378,18 @@ func (m *GuestFileManager) ListFilesInGuest(ctx *Context, req *types.ListFilesIn
 		return body
 	}
 
+	if t := vm.svm.toolbox(); t != nil {
+		info, fault := t.listFiles(ctx, req)
+		if fault != nil {
+			body.Fault_ = Fault("", fault)
+			return body
+		}
+
+		body.Res = &types.ListFilesInGuestResponse{Returnval: *info}
+
+		return body
+	}
+
 	res, fault := vm.svm.exec(ctx, req.Auth, listFiles(req))
 	if fault != nil {
 		body.Fault_ = Fault("", fault)
@@ -305,7 +409,12 @@ func (m *GuestFileManager) DeleteFileInGuest(ctx *Context, req *types.DeleteFile
 
 	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
 
-	_, fault := vm.svm.exec(ctx, req.Auth, args)
+	var fault types.BaseMethodFault
+	if t := vm.svm.toolbox(); t != nil {
+		fault = t.deleteFile(ctx, req.Auth, req.FilePath)
+	} else {
+		_, fault = vm.svm.exec(ctx, req.Auth, args)
+	}
 	if fault != nil {
 		body.Fault_ = Fault("", fault)
 		return body
@@ -326,7 +435,12 @@ func (m *GuestFileManager) DeleteDirectoryInGuest(ctx *Context, req *types.Delet
 
 	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
 
-	_, fault := vm.svm.exec(ctx, req.Auth, args)
+	var fault types.BaseMethodFault
+	if t := vm.svm.toolbox(); t != nil {
+		fault = t.deleteDirectory(ctx, req.Auth, req.DirectoryPath, req.Recursive)
+	} else {
+		_, fault = vm.svm.exec(ctx, req.Auth, args)
+	}
 	if fault != nil {
 		body.Fault_ = Fault("", fault)
 		return body
@@ -347,7 +461,12 @@ func (m *GuestFileManager) MakeDirectoryInGuest(ctx *Context, req *types.MakeDir
 
 	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
 
-	_, fault := vm.svm.exec(ctx, req.Auth, args)
+	var fault types.BaseMethodFault
+	if t := vm.svm.toolbox(); t != nil {
+		fault = t.makeDirectory(ctx, req.Auth, req.DirectoryPath, req.CreateParentDirectories)
+	} else {
+		_, fault = vm.svm.exec(ctx, req.Auth, args)
+	}
 	if fault != nil {
 		body.Fault_ = Fault("", fault)
 		return body
@@ -369,7 +488,12 @@ func (m *GuestFileManager) MoveFileInGuest(ctx *Context, req *types.MoveFileInGu
 
 	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
 
-	_, fault := vm.svm.exec(ctx, req.Auth, args)
+	var fault types.BaseMethodFault
+	if t := vm.svm.toolbox(); t != nil {
+		fault = t.move(ctx, req.Auth, vix.CommandMoveGuestFileEx, req.SrcFilePath, req.DstFilePath, req.Overwrite)
+	} else {
+		_, fault = vm.svm.exec(ctx, req.Auth, args)
+	}
 	if fault != nil {
 		body.Fault_ = Fault("", fault)
 		return body
@@ -387,7 +511,12 @@ func (m *GuestFileManager) MoveDirectoryInGuest(ctx *Context, req *types.MoveDir
 
 	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
 
-	_, fault := vm.svm.exec(ctx, req.Auth, args)
+	var fault types.BaseMethodFault
+	if t := vm.svm.toolbox(); t != nil {
+		fault = t.move(ctx, req.Auth, vix.CommandMoveGuestDirectory, req.SrcDirectoryPath, req.DstDirectoryPath, false)
+	} else {
+		_, fault = vm.svm.exec(ctx, req.Auth, args)
+	}
 	if fault != nil {
 		body.Fault_ = Fault("", fault)
 		return body
@@ -409,6 +538,16 @@ func (m *GuestFileManager) ChangeFileAttributesInGuest(ctx *Context, req *types.
 		return body
 	}
 
+	if t := vm.svm.toolbox(); t != nil {
+		if fault := t.changeFileAttributes(ctx, req, attr); fault != nil {
+			body.Fault_ = Fault("", fault)
+		} else {
+			body.Res = new(types.ChangeFileAttributesInGuestResponse)
+		}
+
+		return body
+	}
+
 	if attr.Permissions != 0 {
 		args := []string{"chmod", fmt.Sprintf("%#o", attr.Permissions), req.GuestFilePath}
 
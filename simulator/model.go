@@ -131,6 +131,12 @@ type Model struct {
 	// Delay configurations
 	DelayConfig DelayConfig `json:"-"`
 
+	// ToolboxRoot enables the RUN.toolbox VirtualMachine backing, see ToolboxBackingOptionKey.
+	// Guest file and process operations are confined to this directory of the vcsim host.
+	// The backing is disabled when empty.
+	// vcsim flag: -toolbox-root
+	ToolboxRoot string `json:"-"`
+
 	// total number of inventory objects, set by Count()
 	total int
 
@@ -465,6 +471,8 @@ func (m *Model) Load(dir string) error {
 	}
 
 	m.Service = New(ctx, s)
+	ctx.Map.toolboxRoot = m.ToolboxRoot
+	ctx.Map.modelDir = dir
 
 	return m.resolveReferences(ctx)
 }
@@ -473,6 +481,7 @@ func (m *Model) Load(dir string) error {
 func (m *Model) Create() error {
 	ctx := NewContext()
 	m.Service = New(NewServiceInstance(ctx, m.ServiceContent, m.RootFolder))
+	ctx.Map.toolboxRoot = m.ToolboxRoot
 	if err := m.createRootTempDir(ctx.Map.OptionManager()); err != nil {
 		return err
 	}
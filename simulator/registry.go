@@ -72,7 +72,11 @@ type Registry struct {
 	Handler   func(*Context, *Method) (mo.Reference, types.BaseMethodFault)
 	Cookie    func(*Context) string
 
-	tagManager tagManager
+	tagManager    tagManager
+	policyManager policyManager
+
+	toolboxRoot string // see Model.ToolboxRoot
+	modelDir    string // see Model.Load
 }
 
 // tagManager is an interface to simplify internal interaction with the vapi tag manager simulator.
@@ -83,6 +87,12 @@ type tagManager interface {
 	DetachTag(types.ManagedObjectReference, types.VslmTagEntry) types.BaseMethodFault
 }
 
+// policyManager is an interface to simplify internal interaction with the vapi compute policy simulator.
+type policyManager interface {
+	// PlacementHosts returns the hosts where the given VM can be placed without violating compute policies.
+	PlacementHosts(vm types.ManagedObjectReference, hosts []types.ManagedObjectReference) []types.ManagedObjectReference
+}
+
 // NewRegistry creates a new instances of Registry
 func NewRegistry() *Registry {
 	r := &Registry{
@@ -572,6 +582,12 @@ func (r *Registry) SessionManager() *SessionManager {
 	return r.Get(r.content().SessionManager.Reference()).(*SessionManager)
 }
 
+// ModelDir returns the directory this Registry's model was loaded from, if any.
+// SDK handlers use it to load their own state (see vapi/simulator).
+func (r *Registry) ModelDir() string {
+	return r.modelDir
+}
+
 // OptionManager returns the OptionManager singleton
 func (r *Registry) OptionManager() *OptionManager {
 	return r.Get(r.content().Setting.Reference()).(*OptionManager)
@@ -479,6 +479,9 @@ func (s *Service) Handle(pattern string, handler http.Handler) {
 	if m, ok := handler.(tagManager); ok {
 		s.sdk[vim25.Path].tagManager = m
 	}
+	if m, ok := handler.(policyManager); ok {
+		s.sdk[vim25.Path].policyManager = m
+	}
 }
 
 type muxHandleFunc interface {
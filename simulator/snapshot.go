@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"regexp"
+	"strings"
 
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/methods"
@@ -61,8 +63,6 @@ func (v *VirtualMachineSnapshot) createSnapshotFiles(ctx *Context) types.BaseMet
 }
 
 func (v *VirtualMachineSnapshot) removeSnapshotFiles(ctx *Context) types.BaseMethodFault {
-	// TODO: also remove delta disks that were created when snapshot was taken
-
 	vm := ctx.Map.Get(v.Vm).(*VirtualMachine)
 
 	for idx, sLayout := range vm.Layout.Snapshot {
@@ -102,8 +102,17 @@ func (v *VirtualMachineSnapshot) RemoveSnapshotTask(ctx *Context, req *types.Rem
 	task := CreateTask(v.Vm, "removeSnapshot", func(t *Task) (types.AnyType, types.BaseMethodFault) {
 		var changes []types.PropertyChange
 
+		var removed []*VirtualMachineSnapshot
+
 		vm := ctx.Map.Get(v.Vm).(*VirtualMachine)
 		ctx.WithLock(vm, func() {
+			refs := []types.ManagedObjectReference{req.This}
+			if req.RemoveChildren {
+				if ss := findSnapshotInTree(vm.Snapshot.RootSnapshotList, req.This); ss != nil {
+					refs = append(refs, allSnapshotsInTree(ss.ChildSnapshotList)...)
+				}
+			}
+
 			if vm.Snapshot.CurrentSnapshot != nil && *vm.Snapshot.CurrentSnapshot == req.This {
 				parent := findParentSnapshotInTree(vm.Snapshot.RootSnapshotList, req.This)
 				changes = append(changes, types.PropertyChange{Name: "snapshot.currentSnapshot", Val: parent})
@@ -125,12 +134,22 @@ func (v *VirtualMachineSnapshot) RemoveSnapshotTask(ctx *Context, req *types.Rem
 				}
 			}
 
-			ctx.Map.Get(req.This).(*VirtualMachineSnapshot).removeSnapshotFiles(ctx)
+			for _, ref := range refs {
+				snapshot := ctx.Map.Get(ref).(*VirtualMachineSnapshot)
+				snapshot.removeSnapshotFiles(ctx)
+				removed = append(removed, snapshot)
+			}
 
 			ctx.Update(vm, changes)
 		})
 
-		ctx.Map.Remove(ctx, req.This)
+		for _, snapshot := range removed {
+			ctx.Map.Remove(ctx, snapshot.Self)
+		}
+
+		ctx.WithLock(vm, func() {
+			vm.consolidate(ctx, removed...)
+		})
 
 		return nil, nil
 	})
@@ -146,14 +165,17 @@ func (v *VirtualMachineSnapshot) RevertToSnapshotTask(ctx *Context, req *types.R
 	task := CreateTask(v.Vm, "revertToSnapshot", func(t *Task) (types.AnyType, types.BaseMethodFault) {
 		vm := ctx.Map.Get(v.Vm).(*VirtualMachine)
 
+		var fault types.BaseMethodFault
+
 		ctx.WithLock(vm, func() {
 			vm.DataSets = copyDataSetsForVmClone(v.DataSets)
+			fault = vm.revertDeltaDisks(ctx, v)
 			ctx.Update(vm, []types.PropertyChange{
 				{Name: "snapshot.currentSnapshot", Val: v.Self},
 			})
 		})
 
-		return nil, nil
+		return nil, fault
 	})
 
 	return &methods.RevertToSnapshot_TaskBody{
@@ -218,3 +240,320 @@ func (v *VirtualMachineSnapshot) ExportSnapshot(ctx *Context, req *types.ExportS
 		},
 	}
 }
+
+// deltaVmdkSuffix matches the suffix of a delta disk name generated by deltaVmdkName.
+var deltaVmdkSuffix = regexp.MustCompile(`-\d{6}\.vmdk$`)
+
+// deltaVmdkName returns the name of a delta disk for the given parent disk, such as "vm-000001.vmdk".
+// When the parent is itself a delta disk, its suffix is replaced, such as "vm-000002.vmdk".
+func deltaVmdkName(parent *types.VirtualDiskFlatVer2BackingInfo, index int) string {
+	var p object.DatastorePath
+	p.FromString(parent.FileName)
+	name := path.Base(p.Path)
+	if parent.Parent != nil {
+		name = deltaVmdkSuffix.ReplaceAllString(name, ".vmdk")
+	}
+	return fmt.Sprintf("%s-%06d.vmdk", strings.TrimSuffix(name, ".vmdk"), index)
+}
+
+// genDeltaVmdkPath returns the path of a new delta disk in the same directory as the parent disk.
+func (vm *VirtualMachine) genDeltaVmdkPath(ctx *Context, parent *types.VirtualDiskFlatVer2BackingInfo) (string, types.BaseMethodFault) {
+	var p object.DatastorePath
+	p.FromString(parent.FileName)
+	p.Path = path.Dir(p.Path)
+	dir := p.String()
+
+	for index := 1; ; index++ {
+		filename := deltaVmdkName(parent, index)
+
+		f, err := vm.createFile(ctx, dir, filename, false)
+		if err != nil {
+			if _, ok := err.(*types.FileAlreadyExists); ok {
+				continue
+			}
+			return "", err
+		}
+
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+
+		return path.Join(dir, filename), nil
+	}
+}
+
+// snapshotDisks returns the disks of the given devices that can have a delta disk chain.
+// Independent disks are not affected by snapshots.
+func snapshotDisks(devices []types.BaseVirtualDevice) []*types.VirtualDisk {
+	var disks []*types.VirtualDisk
+
+	for _, device := range devices {
+		disk, ok := device.(*types.VirtualDisk)
+		if !ok {
+			continue
+		}
+		backing, ok := disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo)
+		if !ok || strings.HasPrefix(backing.DiskMode, "independent") {
+			continue
+		}
+		disks = append(disks, disk)
+	}
+
+	return disks
+}
+
+// createDeltaDisk creates a delta disk with the given parent, returning the backing of the new disk.
+func (vm *VirtualMachine) createDeltaDisk(ctx *Context, dc *Datacenter, parent *types.VirtualDiskFlatVer2BackingInfo) (*types.VirtualDiskFlatVer2BackingInfo, types.BaseMethodFault) {
+	name, fault := vm.genDeltaVmdkPath(ctx, parent)
+	if fault != nil {
+		return nil, fault
+	}
+
+	op := types.VirtualDeviceConfigSpecFileOperationCreate
+	if fault = vdmCreateChildDisk(ctx, op, &dc.Self, name, parent.FileName); fault != nil {
+		return nil, fault
+	}
+
+	child := *parent
+	child.FileName = name
+	child.Parent = parent
+	child.DeltaDiskFormat = string(types.VirtualDiskDeltaDiskFormatRedoLogFormat)
+
+	return &child, nil
+}
+
+// createDeltaDisks creates a delta disk for each of the VM's disks when a snapshot is taken,
+// such that the current disk files become the read-only state of the snapshot.
+func (vm *VirtualMachine) createDeltaDisks(ctx *Context) types.BaseMethodFault {
+	dc := ctx.Map.getEntityDatacenter(vm)
+
+	for _, disk := range snapshotDisks(vm.Config.Hardware.Device) {
+		backing, fault := vm.createDeltaDisk(ctx, dc, disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo))
+		if fault != nil {
+			return fault
+		}
+
+		disk.Backing = backing
+	}
+
+	return vm.updateDiskLayouts(ctx)
+}
+
+// revertDeltaDisks discards the current delta disks and creates new delta disks
+// with the disk files of the given snapshot as their parent.
+func (vm *VirtualMachine) revertDeltaDisks(ctx *Context, snapshot *VirtualMachineSnapshot) types.BaseMethodFault {
+	dc := ctx.Map.getEntityDatacenter(vm)
+	state := object.VirtualDeviceList(snapshot.Config.Hardware.Device)
+	frozen := vm.snapshotDiskFiles(ctx)
+
+	for _, disk := range snapshotDisks(vm.Config.Hardware.Device) {
+		d, ok := state.FindByKey(disk.Key).(*types.VirtualDisk)
+		if !ok {
+			continue // disk was added after the snapshot was taken
+		}
+
+		var parent types.VirtualDiskFlatVer2BackingInfo
+		deepCopy(d.Backing, &parent)
+
+		backing, fault := vm.createDeltaDisk(ctx, dc, &parent)
+		if fault != nil {
+			return fault
+		}
+
+		current := disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo)
+		disk.Backing = backing
+
+		if !frozen[current.FileName] && vm.isDeltaDisk(ctx, current) {
+			if fault = vdmDeleteVirtualDisk(ctx, &dc.Self, current.FileName); fault != nil {
+				return fault
+			}
+		}
+	}
+
+	vm.RefreshStorageInfo(ctx, nil)
+
+	return vm.updateDiskLayouts(ctx)
+}
+
+// snapshotConfigs returns the config of each of the VM's snapshots.
+func (vm *VirtualMachine) snapshotConfigs(ctx *Context) []*types.VirtualMachineConfigInfo {
+	var configs []*types.VirtualMachineConfigInfo
+
+	if vm.Snapshot != nil {
+		for _, ref := range allSnapshotsInTree(vm.Snapshot.RootSnapshotList) {
+			if snapshot, ok := ctx.Map.Get(ref).(*VirtualMachineSnapshot); ok {
+				configs = append(configs, &snapshot.Config)
+			}
+		}
+	}
+
+	return configs
+}
+
+// walkDiskChains calls fn for each backing in the delta disk chain of each disk,
+// along with the backing of its child, which is nil for the disk backing itself.
+func walkDiskChains(configs []*types.VirtualMachineConfigInfo, fn func(disk *types.VirtualDisk, child, backing *types.VirtualDiskFlatVer2BackingInfo)) {
+	for _, config := range configs {
+		for _, disk := range snapshotDisks(config.Hardware.Device) {
+			var child *types.VirtualDiskFlatVer2BackingInfo
+			for backing := disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo); backing != nil; backing = backing.Parent {
+				fn(disk, child, backing)
+				child = backing
+			}
+		}
+	}
+}
+
+// snapshotDiskFiles returns the set of disk files that are the state of a snapshot.
+func (vm *VirtualMachine) snapshotDiskFiles(ctx *Context) map[string]bool {
+	files := make(map[string]bool)
+
+	walkDiskChains(vm.snapshotConfigs(ctx), func(_ *types.VirtualDisk, child, backing *types.VirtualDiskFlatVer2BackingInfo) {
+		if child == nil {
+			files[backing.FileName] = true
+		}
+	})
+
+	return files
+}
+
+// isDeltaDisk returns true if the given backing is a delta disk created by a snapshot
+// or linked clone of this VM, that is not shared with other VMs.
+func (vm *VirtualMachine) isDeltaDisk(ctx *Context, backing *types.VirtualDiskFlatVer2BackingInfo) bool {
+	if backing.Parent == nil {
+		return false
+	}
+
+	return !vm.isSharedDisk(ctx, backing.FileName)
+}
+
+// isSharedDisk returns true if the given disk file is used by another VM,
+// such as the parent disk of a linked clone.
+func (vm *VirtualMachine) isSharedDisk(ctx *Context, name string) bool {
+	for _, obj := range ctx.Map.All("VirtualMachine") {
+		other := obj.(*VirtualMachine)
+		if other.Self == vm.Self || other.Config == nil {
+			continue
+		}
+
+		shared := false
+		configs := append([]*types.VirtualMachineConfigInfo{other.Config}, other.snapshotConfigs(ctx)...)
+		walkDiskChains(configs, func(_ *types.VirtualDisk, _, backing *types.VirtualDiskFlatVer2BackingInfo) {
+			shared = shared || backing.FileName == name
+		})
+		if shared {
+			return true
+		}
+	}
+
+	return false
+}
+
+// consolidate calls consolidateDisks and updates runtime.consolidationNeeded,
+// as consolidation failure does not fail snapshot removal.
+func (vm *VirtualMachine) consolidate(ctx *Context, removed ...*VirtualMachineSnapshot) types.BaseMethodFault {
+	fault := vm.consolidateDisks(ctx, removed...)
+
+	needed := fault != nil
+	ctx.Update(vm, []types.PropertyChange{
+		{Name: "runtime.consolidationNeeded", Val: needed},
+		{Name: "summary.runtime.consolidationNeeded", Val: needed},
+	})
+
+	return fault
+}
+
+// consolidateDisks removes the delta disks that are no longer needed once the given snapshots are removed.
+// Delta disks only referenced by the removed snapshots are deleted and a delta disk is merged into its parent,
+// when the parent is not the state of a remaining snapshot or the parent of any other disk.
+func (vm *VirtualMachine) consolidateDisks(ctx *Context, removed ...*VirtualMachineSnapshot) types.BaseMethodFault {
+	dc := ctx.Map.getEntityDatacenter(vm)
+	configs := append([]*types.VirtualMachineConfigInfo{vm.Config}, vm.snapshotConfigs(ctx)...)
+
+	inuse := make(map[string]bool)
+	walkDiskChains(configs, func(_ *types.VirtualDisk, _, backing *types.VirtualDiskFlatVer2BackingInfo) {
+		inuse[backing.FileName] = true
+	})
+
+	var fault types.BaseMethodFault
+
+	for _, snapshot := range removed {
+		walkDiskChains([]*types.VirtualMachineConfigInfo{&snapshot.Config}, func(_ *types.VirtualDisk, _, backing *types.VirtualDiskFlatVer2BackingInfo) {
+			if fault != nil || inuse[backing.FileName] || !vm.isDeltaDisk(ctx, backing) {
+				return
+			}
+			inuse[backing.FileName] = true // delete once
+			fault = vdmDeleteVirtualDisk(ctx, &dc.Self, backing.FileName)
+		})
+	}
+
+	for fault == nil {
+		frozen := vm.snapshotDiskFiles(ctx)
+		children := make(map[string]map[string]bool)
+
+		walkDiskChains(configs, func(_ *types.VirtualDisk, child, backing *types.VirtualDiskFlatVer2BackingInfo) {
+			if child != nil {
+				if children[backing.FileName] == nil {
+					children[backing.FileName] = make(map[string]bool)
+				}
+				children[backing.FileName][child.FileName] = true
+			}
+		})
+
+		var delta *types.VirtualDiskFlatVer2BackingInfo
+		walkDiskChains(configs, func(_ *types.VirtualDisk, child, backing *types.VirtualDiskFlatVer2BackingInfo) {
+			if delta != nil || child == nil || frozen[backing.FileName] || len(children[backing.FileName]) != 1 {
+				return
+			}
+			if vm.isDeltaDisk(ctx, child) && !vm.isSharedDisk(ctx, backing.FileName) {
+				delta = child
+			}
+		})
+
+		if delta == nil {
+			break
+		}
+
+		fault = vm.mergeDeltaDisk(ctx, dc, configs, delta)
+	}
+
+	vm.RefreshStorageInfo(ctx, nil)
+
+	if err := vm.updateDiskLayouts(ctx); fault == nil {
+		fault = err
+	}
+
+	return fault
+}
+
+// mergeDeltaDisk merges the given delta disk into its parent, updating any backing and
+// delta disk descriptor that refers to it. Disk content is not tracked by the simulator,
+// so the delta disk files are simply removed.
+func (vm *VirtualMachine) mergeDeltaDisk(ctx *Context, dc *Datacenter, configs []*types.VirtualMachineConfigInfo, delta *types.VirtualDiskFlatVer2BackingInfo) types.BaseMethodFault {
+	name, parent := delta.FileName, delta.Parent
+
+	if fault := vdmDeleteVirtualDisk(ctx, &dc.Self, name); fault != nil {
+		return fault
+	}
+
+	reparent := make(map[string]bool)
+
+	walkDiskChains(configs, func(disk *types.VirtualDisk, child, backing *types.VirtualDiskFlatVer2BackingInfo) {
+		if backing.FileName != name {
+			return
+		}
+		if child == nil {
+			disk.Backing = backing.Parent
+		} else {
+			child.Parent = backing.Parent
+			reparent[child.FileName] = true
+		}
+	})
+
+	for child := range reparent {
+		if fault := vdmReparentVirtualDisk(ctx, &dc.Self, child, parent.FileName); fault != nil {
+			return fault
+		}
+	}
+
+	return nil
+}
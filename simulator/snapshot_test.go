@@ -0,0 +1,176 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/govmomi/vmdk"
+)
+
+func TestSnapshotDeltaDisks(t *testing.T) {
+	m := ESX()
+
+	m.Run(func(ctx context.Context, c *vim25.Client) error {
+		sctx := m.Service.Context
+		fm := m.Map().FileManager()
+
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "*VM0")
+		require.NoError(t, err)
+
+		backing := func(vm *object.VirtualMachine) *types.VirtualDiskFlatVer2BackingInfo {
+			devices, err := vm.Device(ctx)
+			require.NoError(t, err)
+			disks := devices.SelectByType((*types.VirtualDisk)(nil))
+			require.Len(t, disks, 1)
+			return disks[0].GetVirtualDevice().Backing.(*types.VirtualDiskFlatVer2BackingInfo)
+		}
+
+		descriptor := func(name string) (*vmdk.Descriptor, string) {
+			desc, file, fault := fm.DiskDescriptor(sctx, nil, name)
+			require.Nil(t, fault, name)
+			return desc, file
+		}
+
+		exists := func(name string) bool {
+			file, fault := fm.resolve(sctx, nil, name)
+			require.Nil(t, fault)
+			_, err := os.Stat(file)
+			return err == nil
+		}
+
+		consolidationNeeded := func(vm *object.VirtualMachine) bool {
+			var props mo.VirtualMachine
+			require.NoError(t, vm.Properties(ctx, vm.Reference(), []string{"runtime.consolidationNeeded"}, &props))
+			require.NotNil(t, props.Runtime.ConsolidationNeeded)
+			return *props.Runtime.ConsolidationNeeded
+		}
+
+		snapshot := func(name string) {
+			task, err := vm.CreateSnapshot(ctx, name, "", false, false)
+			require.NoError(t, err)
+			require.NoError(t, task.Wait(ctx))
+		}
+
+		base := backing(vm)
+		assert.Nil(t, base.Parent)
+		assert.False(t, consolidationNeeded(vm))
+
+		snapshot("s1")
+		d1 := backing(vm)
+		assert.Equal(t, "disk1-000001.vmdk", path.Base(d1.FileName))
+		require.NotNil(t, d1.Parent)
+		assert.Equal(t, base.FileName, d1.Parent.FileName)
+		assert.True(t, exists(deltaDiskBackingFileName(d1.FileName)))
+
+		desc, _ := descriptor(d1.FileName)
+		pdesc, _ := descriptor(base.FileName)
+		assert.Equal(t, "vmfsSparse", desc.Type)
+		assert.Equal(t, "disk1.vmdk", desc.ParentFileNameHint)
+		assert.Equal(t, pdesc.CID, desc.ParentCID)
+		assert.Equal(t, pdesc.Capacity(), desc.Capacity())
+
+		snapshot("s2")
+		d2 := backing(vm)
+		assert.Equal(t, "disk1-000002.vmdk", path.Base(d2.FileName))
+		assert.Equal(t, d1.FileName, d2.Parent.FileName)
+		assert.Equal(t, base.FileName, d2.Parent.Parent.FileName)
+
+		// Removing s1 merges the first delta into the base disk
+		task, err := vm.RemoveSnapshot(ctx, "s1", false, nil)
+		require.NoError(t, err)
+		require.NoError(t, task.Wait(ctx))
+
+		assert.False(t, exists(d1.FileName))
+		assert.False(t, exists(deltaDiskBackingFileName(d1.FileName)))
+		assert.False(t, consolidationNeeded(vm))
+
+		current := backing(vm)
+		assert.Equal(t, d2.FileName, current.FileName)
+		assert.Equal(t, base.FileName, current.Parent.FileName)
+		assert.Nil(t, current.Parent.Parent)
+		desc, _ = descriptor(d2.FileName)
+		assert.Equal(t, "disk1.vmdk", desc.ParentFileNameHint)
+
+		s2, err := vm.FindSnapshot(ctx, "s2")
+		require.NoError(t, err)
+		ss := m.Map().Get(*s2).(*VirtualMachineSnapshot)
+		state := snapshotDisks(ss.Config.Hardware.Device)[0].Backing.(*types.VirtualDiskFlatVer2BackingInfo)
+		assert.Equal(t, base.FileName, state.FileName)
+
+		// Linked clone from s2 shares the base disk
+		folder, err := find.NewFinder(c).DefaultFolder(ctx)
+		require.NoError(t, err)
+		task, err = vm.Clone(ctx, folder, "linked", types.VirtualMachineCloneSpec{
+			Location: types.VirtualMachineRelocateSpec{
+				DiskMoveType: string(types.VirtualMachineRelocateDiskMoveOptionsCreateNewChildDiskBacking),
+			},
+			Snapshot: s2,
+		})
+		require.NoError(t, err)
+		info, err := task.WaitForResult(ctx)
+		require.NoError(t, err)
+
+		clone := object.NewVirtualMachine(c, info.Result.(types.ManagedObjectReference))
+		linked := backing(clone)
+		assert.Equal(t, "[LocalDS_0] linked/disk1-000001.vmdk", linked.FileName)
+		require.NotNil(t, linked.Parent)
+		assert.Equal(t, base.FileName, linked.Parent.FileName)
+
+		desc, _ = descriptor(linked.FileName)
+		_, pfile := descriptor(base.FileName)
+		assert.Equal(t, pfile, desc.ParentFileNameHint)
+
+		// Revert discards the current delta
+		task, err = vm.RevertToSnapshot(ctx, "s2", true)
+		require.NoError(t, err)
+		require.NoError(t, task.Wait(ctx))
+
+		reverted := backing(vm)
+		assert.NotEqual(t, d2.FileName, reverted.FileName)
+		assert.Equal(t, base.FileName, reverted.Parent.FileName)
+		assert.False(t, exists(d2.FileName))
+
+		// The base disk is shared with the linked clone, so it remains the parent
+		task, err = vm.RemoveAllSnapshot(ctx, nil)
+		require.NoError(t, err)
+		require.NoError(t, task.Wait(ctx))
+
+		current = backing(vm)
+		assert.Equal(t, reverted.FileName, current.FileName)
+		assert.Equal(t, base.FileName, current.Parent.FileName)
+		assert.False(t, consolidationNeeded(vm))
+
+		// Once the linked clone is destroyed, the delta can be consolidated
+		task, err = clone.Destroy(ctx)
+		require.NoError(t, err)
+		require.NoError(t, task.Wait(ctx))
+		assert.False(t, exists(linked.FileName))
+		assert.True(t, exists(base.FileName))
+
+		res, err := methods.ConsolidateVMDisks_Task(ctx, c, &types.ConsolidateVMDisks_Task{This: vm.Reference()})
+		require.NoError(t, err)
+		require.NoError(t, object.NewTask(c, res.Returnval).Wait(ctx))
+
+		current = backing(vm)
+		assert.Equal(t, base.FileName, current.FileName)
+		assert.Nil(t, current.Parent)
+		assert.False(t, exists(reverted.FileName))
+
+		return nil
+	})
+}
@@ -0,0 +1,700 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"encoding"
+	"encoding/xml"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/toolbox"
+	"github.com/vmware/govmomi/toolbox/hgfs"
+	"github.com/vmware/govmomi/toolbox/process"
+	"github.com/vmware/govmomi/toolbox/vix"
+	"github.com/vmware/govmomi/toolbox/vmx"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// ToolboxBackingOptionKey enables an in-process toolbox.Service as the guest tools of a VM,
+// when the VirtualMachine ExtraConfig value is "true".
+// The toolbox runs on the vcsim host and is connected to the VM via an emulated VMX,
+// serving GuestOperationsManager requests, ShutdownGuest, RebootGuest and reporting guest.net and guest.disk.
+// RUN.container takes precedence if both are set.
+//
+// As the toolbox runs with the privileges of vcsim, the backing must be enabled by the operator via
+// Model.ToolboxRoot (vcsim flag -toolbox-root), otherwise the option is ignored.
+// Guest file paths, program paths and working directories are resolved within that root directory,
+// program names without a path are resolved within its "/bin" directory.
+// Program arguments and environment variables are passed as-is and programs are not otherwise sandboxed,
+// only programs the operator places within the root can be started.
+const ToolboxBackingOptionKey = "RUN.toolbox"
+
+var (
+	// toolboxTimeout is the maximum time to wait for a reply from the toolbox
+	toolboxTimeout = time.Minute
+
+	// guestToolboxes maps guestToolbox.id to *guestToolbox, for use by ServeGuest
+	guestToolboxes sync.Map
+)
+
+// guestToolbox binds a VM to a toolbox.Service instance via the vmx emulator
+type guestToolbox struct {
+	id      string
+	root    string
+	vmx     *vmx.VMX
+	service *toolbox.Service
+
+	mu        sync.Mutex
+	transfers map[string]*vix.UserCredentialNamePassword
+}
+
+// toolboxEnabled returns true if Model.ToolboxRoot is set and the given vm has
+// ExtraConfig "RUN.toolbox" = "true" and no "RUN.container"
+func toolboxEnabled(ctx *Context, vm *VirtualMachine) bool {
+	if ctx.Map.toolboxRoot == "" {
+		return false
+	}
+
+	enabled := false
+
+	for _, opt := range vm.Config.ExtraConfig {
+		val := opt.GetOptionValue()
+		switch val.Key {
+		case ContainerBackingOptionKey:
+			return false
+		case ToolboxBackingOptionKey:
+			s, _ := val.Value.(string)
+			enabled, _ = strconv.ParseBool(s)
+		}
+	}
+
+	return enabled
+}
+
+// toolbox returns the guestToolbox if the vm is backed by a running toolbox, otherwise nil
+func (svm *simVM) toolbox() *guestToolbox {
+	if svm == nil {
+		return nil
+	}
+	return svm.t
+}
+
+// startToolbox starts a toolbox.Service connected to a new vmx emulator, as the guest tools would be started at guest boot.
+// The vmx sends the tools reset sequence, after which the guest reported nic and disk info is applied to vm.Guest.
+func (svm *simVM) startToolbox(ctx *Context) error {
+	if svm.t != nil {
+		return nil
+	}
+
+	t := &guestToolbox{
+		id:        "toolbox-" + svm.vm.uid.String(),
+		root:      ctx.Map.toolboxRoot,
+		vmx:       vmx.New(),
+		transfers: make(map[string]*vix.UserCredentialNamePassword),
+	}
+
+	// default directory of CreateTemporaryFileInGuest and CreateTemporaryDirectoryInGuest
+	if err := os.MkdirAll(t.path("/tmp"), 0700); err != nil {
+		return err
+	}
+
+	for _, opt := range svm.vm.Config.ExtraConfig {
+		val := opt.GetOptionValue()
+		if s, ok := val.Value.(string); ok && strings.HasPrefix(val.Key, "guestinfo.") {
+			t.vmx.SetVar(val.Key, s)
+		}
+	}
+
+	t.service = t.vmx.NewService()
+	// The simulator applies the power state change, the guest is not actually halted or rebooted
+	noop := func() error { return nil }
+	t.service.Power.Halt.Handler = noop
+	t.service.Power.Reboot.Handler = noop
+	t.service.Command.ProcessStartCommand = t.startCommand
+
+	if err := t.service.Start(); err != nil {
+		return err
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, toolboxTimeout)
+	defer cancel()
+
+	if err := t.vmx.Reset(tctx); err != nil {
+		t.service.Stop()
+		t.service.Wait()
+		return err
+	}
+
+	svm.t = t
+	guestToolboxes.Store(t.id, t)
+
+	ctx.Update(svm.vm, append(toolsRunning, svm.toolboxGuestInfo()...))
+
+	return nil
+}
+
+// stopToolbox stops the toolbox.Service, as the guest tools would be stopped at guest shutdown.
+func (svm *simVM) stopToolbox(ctx *Context) {
+	if svm.t == nil {
+		return
+	}
+
+	svm.t.stop()
+	svm.t = nil
+
+	ctx.Update(svm.vm, toolsNotRunning)
+}
+
+// stop the toolbox service, without updating vm properties.
+func (t *guestToolbox) stop() {
+	guestToolboxes.Delete(t.id)
+	t.service.Stop()
+	t.service.Wait()
+}
+
+// path maps the given guest path to a path within the toolbox root directory.
+// Process i/o paths, such as "/proc:/123/stdout", are not mapped.
+func (t *guestToolbox) path(name string) string {
+	if strings.HasPrefix(name, "/proc:/") {
+		return name
+	}
+
+	scheme := "/" + hgfs.ArchiveScheme + ":"
+	if strings.HasPrefix(name, scheme+"/") {
+		return scheme + t.path(strings.TrimPrefix(name, scheme))
+	}
+
+	return filepath.Join(t.root, filepath.FromSlash(path.Clean("/"+name)))
+}
+
+// guestPath maps the given path within the toolbox root directory to a guest path.
+func (t *guestToolbox) guestPath(name string) string {
+	rel, err := filepath.Rel(t.root, name)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return name
+	}
+	return path.Join("/", filepath.ToSlash(rel))
+}
+
+// startCommand is the toolbox ProcessStartCommand, starting programs within the toolbox root directory.
+// As with toolbox.DefaultStartCommand, i/o redirection is enabled for program names without a path.
+func (t *guestToolbox) startCommand(m *process.Manager, r *vix.StartProgramRequest) (int64, error) {
+	p := process.New()
+
+	name := r.ProgramPath
+	if !strings.Contains(name, "/") {
+		p = p.WithIO()
+		name = path.Join("/bin", name)
+	}
+
+	r.ProgramPath = t.path(name)
+	r.WorkingDir = t.path(r.WorkingDir)
+
+	return m.Start(r, p)
+}
+
+// shutdown sends OS_Halt to the toolbox (if any) for the given vm.
+func (svm *simVM) shutdown(ctx *Context) error {
+	t := svm.toolbox()
+	if t == nil {
+		return nil
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, toolboxTimeout)
+	defer cancel()
+
+	return t.vmx.Halt(tctx)
+}
+
+// reboot sends OS_Reboot to the toolbox (if any) for the given vm.
+func (svm *simVM) reboot(ctx *Context) error {
+	t := svm.toolbox()
+	if t == nil {
+		return nil
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, toolboxTimeout)
+	defer cancel()
+
+	return t.vmx.Reboot(tctx)
+}
+
+// toolboxGuestInfo applies the guest reported nic and disk info to vm.Guest, returning the property changes.
+// Guest nics are mapped to vm.Guest.Net by index, retaining the VM's virtual ethernet card MAC addresses.
+func (svm *simVM) toolboxGuestInfo() []types.PropertyChange {
+	vm := svm.vm
+	var changes []types.PropertyChange
+
+	if ip, ok := svm.t.vmx.Var("ip"); ok {
+		vm.Guest.IpAddress = ip
+		changes = append(changes,
+			types.PropertyChange{Name: "guest.ipAddress", Val: ip},
+			types.PropertyChange{Name: "summary.guest.ipAddress", Val: ip},
+		)
+	}
+
+	if info := svm.t.vmx.NicInfo(); info != nil && info.V3 != nil {
+		for i, nic := range info.V3.Nics {
+			if i >= len(vm.Guest.Net) {
+				break
+			}
+
+			net := &vm.Guest.Net[i]
+			net.IpAddress = nil
+			net.IpConfig = new(types.NetIpConfigInfo)
+
+			for _, addr := range nic.IPs {
+				ip := guestIP(addr.Address)
+				if ip == "" {
+					continue
+				}
+				net.IpAddress = append(net.IpAddress, ip)
+				net.IpConfig.IpAddress = append(net.IpConfig.IpAddress, types.NetIpConfigInfoIpAddress{
+					IpAddress:    ip,
+					PrefixLength: int32(addr.PrefixLength),
+					State:        string(types.NetIpConfigInfoIpAddressStatusPreferred),
+				})
+			}
+		}
+
+		changes = append(changes, types.PropertyChange{Name: "guest.net", Val: vm.Guest.Net})
+	}
+
+	vm.Guest.Disk = nil
+	for _, disk := range svm.t.vmx.Disks() {
+		vm.Guest.Disk = append(vm.Guest.Disk, types.GuestDiskInfo{
+			DiskPath:       disk.Name,
+			Capacity:       int64(disk.Total),
+			FreeSpace:      int64(disk.Free),
+			FilesystemType: disk.FSType,
+		})
+	}
+	changes = append(changes, types.PropertyChange{Name: "guest.disk", Val: vm.Guest.Disk})
+
+	return changes
+}
+
+func guestIP(addr toolbox.TypedIPAddress) string {
+	switch len(addr.Address) {
+	case net.IPv4len, net.IPv6len:
+		return net.IP(addr.Address).String()
+	}
+	return ""
+}
+
+// vixAuth converts the given guest authentication to vix credentials
+func vixAuth(auth types.BaseGuestAuthentication) *vix.UserCredentialNamePassword {
+	creds := new(vix.UserCredentialNamePassword)
+	if a, ok := auth.(*types.NamePasswordAuthentication); ok {
+		creds.Name = a.Username
+		creds.Password = a.Password
+	}
+	return creds
+}
+
+// vixFault maps a vix.Error returned by the toolbox to a MethodFault
+func vixFault(err error, name string) types.BaseMethodFault {
+	var code vix.Error
+	if !errors.As(err, &code) {
+		return &types.GuestOperationsFault{
+			VimFault: types.VimFault{MethodFault: types.MethodFault{
+				FaultCause: &types.LocalizedMethodFault{
+					Fault:            &types.SystemErrorFault{Reason: err.Error()},
+					LocalizedMessage: err.Error()}}}}
+	}
+
+	switch int(code) {
+	case vix.FileNotFound:
+		return &types.FileNotFound{FileFault: types.FileFault{File: name}}
+	case vix.FileAlreadyExists:
+		return &types.FileAlreadyExists{FileFault: types.FileFault{File: name}}
+	case vix.NotADirectory:
+		return &types.NotADirectory{FileFault: types.FileFault{File: name}}
+	case vix.NotAFile:
+		return &types.NotAFile{FileFault: types.FileFault{File: name}}
+	case vix.FileAccessError:
+		return &types.GuestPermissionDenied{}
+	case vix.AuthenticationFail:
+		return new(types.InvalidGuestLogin)
+	case vix.NoSuchProcess:
+		return new(types.GuestProcessNotFound)
+	}
+
+	return new(types.GuestOperationsFault)
+}
+
+// vix sends the given command to the toolbox, mapping any vix error to a MethodFault
+func (t *guestToolbox) vix(ctx *Context, auth types.BaseGuestAuthentication, op uint32, name string, r encoding.BinaryMarshaler) (string, types.BaseMethodFault) {
+	if fault := guestAuthFault(auth); fault != nil {
+		return "", fault
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, toolboxTimeout)
+	defer cancel()
+
+	res, err := t.vmx.Vix(tctx, vixAuth(auth), op, r)
+	if err != nil {
+		return "", vixFault(err, name)
+	}
+
+	return string(res), nil
+}
+
+func (t *guestToolbox) startProgram(ctx *Context, auth types.BaseGuestAuthentication, spec *types.GuestProgramSpec) (int64, types.BaseMethodFault) {
+	r := &vix.StartProgramRequest{
+		ProgramPath: spec.ProgramPath,
+		Arguments:   spec.Arguments,
+		WorkingDir:  spec.WorkingDirectory,
+		EnvVars:     spec.EnvVariables,
+	}
+
+	res, fault := t.vix(ctx, auth, vix.CommandStartProgram, spec.ProgramPath, r)
+	if fault != nil {
+		return -1, fault
+	}
+
+	pid, err := strconv.ParseInt(res, 10, 64)
+	if err != nil {
+		return -1, vixFault(err, spec.ProgramPath)
+	}
+
+	return pid, nil
+}
+
+// guestProcessXML is the vix ListProcessesEx response format, as encoded by process.State
+type guestProcessXML struct {
+	Name      string `xml:"cmd"`
+	CmdLine   string `xml:"name"`
+	Pid       int64  `xml:"pid"`
+	Owner     string `xml:"user"`
+	StartTime int64  `xml:"start"`
+	ExitCode  int32  `xml:"eCode"`
+	EndTime   int64  `xml:"eTime"`
+}
+
+func (t *guestToolbox) listProcesses(ctx *Context, auth types.BaseGuestAuthentication, pids []int64) ([]types.GuestProcessInfo, types.BaseMethodFault) {
+	res, fault := t.vix(ctx, auth, vix.CommandListProcessesEx, "", &vix.ListProcessesRequest{Pids: pids})
+	if fault != nil {
+		return nil, fault
+	}
+
+	var list struct {
+		Procs []guestProcessXML `xml:"proc"`
+	}
+
+	if err := xml.Unmarshal([]byte("<procs>"+res+"</procs>"), &list); err != nil {
+		return nil, vixFault(err, "")
+	}
+
+	var procs []types.GuestProcessInfo
+
+	for _, p := range list.Procs {
+		name := t.guestPath(p.Name)
+		if strings.HasPrefix(p.CmdLine, p.Name) {
+			p.CmdLine = name + strings.TrimPrefix(p.CmdLine, p.Name)
+		}
+		p.Name = name
+
+		var end *time.Time
+		if p.EndTime != 0 {
+			end = types.NewTime(time.Unix(p.EndTime, 0))
+		}
+
+		procs = append(procs, types.GuestProcessInfo{
+			Name:      p.Name,
+			Pid:       p.Pid,
+			Owner:     p.Owner,
+			CmdLine:   p.CmdLine,
+			StartTime: time.Unix(p.StartTime, 0),
+			EndTime:   end,
+			ExitCode:  p.ExitCode,
+		})
+	}
+
+	return procs, nil
+}
+
+func (t *guestToolbox) terminateProcess(ctx *Context, auth types.BaseGuestAuthentication, pid int64) types.BaseMethodFault {
+	r := new(vix.KillProcessRequest)
+	r.Body.Pid = pid
+
+	_, fault := t.vix(ctx, auth, vix.CommandTerminateProcess, "", r)
+	if _, ok := fault.(*types.GuestProcessNotFound); ok {
+		return &types.GuestProcessNotFound{Pid: pid}
+	}
+
+	return fault
+}
+
+func (t *guestToolbox) mktemp(ctx *Context, req *types.CreateTemporaryFileInGuest, dir bool) (string, types.BaseMethodFault) {
+	op := uint32(vix.CommandCreateTemporaryFileEx)
+	if dir {
+		op = vix.CommandCreateTemporaryDirectory
+	}
+
+	tmp := req.DirectoryPath
+	if tmp == "" {
+		tmp = "/tmp"
+	}
+
+	r := &vix.CreateTempFileRequest{
+		FilePrefix:    req.Prefix,
+		FileSuffix:    req.Suffix,
+		DirectoryPath: t.path(tmp),
+	}
+
+	name, fault := t.vix(ctx, req.Auth, op, req.DirectoryPath, r)
+	if fault != nil {
+		return "", fault
+	}
+
+	return t.guestPath(name), nil
+}
+
+// guestFileXML is the vix ListFiles response format, as encoded by toolbox fileExtendedInfoFormat
+type guestFileXML struct {
+	Name  string `xml:"Name"`
+	Type  int    `xml:"ft"`
+	Size  int64  `xml:"fs"`
+	Mtime int64  `xml:"mt"`
+	Atime int64  `xml:"at"`
+	UID   int32  `xml:"uid"`
+	GID   int32  `xml:"gid"`
+	Perm  int64  `xml:"perm"`
+	Link  string `xml:"slt"`
+}
+
+func (f *guestFileXML) info() types.GuestFileInfo {
+	attr := &types.GuestPosixFileAttributes{
+		OwnerId:     types.NewInt32(f.UID),
+		GroupId:     types.NewInt32(f.GID),
+		Permissions: f.Perm,
+	}
+	attr.ModificationTime = types.NewTime(time.Unix(f.Mtime, 0))
+	attr.AccessTime = types.NewTime(time.Unix(f.Atime, 0))
+	attr.SymlinkTarget = f.Link
+
+	info := types.GuestFileInfo{
+		Path:       f.Name,
+		Size:       f.Size,
+		Type:       string(types.GuestFileTypeFile),
+		Attributes: attr,
+	}
+
+	switch {
+	case f.Type&vix.FileAttributesSymlink != 0:
+		info.Type = string(types.GuestFileTypeSymlink)
+	case f.Type&vix.FileAttributesDirectory != 0:
+		info.Type = string(types.GuestFileTypeDirectory)
+	}
+
+	return info
+}
+
+func (t *guestToolbox) listFiles(ctx *Context, req *types.ListFilesInGuest) (*types.GuestListFileInfo, types.BaseMethodFault) {
+	r := &vix.ListFilesRequest{
+		GuestPathName: t.path(req.FilePath),
+		Pattern:       req.MatchPattern,
+	}
+	r.Body.Index = req.Index
+	r.Body.MaxResults = req.MaxResults
+
+	res, fault := t.vix(ctx, req.Auth, vix.CommandListFiles, req.FilePath, r)
+	if fault != nil {
+		return nil, fault
+	}
+
+	var list struct {
+		Remaining int32          `xml:"rem"`
+		Files     []guestFileXML `xml:"fxi"`
+	}
+
+	if err := xml.Unmarshal([]byte("<files>"+res+"</files>"), &list); err != nil {
+		return nil, vixFault(err, req.FilePath)
+	}
+
+	info := &types.GuestListFileInfo{Remaining: list.Remaining}
+	for i := range list.Files {
+		if list.Files[i].Link != "" {
+			list.Files[i].Link = t.guestPath(list.Files[i].Link)
+		}
+		info.Files = append(info.Files, list.Files[i].info())
+	}
+
+	return info, nil
+}
+
+// stat returns the GuestFileInfo for the given file
+func (t *guestToolbox) stat(ctx *Context, auth types.BaseGuestAuthentication, name string) (*types.GuestFileInfo, types.BaseMethodFault) {
+	res, fault := t.vix(ctx, auth, vix.CommandInitiateFileTransferFromGuest, name, &vix.ListFilesRequest{GuestPathName: t.path(name)})
+	if fault != nil {
+		return nil, fault
+	}
+
+	var file guestFileXML
+	if err := xml.Unmarshal([]byte(res), &file); err != nil {
+		return nil, vixFault(err, name)
+	}
+
+	info := file.info()
+	return &info, nil
+}
+
+func (t *guestToolbox) deleteFile(ctx *Context, auth types.BaseGuestAuthentication, name string) types.BaseMethodFault {
+	_, fault := t.vix(ctx, auth, vix.CommandDeleteGuestFileEx, name, &vix.FileRequest{GuestPathName: t.path(name)})
+	return fault
+}
+
+func (t *guestToolbox) deleteDirectory(ctx *Context, auth types.BaseGuestAuthentication, name string, recursive bool) types.BaseMethodFault {
+	r := &vix.DirRequest{GuestPathName: t.path(name)}
+	r.Body.Recursive = recursive
+
+	_, fault := t.vix(ctx, auth, vix.CommandDeleteGuestDirectoryEx, name, r)
+	return fault
+}
+
+func (t *guestToolbox) makeDirectory(ctx *Context, auth types.BaseGuestAuthentication, name string, parents bool) types.BaseMethodFault {
+	r := &vix.DirRequest{GuestPathName: t.path(name)}
+	r.Body.Recursive = parents
+
+	_, fault := t.vix(ctx, auth, vix.CommandCreateDirectoryEx, name, r)
+	return fault
+}
+
+func (t *guestToolbox) move(ctx *Context, auth types.BaseGuestAuthentication, op uint32, src, dst string, overwrite bool) types.BaseMethodFault {
+	r := &vix.RenameFileRequest{OldPathName: t.path(src), NewPathName: t.path(dst)}
+	r.Body.Overwrite = overwrite
+
+	_, fault := t.vix(ctx, auth, op, src, r)
+	return fault
+}
+
+func (t *guestToolbox) changeFileAttributes(ctx *Context, req *types.ChangeFileAttributesInGuest, attr *types.GuestPosixFileAttributes) types.BaseMethodFault {
+	r := &vix.SetGuestFileAttributesRequest{GuestPathName: t.path(req.GuestFilePath)}
+
+	if attr.Permissions != 0 {
+		r.Body.FileOptions |= vix.FileAttributeSetUnixPermissions
+		r.Body.Permissions = int32(attr.Permissions)
+	}
+	if attr.OwnerId != nil {
+		r.Body.FileOptions |= vix.FileAttributeSetUnixOwnerid
+		r.Body.OwnerID = *attr.OwnerId
+	}
+	if attr.GroupId != nil {
+		r.Body.FileOptions |= vix.FileAttributeSetUnixGroupid
+		r.Body.GroupID = *attr.GroupId
+	}
+	if attr.ModificationTime != nil {
+		r.Body.FileOptions |= vix.FileAttributeSetModifyDate
+		r.Body.ModificationTime = attr.ModificationTime.Unix()
+	}
+	if attr.AccessTime != nil {
+		r.Body.FileOptions |= vix.FileAttributeSetAccessDate
+		r.Body.AccessTime = attr.AccessTime.Unix()
+	}
+
+	_, fault := t.vix(ctx, req.Auth, vix.CommandSetGuestFileAttributes, req.GuestFilePath, r)
+	return fault
+}
+
+// initiateUpload checks the given file can be written
+func (t *guestToolbox) initiateUpload(ctx *Context, req *types.InitiateFileTransferToGuest) types.BaseMethodFault {
+	r := &vix.InitiateFileTransferToGuestRequest{GuestPathName: t.path(req.GuestFilePath)}
+	r.Body.Overwrite = req.Overwrite
+
+	_, fault := t.vix(ctx, req.Auth, vix.CommandInitiateFileTransferToGuest, req.GuestFilePath, r)
+	return fault
+}
+
+// transfer records the credentials for a file transfer, used by ServeGuest
+func (t *guestToolbox) transfer(auth types.BaseGuestAuthentication, name string) {
+	t.mu.Lock()
+	t.transfers[name] = vixAuth(auth)
+	t.mu.Unlock()
+}
+
+// transferAuth returns and removes the credentials recorded for a file transfer
+func (t *guestToolbox) transferAuth(name string) (*vix.UserCredentialNamePassword, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	creds, ok := t.transfers[name]
+	delete(t.transfers, name)
+	return creds, ok
+}
+
+// serve handles guest file upload/download via the toolbox hgfs server
+func (t *guestToolbox) serve(w http.ResponseWriter, r *http.Request, name string) error {
+	creds, ok := t.transferAuth(name)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), toolboxTimeout)
+	defer cancel()
+
+	switch r.Method {
+	case http.MethodPut:
+		defer r.Body.Close()
+		return t.vmx.Upload(ctx, creds, t.path(name), r.Body)
+	case http.MethodGet:
+		// Buffer the download, such that an error status can be returned if the transfer fails
+		f, err := os.CreateTemp("", "vcsim-guest-")
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}()
+
+		if err = t.vmx.Download(ctx, creds, t.path(name), f); err != nil {
+			return err
+		}
+
+		size, err := f.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		if _, err = f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+		_, err = io.Copy(w, f)
+		return err
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return nil
+	}
+}
+
+// serveToolboxGuest returns true if id refers to a toolbox backed VM, in which case the request is handled by its toolbox.
+func serveToolboxGuest(w http.ResponseWriter, r *http.Request, id string, file string) bool {
+	val, ok := guestToolboxes.Load(id)
+	if !ok {
+		return false
+	}
+
+	if err := val.(*guestToolbox).serve(w, r, file); err != nil {
+		log.Printf("%s %s: %s", r.Method, r.URL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	return true
+}
@@ -0,0 +1,198 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/govmomi/fault"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/guest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestToolboxBacking(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a posix guest")
+	}
+
+	// Guest operations are confined to root, which provides the only program
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "bin"), 0700))
+	require.NoError(t, os.Symlink("/bin/sh", filepath.Join(root, "bin", "sh")))
+
+	model := VPX()
+	model.ToolboxRoot = root
+
+	Test(func(ctx context.Context, c *vim25.Client) {
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		require.NoError(t, err)
+
+		auth := &types.NamePasswordAuthentication{Username: "user", Password: "pass"}
+		m := guest.NewOperationsManager(c, vm.Reference())
+
+		fm, err := m.FileManager(ctx)
+		require.NoError(t, err)
+
+		pm, err := m.ProcessManager(ctx)
+		require.NoError(t, err)
+
+		dir := "/guest"
+
+		task, err := vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{
+			ExtraConfig: []types.BaseOptionValue{
+				&types.OptionValue{Key: ToolboxBackingOptionKey, Value: "true"},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, task.Wait(ctx))
+
+		var props mo.VirtualMachine
+		require.NoError(t, vm.Properties(ctx, vm.Reference(), []string{"guest"}, &props))
+		assert.Equal(t, string(types.VirtualMachineToolsRunningStatusGuestToolsRunning), props.Guest.ToolsRunningStatus)
+
+		require.NoError(t, fm.MakeDirectory(ctx, auth, dir, false))
+		assert.DirExists(t, filepath.Join(root, dir))
+
+		require.NoError(t, fm.MakeDirectory(ctx, auth, "/../escape", false))
+		assert.DirExists(t, filepath.Join(root, "escape"))
+
+		sub := filepath.Join(dir, "sub")
+		require.NoError(t, fm.MakeDirectory(ctx, auth, sub, false))
+
+		err = fm.MakeDirectory(ctx, auth, sub, false)
+		require.True(t, fault.Is(err, &types.FileAlreadyExists{}), err)
+
+		err = fm.MakeDirectory(ctx, &types.NamePasswordAuthentication{}, sub, false)
+		require.True(t, fault.Is(err, &types.InvalidGuestLogin{}), err)
+
+		files, err := fm.ListFiles(ctx, auth, dir, 0, 0, "")
+		require.NoError(t, err)
+		require.Len(t, files.Files, 1)
+		assert.Equal(t, "sub", files.Files[0].Path)
+		assert.Equal(t, string(types.GuestFileTypeDirectory), files.Files[0].Type)
+
+		name := filepath.Join(sub, "file.txt")
+		content := []byte("hello toolbox\n")
+
+		turl, err := fm.InitiateFileTransferToGuest(ctx, auth, name, new(types.GuestPosixFileAttributes), int64(len(content)), false)
+		require.NoError(t, err)
+
+		u := transferURL(t, c, turl)
+		p := soap.DefaultUpload
+		p.ContentLength = int64(len(content))
+		require.NoError(t, c.Client.Upload(ctx, bytes.NewReader(content), u, &p))
+
+		b, err := os.ReadFile(filepath.Join(root, name))
+		require.NoError(t, err)
+		assert.Equal(t, content, b)
+
+		_, err = fm.InitiateFileTransferToGuest(ctx, auth, name, new(types.GuestPosixFileAttributes), 0, false)
+		require.True(t, fault.Is(err, &types.FileAlreadyExists{}), err)
+
+		info, err := fm.InitiateFileTransferFromGuest(ctx, auth, name)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(content)), info.Size)
+
+		f, n, err := c.Client.Download(ctx, transferURL(t, c, info.Url), &soap.DefaultDownload)
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		_, err = buf.ReadFrom(f)
+		require.NoError(t, err)
+		_ = f.Close()
+		assert.Equal(t, int64(len(content)), n)
+		assert.Equal(t, content, buf.Bytes())
+
+		moved := filepath.Join(dir, "moved.txt")
+		require.NoError(t, fm.MoveFile(ctx, auth, name, moved, false))
+		require.NoError(t, fm.DeleteFile(ctx, auth, moved))
+
+		err = fm.DeleteFile(ctx, auth, moved)
+		require.True(t, fault.Is(err, &types.FileNotFound{}), err)
+
+		tmp, err := fm.CreateTemporaryDirectory(ctx, auth, "vcsim", "", dir)
+		require.NoError(t, err)
+		assert.Equal(t, dir, filepath.Dir(tmp))
+		require.NoError(t, fm.DeleteDirectory(ctx, auth, tmp, false))
+		require.NoError(t, fm.DeleteDirectory(ctx, auth, sub, true))
+
+		pid, err := pm.StartProgram(ctx, auth, &types.GuestProgramSpec{ProgramPath: "/bin/sh", Arguments: "-c true"})
+		require.NoError(t, err)
+
+		for {
+			procs, err := pm.ListProcesses(ctx, auth, []int64{pid})
+			require.NoError(t, err)
+			require.Len(t, procs, 1)
+			if procs[0].EndTime != nil {
+				assert.Equal(t, int32(0), procs[0].ExitCode)
+				assert.Equal(t, "sh", procs[0].Name)
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		_, err = pm.StartProgram(ctx, auth, &types.GuestProgramSpec{ProgramPath: "/usr/bin/env"})
+		require.Error(t, err) // not within root
+
+		err = pm.TerminateProcess(ctx, auth, -1)
+		require.True(t, fault.Is(err, &types.GuestProcessNotFound{}), err)
+
+		require.NoError(t, vm.RebootGuest(ctx))
+		require.NoError(t, fm.MakeDirectory(ctx, auth, sub, false))
+
+		require.NoError(t, vm.ShutdownGuest(ctx))
+		require.NoError(t, vm.WaitForPowerState(ctx, types.VirtualMachinePowerStatePoweredOff))
+
+		require.NoError(t, vm.Properties(ctx, vm.Reference(), []string{"guest"}, &props))
+		assert.Equal(t, string(types.VirtualMachineToolsRunningStatusGuestToolsNotRunning), props.Guest.ToolsRunningStatus)
+
+		// Power on starts the toolbox
+		task, err = vm.PowerOn(ctx)
+		require.NoError(t, err)
+		require.NoError(t, task.Wait(ctx))
+
+		require.NoError(t, fm.DeleteDirectory(ctx, auth, sub, false))
+	}, model)
+}
+
+func TestToolboxBackingDisabled(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		require.NoError(t, err)
+
+		task, err := vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{
+			ExtraConfig: []types.BaseOptionValue{
+				&types.OptionValue{Key: ToolboxBackingOptionKey, Value: "true"},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, task.Wait(ctx))
+
+		// Model.ToolboxRoot is not set
+		var props mo.VirtualMachine
+		require.NoError(t, vm.Properties(ctx, vm.Reference(), []string{"guest"}, &props))
+		assert.NotEqual(t, string(types.VirtualMachineToolsRunningStatusGuestToolsRunning), props.Guest.ToolsRunningStatus)
+	})
+}
+
+func transferURL(t *testing.T, c *vim25.Client, s string) *url.URL {
+	u, err := url.Parse(s)
+	require.NoError(t, err)
+	u.Host = c.URL().Host
+	return u
+}
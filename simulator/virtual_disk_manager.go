@@ -7,6 +7,7 @@ package simulator
 import (
 	"errors"
 	"fmt"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"strings"
@@ -31,34 +32,63 @@ func VirtualDiskBackingFileName(name string) string {
 	return strings.Replace(name, ".vmdk", "-flat.vmdk", 1)
 }
 
-func vdmNames(name string) []string {
-	return []string{
-		VirtualDiskBackingFileName(name),
-		name,
-	}
+// deltaDiskBackingFileName returns the name of the extent file of a delta disk, such as "vm-000001-delta.vmdk".
+func deltaDiskBackingFileName(name string) string {
+	return strings.TrimSuffix(name, ".vmdk") + "-delta.vmdk"
 }
 
-func vdmCreateVirtualDisk(ctx *Context, op types.VirtualDeviceConfigSpecFileOperation, req *types.CreateVirtualDisk_Task) types.BaseMethodFault {
-	fm := ctx.Map.FileManager()
+// vdmIsDelta returns true if the disk named name is a delta disk, as indicated by the
+// parentFileNameHint of its descriptor. Ordinary disks are not named by a convention that
+// distinguishes them from delta disks, such as "vm-000001.vmdk".
+func vdmIsDelta(ctx *Context, dc *types.ManagedObjectReference, name string) bool {
+	desc, _, fault := ctx.Map.FileManager().DiskDescriptor(ctx, dc, name)
+	return fault == nil && desc.ParentFileNameHint != ""
+}
 
-	file, fault := fm.resolve(ctx, req.Datacenter, req.Name)
-	if fault != nil {
-		return fault
+// vdmNames returns the names of the extent and descriptor files of the disk named name.
+func vdmNames(name string, delta bool) []string {
+	backing := VirtualDiskBackingFileName(name)
+	if delta {
+		backing = deltaDiskBackingFileName(name)
 	}
 
+	return []string{
+		backing,
+		name,
+	}
+}
+
+// vdmExists checks the state of the disk descriptor file against the given file operation,
+// returning true if the file exists and should be used as-is.
+func vdmExists(fm *FileManager, op types.VirtualDeviceConfigSpecFileOperation, file string) (bool, types.BaseMethodFault) {
 	shouldReplace := op == types.VirtualDeviceConfigSpecFileOperationReplace
 	shouldExist := op == ""
 
 	_, err := os.Stat(file)
 	if err == nil {
 		if shouldExist {
-			return nil
+			return true, nil
 		}
 		if !shouldReplace {
-			return fm.fault(file, nil, new(types.FileAlreadyExists))
+			return false, fm.fault(file, nil, new(types.FileAlreadyExists))
 		}
 	} else if shouldExist {
-		return fm.fault(file, nil, new(types.FileNotFound))
+		return false, fm.fault(file, nil, new(types.FileNotFound))
+	}
+
+	return false, nil
+}
+
+func vdmCreateVirtualDisk(ctx *Context, op types.VirtualDeviceConfigSpecFileOperation, req *types.CreateVirtualDisk_Task) types.BaseMethodFault {
+	fm := ctx.Map.FileManager()
+
+	file, fault := fm.resolve(ctx, req.Datacenter, req.Name)
+	if fault != nil {
+		return fault
+	}
+
+	if exists, fault := vdmExists(fm, op, file); exists || fault != nil {
+		return fault
 	}
 
 	backing := VirtualDiskBackingFileName(file)
@@ -98,6 +128,95 @@ func vdmCreateVirtualDisk(ctx *Context, op types.VirtualDeviceConfigSpecFileOper
 	return nil
 }
 
+// parentFileNameHint returns the parentFileNameHint of a delta disk descriptor:
+// the name of the parent if both are in the same directory, otherwise its absolute path.
+func parentFileNameHint(file, parent string) string {
+	if filepath.Dir(file) == filepath.Dir(parent) {
+		return filepath.Base(parent)
+	}
+	return parent
+}
+
+// vdmCreateChildDisk creates a sparse delta disk named name, with the disk named parent as its parent.
+func vdmCreateChildDisk(ctx *Context, op types.VirtualDeviceConfigSpecFileOperation, dc *types.ManagedObjectReference, name, parent string) types.BaseMethodFault {
+	fm := ctx.Map.FileManager()
+
+	pdesc, ppath, fault := fm.DiskDescriptor(ctx, dc, parent)
+	if fault != nil {
+		return fault
+	}
+
+	file, fault := fm.resolve(ctx, dc, name)
+	if fault != nil {
+		return fault
+	}
+
+	if exists, fault := vdmExists(fm, op, file); exists || fault != nil {
+		return fault
+	}
+
+	backing := deltaDiskBackingFileName(file)
+
+	desc := vmdk.NewDescriptor(vmdk.Extent{
+		Type: "VMFSSPARSE",
+		Size: pdesc.Capacity() / vmdk.SectorSize,
+		Info: filepath.Base(backing),
+	})
+	desc.Type = "vmfsSparse"
+	desc.CID = vmdk.DiskContentID(rand.Uint32())
+	desc.ParentCID = pdesc.CID
+	desc.ParentFileNameHint = parentFileNameHint(file, ppath)
+
+	if fault = fm.SaveDiskDescriptor(ctx, desc, file); fault != nil {
+		return fault
+	}
+
+	b, err := os.Create(backing)
+	if err != nil {
+		return fm.fault(backing, err, new(types.CannotCreateFile))
+	}
+	_ = b.Close()
+
+	return nil
+}
+
+// vdmReparentVirtualDisk updates the parent of the delta disk named name, as done when its parent is consolidated.
+func vdmReparentVirtualDisk(ctx *Context, dc *types.ManagedObjectReference, name, parent string) types.BaseMethodFault {
+	fm := ctx.Map.FileManager()
+
+	pdesc, ppath, fault := fm.DiskDescriptor(ctx, dc, parent)
+	if fault != nil {
+		return fault
+	}
+
+	desc, file, fault := fm.DiskDescriptor(ctx, dc, name)
+	if fault != nil {
+		return fault
+	}
+
+	desc.ParentCID = pdesc.CID
+	desc.ParentFileNameHint = parentFileNameHint(file, ppath)
+
+	return fm.SaveDiskDescriptor(ctx, desc, file)
+}
+
+func vdmDeleteVirtualDisk(ctx *Context, dc *types.ManagedObjectReference, name string) types.BaseMethodFault {
+	fm := ctx.Map.FileManager()
+
+	for _, name := range vdmNames(name, vdmIsDelta(ctx, dc, name)) {
+		err := fm.deleteDatastoreFile(ctx, &types.DeleteDatastoreFile_Task{
+			Name:       name,
+			Datacenter: dc,
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func vdmExtendVirtualDisk(ctx *Context, req *types.ExtendVirtualDisk_Task) types.BaseMethodFault {
 	fm := ctx.Map.FileManager()
 
@@ -148,20 +267,7 @@ func (m *VirtualDiskManager) ExtendVirtualDiskTask(ctx *Context, req *types.Exte
 
 func (m *VirtualDiskManager) DeleteVirtualDiskTask(ctx *Context, req *types.DeleteVirtualDisk_Task) soap.HasFault {
 	task := CreateTask(m, "deleteVirtualDisk", func(*Task) (types.AnyType, types.BaseMethodFault) {
-		fm := ctx.Map.FileManager()
-
-		for _, name := range vdmNames(req.Name) {
-			err := fm.deleteDatastoreFile(ctx, &types.DeleteDatastoreFile_Task{
-				Name:       name,
-				Datacenter: req.Datacenter,
-			})
-
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		return nil, nil
+		return nil, vdmDeleteVirtualDisk(ctx, req.Datacenter, req.Name)
 	})
 
 	return &methods.DeleteVirtualDisk_TaskBody{
@@ -175,9 +281,10 @@ func (m *VirtualDiskManager) MoveVirtualDiskTask(ctx *Context, req *types.MoveVi
 	task := CreateTask(m, "moveVirtualDisk", func(*Task) (types.AnyType, types.BaseMethodFault) {
 		fm := ctx.Map.FileManager()
 
-		dest := vdmNames(req.DestName)
+		delta := vdmIsDelta(ctx, req.SourceDatacenter, req.SourceName)
+		dest := vdmNames(req.DestName, delta)
 
-		for i, name := range vdmNames(req.SourceName) {
+		for i, name := range vdmNames(req.SourceName, delta) {
 			err := fm.moveDatastoreFile(ctx, &types.MoveDatastoreFile_Task{
 				SourceName:            name,
 				SourceDatacenter:      req.SourceDatacenter,
@@ -209,9 +316,10 @@ func (m *VirtualDiskManager) CopyVirtualDiskTask(ctx *Context, req *types.CopyVi
 
 		fm := ctx.Map.FileManager()
 
-		dest := vdmNames(req.DestName)
+		delta := vdmIsDelta(ctx, req.SourceDatacenter, req.SourceName)
+		dest := vdmNames(req.DestName, delta)
 
-		for i, name := range vdmNames(req.SourceName) {
+		for i, name := range vdmNames(req.SourceName, delta) {
 			err := fm.copyDatastoreFile(ctx, &types.CopyDatastoreFile_Task{
 				SourceName:            name,
 				SourceDatacenter:      req.SourceDatacenter,
@@ -6,6 +6,7 @@ package simulator
 
 import (
 	"context"
+	"os"
 	"path"
 	"strings"
 	"testing"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/types"
 )
 
@@ -176,3 +178,70 @@ func TestVirtualDiskManager(t *testing.T) {
 		}
 	}
 }
+
+func TestVirtualDiskManagerDigitSuffix(t *testing.T) {
+	m := ESX()
+
+	m.Run(func(ctx context.Context, c *vim25.Client) error {
+		sctx := m.Service.Context
+		fm := m.Map().FileManager()
+		dm := object.NewVirtualDiskManager(c)
+
+		exists := func(name string) bool {
+			file, fault := fm.resolve(sctx, nil, name)
+			if fault != nil {
+				t.Fatal(fault)
+			}
+			_, err := os.Stat(file)
+			return err == nil
+		}
+
+		spec := &types.FileBackedVirtualDiskSpec{
+			VirtualDiskSpec: types.VirtualDiskSpec{
+				AdapterType: string(types.VirtualDiskAdapterTypeLsiLogic),
+				DiskType:    string(types.VirtualDiskTypeThin),
+			},
+			CapacityKb: 1024,
+		}
+
+		// an ordinary disk name that looks like a delta disk name
+		name := "[LocalDS_0] data-201801.vmdk"
+		task, err := dm.CreateVirtualDisk(ctx, name, nil, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if !exists("[LocalDS_0] data-201801-flat.vmdk") {
+			t.Error("flat extent not created")
+		}
+
+		dest := "[LocalDS_0] data-201802.vmdk"
+		task, err = dm.CopyVirtualDisk(ctx, name, nil, dest, nil, nil, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if !exists("[LocalDS_0] data-201802-flat.vmdk") {
+			t.Error("flat extent not copied")
+		}
+
+		for _, disk := range []string{name, dest} {
+			task, err = dm.DeleteVirtualDisk(ctx, disk, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = task.Wait(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if exists(disk) || exists(VirtualDiskBackingFileName(disk)) {
+				t.Errorf("%s not deleted", disk)
+			}
+		}
+
+		return nil
+	})
+}
@@ -151,6 +151,7 @@ func NewVirtualMachine(ctx *Context, parent types.ManagedObjectReference, spec *
 
 	vm.Runtime.PowerState = types.VirtualMachinePowerStatePoweredOff
 	vm.Runtime.ConnectionState = types.VirtualMachineConnectionStateConnected
+	vm.Runtime.ConsolidationNeeded = types.NewBool(false)
 	vm.Summary.Runtime = vm.Runtime
 
 	vm.Capability.ChangeTrackingSupported = types.NewBool(changeTrackingSupported(spec))
@@ -774,7 +775,7 @@ func (vm *VirtualMachine) updateDiskLayouts(ctx *Context) types.BaseMethodFault
 			var fileKeys []int32
 
 			// Add disk descriptor and extent files
-			for _, diskName := range vdmNames(dFileName) {
+			for _, diskName := range vdmNames(dFileName, diskBacking.Parent != nil) {
 				// get full path including datastore location
 				p, fault := parseDatastorePath(diskName)
 				if fault != nil {
@@ -902,9 +903,15 @@ func (vm *VirtualMachine) RefreshStorageInfo(ctx *Context, req *types.RefreshSto
 			return body
 		}
 
-		if _, err := os.Stat(p.String()); err != nil {
+		datastore := vm.useDatastore(ctx, p.Datastore)
+		info, err := os.Stat(datastore.resolve(ctx, p.Path))
+		if err != nil {
 			vm.LayoutEx.File = append(vm.LayoutEx.File[:idx], vm.LayoutEx.File[idx+1:]...)
+			continue
 		}
+
+		vm.LayoutEx.File[idx].Size = info.Size()
+		vm.LayoutEx.File[idx].UniqueSize = info.Size()
 	}
 
 	vmPathName := vm.Config.Files.VmPathName
@@ -1459,11 +1466,16 @@ func (vm *VirtualMachine) configureDevice(
 				info.FileName = filename
 			}
 
-			err := vdmCreateVirtualDisk(ctx, spec.FileOperation, &types.CreateVirtualDisk_Task{
-				Datacenter: &dc.Self,
-				Name:       info.FileName,
-				Spec:       &types.FileBackedVirtualDiskSpec{CapacityKb: x.CapacityInKB},
-			})
+			var err types.BaseMethodFault
+			if parent == "" {
+				err = vdmCreateVirtualDisk(ctx, spec.FileOperation, &types.CreateVirtualDisk_Task{
+					Datacenter: &dc.Self,
+					Name:       info.FileName,
+					Spec:       &types.FileBackedVirtualDiskSpec{CapacityKb: x.CapacityInKB},
+				})
+			} else {
+				err = vdmCreateChildDisk(ctx, spec.FileOperation, &dc.Self, info.FileName, parent)
+			}
 			if err != nil {
 				return err
 			}
@@ -2129,6 +2141,10 @@ func (vm *VirtualMachine) RebootGuest(ctx *Context, req *types.RebootGuest) soap
 	}
 
 	if vm.Guest.ToolsRunningStatus == string(types.VirtualMachineToolsRunningStatusGuestToolsRunning) {
+		if err := vm.svm.reboot(ctx); err != nil {
+			body.Fault_ = Fault(err.Error(), new(types.ToolsUnavailable))
+			return body
+		}
 		vm.svm.restart(ctx)
 		body.Res = new(types.RebootGuestResponse)
 	} else {
@@ -2396,8 +2412,12 @@ type vmFolder interface {
 }
 
 func (vm *VirtualMachine) cloneDevice() []types.BaseVirtualDevice {
+	return cloneDevices(vm.Config.Hardware.Device)
+}
+
+func cloneDevices(devices []types.BaseVirtualDevice) []types.BaseVirtualDevice {
 	src := types.ArrayOfVirtualDevice{
-		VirtualDevice: vm.Config.Hardware.Device,
+		VirtualDevice: devices,
 	}
 	dst := types.ArrayOfVirtualDevice{}
 	deepCopy(src, &dst)
@@ -2470,6 +2490,16 @@ func (vm *VirtualMachine) CloneVMTask(ctx *Context, req *types.CloneVM_Task) soa
 
 		defaultDevices := object.VirtualDeviceList(esx.VirtualDevice)
 		devices := vm.cloneDevice()
+		if req.Spec.Snapshot != nil {
+			snapshot, ok := ctx.Map.Get(*req.Spec.Snapshot).(*VirtualMachineSnapshot)
+			if !ok || snapshot.Vm != vm.Self {
+				return nil, &types.InvalidArgument{InvalidProperty: "spec.snapshot"}
+			}
+			devices = cloneDevices(snapshot.Config.Hardware.Device)
+		}
+
+		linked := req.Spec.Location.DiskMoveType == string(types.VirtualMachineRelocateDiskMoveOptionsCreateNewChildDiskBacking)
+		deltas := make(map[string]bool)
 
 		for _, device := range devices {
 			var fop types.VirtualDeviceConfigSpecFileOperation
@@ -2481,12 +2511,36 @@ func (vm *VirtualMachine) CloneVMTask(ctx *Context, req *types.CloneVM_Task) soa
 
 			switch disk := device.(type) {
 			case *types.VirtualDisk:
-				// TODO: consider VirtualMachineCloneSpec.DiskMoveType
 				fop = types.VirtualDeviceConfigSpecFileOperationCreate
+				backing := disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo)
+
+				if linked {
+					// Create a delta disk in the clone's directory, sharing the source disk as its parent
+					var name string
+					for index := 1; name == "" || deltas[name]; index++ {
+						p := object.DatastorePath{
+							Datastore: vmx.Datastore,
+							Path:      path.Join(req.Name, deltaVmdkName(backing, index)),
+						}
+						name = p.String()
+					}
+					deltas[name] = true
+
+					disk.Backing = &types.VirtualDiskFlatVer2BackingInfo{
+						VirtualDeviceFileBackingInfo: types.VirtualDeviceFileBackingInfo{
+							FileName: name,
+						},
+						Parent:          backing,
+						DiskMode:        backing.DiskMode,
+						ThinProvisioned: backing.ThinProvisioned,
+						DeltaDiskFormat: string(types.VirtualDiskDeltaDiskFormatRedoLogFormat),
+					}
+					break
+				}
 
 				// Leave FileName empty so CreateVM will just create a new one under VmPathName
-				disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo).FileName = ""
-				disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo).Parent = nil
+				backing.FileName = ""
+				backing.Parent = nil
 			}
 
 			config.DeviceChange = append(config.DeviceChange, &types.VirtualDeviceConfigSpec{
@@ -2788,8 +2842,13 @@ func (vm *VirtualMachine) CreateSnapshotExTask(ctx *Context, req *types.CreateSn
 		snapshot := &VirtualMachineSnapshot{}
 		snapshot.Vm = vm.Reference()
 		snapshot.Config = *vm.Config
+		snapshot.Config.Hardware.Device = vm.cloneDevice()
 		snapshot.DataSets = copyDataSetsForVmClone(vm.DataSets)
 
+		if fault := vm.createDeltaDisks(ctx); fault != nil {
+			return nil, fault
+		}
+
 		ctx.Map.Put(snapshot)
 
 		quiesced := false
@@ -2855,7 +2914,7 @@ func (vm *VirtualMachine) RevertToCurrentSnapshotTask(ctx *Context, req *types.R
 
 	task := CreateTask(vm, "revertSnapshot", func(t *Task) (types.AnyType, types.BaseMethodFault) {
 		vm.DataSets = copyDataSetsForVmClone(snapshot.DataSets)
-		return nil, nil
+		return nil, vm.revertDeltaDisks(ctx, snapshot)
 	})
 
 	body.Res = &types.RevertToCurrentSnapshot_TaskResponse{
@@ -2878,11 +2937,16 @@ func (vm *VirtualMachine) RemoveAllSnapshotsTask(ctx *Context, req *types.Remove
 			{Name: "rootSnapshot", Val: nil},
 		})
 
+		var removed []*VirtualMachineSnapshot
 		for _, ref := range refs {
-			ctx.Map.Get(ref).(*VirtualMachineSnapshot).removeSnapshotFiles(ctx)
+			snapshot := ctx.Map.Get(ref).(*VirtualMachineSnapshot)
+			snapshot.removeSnapshotFiles(ctx)
+			removed = append(removed, snapshot)
 			ctx.Map.Remove(ctx, ref)
 		}
 
+		vm.consolidate(ctx, removed...)
+
 		return nil, nil
 	})
 
@@ -2893,6 +2957,18 @@ func (vm *VirtualMachine) RemoveAllSnapshotsTask(ctx *Context, req *types.Remove
 	}
 }
 
+func (vm *VirtualMachine) ConsolidateVMDisksTask(ctx *Context, req *types.ConsolidateVMDisks_Task) soap.HasFault {
+	task := CreateTask(vm, "consolidateVMDisks", func(t *Task) (types.AnyType, types.BaseMethodFault) {
+		return nil, vm.consolidate(ctx)
+	})
+
+	return &methods.ConsolidateVMDisks_TaskBody{
+		Res: &types.ConsolidateVMDisks_TaskResponse{
+			Returnval: task.Run(ctx),
+		},
+	}
+}
+
 func (vm *VirtualMachine) fcd(ctx *Context, ds types.ManagedObjectReference, id types.ID) *VStorageObject {
 	m := ctx.Map.VStorageObjectManager()
 	if ds.Value != "" {
@@ -3019,6 +3095,11 @@ func (vm *VirtualMachine) ShutdownGuest(ctx *Context, c *types.ShutdownGuest) so
 		return r
 	}
 
+	if err := vm.svm.shutdown(ctx); err != nil {
+		r.Fault_ = Fault(err.Error(), new(types.ToolsUnavailable))
+		return r
+	}
+
 	event := vm.event(ctx)
 	ctx.postEvent(&types.VmGuestShutdownEvent{VmEvent: event})
 
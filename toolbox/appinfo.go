@@ -0,0 +1,170 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"encoding/json"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/vmware/govmomi/toolbox/process"
+)
+
+// Defs from: open-vm-tools/services/plugins/appInfo/appInfo.c
+
+const (
+	appInfoKey     = "guestinfo.appInfo"
+	appInfoVersion = 1
+	appInfoMaxSize = 62 * 1024 // guestinfo value size limit, less room for the info-set command
+)
+
+// AppInfoApplication is an application entry of the guest application inventory
+type AppInfoApplication struct {
+	Name    string `json:"a"`
+	Version string `json:"v"`
+}
+
+// AppInfo is the guest application inventory, published to the VMX as guestinfo.appInfo
+type AppInfo struct {
+	Version       string               `json:"version"`
+	UpdateCounter string               `json:"updateCounter"`
+	PublishTime   string               `json:"publishTime"`
+	Applications  []AppInfoApplication `json:"applications"`
+}
+
+// AppInfoPublisher periodically publishes the running guest applications to the VMX.
+// The inventory is exposed via the vSphere API as the guestinfo.appInfo VirtualMachine extraConfig value.
+type AppInfoPublisher struct {
+	*GuestInfo
+
+	// Interval between publishing, publishing is disabled if Interval is 0
+	Interval time.Duration
+
+	// Exclude applications with a name or path matching any of the path.Match patterns
+	Exclude []string
+
+	// Processes returns the processes running in the guest
+	Processes func() ([]process.State, error)
+
+	counter int
+}
+
+func newAppInfoPublisher(service *Service) *AppInfoPublisher {
+	return &AppInfoPublisher{
+		GuestInfo: &GuestInfo{out: service.out},
+		Processes: func() ([]process.State, error) {
+			list, err := process.System()
+			if err != nil {
+				return nil, err
+			}
+			return append(list, service.Command.ProcessManager.List(nil)...), nil
+		},
+	}
+}
+
+func (p *AppInfoPublisher) excluded(name string) bool {
+	for _, pattern := range p.Exclude {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, filepath.Base(name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Applications returns the running applications, sorted by name, excluding duplicates and Exclude matches.
+func (p *AppInfoPublisher) Applications() ([]AppInfoApplication, error) {
+	list, err := p.Processes()
+	if err != nil {
+		return nil, err
+	}
+
+	var apps []AppInfoApplication
+	seen := make(map[string]bool)
+
+	for _, proc := range list {
+		if proc.EndTime != 0 || proc.Name == "" || p.excluded(proc.Name) {
+			continue
+		}
+
+		name := filepath.Base(proc.Name)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		apps = append(apps, AppInfoApplication{Name: name})
+	}
+
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].Name < apps[j].Name
+	})
+
+	return apps, nil
+}
+
+// Document encodes the AppInfo JSON document for the given applications,
+// dropping applications from the end of the list if the encoding exceeds the guestinfo size limit.
+func (p *AppInfoPublisher) Document(apps []AppInfoApplication) ([]byte, error) {
+	info := AppInfo{
+		Version:       strconv.Itoa(appInfoVersion),
+		UpdateCounter: strconv.Itoa(p.counter),
+		PublishTime:   time.Now().UTC().Format(time.RFC3339),
+		Applications:  apps,
+	}
+
+	if info.Applications == nil {
+		info.Applications = []AppInfoApplication{}
+	}
+
+	b, err := json.Marshal(info)
+	if err != nil || len(b) <= appInfoMaxSize {
+		return b, err
+	}
+
+	// Size of the document without applications, plus the size of each application with a separator
+	empty := info
+	empty.Applications = []AppInfoApplication{}
+	if b, err = json.Marshal(empty); err != nil {
+		return nil, err
+	}
+	size := len(b)
+
+	for i, app := range info.Applications {
+		a, err := json.Marshal(app)
+		if err != nil {
+			return nil, err
+		}
+		size += len(a) + 1
+		if size > appInfoMaxSize {
+			info.Applications = info.Applications[:i]
+			break
+		}
+	}
+
+	return json.Marshal(info)
+}
+
+// Publish sets guestinfo.appInfo to the current application inventory.
+func (p *AppInfoPublisher) Publish() error {
+	apps, err := p.Applications()
+	if err != nil {
+		return err
+	}
+
+	p.counter++
+
+	b, err := p.Document(apps)
+	if err != nil {
+		return err
+	}
+
+	return p.Set(appInfoKey, string(b))
+}
@@ -0,0 +1,117 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/toolbox/process"
+)
+
+func TestAppInfo(t *testing.T) {
+	c := &mockGuestInfoChannel{vars: make(map[string]string)}
+	service := NewService(new(mockChannelIn), c)
+	p := service.AppInfo
+
+	p.Exclude = []string{"/usr/sbin/*", "bash"}
+	p.Processes = func() ([]process.State, error) {
+		return []process.State{
+			{Name: "/usr/bin/postgres", Pid: 10},
+			{Name: "/usr/bin/postgres", Pid: 11},
+			{Name: "/usr/sbin/sshd", Pid: 12},
+			{Name: "/bin/bash", Pid: 13},
+			{Name: "nginx", Pid: 14},
+			{Name: "/bin/date", Pid: 15, EndTime: 1},
+		}, nil
+	}
+
+	for i := 1; i <= 2; i++ {
+		if err := p.Publish(); err != nil {
+			t.Fatal(err)
+		}
+
+		var info AppInfo
+		if err := json.Unmarshal([]byte(c.vars["guestinfo.appInfo"]), &info); err != nil {
+			t.Fatal(err)
+		}
+
+		if info.Version != "1" || info.UpdateCounter != fmt.Sprint(i) {
+			t.Errorf("info=%#v", info)
+		}
+
+		if _, err := time.Parse(time.RFC3339, info.PublishTime); err != nil {
+			t.Error(err)
+		}
+
+		expect := []AppInfoApplication{{Name: "nginx"}, {Name: "postgres"}}
+		if !reflect.DeepEqual(info.Applications, expect) {
+			t.Errorf("apps=%#v", info.Applications)
+		}
+	}
+
+	var apps []AppInfoApplication
+	for i := 0; i < 5000; i++ {
+		apps = append(apps, AppInfoApplication{Name: fmt.Sprintf("app-%04d", i)})
+	}
+
+	b, err := p.Document(apps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(b) > appInfoMaxSize {
+		t.Errorf("size=%d", len(b))
+	}
+
+	b, err = p.Document(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var info map[string]any
+	if err = json.Unmarshal(b, &info); err != nil {
+		t.Fatal(err)
+	}
+
+	if info["applications"] == nil {
+		t.Errorf("info=%s", b)
+	}
+}
+
+func TestAppInfoService(t *testing.T) {
+	c := &mockGuestInfoChannel{vars: make(map[string]string)}
+	service := NewService(new(mockChannelIn), c)
+
+	service.AppInfo.Interval = time.Hour // published once at Start
+	service.AppInfo.Processes = func() ([]process.State, error) {
+		return []process.State{{Name: "/usr/bin/toolbox"}}, nil
+	}
+
+	if err := service.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	for {
+		val, err := service.AppInfo.Get("appInfo")
+		if err == nil {
+			var info AppInfo
+			if err = json.Unmarshal([]byte(val), &info); err != nil {
+				t.Fatal(err)
+			}
+			if len(info.Applications) != 1 || info.Applications[0].Name != "toolbox" {
+				t.Errorf("info=%#v", info)
+			}
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	service.Stop()
+	service.Wait()
+}
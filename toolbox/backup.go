@@ -0,0 +1,446 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defs from: open-vm-tools/lib/include/vmBackupSignals.h and services/plugins/vmbackup/vmBackupInt.h
+
+const (
+	backupEventSet = "vmbackup.eventSet"
+
+	backupEventReset          = "reset"
+	backupEventRequestorAbort = "req.aborted"
+	backupEventRequestorDone  = "req.done"
+	backupEventRequestorError = "req.error"
+	backupEventSnapshotCommit = "prov.snapshotCommit"
+	backupEventKeepAlive      = "req.keepAlive"
+)
+
+// VmBackupStatus as defined in vmBackupSignals.h
+const (
+	backupSuccess = iota
+	backupInvalidState
+	backupScriptError
+	backupSyncError
+	backupRemoteAbort
+	backupUnexpectedError
+)
+
+// Backup operation states
+const (
+	backupIdle = iota
+	backupFreezing
+	backupFrozen
+	backupThawing
+)
+
+var (
+	// DefaultBackupScriptsDir is the directory used by open-vm-tools for freeze and thaw scripts
+	DefaultBackupScriptsDir = "/etc/vmware-tools/backupScripts.d"
+
+	// DefaultBackupTimeout is the time to wait for vmbackup.snapshotDone before thawing the guest
+	DefaultBackupTimeout = 15 * time.Minute
+
+	// DefaultBackupKeepAlive is the interval between keep-alive events sent during a backup operation
+	DefaultBackupKeepAlive = 3 * time.Second
+
+	freezeFilesystem = fsFreeze
+	thawFilesystem   = fsThaw
+)
+
+// BackupHook is called to quiesce the guest before a snapshot is taken and to resume once the snapshot is done.
+type BackupHook interface {
+	Freeze() error
+	Thaw() error
+}
+
+// BackupCommandHandler implements the vmbackup protocol, used by the VMX to create quiesced snapshots.
+// The VMX sends vmbackup.start, the handler freezes the guest using Hooks and notifies the VMX to take the snapshot.
+// Once the VMX sends vmbackup.snapshotDone (or vmbackup.abort), the guest is thawed using Hooks in reverse order.
+// While an operation is in progress, keep-alive events are sent to the VMX.
+type BackupCommandHandler struct {
+	// Hooks are frozen in order and thawed in reverse order.
+	// If no hooks are set, quiesce requests succeed without quiescing the guest.
+	Hooks []BackupHook
+
+	// Timeout is the maximum time the guest remains frozen waiting for vmbackup.snapshotDone
+	Timeout time.Duration
+
+	// KeepAlive is the interval between keep-alive events
+	KeepAlive time.Duration
+
+	out    *ChannelOut
+	mu     sync.Mutex
+	state  int
+	signal chan string
+	done   chan struct{}
+}
+
+func registerBackupCommandHandler(service *Service) *BackupCommandHandler {
+	handler := &BackupCommandHandler{
+		Timeout:   DefaultBackupTimeout,
+		KeepAlive: DefaultBackupKeepAlive,
+		out:       service.out,
+	}
+
+	service.RegisterHandler("vmbackup.start", handler.Start)
+	service.RegisterHandler("vmbackup.snapshotDone", handler.SnapshotDone)
+	service.RegisterHandler("vmbackup.abort", handler.Abort)
+
+	return handler
+}
+
+// Start handles the vmbackup.start RPC, which starts an asynchronous freeze of the guest.
+// The arguments are "generateManifests [volumes]", manifests are not supported and
+// the volume list is ignored: the hooks quiesce the guest as a whole.
+func (b *BackupCommandHandler) Start(args []byte) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != backupIdle {
+		return []byte("Backup operation already in progress."), errors.New("vmbackup: operation already in progress")
+	}
+
+	fields := strings.Fields(string(args))
+	if len(fields) != 0 {
+		if _, err := strconv.Atoi(fields[0]); err != nil {
+			return []byte("Invalid arguments."), fmt.Errorf("vmbackup: invalid arguments %q", args)
+		}
+	}
+
+	b.state = backupFreezing
+	b.signal = make(chan string, 1)
+	b.done = make(chan struct{})
+
+	go b.run(b.signal, b.done)
+
+	return nil, nil
+}
+
+// SnapshotDone handles the vmbackup.snapshotDone RPC, sent by the VMX once the snapshot has been taken.
+func (b *BackupCommandHandler) SnapshotDone([]byte) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != backupFrozen {
+		return []byte("Error: unexpected state for snapshot done message."), errors.New("vmbackup: snapshotDone while not frozen")
+	}
+
+	b.state = backupThawing
+	b.signal <- backupEventRequestorDone
+
+	return nil, nil
+}
+
+// Abort handles the vmbackup.abort RPC, which thaws the guest if needed and cancels the operation.
+func (b *BackupCommandHandler) Abort([]byte) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case backupIdle:
+		return []byte("Error: no backup in progress"), errors.New("vmbackup: no backup in progress")
+	case backupThawing:
+		return nil, nil // already finishing
+	}
+
+	b.state = backupThawing
+	b.signal <- backupEventRequestorAbort
+
+	return nil, nil
+}
+
+// Wait blocks until the current backup operation, if any, has completed.
+func (b *BackupCommandHandler) Wait() {
+	b.mu.Lock()
+	done := b.done
+	b.mu.Unlock()
+
+	if done != nil {
+		<-done
+	}
+}
+
+func (b *BackupCommandHandler) event(name string, code int, msg string) {
+	req := fmt.Sprintf("%s %s %d %s", backupEventSet, name, code, msg)
+
+	if _, err := b.out.Request([]byte(req)); err != nil {
+		log.Printf("unable to send %q: %s", req, err)
+	}
+}
+
+// freeze calls Freeze on each hook, returning the number of hooks frozen.
+func (b *BackupCommandHandler) freeze() (int, error) {
+	for i, hook := range b.Hooks {
+		if err := hook.Freeze(); err != nil {
+			return i, err
+		}
+	}
+	return len(b.Hooks), nil
+}
+
+// thaw calls Thaw on the first n hooks in reverse order, returning the first error if any.
+func (b *BackupCommandHandler) thaw(n int) error {
+	var errs []error
+	for i := n - 1; i >= 0; i-- {
+		if err := b.Hooks[i].Thaw(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *BackupCommandHandler) keepAlive(done chan struct{}) {
+	if b.KeepAlive <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(b.KeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			b.event(backupEventKeepAlive, backupSuccess, "")
+		}
+	}
+}
+
+func (b *BackupCommandHandler) run(signal chan string, done chan struct{}) {
+	stop := make(chan struct{})
+
+	defer func() {
+		close(stop)
+		b.mu.Lock()
+		b.state = backupIdle
+		b.mu.Unlock()
+		close(done)
+	}()
+
+	b.event(backupEventReset, backupSuccess, "")
+
+	go b.keepAlive(stop)
+
+	n, err := b.freeze()
+	if err != nil {
+		code := backupSyncError
+		if errors.As(err, new(*BackupScriptError)) {
+			code = backupScriptError
+		}
+		b.event(backupEventRequestorError, code, err.Error())
+		if err = b.thaw(n); err != nil {
+			log.Printf("vmbackup: %s", err)
+		}
+		b.event(backupEventRequestorDone, code, "Quiesce aborted.")
+		return
+	}
+
+	b.mu.Lock()
+	frozen := b.state == backupFreezing
+	if frozen {
+		b.state = backupFrozen
+	}
+	b.mu.Unlock()
+
+	if frozen {
+		b.event(backupEventSnapshotCommit, backupSuccess, "")
+	}
+
+	var reason string
+	select {
+	case reason = <-signal:
+	case <-time.After(b.Timeout):
+		b.mu.Lock()
+		if b.state == backupFrozen {
+			b.state = backupThawing
+		} else {
+			reason = <-signal // snapshotDone or abort raced with the timeout
+		}
+		b.mu.Unlock()
+	}
+
+	err = b.thaw(n)
+
+	switch reason {
+	case backupEventRequestorAbort:
+		if err != nil {
+			log.Printf("vmbackup: %s", err)
+		}
+		b.event(backupEventRequestorAbort, backupRemoteAbort, "Quiesce aborted.")
+	case backupEventRequestorDone:
+		if err != nil {
+			b.event(backupEventRequestorError, backupSyncError, err.Error())
+			b.event(backupEventRequestorDone, backupSyncError, "Error when thawing.")
+			return
+		}
+		b.event(backupEventRequestorDone, backupSuccess, "")
+	default:
+		if err != nil {
+			log.Printf("vmbackup: %s", err)
+		}
+		b.event(backupEventRequestorError, backupUnexpectedError, "Timed out waiting for snapshot.")
+		b.event(backupEventRequestorDone, backupUnexpectedError, "Quiesce aborted.")
+	}
+}
+
+// BackupScriptError is returned by BackupScripts when a freeze script fails.
+type BackupScriptError struct {
+	Script string
+	Err    error
+}
+
+func (e *BackupScriptError) Error() string {
+	return fmt.Sprintf("script %s: %s", e.Script, e.Err)
+}
+
+func (e *BackupScriptError) Unwrap() error {
+	return e.Err
+}
+
+// BackupScripts is a BackupHook that runs the executable files in Dir, in the same manner as open-vm-tools:
+// scripts are run in lexical order with the "freeze" argument and in reverse order with the "thaw" argument.
+// If a script fails to freeze, the scripts that were already run are called with the "freezeFail" argument.
+type BackupScripts struct {
+	Dir string
+
+	frozen []string
+}
+
+func (s *BackupScripts) scripts() ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var scripts []string
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+			continue
+		}
+		scripts = append(scripts, filepath.Join(s.Dir, entry.Name()))
+	}
+
+	sort.Strings(scripts)
+
+	return scripts, nil
+}
+
+func (s *BackupScripts) run(script, arg string) error {
+	// #nosec: Subprocess launching with variable
+	out, err := exec.Command(script, arg).CombinedOutput()
+	if err != nil {
+		if len(out) != 0 {
+			err = fmt.Errorf("%s (%s)", err, strings.TrimSpace(string(out)))
+		}
+		return &BackupScriptError{Script: script, Err: err}
+	}
+	return nil
+}
+
+// Freeze runs each script with the "freeze" argument.
+func (s *BackupScripts) Freeze() error {
+	scripts, err := s.scripts()
+	if err != nil {
+		return err
+	}
+
+	s.frozen = nil
+
+	for _, script := range scripts {
+		if err := s.run(script, "freeze"); err != nil {
+			for i := len(s.frozen) - 1; i >= 0; i-- {
+				if ferr := s.run(s.frozen[i], "freezeFail"); ferr != nil {
+					log.Printf("vmbackup: %s", ferr)
+				}
+			}
+			s.frozen = nil
+			return err
+		}
+		s.frozen = append(s.frozen, script)
+	}
+
+	return nil
+}
+
+// Thaw runs each script that was frozen with the "thaw" argument, in reverse order.
+func (s *BackupScripts) Thaw() error {
+	var errs []error
+
+	for i := len(s.frozen) - 1; i >= 0; i-- {
+		if err := s.run(s.frozen[i], "thaw"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	s.frozen = nil
+
+	return errors.Join(errs...)
+}
+
+// FilesystemFreeze is a BackupHook that freezes the mounted local disk filesystems reported by guest disk info.
+// Only Linux is currently supported (FIFREEZE), on other platforms this hook does nothing.
+type FilesystemFreeze struct {
+	frozen []string
+}
+
+// Freeze flushes and freezes each mounted filesystem, once per device.
+func (f *FilesystemFreeze) Freeze() error {
+	names, err := guestFilesystems()
+	if err != nil {
+		return err
+	}
+
+	f.frozen = nil
+
+	for _, name := range names {
+		if err := freezeFilesystem(name); err != nil {
+			if terr := f.Thaw(); terr != nil {
+				log.Printf("vmbackup: %s", terr)
+			}
+			return fmt.Errorf("freeze %s: %w", name, err)
+		}
+		f.frozen = append(f.frozen, name)
+	}
+
+	return nil
+}
+
+// Thaw thaws each filesystem that was frozen, in reverse order.
+func (f *FilesystemFreeze) Thaw() error {
+	var errs []error
+
+	for i := len(f.frozen) - 1; i >= 0; i-- {
+		if err := thawFilesystem(f.frozen[i]); err != nil {
+			errs = append(errs, fmt.Errorf("thaw %s: %w", f.frozen[i], err))
+		}
+	}
+
+	f.frozen = nil
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,39 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"os"
+	"syscall"
+)
+
+// ioctl numbers as defined in linux/fs.h
+const (
+	fiFreeze = 0xc0045877 // _IOWR('X', 119, int)
+	fiThaw   = 0xc0045878 // _IOWR('X', 120, int)
+)
+
+func fsIoctl(path string, req uintptr) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), req, 0)
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+func fsFreeze(path string) error {
+	return fsIoctl(path, fiFreeze)
+}
+
+func fsThaw(path string) error {
+	return fsIoctl(path, fiThaw)
+}
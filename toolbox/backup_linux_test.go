@@ -0,0 +1,134 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestFilesystemFreeze(t *testing.T) {
+	dir := t.TempDir()
+	procMounts = filepath.Join(dir, "mounts")
+	mounts := "/dev/sda1 / ext4 rw 0 0\n/dev/sdb /data xfs rw 0 0\n/dev/sdc /backup xfs rw 0 0\n"
+	if err := os.WriteFile(procMounts, []byte(mounts), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	statfs = func(string, *syscall.Statfs_t) error {
+		return nil
+	}
+
+	var calls []string
+	freezeFilesystem = func(path string) error {
+		calls = append(calls, "freeze "+path)
+		if path == "/backup" {
+			return errors.New("EBUSY")
+		}
+		return nil
+	}
+	thawFilesystem = func(path string) error {
+		calls = append(calls, "thaw "+path)
+		return nil
+	}
+
+	defer func() {
+		procMounts = "/proc/self/mounts"
+		statfs = syscall.Statfs
+		freezeFilesystem = fsFreeze
+		thawFilesystem = fsThaw
+	}()
+
+	hook := new(FilesystemFreeze)
+
+	if err := hook.Freeze(); err == nil {
+		t.Error("expected error")
+	}
+
+	mounts = strings.ReplaceAll(mounts, "/backup", "/home")
+	if err := os.WriteFile(procMounts, []byte(mounts), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := hook.Freeze(); err != nil {
+		t.Fatal(err)
+	}
+	if err := hook.Thaw(); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []string{
+		"freeze /", "freeze /data", "freeze /backup", "thaw /data", "thaw /",
+		"freeze /", "freeze /data", "freeze /home", "thaw /home", "thaw /data", "thaw /",
+	}
+	if !reflect.DeepEqual(calls, expect) {
+		t.Errorf("calls=%v", calls)
+	}
+}
+
+func TestFilesystemFreezeSharedDevice(t *testing.T) {
+	dir := t.TempDir()
+	procMounts = filepath.Join(dir, "mounts")
+	dev := filepath.Join(dir, "dev")
+	devPrefix = dev + "/"
+	if err := os.Mkdir(dev, 0750); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"sda1", "sdb"} {
+		if err := os.WriteFile(filepath.Join(dev, name), nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(dev, "sdb"), filepath.Join(dev, "vg-data")); err != nil {
+		t.Fatal(err)
+	}
+
+	mounts := []string{
+		dev + "/sda1 / btrfs rw 0 0",
+		dev + "/sda1 /home btrfs rw,subvol=/home 0 0", // btrfs subvolume
+		dev + "/sdb /data xfs rw 0 0",
+		dev + "/sdb /srv/data xfs rw 0 0", // bind mount
+		dev + "/vg-data /mnt/data xfs rw 0 0",
+	}
+	if err := os.WriteFile(procMounts, []byte(strings.Join(mounts, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var calls []string
+	freezeFilesystem = func(path string) error {
+		calls = append(calls, "freeze "+path)
+		return nil
+	}
+	thawFilesystem = func(path string) error {
+		calls = append(calls, "thaw "+path)
+		return nil
+	}
+
+	defer func() {
+		procMounts = "/proc/self/mounts"
+		devPrefix = "/dev/"
+		freezeFilesystem = fsFreeze
+		thawFilesystem = fsThaw
+	}()
+
+	hook := new(FilesystemFreeze)
+
+	if err := hook.Freeze(); err != nil {
+		t.Fatal(err)
+	}
+	if err := hook.Thaw(); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []string{"freeze /", "freeze /data", "thaw /data", "thaw /"}
+	if !reflect.DeepEqual(calls, expect) {
+		t.Errorf("calls=%v", calls)
+	}
+}
@@ -0,0 +1,15 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+package toolbox
+
+func fsFreeze(string) error {
+	return nil
+}
+
+func fsThaw(string) error {
+	return nil
+}
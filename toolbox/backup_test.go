@@ -0,0 +1,262 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// backupChannelOut records vmbackup events sent to the VMX
+type backupChannelOut struct {
+	events chan string
+}
+
+func (c *backupChannelOut) Start() error {
+	return nil
+}
+
+func (c *backupChannelOut) Stop() error {
+	return nil
+}
+
+func (c *backupChannelOut) Receive() ([]byte, error) {
+	return rpciOK, nil
+}
+
+func (c *backupChannelOut) Send(buf []byte) error {
+	if event, ok := strings.CutPrefix(string(buf), backupEventSet+" "); ok {
+		event = strings.TrimSpace(event)
+		if event == backupEventKeepAlive+" 0" {
+			select {
+			case c.events <- event:
+			default: // don't block if the test is done reading keep-alive events
+			}
+			return nil
+		}
+		c.events <- event
+	}
+	return nil
+}
+
+func (c *backupChannelOut) expect(t *testing.T, events ...string) {
+	t.Helper()
+
+	for _, expect := range events {
+		select {
+		case event := <-c.events:
+			if event != expect {
+				t.Errorf("expected event %q, got %q", expect, event)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for event %q", expect)
+		}
+	}
+}
+
+type testBackupHook struct {
+	name   string
+	calls  *[]string
+	mu     *sync.Mutex
+	freeze error
+}
+
+func (h *testBackupHook) record(op string) {
+	h.mu.Lock()
+	*h.calls = append(*h.calls, op+" "+h.name)
+	h.mu.Unlock()
+}
+
+func (h *testBackupHook) Freeze() error {
+	h.record("freeze")
+	return h.freeze
+}
+
+func (h *testBackupHook) Thaw() error {
+	h.record("thaw")
+	return nil
+}
+
+func newBackupTest(t *testing.T, hooks ...string) (*Service, *backupChannelOut, func() []string) {
+	out := &backupChannelOut{events: make(chan string, 100)}
+	service := NewService(new(mockChannelIn), out)
+	service.Backup.KeepAlive = 0
+
+	var calls []string
+	var mu sync.Mutex
+
+	for _, name := range hooks {
+		service.Backup.Hooks = append(service.Backup.Hooks, &testBackupHook{name: name, calls: &calls, mu: &mu})
+	}
+
+	return service, out, func() []string {
+		service.Backup.Wait()
+		mu.Lock()
+		defer mu.Unlock()
+		return calls
+	}
+}
+
+func dispatch(t *testing.T, s *Service, request, expect string) {
+	t.Helper()
+
+	reply := string(s.Dispatch([]byte(request)))
+	if reply != expect {
+		t.Errorf("%s: expected %q, got %q", request, expect, reply)
+	}
+}
+
+func TestBackupQuiesce(t *testing.T) {
+	service, out, calls := newBackupTest(t, "a", "b")
+
+	dispatch(t, service, "vmbackup.snapshotDone", "ERR Error: unexpected state for snapshot done message.")
+	dispatch(t, service, "vmbackup.abort", "ERR Error: no backup in progress")
+	dispatch(t, service, "vmbackup.start foo", "ERR Invalid arguments.")
+
+	dispatch(t, service, "vmbackup.start 1", "OK ")
+	out.expect(t, "reset 0", "prov.snapshotCommit 0")
+	dispatch(t, service, "vmbackup.start 1", "ERR Backup operation already in progress.")
+	dispatch(t, service, "vmbackup.snapshotDone", "OK ")
+	out.expect(t, "req.done 0")
+
+	expect := []string{"freeze a", "freeze b", "thaw b", "thaw a"}
+	if c := calls(); !reflect.DeepEqual(c, expect) {
+		t.Errorf("calls=%v", c)
+	}
+
+	// Start again once the previous operation completed
+	dispatch(t, service, "vmbackup.start", "OK ")
+	out.expect(t, "reset 0", "prov.snapshotCommit 0")
+	dispatch(t, service, "vmbackup.abort", "OK ")
+	out.expect(t, "req.aborted 4 Quiesce aborted.")
+	calls()
+}
+
+func TestBackupFreezeError(t *testing.T) {
+	service, out, calls := newBackupTest(t, "a", "b", "c")
+	service.Backup.Hooks[1].(*testBackupHook).freeze = errors.New("sync failed")
+
+	dispatch(t, service, "vmbackup.start 0", "OK ")
+	out.expect(t, "reset 0", "req.error 3 sync failed", "req.done 3 Quiesce aborted.")
+
+	expect := []string{"freeze a", "freeze b", "thaw a"}
+	if c := calls(); !reflect.DeepEqual(c, expect) {
+		t.Errorf("calls=%v", c)
+	}
+
+	dispatch(t, service, "vmbackup.snapshotDone", "ERR Error: unexpected state for snapshot done message.")
+}
+
+func TestBackupTimeout(t *testing.T) {
+	service, out, calls := newBackupTest(t, "a")
+	service.Backup.Timeout = time.Millisecond * 10
+
+	dispatch(t, service, "vmbackup.start 0", "OK ")
+	out.expect(t,
+		"reset 0",
+		"prov.snapshotCommit 0",
+		"req.error 5 Timed out waiting for snapshot.",
+		"req.done 5 Quiesce aborted.",
+	)
+
+	expect := []string{"freeze a", "thaw a"}
+	if c := calls(); !reflect.DeepEqual(c, expect) {
+		t.Errorf("calls=%v", c)
+	}
+}
+
+func TestBackupKeepAlive(t *testing.T) {
+	service, out, calls := newBackupTest(t)
+	service.Backup.KeepAlive = time.Millisecond
+
+	dispatch(t, service, "vmbackup.start 0", "OK ")
+	out.expect(t, "reset 0")
+
+	keepAlive := 0
+	for keepAlive < 3 {
+		select {
+		case event := <-out.events:
+			if event == "req.keepAlive 0" {
+				keepAlive++
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for keep-alive")
+		}
+	}
+
+	dispatch(t, service, "vmbackup.snapshotDone", "OK ")
+	calls()
+}
+
+func TestBackupScripts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires /bin/sh")
+	}
+
+	dir := t.TempDir()
+	log := filepath.Join(dir, "log")
+
+	script := func(name string, fail bool) {
+		exit := 0
+		if fail {
+			exit = 1
+		}
+		src := fmt.Sprintf("#!/bin/sh\necho %s $1 >> %s\n[ \"$1\" = freeze ] && exit %d\nexit 0\n", name, log, exit)
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0700); err != nil { // #nosec: test script
+			t.Fatal(err)
+		}
+	}
+
+	output := func() []string {
+		b, _ := os.ReadFile(log)
+		_ = os.Remove(log)
+		return strings.Split(strings.TrimSpace(string(b)), "\n")
+	}
+
+	script("20-db", false)
+	script("10-app", false)
+	if err := os.WriteFile(filepath.Join(dir, "README"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	hook := &BackupScripts{Dir: dir}
+
+	if err := hook.Freeze(); err != nil {
+		t.Fatal(err)
+	}
+	if err := hook.Thaw(); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []string{"10-app freeze", "20-db freeze", "20-db thaw", "10-app thaw"}
+	if out := output(); !reflect.DeepEqual(out, expect) {
+		t.Errorf("output=%v", out)
+	}
+
+	script("30-fail", true)
+
+	err := hook.Freeze()
+	var serr *BackupScriptError
+	if !errors.As(err, &serr) || filepath.Base(serr.Script) != "30-fail" {
+		t.Errorf("err=%v", err)
+	}
+
+	expect = []string{"10-app freeze", "20-db freeze", "30-fail freeze", "20-db freezeFail", "10-app freezeFail"}
+	if out := output(); !reflect.DeepEqual(out, expect) {
+		t.Errorf("output=%v", out)
+	}
+
+	if err := (&BackupScripts{Dir: filepath.Join(dir, "enoent")}).Freeze(); err != nil {
+		t.Error(err)
+	}
+}
@@ -7,6 +7,7 @@ package toolbox
 import (
 	"bytes"
 	"fmt"
+	"sync"
 )
 
 // Channel abstracts the guest<->vmx RPC transport
@@ -25,10 +26,16 @@ var (
 // ChannelOut extends Channel to provide RPCI protocol helpers
 type ChannelOut struct {
 	Channel
+
+	mu sync.Mutex
 }
 
 // Request sends an RPC command to the vmx and checks the return code for success or error
+// Requests are serialized, as they can be sent from multiple goroutines.
 func (c *ChannelOut) Request(request []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if err := c.Send(request); err != nil {
 		return nil, err
 	}
@@ -42,5 +49,15 @@ func (c *ChannelOut) Request(request []byte) ([]byte, error) {
 		return reply[2:], nil
 	}
 
-	return nil, fmt.Errorf("request %q: %q", request, reply)
+	return nil, &RequestError{Request: request, Reply: reply}
+}
+
+// RequestError is returned by ChannelOut.Request when the vmx replies with an error
+type RequestError struct {
+	Request []byte
+	Reply   []byte
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("request %q: %q", e.Request, e.Reply)
 }
@@ -10,6 +10,7 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -102,6 +103,10 @@ func commandResult(header vix.CommandRequestHeader, rc int, err error, response
 }
 
 func (c *CommandServer) Dispatch(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
 	// See ToolsDaemonTcloGetQuotedString
 	if data[0] == '"' {
 		data = data[1:]
@@ -115,7 +120,7 @@ func (c *CommandServer) Dispatch(data []byte) ([]byte, error) {
 		data = data[ix+1:]
 	}
 	// skip the NULL
-	if data[0] == 0 {
+	if len(data) != 0 && data[0] == 0 {
 		data = data[1:]
 	}
 
@@ -141,7 +146,12 @@ func (c *CommandServer) Dispatch(data []byte) ([]byte, error) {
 
 	if header.OpCode != vix.CommandGetToolsState {
 		// Every command expect GetToolsState requires authentication
-		creds := buf.Bytes()[header.BodyLength:]
+		body := buf.Bytes()
+		if uint64(header.BodyLength)+uint64(header.CredentialLength) > uint64(len(body)) {
+			return commandResult(header, vix.InvalidMessageHeader, nil, nil), nil
+		}
+
+		creds := body[header.BodyLength:]
 
 		err = c.authenticate(header, creds[:header.CredentialLength])
 		if err != nil {
@@ -229,9 +229,16 @@ func TestVixCommandErrors(t *testing.T) {
 		t.Error("expected error")
 	}
 
-	r.Body.NumEnvVars = 1
+	// MarshalBinary sets NumEnvVars from EnvVars, remove the env var data to encode NumEnvVars = 1 without any
+	env := "FOO=bar\x00"
+	r.EnvVars = []string{env[:len(env)-1]}
 	buf, _ := r.MarshalBinary()
-	err = r.UnmarshalBinary(buf)
+	err = r.UnmarshalBinary(buf[:len(buf)-len(env)])
+	if err == nil {
+		t.Error("expected error")
+	}
+
+	err = r.UnmarshalBinary(buf[:len(buf)-1]) // truncate env var NULL terminator
 	if err == nil {
 		t.Error("expected error")
 	}
@@ -0,0 +1,151 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vmware/govmomi/toolbox/deploypkg"
+)
+
+// ToolsDeployPkgState as defined in open-vm-tools/lib/include/deployPkg/toolsDeployPkg.h
+const (
+	deployPkgStateIdle = iota
+	deployPkgStatePending
+	deployPkgStateCopying
+	deployPkgStateDeploying
+	deployPkgStateRunning
+	deployPkgStateDone
+)
+
+// ToolsDeployPkgError as defined in toolsDeployPkg.h
+const (
+	deployPkgSuccess = iota
+	deployPkgNotSupported
+	deployPkgPkgNotFound
+	deployPkgRPCInvalid
+	deployPkgCopyFailed
+	deployPkgParseFailed
+	deployPkgDeployFailed
+)
+
+// DeployPkgCommandHandler implements the deployPkg RPCs, used by the VMX to customize the guest.
+// The VMX sends deployPkg.begin, to which the handler replies with a temporary directory.
+// The VMX then transfers the package to this directory via the HGFS file server and sends deployPkg.deploy.
+// The handler decodes the package, applies the customization spec using the Backend
+// and reports the deployment state to the VMX.
+type DeployPkgCommandHandler struct {
+	// Backend applies the customization spec, if nil customization is reported as not supported
+	Backend deploypkg.Backend
+
+	// Reboot is called after the customization is applied, unless the package disables reboot
+	Reboot func() error
+
+	out *ChannelOut
+	dir string
+}
+
+func registerDeployPkgCommandHandler(service *Service) *DeployPkgCommandHandler {
+	handler := &DeployPkgCommandHandler{
+		out: service.out,
+	}
+
+	service.RegisterHandler("deployPkg.begin", handler.Begin)
+	service.RegisterHandler("deployPkg.deploy", handler.Deploy)
+
+	return handler
+}
+
+func (c *DeployPkgCommandHandler) state(state, code int, msg string) {
+	req := fmt.Sprintf("deployPkg.update.state %d %d %s", state, code, msg)
+
+	if _, err := c.out.Request([]byte(strings.TrimSpace(req))); err != nil {
+		log.Printf("unable to send %q: %s", req, err)
+	}
+}
+
+func (c *DeployPkgCommandHandler) cleanup() {
+	if c.dir != "" {
+		_ = os.RemoveAll(c.dir)
+		c.dir = ""
+	}
+}
+
+// Begin handles the deployPkg.begin RPC, replying with the directory the VMX copies the package to.
+func (c *DeployPkgCommandHandler) Begin([]byte) ([]byte, error) {
+	c.cleanup()
+
+	dir, err := os.MkdirTemp("", "deployPkg")
+	if err != nil {
+		return nil, err
+	}
+
+	c.dir = dir
+
+	return []byte(dir), nil
+}
+
+// Deploy handles the deployPkg.deploy RPC, where the argument is the package file path.
+func (c *DeployPkgCommandHandler) Deploy(args []byte) ([]byte, error) {
+	name := strings.TrimRight(string(args), "\x00")
+
+	if c.dir == "" || filepath.Dir(filepath.Clean(name)) != c.dir {
+		c.state(deployPkgStateDone, deployPkgRPCInvalid, "Invalid package path.")
+		return nil, fmt.Errorf("deployPkg: invalid package path %q", name)
+	}
+
+	defer c.cleanup()
+
+	c.state(deployPkgStateDeploying, deployPkgSuccess, "")
+
+	pkg, code, err := c.deploy(name)
+	if err != nil {
+		c.state(deployPkgStateDone, code, err.Error())
+		return nil, err
+	}
+
+	c.state(deployPkgStateDone, deployPkgSuccess, "")
+
+	if c.Reboot != nil && pkg.Flags&deploypkg.FlagSkipReboot == 0 {
+		if err := c.Reboot(); err != nil {
+			log.Printf("deployPkg: %s", err)
+		}
+	}
+
+	return nil, nil
+}
+
+func (c *DeployPkgCommandHandler) deploy(name string) (*deploypkg.Package, int, error) {
+	f, err := os.Open(filepath.Clean(name))
+	if err != nil {
+		return nil, deployPkgPkgNotFound, err
+	}
+	defer f.Close()
+
+	pkg, err := deploypkg.Read(f)
+	if err != nil {
+		return nil, deployPkgParseFailed, err
+	}
+
+	config, err := pkg.Config()
+	if err != nil {
+		return nil, deployPkgParseFailed, err
+	}
+
+	if c.Backend == nil {
+		return nil, deployPkgNotSupported, errors.New("deployPkg: customization not supported")
+	}
+
+	if err = c.Backend.Apply(config); err != nil {
+		return nil, deployPkgDeployFailed, err
+	}
+
+	return pkg, deployPkgSuccess, nil
+}
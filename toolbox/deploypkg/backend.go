@@ -0,0 +1,192 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package deploypkg
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Backend applies a customization spec to the guest
+type Backend interface {
+	Apply(*Config) error
+}
+
+// BackendFunc adapts a function to the Backend interface
+type BackendFunc func(*Config) error
+
+func (f BackendFunc) Apply(c *Config) error {
+	return f(c)
+}
+
+// fileName is the base name of the files written by the Backend implementations
+const fileName = "90-toolbox-customization"
+
+// writeFile writes data to name, relative to the root directory
+func writeFile(root, name string, data []byte, perm os.FileMode) error {
+	name = filepath.Join(root, name)
+
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(name, data, perm)
+}
+
+// writeHostname writes /etc/hostname and the /etc/hosts entry for the configured host name
+func writeHostname(root string, c *Config) error {
+	if c.Hostname == "" {
+		return nil
+	}
+
+	if err := writeFile(root, "/etc/hostname", []byte(c.Hostname+"\n"), 0644); err != nil {
+		return err
+	}
+
+	var hosts bytes.Buffer
+	entry := "127.0.1.1\t" + c.FQDN()
+	if c.Domain != "" {
+		entry += " " + c.Hostname
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "/etc/hosts"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if strings.HasPrefix(line, "127.0.1.1") || line == "" {
+			continue
+		}
+		hosts.WriteString(line + "\n")
+	}
+
+	if hosts.Len() == 0 {
+		hosts.WriteString("127.0.0.1\tlocalhost\n")
+	}
+
+	hosts.WriteString(entry + "\n")
+
+	return writeFile(root, "/etc/hosts", hosts.Bytes(), 0644)
+}
+
+// Netplan is a Backend that writes the host name and a netplan configuration file.
+// The configuration is applied by netplan on the next boot, or by running "netplan apply".
+type Netplan struct {
+	// Root directory, defaults to "/"
+	Root string
+}
+
+func quote(vals []string) string {
+	q := make([]string, len(vals))
+	for i := range vals {
+		q[i] = strconv.Quote(vals[i])
+	}
+	return "[" + strings.Join(q, ", ") + "]"
+}
+
+// Apply writes /etc/netplan/90-toolbox-customization.yaml
+func (n *Netplan) Apply(c *Config) error {
+	if err := writeHostname(n.Root, c); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString("# Generated by toolbox guest customization\n")
+	buf.WriteString("network:\n  version: 2\n  ethernets:\n")
+
+	for _, nic := range c.NICs {
+		if err := validNICName(nic.Name); err != nil {
+			return err
+		}
+		fmt.Fprintf(&buf, "    %s:\n", strings.ToLower(nic.Name))
+		if nic.MAC != "" {
+			fmt.Fprintf(&buf, "      match:\n        macaddress: %q\n", nic.MAC)
+		}
+		fmt.Fprintf(&buf, "      dhcp4: %t\n", nic.DHCP)
+		if len(nic.Addresses) != 0 {
+			fmt.Fprintf(&buf, "      addresses: %s\n", quote(nic.Addresses))
+		}
+		if len(nic.Gateways) != 0 {
+			buf.WriteString("      routes:\n")
+			for _, gw := range nic.Gateways {
+				fmt.Fprintf(&buf, "        - to: default\n          via: %q\n", gw)
+			}
+		}
+		if len(c.DNS.Servers) != 0 || len(c.DNS.Search) != 0 {
+			buf.WriteString("      nameservers:\n")
+			if len(c.DNS.Servers) != 0 {
+				fmt.Fprintf(&buf, "        addresses: %s\n", quote(c.DNS.Servers))
+			}
+			if len(c.DNS.Search) != 0 {
+				fmt.Fprintf(&buf, "        search: %s\n", quote(c.DNS.Search))
+			}
+		}
+	}
+
+	return writeFile(n.Root, filepath.Join("/etc/netplan", fileName+".yaml"), buf.Bytes(), 0600)
+}
+
+// Networkd is a Backend that writes the host name and a systemd-networkd .network file per NIC.
+// The configuration is applied by systemd-networkd on the next boot, or by running "networkctl reload".
+type Networkd struct {
+	// Root directory, defaults to "/"
+	Root string
+}
+
+// Apply writes /etc/systemd/network/90-toolbox-customization-$nic.network
+func (n *Networkd) Apply(c *Config) error {
+	if err := writeHostname(n.Root, c); err != nil {
+		return err
+	}
+
+	for _, nic := range c.NICs {
+		if err := validNICName(nic.Name); err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+
+		buf.WriteString("# Generated by toolbox guest customization\n")
+		buf.WriteString("[Match]\n")
+		if nic.MAC != "" {
+			fmt.Fprintf(&buf, "MACAddress=%s\n", nic.MAC)
+		} else {
+			buf.WriteString("Name=*\n")
+		}
+
+		buf.WriteString("\n[Network]\n")
+		if nic.DHCP {
+			buf.WriteString("DHCP=ipv4\n")
+		} else {
+			buf.WriteString("DHCP=no\n")
+		}
+		for _, addr := range nic.Addresses {
+			fmt.Fprintf(&buf, "Address=%s\n", addr)
+		}
+		for _, gw := range nic.Gateways {
+			fmt.Fprintf(&buf, "Gateway=%s\n", gw)
+		}
+		for _, dns := range c.DNS.Servers {
+			fmt.Fprintf(&buf, "DNS=%s\n", dns)
+		}
+		if len(c.DNS.Search) != 0 {
+			fmt.Fprintf(&buf, "Domains=%s\n", strings.Join(c.DNS.Search, " "))
+		}
+
+		name := fmt.Sprintf("%s-%s.network", fileName, strings.ToLower(nic.Name))
+
+		if err := writeFile(n.Root, filepath.Join("/etc/systemd/network", name), buf.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
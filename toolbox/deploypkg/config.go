@@ -0,0 +1,251 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package deploypkg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NIC is the customization of a network interface
+type NIC struct {
+	// Name of the NIC section in the spec, for example: NIC1
+	Name string
+	// MAC address used to match the guest interface
+	MAC string
+	// DHCP is true if the IPv4 address is obtained via DHCP
+	DHCP bool
+	// Addresses in CIDR notation, IPv4 and IPv6
+	Addresses []string
+	// Gateways used as default routes, IPv4 and IPv6
+	Gateways []string
+}
+
+// DNS is the guest resolver customization
+type DNS struct {
+	Servers []string
+	Search  []string
+}
+
+// Config is the Linux customization spec, as generated by vCenter from a CustomizationSpec.
+type Config struct {
+	Hostname string
+	Domain   string
+	NICs     []NIC
+	DNS      DNS
+	Timezone string
+	UTC      bool
+
+	// Sections contains the raw key value pairs of each section
+	Sections map[string]map[string]string
+}
+
+// FQDN returns the fully qualified host name
+func (c *Config) FQDN() string {
+	if c.Domain == "" {
+		return c.Hostname
+	}
+	return c.Hostname + "." + c.Domain
+}
+
+// parseSections reads the INI formatted spec:
+//
+//	[NETWORK]
+//	HOSTNAME = myhost
+//	DOMAINNAME = example.com
+func parseSections(r io.Reader) (map[string]map[string]string, error) {
+	sections := make(map[string]map[string]string)
+	var section map[string]string
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		s := strings.TrimSpace(scanner.Text())
+
+		if s == "" || s[0] == '#' || s[0] == ';' {
+			continue
+		}
+
+		if s[0] == '[' && s[len(s)-1] == ']' {
+			name := strings.TrimSpace(s[1 : len(s)-1])
+			section = make(map[string]string)
+			sections[name] = section
+			continue
+		}
+
+		key, val, ok := strings.Cut(s, "=")
+		if !ok || section == nil {
+			return nil, fmt.Errorf("deploypkg: %s line %d: invalid syntax", ConfigFile, line)
+		}
+
+		section[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+
+	return sections, scanner.Err()
+}
+
+// indexed returns the values of keys in the form of "KEY|n", ordered by n
+func indexed(section map[string]string, key string) []string {
+	type entry struct {
+		n   int
+		val string
+	}
+	var entries []entry
+
+	for k, v := range section {
+		name, index, ok := strings.Cut(k, "|")
+		if !ok || name != key {
+			continue
+		}
+		n, err := strconv.Atoi(index)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry{n, v})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].n < entries[j].n
+	})
+
+	vals := make([]string, len(entries))
+	for i := range entries {
+		vals[i] = entries[i].val
+	}
+
+	return vals
+}
+
+func split(s string) []string {
+	var vals []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			vals = append(vals, v)
+		}
+	}
+	return vals
+}
+
+func cidr(addr, mask string) (string, error) {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return "", fmt.Errorf("invalid address %q", addr)
+	}
+
+	if ip.To4() != nil {
+		m := net.ParseIP(mask)
+		if m == nil || m.To4() == nil {
+			return "", fmt.Errorf("invalid netmask %q", mask)
+		}
+		ones, bits := net.IPMask(m.To4()).Size()
+		if bits == 0 {
+			return "", fmt.Errorf("invalid netmask %q", mask)
+		}
+		return fmt.Sprintf("%s/%d", addr, ones), nil
+	}
+
+	prefix, err := strconv.Atoi(mask)
+	if err != nil || prefix < 0 || prefix > 128 {
+		return "", fmt.Errorf("invalid prefix %q", mask)
+	}
+	return fmt.Sprintf("%s/%d", addr, prefix), nil
+}
+
+// validNICName reports an error if name cannot be used as a file name component or netplan key.
+func validNICName(name string) error {
+	if name == "" || strings.Contains(name, "..") {
+		return fmt.Errorf("deploypkg: invalid NIC name %q", name)
+	}
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return fmt.Errorf("deploypkg: invalid NIC name %q", name)
+		}
+	}
+	return nil
+}
+
+func parseNIC(name string, section map[string]string) (NIC, error) {
+	if err := validNICName(name); err != nil {
+		return NIC{}, err
+	}
+
+	nic := NIC{
+		Name: name,
+		MAC:  strings.ToLower(section["MACADDR"]),
+		DHCP: strings.EqualFold(section["BOOTPROTO"], "dhcp"),
+	}
+
+	if !nic.DHCP && section["IPADDR"] != "" {
+		addr, err := cidr(section["IPADDR"], section["NETMASK"])
+		if err != nil {
+			return nic, fmt.Errorf("deploypkg: %s: %s", name, err)
+		}
+		nic.Addresses = append(nic.Addresses, addr)
+		nic.Gateways = append(nic.Gateways, split(section["GATEWAY"])...)
+	}
+
+	masks := indexed(section, "IPv6NETMASK")
+	for i, ip := range indexed(section, "IPv6ADDR") {
+		mask := "64"
+		if i < len(masks) {
+			mask = masks[i]
+		}
+		addr, err := cidr(ip, mask)
+		if err != nil {
+			return nic, fmt.Errorf("deploypkg: %s: %s", name, err)
+		}
+		nic.Addresses = append(nic.Addresses, addr)
+	}
+
+	nic.Gateways = append(nic.Gateways, indexed(section, "IPv6GATEWAY")...)
+
+	return nic, nil
+}
+
+// ParseConfig decodes the Linux customization spec (cust.cfg) from r
+func ParseConfig(r io.Reader) (*Config, error) {
+	sections, err := parseSections(r)
+	if err != nil {
+		return nil, err
+	}
+
+	network := sections["NETWORK"]
+	dns := sections["DNS"]
+	datetime := sections["DATETIME"]
+
+	c := &Config{
+		Hostname: network["HOSTNAME"],
+		Domain:   network["DOMAINNAME"],
+		DNS: DNS{
+			Servers: indexed(dns, "NAMESERVER"),
+			Search:  indexed(dns, "SUFFIX"),
+		},
+		Timezone: datetime["TIMEZONE"],
+		UTC:      strings.EqualFold(datetime["UTC"], "yes"),
+		Sections: sections,
+	}
+
+	for _, name := range split(sections["NIC-CONFIG"]["NICS"]) {
+		section, ok := sections[name]
+		if !ok {
+			return nil, fmt.Errorf("deploypkg: section %s not found", name)
+		}
+
+		nic, err := parseNIC(name, section)
+		if err != nil {
+			return nil, err
+		}
+
+		c.NICs = append(c.NICs, nic)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,296 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package deploypkg
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testConfig = `
+[NETWORK]
+NETWORKING = yes
+BOOTPROTO = dhcp
+HOSTNAME = vm1
+DOMAINNAME = example.com
+
+[NIC-CONFIG]
+NICS = NIC1,NIC2
+
+[NIC1]
+MACADDR = 00:50:56:A6:8C:3C
+ONBOOT = yes
+IPv4_MODE = BACKWARDS_COMPATIBLE
+BOOTPROTO = static
+IPADDR = 10.0.0.2
+NETMASK = 255.255.255.0
+GATEWAY = 10.0.0.1
+IPv6ADDR|1 = fc00:10:20:87::100
+IPv6NETMASK|1 = 64
+IPv6GATEWAY|1 = fc00:10:20:87::1
+
+[NIC2]
+MACADDR = 00:50:56:a6:8c:3d
+BOOTPROTO = dhcp
+
+# DNS settings
+[DNS]
+DNSFROMDHCP=no
+SUFFIX|2 = example.org
+SUFFIX|1 = example.com
+NAMESERVER|1 = 10.0.0.53
+
+[DATETIME]
+TIMEZONE = Etc/UTC
+UTC = yes
+`
+
+func TestParseConfig(t *testing.T) {
+	c, err := ParseConfig(strings.NewReader(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.FQDN() != "vm1.example.com" {
+		t.Errorf("fqdn=%s", c.FQDN())
+	}
+
+	expect := []NIC{
+		{
+			Name:      "NIC1",
+			MAC:       "00:50:56:a6:8c:3c",
+			Addresses: []string{"10.0.0.2/24", "fc00:10:20:87::100/64"},
+			Gateways:  []string{"10.0.0.1", "fc00:10:20:87::1"},
+		},
+		{
+			Name: "NIC2",
+			MAC:  "00:50:56:a6:8c:3d",
+			DHCP: true,
+		},
+	}
+
+	if !reflect.DeepEqual(c.NICs, expect) {
+		t.Errorf("nics=%#v", c.NICs)
+	}
+
+	dns := DNS{Servers: []string{"10.0.0.53"}, Search: []string{"example.com", "example.org"}}
+	if !reflect.DeepEqual(c.DNS, dns) {
+		t.Errorf("dns=%#v", c.DNS)
+	}
+
+	if c.Timezone != "Etc/UTC" || !c.UTC {
+		t.Errorf("timezone=%s, utc=%t", c.Timezone, c.UTC)
+	}
+
+	invalid := []string{
+		"HOSTNAME = vm1",
+		"[NETWORK]\nHOSTNAME",
+		"[NIC-CONFIG]\nNICS = NIC1",
+		"[NIC-CONFIG]\nNICS = NIC1\n[NIC1]\nIPADDR = 10.0.0.2\nNETMASK = 24",
+		"[NIC-CONFIG]\nNICS = ../../etc/x\n[../../etc/x]\nBOOTPROTO = dhcp",
+		"[NIC-CONFIG]\nNICS = NIC1: {}\n[NIC1: {}]\nBOOTPROTO = dhcp",
+	}
+
+	for _, s := range invalid {
+		if _, err = ParseConfig(strings.NewReader(s)); err == nil {
+			t.Errorf("expected error parsing %q", s)
+		}
+	}
+}
+
+func TestPackage(t *testing.T) {
+	var buf bytes.Buffer
+
+	files := map[string][]byte{
+		"scripts/Customize.pl": []byte("#!/usr/bin/perl"),
+		ConfigFile:             []byte(testConfig),
+	}
+
+	cmd := "/usr/bin/perl /tmp/.vmware/linux/deploy/scripts/Customize.pl /tmp/.vmware/linux/deploy/cust.cfg"
+
+	if err := Write(&buf, FlagSkipReboot, cmd, files); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.HasPrefix(buf.Bytes(), []byte(Signature)) {
+		t.Fatal("missing signature")
+	}
+
+	pkg, err := Read(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pkg.CommandLine() != cmd || pkg.Flags != FlagSkipReboot || int(pkg.PkgLength) != buf.Len() {
+		t.Errorf("header=%#v", pkg.Header)
+	}
+
+	if !reflect.DeepEqual(pkg.Files, files) {
+		t.Errorf("files=%v", pkg.Files)
+	}
+
+	c, err := pkg.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Hostname != "vm1" {
+		t.Errorf("hostname=%s", c.Hostname)
+	}
+
+	if _, err = Read(strings.NewReader(strings.Repeat("x", HeaderSize))); err != ErrSignature {
+		t.Errorf("err=%v", err)
+	}
+
+	if _, err = Read(bytes.NewReader(buf.Bytes()[:HeaderSize/2])); err == nil {
+		t.Error("expected error")
+	}
+
+	buf.Reset()
+	files = map[string][]byte{"large": make([]byte, maxFileSize+1)}
+	if err = Write(&buf, 0, cmd, files); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Read(bytes.NewReader(buf.Bytes())); err == nil {
+		t.Error("expected error")
+	}
+
+	buf.Reset()
+	files = map[string][]byte{}
+	for i := 0; i <= maxPayloadSize/maxFileSize; i++ {
+		files[fmt.Sprintf("file%d", i)] = make([]byte, maxFileSize)
+	}
+	if err = Write(&buf, 0, cmd, files); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Read(bytes.NewReader(buf.Bytes())); err == nil || !strings.Contains(err.Error(), "payload exceeds") {
+		t.Errorf("err=%v", err)
+	}
+
+	buf.Reset()
+	files = map[string][]byte{}
+	for i := 0; i <= maxFiles; i++ {
+		files[fmt.Sprintf("file%d", i)] = nil
+	}
+	if err = Write(&buf, 0, cmd, files); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Read(bytes.NewReader(buf.Bytes())); err == nil || !strings.Contains(err.Error(), "files") {
+		t.Errorf("err=%v", err)
+	}
+}
+
+func readFiles(t *testing.T, root string) map[string]string {
+	files := make(map[string]string)
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		files[strings.TrimPrefix(path, root)] = string(b)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return files
+}
+
+func TestBackends(t *testing.T) {
+	c, err := ParseConfig(strings.NewReader(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := t.TempDir()
+	if err = writeFile(root, "/etc/hosts", []byte("127.0.0.1\tlocalhost\n127.0.1.1\tubuntu\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = (&Netplan{Root: root}).Apply(c); err != nil {
+		t.Fatal(err)
+	}
+
+	files := readFiles(t, root)
+
+	netplan := `# Generated by toolbox guest customization
+network:
+  version: 2
+  ethernets:
+    nic1:
+      match:
+        macaddress: "00:50:56:a6:8c:3c"
+      dhcp4: false
+      addresses: ["10.0.0.2/24", "fc00:10:20:87::100/64"]
+      routes:
+        - to: default
+          via: "10.0.0.1"
+        - to: default
+          via: "fc00:10:20:87::1"
+      nameservers:
+        addresses: ["10.0.0.53"]
+        search: ["example.com", "example.org"]
+    nic2:
+      match:
+        macaddress: "00:50:56:a6:8c:3d"
+      dhcp4: true
+      nameservers:
+        addresses: ["10.0.0.53"]
+        search: ["example.com", "example.org"]
+`
+
+	expect := map[string]string{
+		"/etc/hostname": "vm1\n",
+		"/etc/hosts":    "127.0.0.1\tlocalhost\n127.0.1.1\tvm1.example.com vm1\n",
+		"/etc/netplan/90-toolbox-customization.yaml": netplan,
+	}
+
+	if !reflect.DeepEqual(files, expect) {
+		t.Errorf("files=%#v", files)
+	}
+
+	root = t.TempDir()
+	if err = (&Networkd{Root: root}).Apply(c); err != nil {
+		t.Fatal(err)
+	}
+
+	files = readFiles(t, root)
+
+	nic1 := `# Generated by toolbox guest customization
+[Match]
+MACAddress=00:50:56:a6:8c:3c
+
+[Network]
+DHCP=no
+Address=10.0.0.2/24
+Address=fc00:10:20:87::100/64
+Gateway=10.0.0.1
+Gateway=fc00:10:20:87::1
+DNS=10.0.0.53
+Domains=example.com example.org
+`
+
+	if files["/etc/systemd/network/90-toolbox-customization-nic1.network"] != nic1 {
+		t.Errorf("files=%#v", files)
+	}
+
+	if !strings.Contains(files["/etc/systemd/network/90-toolbox-customization-nic2.network"], "DHCP=ipv4\n") {
+		t.Errorf("files=%#v", files)
+	}
+
+	if files["/etc/hosts"] != "127.0.0.1\tlocalhost\n127.0.1.1\tvm1.example.com vm1\n" {
+		t.Errorf("hosts=%q", files["/etc/hosts"])
+	}
+}
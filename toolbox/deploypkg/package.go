@@ -0,0 +1,232 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package deploypkg implements the guest side of VMware guest customization packages.
+
+A deploy package is delivered to the guest by the VMX when a VirtualMachine is customized,
+for example via CustomizeVM_Task or govc vm.customize. The package consists of a Header followed
+by a gzip'd tar payload, which contains the Linux customization spec (cust.cfg).
+*/
+package deploypkg
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Defs from: open-vm-tools/libDeployPkg/vmware/deployPkgFormat.h
+
+const (
+	// Signature identifies a deploy package
+	Signature = "VMWPKGV1"
+
+	// HeaderSize is the size of the encoded Header
+	HeaderSize = 512
+
+	commandLength = 464
+
+	// maxFileSize limits the size of a file extracted from the payload.
+	// The payload is expected to contain the customization spec and scripts only.
+	maxFileSize = 16 << 20
+
+	// maxPayloadSize limits the total size of the files extracted from the payload.
+	maxPayloadSize = 64 << 20
+
+	// maxFiles limits the number of files extracted from the payload.
+	maxFiles = 1024
+)
+
+// Payload types
+const (
+	PayloadCAB = iota
+	PayloadZIP
+	PayloadGzippedTar
+)
+
+// Header flags
+const (
+	FlagSkipReboot       = 1 << 0
+	FlagIgnoreCloudInit  = 1 << 1
+	FlagRawCloudInitData = 1 << 2
+)
+
+// ConfigFile is the name of the Linux customization spec within the payload
+const ConfigFile = "cust.cfg"
+
+// ErrSignature is returned when a package does not start with Signature
+var ErrSignature = errors.New("deploypkg: invalid package signature")
+
+// Header is the deploy package header
+type Header struct {
+	Signature     [8]byte
+	MajorVersion  uint8
+	MinorVersion  uint8
+	PayloadType   uint8
+	Flags         uint8
+	PkgLength     uint64
+	PayloadOffset uint64
+	PayloadLength uint64
+	Command       [commandLength]byte
+	Reserved      [HeaderSize - commandLength - 36]byte
+}
+
+// CommandLine returns the Header's command as a string
+func (h *Header) CommandLine() string {
+	return string(bytes.TrimRight(h.Command[:], "\x00"))
+}
+
+// Package is a decoded deploy package
+type Package struct {
+	Header
+
+	// Files contained in the payload, keyed by path
+	Files map[string][]byte
+}
+
+// Config returns the customization spec contained in the package
+func (p *Package) Config() (*Config, error) {
+	for name, data := range p.Files {
+		if path.Base(name) == ConfigFile {
+			return ParseConfig(bytes.NewReader(data))
+		}
+	}
+
+	return nil, fmt.Errorf("deploypkg: %s not found in package", ConfigFile)
+}
+
+// Read decodes a deploy package from r
+func Read(r io.Reader) (*Package, error) {
+	var p Package
+
+	if err := binary.Read(r, binary.LittleEndian, &p.Header); err != nil {
+		return nil, fmt.Errorf("deploypkg: reading header: %w", err)
+	}
+
+	if string(p.Signature[:]) != Signature {
+		return nil, ErrSignature
+	}
+
+	if p.PayloadType != PayloadGzippedTar {
+		return nil, fmt.Errorf("deploypkg: unsupported payload type %d", p.PayloadType)
+	}
+
+	if p.PayloadOffset < HeaderSize {
+		return nil, fmt.Errorf("deploypkg: invalid payload offset %d", p.PayloadOffset)
+	}
+
+	if _, err := io.CopyN(io.Discard, r, int64(p.PayloadOffset-HeaderSize)); err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewReader(io.LimitReader(r, int64(p.PayloadLength)))
+	if err != nil {
+		return nil, err
+	}
+
+	p.Files = make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	size := 0
+
+	for {
+		h, err := tr.Next()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if len(p.Files) == maxFiles {
+			return nil, fmt.Errorf("deploypkg: payload exceeds %d files", maxFiles)
+		}
+
+		var buf bytes.Buffer
+		if _, err = io.Copy(&buf, io.LimitReader(tr, maxFileSize+1)); err != nil {
+			return nil, err
+		}
+		if buf.Len() > maxFileSize {
+			return nil, fmt.Errorf("deploypkg: %s exceeds %d bytes", h.Name, maxFileSize)
+		}
+		size += buf.Len()
+		if size > maxPayloadSize {
+			return nil, fmt.Errorf("deploypkg: payload exceeds %d bytes", maxPayloadSize)
+		}
+
+		p.Files[strings.TrimPrefix(path.Clean(h.Name), "/")] = buf.Bytes()
+	}
+
+	return &p, nil
+}
+
+// Write encodes a deploy package to w, with a gzip'd tar payload containing the given files.
+// The command is the payload command line, which is not used by the toolbox.
+func Write(w io.Writer, flags uint8, command string, files map[string][]byte) error {
+	if len(command) >= commandLength {
+		return fmt.Errorf("deploypkg: command exceeds %d bytes", commandLength-1)
+	}
+
+	var payload bytes.Buffer
+	gz := gzip.NewWriter(&payload)
+	tw := tar.NewWriter(gz)
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		data := files[name]
+		h := &tar.Header{
+			Name:     name,
+			Mode:     0600,
+			Size:     int64(len(data)),
+			Typeflag: tar.TypeReg,
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	h := Header{
+		MajorVersion:  1,
+		PayloadType:   PayloadGzippedTar,
+		Flags:         flags,
+		PkgLength:     uint64(HeaderSize + payload.Len()),
+		PayloadOffset: HeaderSize,
+		PayloadLength: uint64(payload.Len()),
+	}
+	copy(h.Signature[:], Signature)
+	copy(h.Command[:], command)
+
+	if err := binary.Write(w, binary.LittleEndian, &h); err != nil {
+		return err
+	}
+
+	_, err := payload.WriteTo(w)
+	return err
+}
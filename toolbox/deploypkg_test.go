@@ -0,0 +1,135 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi/toolbox/deploypkg"
+)
+
+// recordChannelOut records requests sent to the VMX
+type recordChannelOut struct {
+	requests []string
+}
+
+func (c *recordChannelOut) Start() error {
+	return nil
+}
+
+func (c *recordChannelOut) Stop() error {
+	return nil
+}
+
+func (c *recordChannelOut) Receive() ([]byte, error) {
+	return rpciOK, nil
+}
+
+func (c *recordChannelOut) Send(buf []byte) error {
+	c.requests = append(c.requests, string(buf))
+	return nil
+}
+
+func TestDeployPkg(t *testing.T) {
+	out := new(recordChannelOut)
+	service := NewService(new(mockChannelIn), out)
+
+	var config *deploypkg.Config
+	reboot := 0
+
+	service.DeployPkg.Backend = deploypkg.BackendFunc(func(c *deploypkg.Config) error {
+		config = c
+		if c.Hostname == "fail" {
+			return errors.New("apply failed")
+		}
+		return nil
+	})
+
+	service.DeployPkg.Reboot = func() error {
+		reboot++
+		return nil
+	}
+
+	deploy := func(hostname string, flags uint8) string {
+		reply := string(service.Dispatch([]byte("deployPkg.begin")))
+		dir, ok := strings.CutPrefix(reply, "OK ")
+		if !ok {
+			t.Fatalf("reply=%q", reply)
+		}
+
+		var buf bytes.Buffer
+		spec := "[NETWORK]\nHOSTNAME = " + hostname + "\n"
+		err := deploypkg.Write(&buf, flags, "", map[string][]byte{deploypkg.ConfigFile: []byte(spec)})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Done by the VMX via HGFS file transfer
+		name := filepath.Join(dir, "imcf-xyz")
+		if err = os.WriteFile(name, buf.Bytes(), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		out.requests = nil
+		reply = string(service.Dispatch([]byte("deployPkg.deploy " + name)))
+
+		if _, err = os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("%s not removed: %v", dir, err)
+		}
+
+		return reply
+	}
+
+	if reply := deploy("vm1", 0); reply != "OK " {
+		t.Errorf("reply=%q", reply)
+	}
+
+	expect := []string{"deployPkg.update.state 3 0", "deployPkg.update.state 5 0"}
+	if !reflect.DeepEqual(out.requests, expect) {
+		t.Errorf("requests=%v", out.requests)
+	}
+
+	if config.Hostname != "vm1" || reboot != 1 {
+		t.Errorf("hostname=%s, reboot=%d", config.Hostname, reboot)
+	}
+
+	_ = deploy("vm2", deploypkg.FlagSkipReboot)
+	if config.Hostname != "vm2" || reboot != 1 {
+		t.Errorf("hostname=%s, reboot=%d", config.Hostname, reboot)
+	}
+
+	if reply := deploy("fail", 0); reply != "ERR " {
+		t.Errorf("reply=%q", reply)
+	}
+
+	expect = []string{"deployPkg.update.state 3 0", "deployPkg.update.state 5 6 apply failed"}
+	if !reflect.DeepEqual(out.requests, expect) {
+		t.Errorf("requests=%v", out.requests)
+	}
+
+	service.DeployPkg.Backend = nil
+	_ = deploy("vm3", 0)
+
+	expect = []string{"deployPkg.update.state 3 0", "deployPkg.update.state 5 1 deployPkg: customization not supported"}
+	if !reflect.DeepEqual(out.requests, expect) {
+		t.Errorf("requests=%v", out.requests)
+	}
+
+	out.requests = nil
+	if reply := string(service.Dispatch([]byte("deployPkg.deploy /etc/passwd"))); reply != "ERR " {
+		t.Errorf("reply=%q", reply)
+	}
+
+	expect = []string{"deployPkg.update.state 5 3 Invalid package path."}
+	if !reflect.DeepEqual(out.requests, expect) {
+		t.Errorf("requests=%v", out.requests)
+	}
+}
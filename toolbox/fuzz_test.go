@@ -0,0 +1,29 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"testing"
+
+	"github.com/vmware/govmomi/toolbox/vix"
+)
+
+// The seed corpus is in testdata/fuzz/FuzzCommandServerDispatch, see also vix/testdata/README.md
+
+func FuzzCommandServerDispatch(f *testing.F) {
+	cmd := NewService(new(mockChannelIn), new(mockChannelOut)).Command
+
+	// Request bodies are covered by the vix package fuzz targets,
+	// command handlers are replaced here as they would otherwise operate on the host.
+	for op := range cmd.handlers {
+		cmd.handlers[op] = func(vix.CommandRequestHeader, []byte) ([]byte, error) {
+			return nil, nil
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = cmd.Dispatch(data)
+	})
+}
@@ -0,0 +1,133 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Defs from: open-vm-tools/services/plugins/guestInfo/guestInfoServer.c:GuestInfoSendDiskInfo
+
+const (
+	infoDisks = 3 // guestInfo.h:INFO_DISKS
+
+	diskInfoVersion = 1      // JSON format version
+	maxDisks        = 64     // maximum number of disks reported
+	maxDiskInfoSize = 0x7fff // maximum size of the encoded disk info
+)
+
+// GuestDisk is a mounted filesystem, reported to the VMX as guest.disk
+type GuestDisk struct {
+	// Name is the mount point, for example: /
+	Name string
+	// Total capacity in bytes
+	Total uint64
+	// Free space in bytes
+	Free uint64
+	// FSType is the filesystem type, for example: ext4
+	FSType string
+	// Devices backing the filesystem, in the form of the VMX device name, for example: scsi0:0
+	// The VMX maps these to the virtual disk keys of guest.disk.mappings
+	Devices []string
+}
+
+type guestDiskJSON struct {
+	Name    string   `json:"name"`
+	Free    uint64   `json:"free,string"`
+	Size    uint64   `json:"size,string"`
+	FSType  string   `json:"fstype,omitempty"`
+	Devices []string `json:"devices,omitempty"`
+}
+
+type guestDiskInfoJSON struct {
+	Version string          `json:"version"`
+	Disks   []guestDiskJSON `json:"disks"`
+}
+
+// EncodeGuestDiskInfo encodes disks in the JSON format expected by the VMX:
+//
+//	{"version":"1","disks":[{"name":"/","free":"512","size":"1024","fstype":"ext4","devices":["scsi0:0"]}]}
+//
+// Disks are dropped from the end of the list if the encoding exceeds the VMX size limit.
+func EncodeGuestDiskInfo(disks []GuestDisk) ([]byte, error) {
+	if len(disks) > maxDisks {
+		disks = disks[:maxDisks]
+	}
+
+	info := guestDiskInfoJSON{
+		Version: strconv.Itoa(diskInfoVersion),
+		Disks:   make([]guestDiskJSON, len(disks)),
+	}
+
+	for i, disk := range disks {
+		info.Disks[i] = guestDiskJSON{
+			Name:    disk.Name,
+			Free:    disk.Free,
+			Size:    disk.Total,
+			FSType:  disk.FSType,
+			Devices: disk.Devices,
+		}
+	}
+
+	for {
+		b, err := json.Marshal(info)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(b) <= maxDiskInfoSize || len(info.Disks) == 0 {
+			return b, nil
+		}
+
+		info.Disks = info.Disks[:len(info.Disks)-1]
+	}
+}
+
+// DecodeGuestDiskInfo decodes disks encoded by EncodeGuestDiskInfo.
+func DecodeGuestDiskInfo(b []byte) ([]GuestDisk, error) {
+	var info guestDiskInfoJSON
+
+	if err := json.Unmarshal(b, &info); err != nil {
+		return nil, err
+	}
+
+	if info.Version != strconv.Itoa(diskInfoVersion) {
+		return nil, fmt.Errorf("unsupported disk info version %q", info.Version)
+	}
+
+	disks := make([]GuestDisk, len(info.Disks))
+
+	for i, disk := range info.Disks {
+		disks[i] = GuestDisk{
+			Name:    disk.Name,
+			Total:   disk.Size,
+			Free:    disk.Free,
+			FSType:  disk.FSType,
+			Devices: disk.Devices,
+		}
+	}
+
+	return disks, nil
+}
+
+// DefaultGuestDisks is used by default to report mounted filesystems to the VMX.
+// It can be overridden with the Service.Disks field.
+// Only Linux is currently supported, other platforms report no disks.
+func DefaultGuestDisks() []GuestDisk {
+	disks, _ := guestDisks() // #nosec: Errors unhandled
+	return disks
+}
+
+// GuestInfoDiskInfoRequest returns an INFO_DISKS request for the given disks.
+func GuestInfoDiskInfoRequest(disks []GuestDisk) ([]byte, error) {
+	r, err := EncodeGuestDiskInfo(disks)
+	if err != nil {
+		return nil, err
+	}
+
+	return GuestInfoCommand(infoDisks, r), nil
+}
@@ -0,0 +1,286 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+var (
+	procMounts    = "/proc/self/mounts"
+	sysClassBlock = "/sys/class/block"
+	devPrefix     = "/dev/"
+	statfs        = syscall.Statfs
+)
+
+// Filesystem types of local disks, other types such as network and pseudo filesystems are not reported
+var diskFSTypes = map[string]bool{
+	"btrfs":    true,
+	"ext2":     true,
+	"ext3":     true,
+	"ext4":     true,
+	"jfs":      true,
+	"reiserfs": true,
+	"vfat":     true,
+	"xfs":      true,
+	"zfs":      true,
+}
+
+// unescapeMount decodes the octal escapes used by /proc/mounts for space, tab, newline and backslash.
+func unescapeMount(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// diskMount is a mounted local disk filesystem, as listed in procMounts.
+type diskMount struct {
+	source string // device path
+	name   string // mount point
+	fstype string
+}
+
+// diskMounts returns the mounted local disk filesystems, once per mount point.
+func diskMounts() ([]diskMount, error) {
+	f, err := os.Open(procMounts)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var mounts []diskMount
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+
+		source, name, fstype := unescapeMount(fields[0]), unescapeMount(fields[1]), fields[2]
+
+		if !diskFSTypes[fstype] || !strings.HasPrefix(source, devPrefix) || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		mounts = append(mounts, diskMount{source: source, name: name, fstype: fstype})
+	}
+
+	return mounts, scanner.Err()
+}
+
+func guestDisks() ([]GuestDisk, error) {
+	mounts, err := diskMounts()
+	if err != nil {
+		return nil, err
+	}
+
+	var disks []GuestDisk
+	devices := newGuestDeviceMap()
+
+	for _, m := range mounts {
+		var fs syscall.Statfs_t
+		if err := statfs(m.name, &fs); err != nil {
+			continue
+		}
+
+		disks = append(disks, GuestDisk{
+			Name:    m.name,
+			Total:   fs.Blocks * uint64(fs.Bsize),
+			Free:    fs.Bavail * uint64(fs.Bsize),
+			FSType:  m.fstype,
+			Devices: devices.lookup(m.source),
+		})
+	}
+
+	return disks, nil
+}
+
+// guestFilesystems returns a mount point of each mounted local disk filesystem, once per source device.
+// Bind mounts and btrfs subvolumes share the superblock of their source device, which can only be frozen once.
+func guestFilesystems() ([]string, error) {
+	mounts, err := diskMounts()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+
+	for _, m := range mounts {
+		dev, err := filepath.EvalSymlinks(m.source)
+		if err != nil {
+			dev = m.source
+		}
+		if seen[dev] {
+			continue
+		}
+		seen[dev] = true
+
+		names = append(names, m.name)
+	}
+
+	return names, nil
+}
+
+var (
+	// .../0000:03:00.0/host2/target2:0:1/2:0:1:0/block/sda
+	scsiDevicePath = regexp.MustCompile(`/host\d+/target\d+:\d+:\d+/\d+:\d+:(\d+):\d+/`)
+	// .../0000:0b:00.0/nvme/nvme0/nvme0n1
+	nvmeDevicePath = regexp.MustCompile(`/nvme/nvme\d+/nvme\d+n(\d+)$`)
+	// .../0000:02:03.0/ata1
+	ataPort = regexp.MustCompile(`^ata(\d+)$`)
+)
+
+// guestDeviceMap maps Linux block devices to VMX device names (for example: scsi0:1, sata0:0, nvme0:0).
+// Controller instance numbers are assigned in order of the controllers' PCI addresses per controller type,
+// which matches the VMX assignment for controllers that have not been removed and re-added.
+type guestDeviceMap struct {
+	controllers map[string][]string // controller type -> PCI device paths, sorted
+}
+
+func newGuestDeviceMap() *guestDeviceMap {
+	return &guestDeviceMap{}
+}
+
+// ataPortBase returns the lowest ata port number of the given AHCI controller.
+func ataPortBase(pci string) int {
+	ports, _ := filepath.Glob(filepath.Join(pci, "ata*"))
+	base := -1
+	for _, port := range ports {
+		if m := ataPort.FindStringSubmatch(filepath.Base(port)); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			if base == -1 || n < base {
+				base = n
+			}
+		}
+	}
+	return base
+}
+
+// controller returns the type, PCI device path and unit number of the given sysfs device path.
+func controller(path string) (string, string, int, bool) {
+	if m := nvmeDevicePath.FindStringSubmatchIndex(path); m != nil {
+		ns, _ := strconv.Atoi(path[m[2]:m[3]])
+		return "nvme", path[:m[0]], ns - 1, true
+	}
+
+	m := scsiDevicePath.FindStringSubmatchIndex(path)
+	if m == nil {
+		return "", "", 0, false
+	}
+
+	pci := path[:m[0]]
+	unit, _ := strconv.Atoi(path[m[2]:m[3]])
+
+	// libata devices have a single target per port, where the port is the unit number
+	if p := ataPort.FindStringSubmatch(filepath.Base(pci)); p != nil {
+		pci = filepath.Dir(pci)
+		port, _ := strconv.Atoi(p[1])
+		return "sata", pci, port - ataPortBase(pci), true
+	}
+
+	return "scsi", pci, unit, true
+}
+
+// instance returns the VMX controller number of the given controller.
+func (m *guestDeviceMap) instance(kind, pci string) int {
+	if m.controllers == nil {
+		m.controllers = make(map[string][]string)
+
+		devs, _ := filepath.Glob(filepath.Join(sysClassBlock, "*"))
+		for _, dev := range devs {
+			path, err := filepath.EvalSymlinks(dev)
+			if err != nil {
+				continue
+			}
+			if k, p, _, ok := controller(path); ok {
+				m.controllers[k] = append(m.controllers[k], p)
+			}
+		}
+
+		for k, c := range m.controllers {
+			sort.Strings(c)
+			m.controllers[k] = compactStrings(c)
+		}
+	}
+
+	return sort.SearchStrings(m.controllers[kind], pci)
+}
+
+func compactStrings(s []string) []string {
+	var res []string
+	for i := range s {
+		if i == 0 || s[i] != s[i-1] {
+			res = append(res, s[i])
+		}
+	}
+	return res
+}
+
+// lookup returns the VMX device names of the given /dev block device.
+func (m *guestDeviceMap) lookup(dev string) []string {
+	dev, err := filepath.EvalSymlinks(dev)
+	if err != nil {
+		return nil
+	}
+	return m.lookupBlock(filepath.Base(dev))
+}
+
+// lookupBlock returns the VMX device names of the given block device name.
+func (m *guestDeviceMap) lookupBlock(name string) []string {
+	block := filepath.Join(sysClassBlock, name)
+
+	// device-mapper and md devices are backed by the devices listed in slaves/
+	slaves, _ := filepath.Glob(filepath.Join(block, "slaves", "*"))
+	if len(slaves) != 0 {
+		var names []string
+		for _, slave := range slaves {
+			names = append(names, m.lookupBlock(filepath.Base(slave))...)
+		}
+		sort.Strings(names)
+		return compactStrings(names)
+	}
+
+	path, err := filepath.EvalSymlinks(block)
+	if err != nil {
+		return nil
+	}
+
+	// partitions are resolved to their disk
+	if _, err := os.Stat(filepath.Join(path, "partition")); err == nil {
+		path = filepath.Dir(path)
+	}
+
+	kind, pci, unit, ok := controller(path)
+	if !ok {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("%s%d:%d", kind, m.instance(kind, pci), unit)}
+}
@@ -0,0 +1,123 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestGuestDisksLinux(t *testing.T) {
+	dir := t.TempDir()
+
+	// Fake sysfs tree: 2 pvscsi controllers, an AHCI controller and an NVMe controller
+	devices := map[string]string{
+		"sda":     "pci0000:00/0000:00:15.0/0000:03:00.0/host2/target2:0:0/2:0:0:0/block/sda",
+		"sda1":    "pci0000:00/0000:00:15.0/0000:03:00.0/host2/target2:0:0/2:0:0:0/block/sda/sda1",
+		"sdb":     "pci0000:00/0000:00:15.0/0000:03:00.0/host2/target2:0:1/2:0:1:0/block/sdb",
+		"sdc":     "pci0000:00/0000:00:16.0/0000:0b:00.0/host3/target3:0:2/3:0:2:0/block/sdc",
+		"sdd":     "pci0000:00/0000:00:11.0/0000:02:03.0/ata4/host1/target1:0:0/1:0:0:0/block/sdd",
+		"nvme0n1": "pci0000:00/0000:00:17.0/0000:13:00.0/nvme/nvme0/nvme0n1",
+		"dm-0":    "virtual/block/dm-0",
+	}
+
+	sys := filepath.Join(dir, "sys")
+	sysClassBlock = filepath.Join(sys, "class", "block")
+	dev := filepath.Join(dir, "dev")
+	devPrefix = dev + "/"
+
+	for _, d := range []string{sysClassBlock, dev, filepath.Join(sys, "devices/pci0000:00/0000:00:11.0/0000:02:03.0/ata3")} {
+		if err := os.MkdirAll(d, 0750); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, path := range devices {
+		path = filepath.Join(sys, "devices", path)
+		if err := os.MkdirAll(path, 0750); err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasSuffix(name, "1") && strings.HasPrefix(name, "sd") {
+			if err := os.WriteFile(filepath.Join(path, "partition"), []byte("1"), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.Symlink(path, filepath.Join(sysClassBlock, name)); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dev, name), nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// dm-0 is backed by sdb and sdc
+	slaves := filepath.Join(sys, "devices", devices["dm-0"], "slaves")
+	if err := os.MkdirAll(slaves, 0750); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"sdc", "sdb"} {
+		if err := os.Symlink(filepath.Join(sysClassBlock, name), filepath.Join(slaves, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(dev, "dm-0"), filepath.Join(dev, "vg-data")); err != nil {
+		t.Fatal(err)
+	}
+
+	mounts := []string{
+		"sysfs /sys sysfs rw,nosuid,nodev,noexec,relatime 0 0",
+		"proc /proc proc rw,nosuid,nodev,noexec,relatime 0 0",
+		dev + "/sda1 / ext4 rw,relatime 0 0",
+		dev + "/vg-data /data xfs rw,relatime 0 0",
+		dev + "/sdd /mnt/my\\040disk ext4 rw,relatime 0 0",
+		dev + "/nvme0n1 /var btrfs rw,relatime 0 0",
+		"server:/export /nfs nfs4 rw,relatime 0 0",
+		dev + "/sda1 / ext4 rw,relatime 0 0",
+	}
+
+	procMounts = filepath.Join(dir, "mounts")
+	if err := os.WriteFile(procMounts, []byte(strings.Join(mounts, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	statfs = func(path string, fs *syscall.Statfs_t) error {
+		fs.Bsize = 4096
+		fs.Blocks = 100
+		fs.Bavail = 50
+		return nil
+	}
+
+	defer func() {
+		procMounts = "/proc/self/mounts"
+		sysClassBlock = "/sys/class/block"
+		devPrefix = "/dev/"
+		statfs = syscall.Statfs
+	}()
+
+	disks, err := guestDisks()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []GuestDisk{
+		{Name: "/", FSType: "ext4", Devices: []string{"scsi0:0"}},
+		{Name: "/data", FSType: "xfs", Devices: []string{"scsi0:1", "scsi1:2"}},
+		{Name: "/mnt/my disk", FSType: "ext4", Devices: []string{"sata0:1"}},
+		{Name: "/var", FSType: "btrfs", Devices: []string{"nvme0:0"}},
+	}
+
+	for i := range expect {
+		expect[i].Total = 4096 * 100
+		expect[i].Free = 4096 * 50
+	}
+
+	if !reflect.DeepEqual(disks, expect) {
+		t.Errorf("disks=%#v", disks)
+	}
+}
@@ -0,0 +1,73 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestGuestDiskInfo(t *testing.T) {
+	disks := []GuestDisk{
+		{Name: "/", Total: 1 << 30, Free: 1 << 20, FSType: "ext4", Devices: []string{"scsi0:0"}},
+		{Name: "/data", Total: 1 << 40, Free: 1 << 30, FSType: "xfs", Devices: []string{"scsi0:1", "scsi1:0"}},
+		{Name: "/boot/efi", Total: 512, Free: 256},
+	}
+
+	b, err := EncodeGuestDiskInfo(disks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `{"version":"1","disks":[{"name":"/","free":"1048576","size":"1073741824","fstype":"ext4","devices":["scsi0:0"]},`
+	if !bytes.HasPrefix(b, []byte(expect)) {
+		t.Errorf("encoded=%s", b)
+	}
+
+	dp, err := DecodeGuestDiskInfo(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(disks, dp) {
+		t.Errorf("decode mismatch: %#v", dp)
+	}
+
+	req, err := GuestInfoDiskInfoRequest(disks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(req, append([]byte("SetGuestInfo  3 "), b...)) {
+		t.Errorf("request=%s", req)
+	}
+}
+
+func TestMaxGuestDisks(t *testing.T) {
+	var disks []GuestDisk
+	for i := 0; i < maxDisks*2; i++ {
+		disks = append(disks, GuestDisk{Name: fmt.Sprintf("/mnt/%0600d", i), FSType: "ext4"})
+	}
+
+	b, err := EncodeGuestDiskInfo(disks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(b) > maxDiskInfoSize {
+		t.Errorf("size=%d", len(b))
+	}
+
+	dp, err := DecodeGuestDiskInfo(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dp) == 0 || len(dp) >= maxDisks {
+		t.Errorf("disks=%d", len(dp))
+	}
+}
@@ -0,0 +1,177 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const guestInfoPrefix = "guestinfo."
+
+var (
+	// ErrGuestInfoNotFound is returned by GuestInfo.Get when the variable is not set
+	ErrGuestInfoNotFound = errors.New("guestinfo: no value found")
+
+	// DefaultGuestInfoInterval is the default GuestInfoWatcher polling interval
+	DefaultGuestInfoInterval = 5 * time.Second
+)
+
+// GuestInfo provides access to VMX guestinfo.* variables, using the RPCI info-get and info-set commands.
+// Variables can be set outside the guest with the VirtualMachine extraConfig, for example:
+//
+//	govc vm.change -vm $vm -e guestinfo.foo=bar
+//
+// The "guestinfo." prefix is optional for all key arguments.
+type GuestInfo struct {
+	out *ChannelOut
+}
+
+// NewGuestInfo creates a GuestInfo using the given RPCI Channel, such as NewBackdoorChannelOut.
+// The caller is responsible for starting and stopping the Channel.
+func NewGuestInfo(out Channel) *GuestInfo {
+	return &GuestInfo{out: &ChannelOut{Channel: NewTraceChannel(out)}}
+}
+
+// GuestInfoKey returns key with the "guestinfo." prefix
+func GuestInfoKey(key string) string {
+	if strings.HasPrefix(key, guestInfoPrefix) {
+		return key
+	}
+	return guestInfoPrefix + key
+}
+
+// Get returns the value of the given guestinfo variable or ErrGuestInfoNotFound if not set.
+func (g *GuestInfo) Get(key string) (string, error) {
+	reply, err := g.out.Request([]byte("info-get " + GuestInfoKey(key)))
+	if err != nil {
+		var rerr *RequestError
+		if errors.As(err, &rerr) && bytes.Contains(rerr.Reply, []byte("No value found")) {
+			return "", ErrGuestInfoNotFound
+		}
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// Set the value of the given guestinfo variable.
+func (g *GuestInfo) Set(key, value string) error {
+	_, err := g.out.Request([]byte(fmt.Sprintf("info-set %s %s", GuestInfoKey(key), value)))
+	return err
+}
+
+// List returns the values of the given names with prefix, keyed by the full variable name.
+// The VMX does not provide a method to enumerate variables, so the names must be known by the caller.
+// Variables that are not set are not included in the result.
+func (g *GuestInfo) List(prefix string, names ...string) (map[string]string, error) {
+	vars := make(map[string]string)
+
+	for _, name := range names {
+		key := GuestInfoKey(prefix + name)
+
+		val, err := g.Get(key)
+		if err != nil {
+			if err == ErrGuestInfoNotFound {
+				continue
+			}
+			return nil, err
+		}
+
+		vars[key] = val
+	}
+
+	return vars, nil
+}
+
+// GuestInfoWatcher polls guestinfo variables, invoking callbacks when their values change.
+type GuestInfoWatcher struct {
+	*GuestInfo
+
+	// Interval between polls
+	Interval time.Duration
+
+	mu        sync.Mutex
+	keys      []string
+	callbacks map[string][]func(key, value string)
+	values    map[string]*string
+}
+
+// NewGuestInfoWatcher creates a GuestInfoWatcher using the given GuestInfo.
+func NewGuestInfoWatcher(info *GuestInfo) *GuestInfoWatcher {
+	return &GuestInfoWatcher{
+		GuestInfo: info,
+		Interval:  DefaultGuestInfoInterval,
+		callbacks: make(map[string][]func(string, string)),
+		values:    make(map[string]*string),
+	}
+}
+
+// Watch registers a callback for the given key, invoked on the first Poll if the variable is set,
+// and on each Poll thereafter when its value has changed. A variable that is no longer set has an empty value.
+func (w *GuestInfoWatcher) Watch(key string, callback func(key, value string)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	key = GuestInfoKey(key)
+
+	if _, ok := w.callbacks[key]; !ok {
+		w.keys = append(w.keys, key)
+	}
+
+	w.callbacks[key] = append(w.callbacks[key], callback)
+}
+
+// Poll gets the current value of each watched key, invoking callbacks for those that changed.
+func (w *GuestInfoWatcher) Poll() error {
+	w.mu.Lock()
+	keys := append([]string(nil), w.keys...)
+	w.mu.Unlock()
+
+	for _, key := range keys {
+		val, err := w.Get(key)
+		if err != nil && err != ErrGuestInfoNotFound {
+			return err
+		}
+
+		w.mu.Lock()
+		prev, ok := w.values[key]
+		changed := (ok && *prev != val) || (!ok && err == nil)
+		w.values[key] = &val
+		callbacks := w.callbacks[key]
+		w.mu.Unlock()
+
+		if changed {
+			for _, callback := range callbacks {
+				callback(key, val)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Run calls Poll every Interval until ctx is done or Poll returns an error.
+func (w *GuestInfoWatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Poll(); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
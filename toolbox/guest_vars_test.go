@@ -0,0 +1,174 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockGuestInfoChannel emulates the vmx info-get and info-set RPCI commands
+type mockGuestInfoChannel struct {
+	mu    sync.Mutex
+	vars  map[string]string
+	reply []byte
+}
+
+func (c *mockGuestInfoChannel) Start() error {
+	return nil
+}
+
+func (c *mockGuestInfoChannel) Stop() error {
+	return nil
+}
+
+func (c *mockGuestInfoChannel) set(key, val string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if val == "" {
+		delete(c.vars, key)
+	} else {
+		c.vars[key] = val
+	}
+}
+
+func (c *mockGuestInfoChannel) Send(buf []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cmd, args, _ := strings.Cut(string(buf), " ")
+
+	switch cmd {
+	case "info-get":
+		if val, ok := c.vars[args]; ok {
+			c.reply = []byte("1 " + val)
+		} else {
+			c.reply = []byte("0 No value found")
+		}
+	case "info-set":
+		key, val, _ := strings.Cut(args, " ")
+		c.vars[key] = val
+		c.reply = []byte("1 ")
+	default:
+		c.reply = []byte("0 Unknown command")
+	}
+
+	return nil
+}
+
+func (c *mockGuestInfoChannel) Receive() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.reply, nil
+}
+
+func TestGuestInfo(t *testing.T) {
+	c := &mockGuestInfoChannel{vars: map[string]string{"guestinfo.foo": "bar"}}
+	info := NewGuestInfo(c)
+
+	val, err := info.Get("foo")
+	if err != nil || val != "bar" {
+		t.Errorf("val=%q, err=%v", val, err)
+	}
+
+	_, err = info.Get("guestinfo.enoent")
+	if err != ErrGuestInfoNotFound {
+		t.Errorf("err=%v", err)
+	}
+
+	for key, val := range map[string]string{"app.host": "10.0.0.1", "guestinfo.app.port": "443", "app.mode": "a b c"} {
+		if err = info.Set(key, val); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	vars, err := info.List("app.", "host", "port", "mode", "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]string{
+		"guestinfo.app.host": "10.0.0.1",
+		"guestinfo.app.port": "443",
+		"guestinfo.app.mode": "a b c",
+	}
+
+	if !reflect.DeepEqual(vars, expect) {
+		t.Errorf("vars=%v", vars)
+	}
+
+	if err = NewGuestInfo(new(mockChannelOut)).Set("foo", "bar"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestGuestInfoWatcher(t *testing.T) {
+	c := &mockGuestInfoChannel{vars: map[string]string{"guestinfo.foo": "bar"}}
+	w := NewGuestInfoWatcher(NewGuestInfo(c))
+
+	var changes []string
+	var mu sync.Mutex
+	record := func(key, val string) {
+		mu.Lock()
+		changes = append(changes, key+"="+val)
+		mu.Unlock()
+	}
+
+	w.Watch("foo", record)
+	w.Watch("guestinfo.baz", record)
+
+	poll := func(expect ...string) {
+		t.Helper()
+		changes = nil
+		if err := w.Poll(); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(changes, expect) {
+			t.Errorf("changes=%v", changes)
+		}
+	}
+
+	poll("guestinfo.foo=bar")
+	poll()
+	c.set("guestinfo.baz", "1")
+	poll("guestinfo.baz=1")
+	c.set("guestinfo.foo", "")
+	c.set("guestinfo.baz", "2")
+	poll("guestinfo.foo=", "guestinfo.baz=2")
+	poll()
+
+	w.Interval = time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- w.Run(ctx)
+	}()
+
+	c.set("guestinfo.foo", "again")
+	for {
+		mu.Lock()
+		n := len(changes)
+		mu.Unlock()
+		if n != 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	if err := <-done; err != context.Canceled {
+		t.Errorf("err=%v", err)
+	}
+
+	if changes[0] != "guestinfo.foo=again" {
+		t.Errorf("changes=%v", changes)
+	}
+}
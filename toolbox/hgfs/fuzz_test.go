@@ -0,0 +1,126 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package hgfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+// The seed corpus for each target is in testdata/fuzz/$target, see also testdata/README.md
+
+// fuzzDecoder ensures the decoder of the type returned by val does not panic with arbitrary input,
+// and that a decoded value is unchanged by an encode and decode round trip.
+func fuzzDecoder(f *testing.F, val func() any) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v := val()
+		if UnmarshalBinary(data, v) != nil {
+			return
+		}
+
+		enc, err := MarshalBinary(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		v2 := val()
+		if err = UnmarshalBinary(enc, v2); err != nil {
+			t.Fatalf("decode %x: %s", enc, err)
+		}
+
+		if !reflect.DeepEqual(v, v2) {
+			t.Errorf("round trip: %#v != %#v", v, v2)
+		}
+	})
+}
+
+func FuzzPacket(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		p := new(Packet)
+		if p.UnmarshalBinary(data) != nil {
+			return
+		}
+
+		if int(p.PacketSize) > len(data) || len(p.Payload) != int(p.PacketSize-p.HeaderSize) {
+			t.Errorf("invalid payload size=%d for %#v", len(p.Payload), p.Header)
+		}
+	})
+}
+
+func FuzzRequestCreateSessionV4(f *testing.F) {
+	fuzzDecoder(f, func() any { return new(RequestCreateSessionV4) })
+}
+
+func FuzzReplyCreateSessionV4(f *testing.F) {
+	fuzzDecoder(f, func() any { return new(ReplyCreateSessionV4) })
+}
+
+func FuzzFileName(f *testing.F) {
+	fuzzDecoder(f, func() any { return new(FileName) })
+}
+
+func FuzzFileNameV3(f *testing.F) {
+	fuzzDecoder(f, func() any { return new(FileNameV3) })
+}
+
+func FuzzRequestGetattrV2(f *testing.F) {
+	fuzzDecoder(f, func() any { return new(RequestGetattrV2) })
+}
+
+func FuzzReplyGetattrV2(f *testing.F) {
+	fuzzDecoder(f, func() any { return new(ReplyGetattrV2) })
+}
+
+func FuzzRequestSetattrV2(f *testing.F) {
+	fuzzDecoder(f, func() any { return new(RequestSetattrV2) })
+}
+
+func FuzzRequestOpen(f *testing.F) {
+	fuzzDecoder(f, func() any { return new(RequestOpen) })
+}
+
+func FuzzRequestOpenV3(f *testing.F) {
+	fuzzDecoder(f, func() any { return new(RequestOpenV3) })
+}
+
+func FuzzReplyReadV3(f *testing.F) {
+	fuzzDecoder(f, func() any { return new(ReplyReadV3) })
+}
+
+func FuzzRequestWriteV3(f *testing.F) {
+	fuzzDecoder(f, func() any { return new(RequestWriteV3) })
+}
+
+func FuzzRequestGetattrV3(f *testing.F) {
+	fuzzDecoder(f, func() any { return new(RequestGetattrV3) })
+}
+
+func FuzzReplyGetattrV3(f *testing.F) {
+	fuzzDecoder(f, func() any { return new(ReplyGetattrV3) })
+}
+
+func FuzzRequestSearchOpenV3(f *testing.F) {
+	fuzzDecoder(f, func() any { return new(RequestSearchOpenV3) })
+}
+
+func FuzzReplySearchReadV3(f *testing.F) {
+	fuzzDecoder(f, func() any { return new(ReplySearchReadV3) })
+}
+
+func FuzzRequestCreateDirV3(f *testing.F) {
+	fuzzDecoder(f, func() any { return new(RequestCreateDirV3) })
+}
+
+func FuzzRequestDeleteV3(f *testing.F) {
+	fuzzDecoder(f, func() any { return new(RequestDeleteV3) })
+}
+
+func FuzzRequestRenameV3(f *testing.F) {
+	fuzzDecoder(f, func() any { return new(RequestRenameV3) })
+}
+
+func FuzzReplySearchReadV4(f *testing.F) {
+	fuzzDecoder(f, func() any { return new(ReplySearchReadV4) })
+}
@@ -7,6 +7,7 @@ package hgfs
 import (
 	"os"
 	"syscall"
+	"time"
 )
 
 const attrMask = AttrValidAllocationSize |
@@ -24,7 +25,7 @@ func (a *AttrV2) sysStat(info os.FileInfo) {
 	a.AllocationSize = uint64(sys.Blocks * 512)
 
 	nt := func(t syscall.Timespec) uint64 {
-		return uint64(t.Nano()) // TODO: this is supposed to be Windows NT system time, not needed atm
+		return NTTime(time.Unix(t.Unix()))
 	}
 
 	a.AccessTime = nt(sys.Atim)
@@ -7,10 +7,14 @@ package hgfs
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"strings"
+	"syscall"
+	"time"
 )
 
 // See: https://github.com/vmware/open-vm-tools/blob/master/open-vm-tools/lib/include/hgfsProto.h
@@ -175,6 +179,10 @@ func errorStatus(err error) uint32 {
 		return StatusFileExists
 	case os.IsPermission(err):
 		return StatusOperationNotPermitted
+	case errors.Is(err, syscall.ENOTEMPTY):
+		return StatusDirNotEmpty
+	case errors.Is(err, syscall.ENOTDIR):
+		return StatusNotDirectory
 	}
 
 	return StatusGenericError
@@ -304,6 +312,10 @@ func (r *Packet) UnmarshalBinary(data []byte) error {
 		return err
 	}
 
+	if r.HeaderSize > r.PacketSize || int(r.PacketSize) > len(data) {
+		return ProtocolError(fmt.Errorf("invalid hgfs packet size=%d, header size=%d, data size=%d", r.PacketSize, r.HeaderSize, len(data)))
+	}
+
 	r.Payload = data[r.HeaderSize:r.PacketSize]
 
 	return nil
@@ -524,6 +536,11 @@ func (f *FileName) Path() string {
 	return strings.Join(cp, "/")
 }
 
+// FileNameV3 flags
+const (
+	FileNameUseFileDesc = 1 << 0 // ID field is a file handle, rather than using Name
+)
+
 // FileNameV3 as defined in hgfsProto.h:HgfsFileNameV3
 type FileNameV3 struct {
 	Length   uint32
@@ -606,6 +623,14 @@ type AttrV2 struct {
 	Reserved2      uint64
 }
 
+// ntEpoch is the number of 100ns intervals between the Windows NT epoch (1601) and the unix epoch
+const ntEpoch = 116444736000000000
+
+// NTTime converts t to Windows NT system time, as used by the AttrV2 time fields
+func NTTime(t time.Time) uint64 {
+	return uint64(t.UnixNano()/100 + ntEpoch)
+}
+
 // RequestGetattrV2 as defined in hgfsProto.h:HgfsRequestGetattrV2
 type RequestGetattrV2 struct {
 	Request
@@ -833,3 +858,409 @@ type ReplyWriteV3 struct {
 	ActualSize uint32
 	Reserved   uint64
 }
+
+// RequestCloseV3 as defined in hgfsProto.h:HgfsRequestCloseV3
+type RequestCloseV3 struct {
+	Handle   uint32
+	Reserved uint64
+}
+
+// ReplyCloseV3 as defined in hgfsProto.h:HgfsReplyCloseV3
+type ReplyCloseV3 struct {
+	Reserved uint64
+}
+
+// RequestGetattrV3 as defined in hgfsProto.h:HgfsRequestGetattrV3
+type RequestGetattrV3 struct {
+	Hints    uint64
+	Reserved uint64
+	FileName FileNameV3
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (r *RequestGetattrV3) MarshalBinary() ([]byte, error) {
+	return MarshalBinary(&r.Hints, &r.Reserved, &r.FileName)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (r *RequestGetattrV3) UnmarshalBinary(data []byte) error {
+	return UnmarshalBinary(data, &r.Hints, &r.Reserved, &r.FileName)
+}
+
+// ReplyGetattrV3 as defined in hgfsProto.h:HgfsReplyGetattrV3
+type ReplyGetattrV3 struct {
+	Attr          AttrV2
+	Reserved      uint64
+	SymlinkTarget FileNameV3
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (r *ReplyGetattrV3) MarshalBinary() ([]byte, error) {
+	return MarshalBinary(&r.Attr, &r.Reserved, &r.SymlinkTarget)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (r *ReplyGetattrV3) UnmarshalBinary(data []byte) error {
+	return UnmarshalBinary(data, &r.Attr, &r.Reserved, &r.SymlinkTarget)
+}
+
+// RequestSearchOpenV3 as defined in hgfsProto.h:HgfsRequestSearchOpenV3
+type RequestSearchOpenV3 struct {
+	Reserved uint64
+	DirName  FileNameV3
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (r *RequestSearchOpenV3) MarshalBinary() ([]byte, error) {
+	return MarshalBinary(&r.Reserved, &r.DirName)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (r *RequestSearchOpenV3) UnmarshalBinary(data []byte) error {
+	return UnmarshalBinary(data, &r.Reserved, &r.DirName)
+}
+
+// ReplySearchOpenV3 as defined in hgfsProto.h:HgfsReplySearchOpenV3
+type ReplySearchOpenV3 struct {
+	Search   uint32
+	Reserved uint64
+}
+
+// Search read flags
+const (
+	SearchReadSingleEntry     = 1 << 0 // request flag: return a single entry
+	SearchReadReplyFinalEntry = 1 << 0 // reply flag: no entries follow
+)
+
+// RequestSearchReadV3 as defined in hgfsProto.h:HgfsRequestSearchReadV3
+type RequestSearchReadV3 struct {
+	Search   uint32
+	Offset   uint32
+	Flags    uint32
+	Reserved uint64
+}
+
+// DirEntry as defined in hgfsProto.h:HgfsDirEntry
+type DirEntry struct {
+	NextEntry uint32
+	Attr      AttrV2
+	FileName  FileNameV3
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (e *DirEntry) MarshalBinary() ([]byte, error) {
+	return MarshalBinary(&e.NextEntry, &e.Attr, &e.FileName)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (e *DirEntry) UnmarshalBinary(data []byte) error {
+	return UnmarshalBinary(data, &e.NextEntry, &e.Attr, &e.FileName)
+}
+
+// ReplySearchReadV3 as defined in hgfsProto.h:HgfsReplySearchReadV3
+type ReplySearchReadV3 struct {
+	Count    uint64
+	Reserved uint64
+	Entries  []DirEntry
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (r *ReplySearchReadV3) MarshalBinary() ([]byte, error) {
+	r.Count = uint64(len(r.Entries))
+
+	fields := []any{&r.Count, &r.Reserved}
+
+	for i := range r.Entries {
+		e := &r.Entries[i]
+		e.NextEntry = 0
+		if i != len(r.Entries)-1 {
+			data, err := e.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+			e.NextEntry = uint32(len(data))
+		}
+		fields = append(fields, e)
+	}
+
+	return MarshalBinary(fields...)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (r *ReplySearchReadV3) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+
+	for _, p := range []any{&r.Count, &r.Reserved} {
+		if err := binary.Read(buf, binary.LittleEndian, p); err != nil {
+			return ProtocolError(err)
+		}
+	}
+
+	payload := buf.Bytes()
+	r.Entries = nil
+
+	for i := uint64(0); i < r.Count; i++ {
+		var e DirEntry
+		if err := e.UnmarshalBinary(payload); err != nil {
+			return err
+		}
+		r.Entries = append(r.Entries, e)
+
+		if e.NextEntry == 0 {
+			break
+		}
+		if int(e.NextEntry) > len(payload) {
+			return ProtocolError(io.ErrUnexpectedEOF)
+		}
+		payload = payload[e.NextEntry:]
+	}
+
+	return nil
+}
+
+// RequestSearchCloseV3 as defined in hgfsProto.h:HgfsRequestSearchCloseV3
+type RequestSearchCloseV3 struct {
+	Search   uint32
+	Reserved uint64
+}
+
+// ReplySearchCloseV3 as defined in hgfsProto.h:HgfsReplySearchCloseV3
+type ReplySearchCloseV3 struct {
+	Reserved uint64
+}
+
+// CreateDirV3 valid mask
+const (
+	CreateDirValidSpecialPerms = 1 << iota
+	CreateDirValidOwnerPerms
+	CreateDirValidGroupPerms
+	CreateDirValidOtherPerms
+	CreateDirValidFileName
+	CreateDirValidFileAttr
+)
+
+// RequestCreateDirV3 as defined in hgfsProto.h:HgfsRequestCreateDirV3
+type RequestCreateDirV3 struct {
+	Mask         uint64
+	SpecialPerms uint8
+	OwnerPerms   uint8
+	GroupPerms   uint8
+	OtherPerms   uint8
+	FileAttr     uint64
+	FileName     FileNameV3
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (r *RequestCreateDirV3) MarshalBinary() ([]byte, error) {
+	return MarshalBinary(&r.Mask, &r.SpecialPerms, &r.OwnerPerms, &r.GroupPerms, &r.OtherPerms, &r.FileAttr, &r.FileName)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (r *RequestCreateDirV3) UnmarshalBinary(data []byte) error {
+	return UnmarshalBinary(data, &r.Mask, &r.SpecialPerms, &r.OwnerPerms, &r.GroupPerms, &r.OtherPerms, &r.FileAttr, &r.FileName)
+}
+
+// ReplyCreateDirV3 as defined in hgfsProto.h:HgfsReplyCreateDirV3
+type ReplyCreateDirV3 struct {
+	Reserved uint64
+}
+
+// RequestDeleteV3 as defined in hgfsProto.h:HgfsRequestDeleteV3, used by OpDeleteFileV3 and OpDeleteDirV3
+type RequestDeleteV3 struct {
+	Hints    uint64
+	Reserved uint64
+	FileName FileNameV3
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (r *RequestDeleteV3) MarshalBinary() ([]byte, error) {
+	return MarshalBinary(&r.Hints, &r.Reserved, &r.FileName)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (r *RequestDeleteV3) UnmarshalBinary(data []byte) error {
+	return UnmarshalBinary(data, &r.Hints, &r.Reserved, &r.FileName)
+}
+
+// ReplyDeleteV3 as defined in hgfsProto.h:HgfsReplyDeleteV3
+type ReplyDeleteV3 struct {
+	Reserved uint64
+}
+
+// Rename hints
+const (
+	RenameHintUseSrcFileDesc = 1 << iota
+	RenameHintUseTargetFileDesc
+	RenameHintNoReplaceExisting
+	RenameHintNoCopyAllowed
+)
+
+// RequestRenameV3 as defined in hgfsProto.h:HgfsRequestRenameV3
+type RequestRenameV3 struct {
+	Hints    uint32
+	Reserved uint64
+	OldName  FileNameV3
+	NewName  FileNameV3
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (r *RequestRenameV3) MarshalBinary() ([]byte, error) {
+	old, err := r.OldName.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if r.OldName.Length != 0 {
+		// newName follows the NULL terminator of oldName
+		old = append(old, 0)
+	}
+
+	return MarshalBinary(&r.Hints, &r.Reserved, old, &r.NewName)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (r *RequestRenameV3) UnmarshalBinary(data []byte) error {
+	err := UnmarshalBinary(data, &r.Hints, &r.Reserved, &r.OldName)
+	if err != nil {
+		return err
+	}
+
+	// fixed size fields, oldName fields and name with NULL terminator
+	offset := 4 + 8 + 16 + int(r.OldName.Length) + 1
+	if offset > len(data) {
+		return ProtocolError(io.ErrUnexpectedEOF)
+	}
+
+	return r.NewName.UnmarshalBinary(data[offset:])
+}
+
+// ReplyRenameV3 as defined in hgfsProto.h:HgfsReplyRenameV3
+type ReplyRenameV3 struct {
+	Reserved uint64
+}
+
+// RequestSearchReadV4 as defined in hgfsProto.h:HgfsRequestSearchReadV4
+type RequestSearchReadV4 struct {
+	Flags        uint32
+	InfoFlags    uint32
+	Search       uint32
+	RestartIndex uint32
+	Reserved     uint32
+}
+
+// Search read V4 entry mask
+const (
+	SearchReadName = 1 << iota
+	SearchReadShortName
+	SearchReadFileSize
+	SearchReadAllocationSize
+	SearchReadEaSize
+	SearchReadTimeStamp
+	SearchReadFileAttributes
+	SearchReadFileNodeType
+	SearchReadReparseTag
+	SearchReadFileID
+)
+
+// ShortFileName as defined in hgfsProto.h:HgfsShortFileName
+type ShortFileName struct {
+	Length uint32
+	Name   [48]byte
+}
+
+// DirEntryV4 as defined in hgfsProto.h:HgfsDirEntryV4
+type DirEntryV4 struct {
+	NextEntryOffset uint32
+	FileIndex       uint32
+	Mask            uint32
+	AttrFlags       uint64
+	CreationTime    uint64
+	AccessTime      uint64
+	WriteTime       uint64
+	AttrChangeTime  uint64
+	EndOfFile       uint64
+	AllocationSize  uint64
+	EaSize          uint32
+	FileID          uint64
+	Reserved        uint64
+	ShortName       ShortFileName
+	FileName        FileName
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (e *DirEntryV4) MarshalBinary() ([]byte, error) {
+	return MarshalBinary(&e.NextEntryOffset, &e.FileIndex, &e.Mask, &e.AttrFlags,
+		&e.CreationTime, &e.AccessTime, &e.WriteTime, &e.AttrChangeTime,
+		&e.EndOfFile, &e.AllocationSize, &e.EaSize, &e.FileID, &e.Reserved,
+		&e.ShortName, &e.FileName)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (e *DirEntryV4) UnmarshalBinary(data []byte) error {
+	return UnmarshalBinary(data, &e.NextEntryOffset, &e.FileIndex, &e.Mask, &e.AttrFlags,
+		&e.CreationTime, &e.AccessTime, &e.WriteTime, &e.AttrChangeTime,
+		&e.EndOfFile, &e.AllocationSize, &e.EaSize, &e.FileID, &e.Reserved,
+		&e.ShortName, &e.FileName)
+}
+
+// ReplySearchReadV4 as defined in hgfsProto.h:HgfsReplySearchReadV4
+type ReplySearchReadV4 struct {
+	NumberEntriesReturned uint32
+	OffsetToContinue      uint32
+	Flags                 uint32
+	Reserved              uint32
+	Entries               []DirEntryV4
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (r *ReplySearchReadV4) MarshalBinary() ([]byte, error) {
+	r.NumberEntriesReturned = uint32(len(r.Entries))
+
+	fields := []any{&r.NumberEntriesReturned, &r.OffsetToContinue, &r.Flags, &r.Reserved}
+
+	for i := range r.Entries {
+		e := &r.Entries[i]
+		e.NextEntryOffset = 0
+		if i != len(r.Entries)-1 {
+			data, err := e.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+			e.NextEntryOffset = uint32(len(data))
+		}
+		fields = append(fields, e)
+	}
+
+	return MarshalBinary(fields...)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (r *ReplySearchReadV4) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+
+	for _, p := range []any{&r.NumberEntriesReturned, &r.OffsetToContinue, &r.Flags, &r.Reserved} {
+		if err := binary.Read(buf, binary.LittleEndian, p); err != nil {
+			return ProtocolError(err)
+		}
+	}
+
+	payload := buf.Bytes()
+	r.Entries = nil
+
+	for i := uint32(0); i < r.NumberEntriesReturned; i++ {
+		var e DirEntryV4
+		if err := e.UnmarshalBinary(payload); err != nil {
+			return err
+		}
+		r.Entries = append(r.Entries, e)
+
+		if e.NextEntryOffset == 0 {
+			break
+		}
+		if int(e.NextEntryOffset) > len(payload) {
+			return ProtocolError(io.ErrUnexpectedEOF)
+		}
+		payload = payload[e.NextEntryOffset:]
+	}
+
+	return nil
+}
@@ -6,8 +6,10 @@ package hgfs
 
 import (
 	"bytes"
+	"encoding"
 	"encoding/base64"
 	"io"
+	"reflect"
 	"testing"
 )
 
@@ -184,3 +186,60 @@ func TestProtocolMarshal(t *testing.T) {
 		t.Error("status")
 	}
 }
+
+func TestProtocolRoundTrip(t *testing.T) {
+	name := func(s string) FileNameV3 {
+		return FileNameV3{Name: s, Length: uint32(len(s))}
+	}
+
+	tests := []struct {
+		enc encoding.BinaryMarshaler
+		dec encoding.BinaryUnmarshaler
+	}{
+		{
+			&RequestRenameV3{Hints: RenameHintNoReplaceExisting, OldName: name("data\x00old"), NewName: name("data\x00new")},
+			new(RequestRenameV3),
+		},
+		{
+			&RequestRenameV3{NewName: name("data\x00new")},
+			new(RequestRenameV3),
+		},
+		{
+			&ReplySearchReadV3{Entries: []DirEntry{
+				{Attr: AttrV2{Type: FileTypeDirectory}, FileName: name(".")},
+				{Attr: AttrV2{Size: 42}, FileName: name("file")},
+			}},
+			new(ReplySearchReadV3),
+		},
+		{
+			&ReplySearchReadV4{Flags: SearchReadReplyFinalEntry, Entries: []DirEntryV4{
+				{FileIndex: 1, EndOfFile: 1, FileName: FileName{Name: "a", Length: 1}},
+				{FileIndex: 2, EndOfFile: 2, FileName: FileName{Name: "bb", Length: 2}},
+			}},
+			new(ReplySearchReadV4),
+		},
+		{
+			&RequestCreateDirV3{Mask: CreateDirValidOwnerPerms, OwnerPerms: PermRead, FileName: name("data\x00dir")},
+			new(RequestCreateDirV3),
+		},
+		{
+			&ReplyGetattrV3{Attr: AttrV2{Size: 1}},
+			new(ReplyGetattrV3),
+		},
+	}
+
+	for i, test := range tests {
+		data, err := test.enc.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = test.dec.UnmarshalBinary(data); err != nil {
+			t.Fatalf("%d: %s", i, err)
+		}
+
+		if !reflect.DeepEqual(test.enc, test.dec) {
+			t.Errorf("%d: %#v != %#v", i, test.enc, test.dec)
+		}
+	}
+}
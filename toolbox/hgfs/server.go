@@ -34,6 +34,7 @@ type Server struct {
 	handlers map[int32]func(*Packet) (any, error)
 	schemes  map[string]FileHandler
 	sessions map[uint64]*session
+	shares   map[string]*Share
 	mu       sync.Mutex
 	handle   uint32
 
@@ -50,6 +51,7 @@ func NewServer() *Server {
 	s := &Server{
 		sessions: make(map[uint64]*session),
 		schemes:  make(map[string]FileHandler),
+		shares:   make(map[string]*Share),
 		chmod:    os.Chmod,
 		chown:    os.Chown,
 	}
@@ -64,6 +66,16 @@ func NewServer() *Server {
 		OpOpenV3:           s.OpenV3,
 		OpReadV3:           s.ReadV3,
 		OpWriteV3:          s.WriteV3,
+		OpCloseV3:          s.CloseV3,
+		OpSearchOpenV3:     s.SearchOpenV3,
+		OpSearchReadV3:     s.SearchReadV3,
+		OpSearchReadV4:     s.SearchReadV4,
+		OpSearchCloseV3:    s.SearchCloseV3,
+		OpGetattrV3:        s.GetattrV3,
+		OpCreateDirV3:      s.CreateDirV3,
+		OpDeleteFileV3:     s.DeleteFileV3,
+		OpDeleteDirV3:      s.DeleteDirV3,
+		OpRenameV3:         s.RenameV3,
 	}
 
 	for op := range s.handlers {
@@ -203,8 +215,9 @@ func (s *Server) Stat(name string) (os.FileInfo, error) {
 }
 
 type session struct {
-	files map[uint32]File
-	mu    sync.Mutex
+	files    map[uint32]File
+	searches map[uint32]*search
+	mu       sync.Mutex
 }
 
 // TODO: we currently depend on the VMX to close files and remove sessions,
@@ -212,7 +225,8 @@ type session struct {
 // adding session expiration when implementing OpenModeWriteOnly support.
 func newSession() *session {
 	return &session{
-		files: make(map[uint32]File),
+		files:    make(map[uint32]File),
+		searches: make(map[uint32]*search),
 	}
 }
 
@@ -305,8 +319,9 @@ func (a *AttrV2) Stat(info os.FileInfo) {
 	}
 
 	a.Size = uint64(info.Size())
+	a.WriteTime = NTTime(info.ModTime())
 
-	a.Mask = AttrValidType | AttrValidSize
+	a.Mask = AttrValidType | AttrValidSize | AttrValidWriteTime
 
 	a.sysStat(info)
 }
@@ -321,8 +336,17 @@ func (s *Server) GetattrV2(p *Packet) (any, error) {
 		return nil, err
 	}
 
-	name := req.FileName.Path()
-	info, err := s.Stat(name)
+	share, name, err := s.resolve(req.FileName.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var info os.FileInfo
+	if share == nil {
+		info, err = s.Stat(name)
+	} else {
+		info, err = os.Stat(name)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -342,10 +366,22 @@ func (s *Server) SetattrV2(p *Packet) (any, error) {
 		return nil, err
 	}
 
-	name := req.FileName.Path()
+	share, name, err := s.resolve(req.FileName.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if share != nil {
+		if err = share.writable(); err != nil {
+			return nil, err
+		}
+	}
 
 	_, err = os.Stat(name)
 	if err != nil && os.IsNotExist(err) {
+		if share != nil {
+			return nil, err
+		}
 		// assuming this is a virtual file
 		return res, nil
 	}
@@ -440,25 +476,38 @@ func (s *Server) Close(p *Packet) (any, error) {
 		return nil, err
 	}
 
-	session, err := s.getSession(p)
+	return &ReplyClose{}, s.closeFile(p, req.Handle)
+}
+
+// CloseV3 handles OpCloseV3 requests
+func (s *Server) CloseV3(p *Packet) (any, error) {
+	req := new(RequestCloseV3)
+	err := UnmarshalBinary(p.Payload, req)
 	if err != nil {
 		return nil, err
 	}
 
+	return &ReplyCloseV3{}, s.closeFile(p, req.Handle)
+}
+
+func (s *Server) closeFile(p *Packet, handle uint32) error {
+	session, err := s.getSession(p)
+	if err != nil {
+		return err
+	}
+
 	session.mu.Lock()
-	file, ok := session.files[req.Handle]
+	file, ok := session.files[handle]
 	if ok {
-		delete(session.files, req.Handle)
+		delete(session.files, handle)
 	}
 	session.mu.Unlock()
 
-	if ok {
-		err = file.Close()
-	} else {
-		return nil, &Status{Code: StatusInvalidHandle}
+	if !ok {
+		return &Status{Code: StatusInvalidHandle}
 	}
 
-	return &ReplyClose{}, err
+	return file.Close()
 }
 
 // OpenV3 handles OpOpenV3 requests
@@ -474,7 +523,10 @@ func (s *Server) OpenV3(p *Packet) (any, error) {
 		return nil, err
 	}
 
-	name := req.FileName.Path()
+	share, name, err := s.resolve(req.FileName.Name)
+	if err != nil {
+		return nil, err
+	}
 
 	if req.DesiredLock != LockNone {
 		return nil, &Status{
@@ -483,7 +535,12 @@ func (s *Server) OpenV3(p *Packet) (any, error) {
 		}
 	}
 
-	file, err := s.OpenFile(name, req.OpenMode)
+	var file File
+	if share == nil {
+		file, err = s.OpenFile(name, req.OpenMode)
+	} else {
+		file, err = share.open(name, req)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -520,11 +577,22 @@ func (s *Server) ReadV3(p *Packet) (any, error) {
 		return nil, &Status{Code: StatusInvalidHandle}
 	}
 
-	buf := make([]byte, req.RequiredSize)
+	// The reply payload is limited by the max packet size, regardless of the requested size
+	size := req.RequiredSize
+	if size > LargePacketMax {
+		size = LargePacketMax
+	}
 
-	// Use ReadFull as Read() of an archive io.Pipe may return much smaller chunks,
-	// such as when we've read a tar header.
-	n, err := io.ReadFull(file, buf)
+	buf := make([]byte, size)
+
+	var n int
+	if f, ok := file.(*shareFile); ok {
+		n, err = f.ReadAt(buf, int64(req.Offset))
+	} else {
+		// Use ReadFull as Read() of an archive io.Pipe may return much smaller chunks,
+		// such as when we've read a tar header.
+		n, err = io.ReadFull(file, buf)
+	}
 	if err != nil && n == 0 {
 		if err != io.EOF {
 			return nil, err
@@ -560,7 +628,12 @@ func (s *Server) WriteV3(p *Packet) (any, error) {
 		return nil, &Status{Code: StatusInvalidHandle}
 	}
 
-	n, err := file.Write(req.Payload)
+	var n int
+	if f, ok := file.(*shareFile); ok {
+		n, err = f.write(req)
+	} else {
+		n, err = file.Write(req.Payload)
+	}
 	if err != nil {
 		return nil, err
 	}
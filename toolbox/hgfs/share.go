@@ -0,0 +1,743 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package hgfs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Share is a host directory exported to the guest as an HGFS shared folder
+type Share struct {
+	// Name of the share, the first component of guest file names, for example: /mnt/hgfs/$Name in a Linux guest
+	Name string
+	// Path of the host directory
+	Path string
+	// ReadOnly denies guest requests that modify the share
+	ReadOnly bool
+}
+
+// validName reports whether name is valid as a share name or file name component
+func validName(name string) bool {
+	switch name {
+	case "", ".", "..":
+		return false
+	}
+	return !strings.ContainsAny(name, "/\\\x00")
+}
+
+// AddShare exports the host directory share.Path to the guest, replacing any existing share with the same Name.
+func (s *Server) AddShare(share Share) error {
+	if !validName(share.Name) {
+		return fmt.Errorf("invalid share name %q", share.Name)
+	}
+
+	dir, err := filepath.Abs(share.Path)
+	if err != nil {
+		return err
+	}
+
+	// Symlinks are resolved such that paths within the share can be checked against the real directory path
+	dir, err = filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s: not a directory", share.Path)
+	}
+
+	share.Path = dir
+
+	s.mu.Lock()
+	s.shares[share.Name] = &share
+	s.mu.Unlock()
+
+	return nil
+}
+
+// RemoveShare removes the share with the given name.
+// Files opened by the guest within the share remain open until closed by the guest or the session is destroyed.
+func (s *Server) RemoveShare(name string) {
+	s.mu.Lock()
+	delete(s.shares, name)
+	s.mu.Unlock()
+}
+
+func (s *Server) share(name string) *Share {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.shares[name]
+}
+
+// resolve maps the given CPName to a host file path.
+// If the first name component is a Share name, the path is within Share.Path.
+// Otherwise the returned Share is nil and the "root" share mapping used by file transfers applies.
+func (s *Server) resolve(name string) (*Share, string, error) {
+	cp := strings.Split(name, "\x00")
+
+	share := s.share(cp[0])
+	if share == nil {
+		return nil, (&FileName{Name: name}).Path(), nil
+	}
+
+	path, err := share.path(cp[1:])
+
+	return share, path, err
+}
+
+// resolveShare is resolve for requests that are only supported within a Share
+func (s *Server) resolveShare(name string) (*Share, string, error) {
+	share, path, err := s.resolve(name)
+	if err == nil && share == nil {
+		err = &Status{
+			Err:  fmt.Errorf("no share for name %q", strings.ReplaceAll(name, "\x00", "/")),
+			Code: StatusNoSuchFileOrDir,
+		}
+	}
+
+	return share, path, err
+}
+
+// within reports whether the given path is dir or a descendant of dir
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// path returns the host path of the given name components, ensuring the path does not escape the share directory.
+func (share *Share) path(cp []string) (string, error) {
+	elem := []string{share.Path}
+
+	for _, name := range cp {
+		if name == "" {
+			continue // NUL terminator
+		}
+		if !validName(name) {
+			return "", &Status{
+				Err:  fmt.Errorf("invalid file name %q", name),
+				Code: StatusInvalidName,
+			}
+		}
+		elem = append(elem, name)
+	}
+
+	path := filepath.Join(elem...)
+
+	escape := &Status{
+		Err:  fmt.Errorf("%s: resolves outside of share %q", path, share.Name),
+		Code: StatusAccessDenied,
+	}
+
+	// Symlinks within the path must resolve within the share directory.
+	// Components that do not exist, such as the name of a file to be created, are checked up to the nearest existing parent.
+	for dir := path; ; dir = filepath.Dir(dir) {
+		real, err := filepath.EvalSymlinks(dir)
+		if err == nil {
+			if !within(share.Path, real) {
+				return "", escape
+			}
+			return path, nil
+		}
+
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+
+		if _, err = os.Lstat(dir); err == nil {
+			return "", escape // dangling symlink, where the target may be outside of the share
+		}
+
+		if dir == share.Path {
+			return path, nil // the share directory itself was removed
+		}
+	}
+}
+
+// writable returns an error if the share is read-only
+func (share *Share) writable() error {
+	if share.ReadOnly {
+		return &Status{
+			Err:  fmt.Errorf("share %q is read-only", share.Name),
+			Code: StatusAccessDenied,
+		}
+	}
+	return nil
+}
+
+// modify returns an error if the share is read-only or if path is the share directory itself
+func (share *Share) modify(path string) error {
+	if err := share.writable(); err != nil {
+		return err
+	}
+
+	if path == share.Path {
+		return &Status{
+			Err:  fmt.Errorf("share %q directory cannot be modified", share.Name),
+			Code: StatusAccessDenied,
+		}
+	}
+
+	return nil
+}
+
+// perm returns the permissions given by the request fields, using def for fields that are not set
+func perm(def os.FileMode, owner, group, other uint8) os.FileMode {
+	if owner != 0 {
+		def = def&^0700 | os.FileMode(owner&0x7)<<6
+	}
+	if group != 0 {
+		def = def&^0070 | os.FileMode(group&0x7)<<3
+	}
+	if other != 0 {
+		def = def&^0007 | os.FileMode(other&0x7)
+	}
+	return def
+}
+
+// shareFile is a File opened within a Share, which is read and written at the offset given by each request
+type shareFile struct {
+	*os.File
+}
+
+// write the request payload at the request offset, or at the end of the file with WriteAppend
+func (f *shareFile) write(req *RequestWriteV3) (int, error) {
+	payload := req.Payload
+	if int(req.RequiredSize) < len(payload) {
+		payload = payload[:req.RequiredSize]
+	}
+
+	if req.WriteFlags&WriteAppend != 0 {
+		if _, err := f.Seek(0, io.SeekEnd); err != nil {
+			return 0, err
+		}
+		return f.Write(payload)
+	}
+
+	return f.WriteAt(payload, int64(req.Offset))
+}
+
+// open the file at path within the share, as requested by OpenV3
+func (share *Share) open(path string, req *RequestOpenV3) (File, error) {
+	var flag int
+
+	switch req.OpenMode {
+	case OpenModeReadOnly:
+		flag = os.O_RDONLY
+	case OpenModeWriteOnly:
+		flag = os.O_WRONLY
+	case OpenModeReadWrite:
+		flag = os.O_RDWR
+	default:
+		return nil, &Status{
+			Err:  fmt.Errorf("open mode(%d) not supported for file %q", req.OpenMode, path),
+			Code: StatusInvalidParameter,
+		}
+	}
+
+	switch req.OpenFlags {
+	case Open:
+	case OpenEmpty:
+		flag |= os.O_TRUNC
+	case OpenCreate:
+		flag |= os.O_CREATE
+	case OpenCreateSafe:
+		flag |= os.O_CREATE | os.O_EXCL
+	case OpenCreateEmpty:
+		flag |= os.O_CREATE | os.O_TRUNC
+	default:
+		return nil, &Status{
+			Err:  fmt.Errorf("open flags(%d) not supported for file %q", req.OpenFlags, path),
+			Code: StatusInvalidParameter,
+		}
+	}
+
+	if req.OpenMode != OpenModeReadOnly || flag&(os.O_CREATE|os.O_TRUNC) != 0 {
+		if err := share.writable(); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := os.OpenFile(path, flag, perm(0644, req.OwnerPerms, req.GroupPerms, req.OtherPerms))
+	if err != nil {
+		return nil, err
+	}
+
+	return &shareFile{f}, nil
+}
+
+// search is a snapshot of the directory entries listed by SearchOpenV3
+type search struct {
+	entries []searchEntry
+}
+
+type searchEntry struct {
+	name string
+	attr AttrV2
+}
+
+// shareRootAttr is the attributes of the virtual directory containing all shares
+func shareRootAttr() AttrV2 {
+	return AttrV2{
+		Mask: AttrValidType,
+		Type: FileTypeDirectory,
+	}
+}
+
+// listShares returns the search entries for the virtual directory containing all shares
+func (s *Server) listShares() []searchEntry {
+	s.mu.Lock()
+	shares := make([]*Share, 0, len(s.shares))
+	for _, share := range s.shares {
+		shares = append(shares, share)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(shares, func(i, j int) bool {
+		return shares[i].Name < shares[j].Name
+	})
+
+	root := shareRootAttr()
+	entries := []searchEntry{{".", root}, {"..", root}}
+
+	for _, share := range shares {
+		info, err := os.Stat(share.Path)
+		if err != nil {
+			continue
+		}
+
+		entry := searchEntry{name: share.Name}
+		entry.attr.Stat(info)
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// listDir returns the search entries for the given directory
+func listDir(dir string) ([]searchEntry, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return nil, &Status{
+			Err:  fmt.Errorf("%s: not a directory", dir),
+			Code: StatusNotDirectory,
+		}
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var attr AttrV2
+	attr.Stat(info)
+	entries := []searchEntry{{".", attr}, {"..", attr}}
+
+	for _, file := range files {
+		info, err := os.Stat(filepath.Join(dir, file.Name()))
+		if err != nil {
+			// dangling symlink
+			if info, err = file.Info(); err != nil {
+				continue
+			}
+		}
+
+		entry := searchEntry{name: file.Name()}
+		entry.attr.Stat(info)
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// isShareRoot reports whether the given CPName refers to the virtual directory containing all shares
+func isShareRoot(name string) bool {
+	return strings.Trim(name, "\x00") == ""
+}
+
+// SearchOpenV3 handles OpSearchOpenV3 requests
+func (s *Server) SearchOpenV3(p *Packet) (any, error) {
+	req := new(RequestSearchOpenV3)
+	err := UnmarshalBinary(p.Payload, req)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.getSession(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []searchEntry
+
+	if isShareRoot(req.DirName.Name) {
+		entries = s.listShares()
+	} else {
+		_, dir, err := s.resolveShare(req.DirName.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		entries, err = listDir(dir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res := &ReplySearchOpenV3{
+		Search: s.newHandle(),
+	}
+
+	session.mu.Lock()
+	session.searches[res.Search] = &search{entries: entries}
+	session.mu.Unlock()
+
+	return res, nil
+}
+
+func (s *Server) getSearch(p *Packet, handle uint32) (*search, error) {
+	session, err := s.getSession(p)
+	if err != nil {
+		return nil, err
+	}
+
+	session.mu.Lock()
+	search, ok := session.searches[handle]
+	session.mu.Unlock()
+
+	if !ok {
+		return nil, &Status{Code: StatusInvalidHandle}
+	}
+
+	return search, nil
+}
+
+// maxSearchReadSize is the maximum size of the entries in a search read reply
+const maxSearchReadSize = LargePacketMax - 1024
+
+// SearchReadV3 handles OpSearchReadV3 requests
+func (s *Server) SearchReadV3(p *Packet) (any, error) {
+	req := new(RequestSearchReadV3)
+	err := UnmarshalBinary(p.Payload, req)
+	if err != nil {
+		return nil, err
+	}
+
+	search, err := s.getSearch(p, req.Search)
+	if err != nil {
+		return nil, err
+	}
+
+	res := new(ReplySearchReadV3)
+	size := 0
+
+	for i := int(req.Offset); i >= 0 && i < len(search.entries); i++ {
+		name := search.entries[i].name
+		entry := DirEntry{
+			Attr:     search.entries[i].attr,
+			FileName: FileNameV3{Name: name, Length: uint32(len(name))},
+		}
+
+		data, _ := entry.MarshalBinary()
+		size += len(data)
+		if size > maxSearchReadSize && len(res.Entries) != 0 {
+			break
+		}
+
+		res.Entries = append(res.Entries, entry)
+
+		if req.Flags&SearchReadSingleEntry != 0 {
+			break
+		}
+	}
+
+	return res, nil
+}
+
+// SearchReadV4 handles OpSearchReadV4 requests
+func (s *Server) SearchReadV4(p *Packet) (any, error) {
+	req := new(RequestSearchReadV4)
+	err := UnmarshalBinary(p.Payload, req)
+	if err != nil {
+		return nil, err
+	}
+
+	search, err := s.getSearch(p, req.Search)
+	if err != nil {
+		return nil, err
+	}
+
+	res := new(ReplySearchReadV4)
+	size := 0
+	i := int(req.RestartIndex)
+
+	for ; i >= 0 && i < len(search.entries); i++ {
+		attr := &search.entries[i].attr
+		name := search.entries[i].name
+
+		entry := DirEntryV4{
+			FileIndex:      uint32(i),
+			Mask:           SearchReadName | SearchReadFileSize | SearchReadAllocationSize | SearchReadTimeStamp | SearchReadFileAttributes | SearchReadFileID,
+			AttrFlags:      attr.AttrFlags,
+			CreationTime:   attr.CreationTime,
+			AccessTime:     attr.AccessTime,
+			WriteTime:      attr.WriteTime,
+			AttrChangeTime: attr.AttrChangeTime,
+			EndOfFile:      attr.Size,
+			AllocationSize: attr.AllocationSize,
+			FileID:         attr.HostFileID,
+			FileName:       FileName{Name: name, Length: uint32(len(name))},
+		}
+
+		data, _ := entry.MarshalBinary()
+		size += len(data)
+		if size > maxSearchReadSize && len(res.Entries) != 0 {
+			break
+		}
+
+		res.Entries = append(res.Entries, entry)
+
+		if req.Flags&SearchReadSingleEntry != 0 {
+			i++
+			break
+		}
+	}
+
+	res.OffsetToContinue = uint32(i)
+	if i < 0 || i >= len(search.entries) {
+		res.Flags = SearchReadReplyFinalEntry
+	}
+
+	return res, nil
+}
+
+// SearchCloseV3 handles OpSearchCloseV3 requests
+func (s *Server) SearchCloseV3(p *Packet) (any, error) {
+	req := new(RequestSearchCloseV3)
+	err := UnmarshalBinary(p.Payload, req)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.getSession(p)
+	if err != nil {
+		return nil, err
+	}
+
+	session.mu.Lock()
+	_, ok := session.searches[req.Search]
+	delete(session.searches, req.Search)
+	session.mu.Unlock()
+
+	if !ok {
+		return nil, &Status{Code: StatusInvalidHandle}
+	}
+
+	return &ReplySearchCloseV3{}, nil
+}
+
+// GetattrV3 handles OpGetattrV3 requests
+func (s *Server) GetattrV3(p *Packet) (any, error) {
+	req := new(RequestGetattrV3)
+	err := UnmarshalBinary(p.Payload, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := new(ReplyGetattrV3)
+	var info os.FileInfo
+
+	switch {
+	case req.FileName.Flags&FileNameUseFileDesc != 0:
+		session, err := s.getSession(p)
+		if err != nil {
+			return nil, err
+		}
+
+		session.mu.Lock()
+		file, ok := session.files[req.FileName.ID].(*shareFile)
+		session.mu.Unlock()
+
+		if !ok {
+			return nil, &Status{Code: StatusInvalidHandle}
+		}
+
+		info, err = file.Stat()
+		if err != nil {
+			return nil, err
+		}
+	case isShareRoot(req.FileName.Name):
+		res.Attr = shareRootAttr()
+		return res, nil
+	default:
+		share, name, err := s.resolve(req.FileName.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if share == nil {
+			info, err = s.Stat(name)
+		} else {
+			info, err = os.Stat(name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res.Attr.Stat(info)
+
+	return res, nil
+}
+
+// CreateDirV3 handles OpCreateDirV3 requests
+func (s *Server) CreateDirV3(p *Packet) (any, error) {
+	req := new(RequestCreateDirV3)
+	err := UnmarshalBinary(p.Payload, req)
+	if err != nil {
+		return nil, err
+	}
+
+	share, name, err := s.resolveShare(req.FileName.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = share.modify(name); err != nil {
+		return nil, err
+	}
+
+	var owner, group, other uint8
+	if req.Mask&CreateDirValidOwnerPerms != 0 {
+		owner = req.OwnerPerms
+	}
+	if req.Mask&CreateDirValidGroupPerms != 0 {
+		group = req.GroupPerms
+	}
+	if req.Mask&CreateDirValidOtherPerms != 0 {
+		other = req.OtherPerms
+	}
+
+	if err = os.Mkdir(name, perm(0755, owner, group, other)); err != nil {
+		return nil, err
+	}
+
+	return &ReplyCreateDirV3{}, nil
+}
+
+// DeleteFileV3 handles OpDeleteFileV3 requests
+func (s *Server) DeleteFileV3(p *Packet) (any, error) {
+	return s.deleteV3(p, false)
+}
+
+// DeleteDirV3 handles OpDeleteDirV3 requests
+func (s *Server) DeleteDirV3(p *Packet) (any, error) {
+	return s.deleteV3(p, true)
+}
+
+func (s *Server) deleteV3(p *Packet, dir bool) (any, error) {
+	req := new(RequestDeleteV3)
+	err := UnmarshalBinary(p.Payload, req)
+	if err != nil {
+		return nil, err
+	}
+
+	share, name, err := s.resolveShare(req.FileName.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = share.modify(name); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Lstat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() != dir {
+		if dir {
+			return nil, &Status{
+				Err:  fmt.Errorf("%s: not a directory", name),
+				Code: StatusNotDirectory,
+			}
+		}
+		return nil, &Status{
+			Err:  fmt.Errorf("%s: is a directory", name),
+			Code: StatusOperationNotPermitted,
+		}
+	}
+
+	if err = os.Remove(name); err != nil {
+		return nil, err
+	}
+
+	return &ReplyDeleteV3{}, nil
+}
+
+// RenameV3 handles OpRenameV3 requests
+func (s *Server) RenameV3(p *Packet) (any, error) {
+	req := new(RequestRenameV3)
+	err := UnmarshalBinary(p.Payload, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Hints&(RenameHintUseSrcFileDesc|RenameHintUseTargetFileDesc) != 0 {
+		return nil, &Status{
+			Err:  errors.New("rename by file handle not supported"),
+			Code: StatusOperationNotSupported,
+		}
+	}
+
+	var names [2]string
+
+	for i, name := range []string{req.OldName.Name, req.NewName.Name} {
+		share, path, err := s.resolveShare(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = share.modify(path); err != nil {
+			return nil, err
+		}
+
+		names[i] = path
+	}
+
+	if req.Hints&RenameHintNoReplaceExisting != 0 {
+		if _, err = os.Lstat(names[1]); err == nil {
+			return nil, &Status{
+				Err:  fmt.Errorf("%s: file exists", names[1]),
+				Code: StatusFileExists,
+			}
+		}
+	}
+
+	if err = os.Rename(names[0], names[1]); err != nil {
+		return nil, err
+	}
+
+	return &ReplyRenameV3{}, nil
+}
@@ -0,0 +1,450 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package hgfs
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// cpName converts the given slash separated name to a CPName
+func cpName(name string) FileNameV3 {
+	name = strings.ReplaceAll(name, "/", "\x00")
+	return FileNameV3{Name: name, Length: uint32(len(name))}
+}
+
+func (c *Client) OpenShare(name string, mode, flags int32) (uint32, uint32) {
+	req := &RequestOpenV3{
+		OpenMode:  mode,
+		OpenFlags: flags,
+		FileName:  cpName(name),
+	}
+	res := new(ReplyOpenV3)
+
+	p := c.Dispatch(OpOpenV3, req, res)
+
+	return res.Handle, p.Status
+}
+
+func (c *Client) List(dir string) ([]string, uint32) {
+	req := &RequestSearchOpenV3{DirName: cpName(dir)}
+	res := new(ReplySearchOpenV3)
+
+	p := c.Dispatch(OpSearchOpenV3, req, res)
+	if p.Status != StatusSuccess {
+		return nil, p.Status
+	}
+
+	defer c.Dispatch(OpSearchCloseV3, &RequestSearchCloseV3{Search: res.Search}, new(ReplySearchCloseV3))
+
+	var names []string
+
+	for {
+		rreq := &RequestSearchReadV3{Search: res.Search, Offset: uint32(len(names))}
+		rres := new(ReplySearchReadV3)
+
+		p = c.Dispatch(OpSearchReadV3, rreq, rres)
+		if p.Status != StatusSuccess {
+			return nil, p.Status
+		}
+
+		if rres.Count == 0 {
+			break
+		}
+
+		for _, e := range rres.Entries {
+			names = append(names, e.FileName.Name)
+		}
+	}
+
+	return names, StatusSuccess
+}
+
+func TestAddShare(t *testing.T) {
+	s := NewServer()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		share Share
+		fail  bool
+	}{
+		{Share{Name: "data", Path: dir}, false},
+		{Share{Name: "", Path: dir}, true},
+		{Share{Name: "..", Path: dir}, true},
+		{Share{Name: "a/b", Path: dir}, true},
+		{Share{Name: "file", Path: file}, true},
+		{Share{Name: "enoent", Path: filepath.Join(dir, "enoent")}, true},
+	}
+
+	for _, test := range tests {
+		err := s.AddShare(test.share)
+		if test.fail != (err != nil) {
+			t.Errorf("%#v: err=%v", test.share, err)
+		}
+	}
+
+	if s.share("data") == nil {
+		t.Error("share not found")
+	}
+
+	s.RemoveShare("data")
+
+	if s.share("data") != nil {
+		t.Error("share not removed")
+	}
+}
+
+func TestShare(t *testing.T) {
+	c := NewClient()
+	dir := t.TempDir()
+
+	if err := c.s.AddShare(Share{Name: "data", Path: dir}); err != nil {
+		t.Fatal(err)
+	}
+
+	status := c.CreateSession()
+	if status != StatusSuccess {
+		t.Fatalf("status=%d", status)
+	}
+
+	names, status := c.List("")
+	if status != StatusSuccess {
+		t.Fatalf("status=%d", status)
+	}
+	if strings.Join(names, " ") != ". .. data" {
+		t.Errorf("shares=%v", names)
+	}
+
+	status = c.Dispatch(OpCreateDirV3, &RequestCreateDirV3{FileName: cpName("data/dir")}, new(ReplyCreateDirV3)).Status
+	if status != StatusSuccess {
+		t.Fatalf("status=%d", status)
+	}
+
+	status = c.Dispatch(OpCreateDirV3, &RequestCreateDirV3{FileName: cpName("data/dir")}, new(ReplyCreateDirV3)).Status
+	if status != StatusFileExists {
+		t.Errorf("status=%d", status)
+	}
+
+	handle, status := c.OpenShare("data/dir/file", OpenModeReadWrite, OpenCreateSafe)
+	if status != StatusSuccess {
+		t.Fatalf("status=%d", status)
+	}
+
+	_, status = c.OpenShare("data/dir/file", OpenModeReadWrite, OpenCreateSafe)
+	if status != StatusFileExists {
+		t.Errorf("status=%d", status)
+	}
+
+	for _, w := range []*RequestWriteV3{
+		{Handle: handle, Offset: 0, RequiredSize: 5, Payload: []byte("hello")},
+		{Handle: handle, Offset: 6, RequiredSize: 5, Payload: []byte("world")},
+		{Handle: handle, Offset: 5, RequiredSize: 1, Payload: []byte("_")},
+		{Handle: handle, WriteFlags: WriteAppend, RequiredSize: 1, Payload: []byte("!!")},
+	} {
+		status = c.Dispatch(OpWriteV3, w, new(ReplyWriteV3)).Status
+		if status != StatusSuccess {
+			t.Fatalf("status=%d", status)
+		}
+	}
+
+	rres := new(ReplyReadV3)
+	status = c.Dispatch(OpReadV3, &RequestReadV3{Handle: handle, Offset: 6, RequiredSize: 16}, rres).Status
+	if status != StatusSuccess {
+		t.Fatalf("status=%d", status)
+	}
+	if string(rres.Payload) != "world!" {
+		t.Errorf("read=%q", rres.Payload)
+	}
+
+	ares := new(ReplyGetattrV3)
+	req := &RequestGetattrV3{FileName: FileNameV3{Flags: FileNameUseFileDesc, ID: handle}}
+	status = c.Dispatch(OpGetattrV3, req, ares).Status
+	if status != StatusSuccess {
+		t.Fatalf("status=%d", status)
+	}
+	if ares.Attr.Size != 12 || ares.Attr.Type != FileTypeRegular {
+		t.Errorf("attr=%#v", ares.Attr)
+	}
+
+	status = c.Dispatch(OpCloseV3, &RequestCloseV3{Handle: handle}, new(ReplyCloseV3)).Status
+	if status != StatusSuccess {
+		t.Errorf("status=%d", status)
+	}
+
+	status = c.Dispatch(OpCloseV3, &RequestCloseV3{Handle: handle}, new(ReplyCloseV3)).Status
+	if status != StatusInvalidHandle {
+		t.Errorf("status=%d", status)
+	}
+
+	ares = new(ReplyGetattrV3)
+	status = c.Dispatch(OpGetattrV3, &RequestGetattrV3{FileName: cpName("data/dir")}, ares).Status
+	if status != StatusSuccess {
+		t.Fatalf("status=%d", status)
+	}
+	if ares.Attr.Type != FileTypeDirectory {
+		t.Errorf("attr=%#v", ares.Attr)
+	}
+
+	rename := &RequestRenameV3{OldName: cpName("data/dir/file"), NewName: cpName("data/file")}
+	status = c.Dispatch(OpRenameV3, rename, new(ReplyRenameV3)).Status
+	if status != StatusSuccess {
+		t.Fatalf("status=%d", status)
+	}
+
+	names, _ = c.List("data")
+	if strings.Join(names, " ") != ". .. dir file" {
+		t.Errorf("names=%v", names)
+	}
+
+	deletes := []struct {
+		op     int32
+		name   string
+		status uint32
+	}{
+		{OpDeleteDirV3, "data/file", StatusNotDirectory},
+		{OpDeleteFileV3, "data/dir", StatusOperationNotPermitted},
+		{OpDeleteDirV3, "data", StatusAccessDenied},
+		{OpDeleteFileV3, "data/file", StatusSuccess},
+		{OpDeleteDirV3, "data/dir", StatusSuccess},
+		{OpDeleteDirV3, "data/dir", StatusNoSuchFileOrDir},
+		{OpDeleteFileV3, "enoent/file", StatusNoSuchFileOrDir},
+	}
+
+	for _, test := range deletes {
+		status = c.Dispatch(test.op, &RequestDeleteV3{FileName: cpName(test.name)}, new(ReplyDeleteV3)).Status
+		if status != test.status {
+			t.Errorf("delete %s: status=%d, expected %d", test.name, status, test.status)
+		}
+	}
+
+	names, _ = c.List("data")
+	if len(names) != 2 {
+		t.Errorf("names=%v", names)
+	}
+
+	status = c.DestroySession()
+	if status != StatusSuccess {
+		t.Errorf("status=%d", status)
+	}
+}
+
+func TestShareSearchReadV4(t *testing.T) {
+	c := NewClient()
+	dir := t.TempDir()
+
+	for _, name := range []string{"a", "b", "c"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := c.s.AddShare(Share{Name: "data", Path: dir}); err != nil {
+		t.Fatal(err)
+	}
+
+	c.CreateSession()
+
+	res := new(ReplySearchOpenV3)
+	status := c.Dispatch(OpSearchOpenV3, &RequestSearchOpenV3{DirName: cpName("data")}, res).Status
+	if status != StatusSuccess {
+		t.Fatalf("status=%d", status)
+	}
+
+	var names []string
+	req := &RequestSearchReadV4{Search: res.Search, Flags: SearchReadSingleEntry}
+
+	for {
+		rres := new(ReplySearchReadV4)
+		status = c.Dispatch(OpSearchReadV4, req, rres).Status
+		if status != StatusSuccess {
+			t.Fatalf("status=%d", status)
+		}
+
+		for _, e := range rres.Entries {
+			names = append(names, e.FileName.Name)
+		}
+
+		if rres.Flags&SearchReadReplyFinalEntry != 0 {
+			break
+		}
+
+		req.RestartIndex = rres.OffsetToContinue
+	}
+
+	sort.Strings(names)
+	if strings.Join(names, " ") != ". .. a b c" {
+		t.Errorf("names=%v", names)
+	}
+
+	status = c.Dispatch(OpSearchCloseV3, &RequestSearchCloseV3{Search: res.Search}, new(ReplySearchCloseV3)).Status
+	if status != StatusSuccess {
+		t.Errorf("status=%d", status)
+	}
+
+	status = c.Dispatch(OpSearchReadV4, req, new(ReplySearchReadV4)).Status
+	if status != StatusInvalidHandle {
+		t.Errorf("status=%d", status)
+	}
+}
+
+func TestShareReadOnly(t *testing.T) {
+	c := NewClient()
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "file"), []byte("data"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.s.AddShare(Share{Name: "ro", Path: dir, ReadOnly: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	c.CreateSession()
+
+	handle, status := c.OpenShare("ro/file", OpenModeReadOnly, Open)
+	if status != StatusSuccess {
+		t.Fatalf("status=%d", status)
+	}
+
+	rres := new(ReplyReadV3)
+	status = c.Dispatch(OpReadV3, &RequestReadV3{Handle: handle, RequiredSize: 16}, rres).Status
+	if status != StatusSuccess || string(rres.Payload) != "data" {
+		t.Errorf("status=%d, read=%q", status, rres.Payload)
+	}
+
+	tests := []func() uint32{
+		func() uint32 {
+			_, status := c.OpenShare("ro/file", OpenModeWriteOnly, Open)
+			return status
+		},
+		func() uint32 {
+			_, status := c.OpenShare("ro/file", OpenModeReadOnly, OpenEmpty)
+			return status
+		},
+		func() uint32 {
+			_, status := c.OpenShare("ro/new", OpenModeReadWrite, OpenCreate)
+			return status
+		},
+		func() uint32 {
+			return c.Dispatch(OpCreateDirV3, &RequestCreateDirV3{FileName: cpName("ro/dir")}, new(ReplyCreateDirV3)).Status
+		},
+		func() uint32 {
+			return c.Dispatch(OpDeleteFileV3, &RequestDeleteV3{FileName: cpName("ro/file")}, new(ReplyDeleteV3)).Status
+		},
+		func() uint32 {
+			req := &RequestRenameV3{OldName: cpName("ro/file"), NewName: cpName("ro/renamed")}
+			return c.Dispatch(OpRenameV3, req, new(ReplyRenameV3)).Status
+		},
+		func() uint32 {
+			req := new(RequestSetattrV2)
+			req.FileName.Name = "ro\x00file"
+			return c.Dispatch(OpSetattrV2, req, new(ReplySetattrV2)).Status
+		},
+	}
+
+	for i, test := range tests {
+		status = test()
+		if status != StatusAccessDenied {
+			t.Errorf("%d: status=%d", i, status)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "file")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestShareEscape(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires symlinks")
+	}
+
+	c := NewClient()
+	root := t.TempDir()
+	dir := filepath.Join(root, "share")
+	outside := filepath.Join(root, "outside")
+
+	for _, d := range []string{filepath.Join(dir, "sub"), outside} {
+		if err := os.MkdirAll(d, 0750); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	links := map[string]string{
+		"out":      outside,
+		"rel":      "../outside",
+		"dangling": filepath.Join(outside, "enoent"),
+		"sub/up":   "..",
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := c.s.AddShare(Share{Name: "data", Path: dir}); err != nil {
+		t.Fatal(err)
+	}
+
+	c.CreateSession()
+
+	tests := []struct {
+		name   string
+		status uint32
+	}{
+		{"data/../outside/secret", StatusInvalidName},
+		{"data/sub/../../outside/secret", StatusInvalidName},
+		{"data/./sub", StatusInvalidName},
+		{"data/a\\b", StatusInvalidName},
+		{"data/out/secret", StatusAccessDenied},
+		{"data/out", StatusAccessDenied},
+		{"data/rel/secret", StatusAccessDenied},
+		{"data/dangling", StatusAccessDenied},
+		{"data/out/new", StatusAccessDenied},
+		{"data/sub/up/sub", StatusSuccess},
+	}
+
+	for _, test := range tests {
+		ares := new(ReplyGetattrV3)
+		status := c.Dispatch(OpGetattrV3, &RequestGetattrV3{FileName: cpName(test.name)}, ares).Status
+		if status != test.status {
+			t.Errorf("getattr %s: status=%d, expected %d", test.name, status, test.status)
+		}
+
+		if test.status == StatusSuccess {
+			continue
+		}
+
+		_, status = c.OpenShare(test.name, OpenModeReadWrite, OpenCreate)
+		if status != test.status {
+			t.Errorf("open %s: status=%d, expected %d", test.name, status, test.status)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(outside, "new")); !os.IsNotExist(err) {
+		t.Errorf("err=%v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "enoent")); !os.IsNotExist(err) {
+		t.Errorf("err=%v", err)
+	}
+
+	_, status := c.List("data/out")
+	if status != StatusAccessDenied {
+		t.Errorf("status=%d", status)
+	}
+}
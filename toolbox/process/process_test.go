@@ -14,6 +14,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"testing"
@@ -320,3 +322,27 @@ func TestProcessRoundTripper(t *testing.T) {
 		t.Errorf("len=%d", res.ContentLength)
 	}
 }
+
+func TestProcessSystem(t *testing.T) {
+	list, err := System()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if runtime.GOOS != "linux" {
+		return
+	}
+
+	pid := int64(os.Getpid())
+
+	for _, p := range list {
+		if p.Pid == pid {
+			if filepath.Base(p.Name) != filepath.Base(os.Args[0]) {
+				t.Errorf("name=%s", p.Name)
+			}
+			return
+		}
+	}
+
+	t.Errorf("pid %d not found", pid)
+}
@@ -0,0 +1,69 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"bytes"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+var procDir = "/proc"
+
+// System lists the processes running in the guest OS, as read from /proc.
+// Kernel threads, which have no command line, are not included.
+func System() ([]State, error) {
+	entries, err := os.ReadDir(procDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []State
+	owners := make(map[uint32]string)
+
+	for _, entry := range entries {
+		pid, err := strconv.ParseInt(entry.Name(), 10, 64)
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+
+		dir := filepath.Join(procDir, entry.Name())
+
+		cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline"))
+		if err != nil || len(cmdline) == 0 {
+			continue // process exited or kernel thread
+		}
+
+		args := strings.Split(string(bytes.TrimRight(cmdline, "\x00")), "\x00")
+
+		s := State{
+			Name: args[0],
+			Args: strings.Join(args[1:], " "),
+			Pid:  pid,
+		}
+
+		if info, err := os.Stat(dir); err == nil {
+			if sys, ok := info.Sys().(*syscall.Stat_t); ok {
+				owner, ok := owners[sys.Uid]
+				if !ok {
+					owner = strconv.Itoa(int(sys.Uid))
+					if u, err := user.LookupId(owner); err == nil {
+						owner = u.Username
+					}
+					owners[sys.Uid] = owner
+				}
+				s.Owner = owner
+			}
+		}
+
+		list = append(list, s)
+	}
+
+	return list, nil
+}
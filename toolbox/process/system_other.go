@@ -0,0 +1,13 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+package process
+
+// System lists the processes running in the guest OS.
+// Only Linux is currently supported, other platforms report no processes.
+func System() ([]State, error) {
+	return nil, nil
+}
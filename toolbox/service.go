@@ -53,10 +53,14 @@ type Service struct {
 	delay    time.Duration
 	rpcError bool
 
-	Command *CommandServer
-	Power   *PowerCommandHandler
+	Command   *CommandServer
+	Power     *PowerCommandHandler
+	Backup    *BackupCommandHandler
+	DeployPkg *DeployPkgCommandHandler
+	AppInfo   *AppInfoPublisher
 
 	PrimaryIP func() string
+	Disks     func() []GuestDisk
 }
 
 // NewService initializes a Service instance
@@ -64,12 +68,13 @@ func NewService(rpcIn Channel, rpcOut Channel) *Service {
 	s := &Service{
 		name:     "toolbox", // Same name used by vmtoolsd
 		in:       NewTraceChannel(rpcIn),
-		out:      &ChannelOut{NewTraceChannel(rpcOut)},
+		out:      &ChannelOut{Channel: NewTraceChannel(rpcOut)},
 		handlers: make(map[string]Handler),
 		wg:       new(sync.WaitGroup),
 		stop:     make(chan struct{}),
 
 		PrimaryIP: DefaultIP,
+		Disks:     DefaultGuestDisks,
 	}
 
 	s.RegisterHandler("reset", s.Reset)
@@ -83,6 +88,9 @@ func NewService(rpcIn Channel, rpcOut Channel) *Service {
 	s.Command.FileServer.RegisterFileHandler(hgfs.ArchiveScheme, hgfs.NewArchiveHandler())
 
 	s.Power = registerPowerCommandHandler(s)
+	s.Backup = registerBackupCommandHandler(s)
+	s.DeployPkg = registerDeployPkgCommandHandler(s)
+	s.AppInfo = newAppInfoPublisher(s)
 
 	return s
 }
@@ -180,9 +188,36 @@ func (s *Service) Start() error {
 		}
 	}()
 
+	if s.AppInfo.Interval > 0 {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.publishAppInfo()
+		}()
+	}
+
 	return nil
 }
 
+// publishAppInfo publishes the application inventory once at Start and then
+// every AppInfo.Interval until Stop is called
+func (s *Service) publishAppInfo() {
+	ticker := time.NewTicker(s.AppInfo.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.AppInfo.Publish(); err != nil {
+			log.Printf("publish appInfo: %s", err)
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // Stop cancels the RPC listener routine created via Start
 func (s *Service) Stop() {
 	close(s.stop)
@@ -308,6 +343,9 @@ func (s *Service) CapabilitiesRegister([]byte) ([]byte, error) {
 func (s *Service) SendGuestInfo() {
 	info := []func() ([]byte, error){
 		GuestInfoNicInfoRequest,
+		func() ([]byte, error) {
+			return GuestInfoDiskInfoRequest(s.Disks())
+		},
 	}
 
 	for i, r := range info {
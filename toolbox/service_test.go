@@ -151,6 +151,7 @@ func TestServiceRun(t *testing.T) {
 
 	out.reply = append(out.reply,
 		rpciOK, // reply to SendGuestInfo call in Reset()
+		rpciOK, // reply to SendGuestInfo disk info call in Reset()
 		rpciOK, // reply to IP broadcast
 	)
 
@@ -232,6 +233,7 @@ func TestServiceErrors(t *testing.T) {
 		rpciERR,
 		rpciOK,
 		rpciOK,
+		rpciOK,
 		append(rpciOK, foo...),
 		rpciERR,
 	)
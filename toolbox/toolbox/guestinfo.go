@@ -0,0 +1,93 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+
+	"github.com/vmware/govmomi/toolbox"
+)
+
+const guestInfoUsage = `Usage:
+  toolbox guestinfo get KEY
+  toolbox guestinfo set KEY VALUE
+  toolbox guestinfo list PREFIX NAME...
+  toolbox guestinfo watch [-i INTERVAL] KEY...
+
+The "guestinfo." KEY prefix is optional.`
+
+// guestInfo implements the "guestinfo" subcommand, for use within the guest, for example:
+//
+//	govc vm.change -vm $vm -e guestinfo.foo=bar
+//	toolbox guestinfo get foo
+func guestInfo(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", guestInfoUsage)
+	}
+
+	out := toolbox.NewBackdoorChannelOut()
+	if err := out.Start(); err != nil {
+		return err
+	}
+	defer func() {
+		_ = out.Stop()
+	}()
+
+	info := toolbox.NewGuestInfo(out)
+
+	cmd, args := args[0], args[1:]
+
+	switch {
+	case cmd == "get" && len(args) == 1:
+		val, err := info.Get(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(val)
+	case cmd == "set" && len(args) == 2:
+		return info.Set(args[0], args[1])
+	case cmd == "list" && len(args) > 1:
+		vars, err := info.List(args[0], args[1:]...)
+		if err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(vars))
+		for key := range vars {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Printf("%s=%s\n", key, vars[key])
+		}
+	case cmd == "watch":
+		w := toolbox.NewGuestInfoWatcher(info)
+		fs := flag.NewFlagSet("watch", flag.ExitOnError)
+		fs.DurationVar(&w.Interval, "i", w.Interval, "Polling interval")
+		_ = fs.Parse(args)
+		if fs.NArg() == 0 {
+			return fmt.Errorf("%s", guestInfoUsage)
+		}
+		for _, key := range fs.Args() {
+			w.Watch(key, func(key, value string) {
+				fmt.Printf("%s=%s\n", key, value)
+			})
+		}
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+		if err := w.Run(ctx); err != context.Canceled {
+			return err
+		}
+	default:
+		return fmt.Errorf("%s", guestInfoUsage)
+	}
+
+	return nil
+}
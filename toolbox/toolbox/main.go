@@ -12,12 +12,21 @@ import (
 	"syscall"
 
 	"github.com/vmware/govmomi/toolbox"
+	"github.com/vmware/govmomi/toolbox/deploypkg"
 )
 
 // This example can be run on a VM hosted by ESX, Fusion or Workstation
 func main() {
+	appInfo := flag.Duration("appinfo", 0, "Application inventory publishing interval, 0 to disable")
 	flag.Parse()
 
+	if flag.Arg(0) == "guestinfo" {
+		if err := guestInfo(flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	in := toolbox.NewBackdoorChannelIn()
 	out := toolbox.NewBackdoorChannelOut()
 
@@ -26,8 +35,20 @@ func main() {
 	if os.Getuid() == 0 {
 		service.Power.Halt.Handler = toolbox.Halt
 		service.Power.Reboot.Handler = toolbox.Reboot
+		service.Backup.Hooks = []toolbox.BackupHook{
+			&toolbox.BackupScripts{Dir: toolbox.DefaultBackupScriptsDir},
+			new(toolbox.FilesystemFreeze),
+		}
+		service.DeployPkg.Reboot = toolbox.Reboot
+		if _, err := os.Stat("/etc/netplan"); err == nil {
+			service.DeployPkg.Backend = new(deploypkg.Netplan)
+		} else {
+			service.DeployPkg.Backend = new(deploypkg.Networkd)
+		}
 	}
 
+	service.AppInfo.Interval = *appInfo
+
 	err := service.Start()
 	if err != nil {
 		log.Fatal(err)
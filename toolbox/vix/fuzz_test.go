@@ -0,0 +1,103 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package vix
+
+import (
+	"encoding"
+	"reflect"
+	"testing"
+)
+
+// The seed corpus for each target is in testdata/fuzz/$target, see also testdata/README.md
+
+type codec interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// fuzzDecoder ensures the decoder of the type returned by val does not panic with arbitrary input,
+// and that a decoded value is unchanged by an encode and decode round trip.
+func fuzzDecoder(f *testing.F, val func() codec) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v := val()
+		if v.UnmarshalBinary(data) != nil {
+			return
+		}
+
+		enc, err := v.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		v2 := val()
+		if err = v2.UnmarshalBinary(enc); err != nil {
+			t.Fatalf("decode %x: %s", enc, err)
+		}
+
+		if !reflect.DeepEqual(v, v2) {
+			t.Errorf("round trip: %#v != %#v", v, v2)
+		}
+	})
+}
+
+func FuzzStartProgramRequest(f *testing.F) {
+	fuzzDecoder(f, func() codec { return new(StartProgramRequest) })
+}
+
+func FuzzKillProcessRequest(f *testing.F) {
+	fuzzDecoder(f, func() codec { return new(KillProcessRequest) })
+}
+
+func FuzzListProcessesRequest(f *testing.F) {
+	fuzzDecoder(f, func() codec { return new(ListProcessesRequest) })
+}
+
+func FuzzReadEnvironmentVariablesRequest(f *testing.F) {
+	fuzzDecoder(f, func() codec { return new(ReadEnvironmentVariablesRequest) })
+}
+
+func FuzzCreateTempFileRequest(f *testing.F) {
+	fuzzDecoder(f, func() codec { return new(CreateTempFileRequest) })
+}
+
+func FuzzFileRequest(f *testing.F) {
+	fuzzDecoder(f, func() codec { return new(FileRequest) })
+}
+
+func FuzzDirRequest(f *testing.F) {
+	fuzzDecoder(f, func() codec { return new(DirRequest) })
+}
+
+func FuzzRenameFileRequest(f *testing.F) {
+	fuzzDecoder(f, func() codec { return new(RenameFileRequest) })
+}
+
+func FuzzListFilesRequest(f *testing.F) {
+	fuzzDecoder(f, func() codec { return new(ListFilesRequest) })
+}
+
+func FuzzSetGuestFileAttributesRequest(f *testing.F) {
+	fuzzDecoder(f, func() codec { return new(SetGuestFileAttributesRequest) })
+}
+
+func FuzzCommandHgfsSendPacket(f *testing.F) {
+	fuzzDecoder(f, func() codec { return new(CommandHgfsSendPacket) })
+}
+
+func FuzzInitiateFileTransferToGuestRequest(f *testing.F) {
+	fuzzDecoder(f, func() codec { return new(InitiateFileTransferToGuestRequest) })
+}
+
+func FuzzUserCredentialNamePassword(f *testing.F) {
+	fuzzDecoder(f, func() codec { return new(UserCredentialNamePassword) })
+}
+
+func FuzzProperty(f *testing.F) {
+	fuzzDecoder(f, func() codec { return new(Property) })
+}
+
+func FuzzPropertyList(f *testing.F) {
+	fuzzDecoder(f, func() codec { return new(PropertyList) })
+}
@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"errors"
+	"io"
 )
 
 // Property type enum as defined in open-vm-tools/lib/include/vix.h
@@ -125,6 +126,20 @@ func NewBlobProperty(id int32, val []byte) *Property {
 func (p *Property) MarshalBinary() ([]byte, error) {
 	buf := new(bytes.Buffer)
 
+	// Length is derived from the value, rather than trusting the header of a decoded Property
+	switch p.header.Kind {
+	case vixPropertyTypeBool:
+		p.header.Length = 1
+	case vixPropertyTypeInt32:
+		p.header.Length = int32Size
+	case vixPropertyTypeInt64:
+		p.header.Length = int32Size * 2
+	case vixPropertyTypeString:
+		p.header.Length = int32(len(p.data.String) + 1)
+	case vixPropertyTypeBlob:
+		p.header.Length = int32(len(p.data.Blob))
+	}
+
 	// #nosec: Errors unhandled
 	_ = binary.Write(buf, binary.LittleEndian, &p.header)
 
@@ -160,6 +175,10 @@ func (p *Property) UnmarshalBinary(data []byte) error {
 		return err
 	}
 
+	if p.header.Length < 0 || int(p.header.Length) > buf.Len() {
+		return io.ErrUnexpectedEOF
+	}
+
 	switch p.header.Kind {
 	case vixPropertyTypeBool:
 		return binary.Read(buf, binary.LittleEndian, &p.data.Bool)
@@ -201,6 +220,9 @@ func (l *PropertyList) UnmarshalBinary(data []byte) error {
 		*l = append(*l, p)
 
 		offset := headerSize + p.header.Length
+		if offset < headerSize || int(offset) > len(data) {
+			return io.ErrUnexpectedEOF
+		}
 		data = data[offset:]
 
 		if len(data) == 0 {
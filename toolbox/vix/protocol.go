@@ -9,6 +9,7 @@ import (
 	"encoding/base64"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"syscall"
@@ -162,18 +163,17 @@ type StartProgramRequest struct {
 func (r *StartProgramRequest) MarshalBinary() ([]byte, error) {
 	var env bytes.Buffer
 
-	if n := len(r.EnvVars); n != 0 {
-		for _, e := range r.EnvVars {
-			_, _ = env.Write([]byte(e))
-			_ = env.WriteByte(0)
-		}
-		r.Body.NumEnvVars = uint32(n)
-		r.Body.EnvVarLength = uint32(env.Len())
+	for _, e := range r.EnvVars {
+		_, _ = env.Write([]byte(e))
+		_ = env.WriteByte(0)
 	}
+	r.Body.NumEnvVars = uint32(len(r.EnvVars))
+	r.Body.EnvVarLength = uint32(env.Len())
 
 	var fields []string
 
 	add := func(s string, l *uint32) {
+		*l = 0
 		if n := len(s); n != 0 {
 			*l = uint32(n) + 1
 			fields = append(fields, s)
@@ -301,6 +301,11 @@ func (r *ListProcessesRequest) UnmarshalBinary(data []byte) error {
 		return err
 	}
 
+	// each pid is an int64
+	if uint64(r.Body.NumPids)*8 > uint64(buf.Len()) {
+		return io.ErrUnexpectedEOF
+	}
+
 	r.Pids = make([]int64, r.Body.NumPids)
 
 	for i := uint32(0); i < r.Body.NumPids; i++ {
@@ -328,14 +333,12 @@ type ReadEnvironmentVariablesRequest struct {
 func (r *ReadEnvironmentVariablesRequest) MarshalBinary() ([]byte, error) {
 	var env bytes.Buffer
 
-	if n := len(r.Names); n != 0 {
-		for _, e := range r.Names {
-			_, _ = env.Write([]byte(e))
-			_ = env.WriteByte(0)
-		}
-		r.Body.NumNames = uint32(n)
-		r.Body.NamesLength = uint32(env.Len())
+	for _, e := range r.Names {
+		_, _ = env.Write([]byte(e))
+		_ = env.WriteByte(0)
 	}
+	r.Body.NumNames = uint32(len(r.Names))
+	r.Body.NamesLength = uint32(env.Len())
 
 	buf := new(bytes.Buffer)
 
@@ -608,6 +611,7 @@ func (r *ListFilesRequest) MarshalBinary() ([]byte, error) {
 	var fields []string
 
 	add := func(s string, l *uint32) {
+		*l = 0
 		if n := len(s); n != 0 {
 			*l = uint32(n) + 1
 			fields = append(fields, s)
@@ -809,6 +813,11 @@ func (c *UserCredentialNamePassword) UnmarshalBinary(data []byte) error {
 		return err
 	}
 
+	// name and password are each NULL terminated
+	if uint64(c.Body.NameLength)+2 > uint64(len(str)) {
+		return io.ErrUnexpectedEOF
+	}
+
 	c.Name = string(str[0:c.Body.NameLength])
 	c.Password = string(str[c.Body.NameLength+1 : len(str)-1])
 
@@ -0,0 +1,254 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package vmx
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/vmware/govmomi/toolbox/hgfs"
+	"github.com/vmware/govmomi/toolbox/vix"
+)
+
+// hgfsChunkSize is the size of file data sent or received per hgfs packet, less than hgfs.LargePacketMax
+const hgfsChunkSize = 0x8000
+
+// Vix sends the given command to the guest via the Vix_1_Relayed_Command TCLO request.
+// The guest's response data is returned, or a vix.Error if the guest replies with an error code.
+func (v *VMX) Vix(ctx context.Context, creds *vix.UserCredentialNamePassword, op uint32, r encoding.BinaryMarshaler) ([]byte, error) {
+	body, err := r.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := creds.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	header := vix.CommandRequestHeader{
+		OpCode:             op,
+		UserCredentialType: vix.UserCredentialTypeNamePassword,
+	}
+	header.Magic = vix.CommandMagicWord
+	header.BodyLength = uint32(len(body))
+	header.CredentialLength = uint32(len(auth))
+
+	if op == vix.HgfsSendPacketCommand {
+		header.CommonFlags = vix.CommandGuestReturnsBinary
+	}
+
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("Vix_1_Relayed_Command \"reqname\"\x00")
+	_ = binary.Write(&buf, binary.LittleEndian, &header)
+	_, _ = buf.Write(body)
+	_, _ = buf.Write(auth)
+
+	reply, err := v.Request(ctx, buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	// All Foundry tools commands return results that start with a foundry error and a guest-OS-specific error
+	fields := bytes.SplitN(reply, []byte{' '}, 3)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("vix op=%d: invalid reply %q", op, reply)
+	}
+
+	rc, err := strconv.Atoi(string(fields[0]))
+	if err != nil {
+		return nil, fmt.Errorf("vix op=%d: invalid reply %q", op, reply)
+	}
+
+	data := fields[2]
+
+	if header.CommonFlags&vix.CommandGuestReturnsBinary != 0 {
+		data = bytes.TrimPrefix(data, []byte{'#'})
+	} else {
+		data = bytes.TrimRight(data, "\x00")
+	}
+
+	if rc != vix.OK {
+		return data, vix.Error(rc)
+	}
+
+	return data, nil
+}
+
+// hgfsClient sends hgfs packets to the guest's file server via the VIX HgfsSendPacketCommand
+type hgfsClient struct {
+	*VMX
+
+	ctx     context.Context
+	creds   *vix.UserCredentialNamePassword
+	session uint64
+	id      uint32
+}
+
+func (c *hgfsClient) call(op int32, req any, res any) error {
+	p := new(hgfs.Packet)
+
+	payload, err := hgfs.MarshalBinary(req)
+	if err != nil {
+		return err
+	}
+
+	c.id++
+	p.Payload = payload
+	p.Header = hgfs.Header{
+		Version:    hgfs.HeaderVersion,
+		Dummy:      hgfs.OpNewHeader,
+		HeaderSize: uint32(binary.Size(&p.Header)),
+		RequestID:  c.id,
+		Op:         op,
+		Flags:      hgfs.PacketFlagRequest,
+		SessionID:  c.session,
+	}
+
+	r := new(vix.CommandHgfsSendPacket)
+	r.Packet, _ = p.MarshalBinary()
+	r.Body.PacketSize = uint32(len(r.Packet))
+
+	reply, err := c.Vix(c.ctx, c.creds, vix.HgfsSendPacketCommand, r)
+	if err != nil {
+		return err
+	}
+
+	p = new(hgfs.Packet)
+	if err = p.UnmarshalBinary(reply); err != nil {
+		return err
+	}
+
+	if p.Status != hgfs.StatusSuccess {
+		return &hgfs.Status{Code: p.Status}
+	}
+
+	return hgfs.UnmarshalBinary(p.Payload, res)
+}
+
+// open creates an hgfs session and opens the given file, returning the file handle
+func (c *hgfsClient) open(name string, mode, flags int32) (uint32, error) {
+	session := new(hgfs.ReplyCreateSessionV4)
+	if err := c.call(hgfs.OpCreateSessionV4, new(hgfs.RequestCreateSessionV4), session); err != nil {
+		return 0, err
+	}
+	c.session = session.SessionID
+
+	req := &hgfs.RequestOpenV3{
+		OpenMode:  mode,
+		OpenFlags: flags,
+	}
+	req.FileName.FromString(name)
+
+	res := new(hgfs.ReplyOpenV3)
+	if err := c.call(hgfs.OpOpenV3, req, res); err != nil {
+		c.destroy()
+		return 0, err
+	}
+
+	return res.Handle, nil
+}
+
+// close the given file handle and destroy the session
+func (c *hgfsClient) close(handle uint32) error {
+	err := c.call(hgfs.OpClose, &hgfs.RequestClose{Handle: handle}, new(hgfs.ReplyClose))
+	c.destroy()
+	return err
+}
+
+func (c *hgfsClient) destroy() {
+	_ = c.call(hgfs.OpDestroySessionV4, new(hgfs.RequestDestroySessionV4), new(hgfs.ReplyDestroySessionV4))
+}
+
+// Download writes the contents of the given guest file to w, using the guest's hgfs file server.
+// As with guest file transfers via vCenter, a directory name is transferred as a tar archive.
+func (v *VMX) Download(ctx context.Context, creds *vix.UserCredentialNamePassword, name string, w io.Writer) error {
+	c := &hgfsClient{VMX: v, ctx: ctx, creds: creds}
+
+	handle, err := c.open(name, hgfs.OpenModeReadOnly, 0)
+	if err != nil {
+		return err
+	}
+
+	var offset uint64
+
+	for {
+		req := &hgfs.RequestReadV3{
+			Handle:       handle,
+			Offset:       offset,
+			RequiredSize: hgfsChunkSize,
+		}
+
+		res := new(hgfs.ReplyReadV3)
+		if err = c.call(hgfs.OpReadV3, req, res); err != nil {
+			break
+		}
+
+		if res.ActualSize == 0 {
+			break
+		}
+
+		if _, err = w.Write(res.Payload[:res.ActualSize]); err != nil {
+			break
+		}
+
+		offset += uint64(res.ActualSize)
+	}
+
+	if cerr := c.close(handle); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+// Upload writes the contents of r to the given guest file, using the guest's hgfs file server.
+func (v *VMX) Upload(ctx context.Context, creds *vix.UserCredentialNamePassword, name string, r io.Reader) error {
+	c := &hgfsClient{VMX: v, ctx: ctx, creds: creds}
+
+	handle, err := c.open(name, hgfs.OpenModeWriteOnly, hgfs.OpenCreateEmpty)
+	if err != nil {
+		return err
+	}
+
+	var offset uint64
+	buf := make([]byte, hgfsChunkSize)
+
+	for {
+		n, rerr := io.ReadFull(r, buf)
+		if n > 0 {
+			req := &hgfs.RequestWriteV3{
+				Handle:       handle,
+				Offset:       offset,
+				RequiredSize: uint32(n),
+				Payload:      buf[:n],
+			}
+
+			if err = c.call(hgfs.OpWriteV3, req, new(hgfs.ReplyWriteV3)); err != nil {
+				break
+			}
+
+			offset += uint64(n)
+		}
+
+		if rerr != nil {
+			if rerr != io.EOF && rerr != io.ErrUnexpectedEOF {
+				err = rerr
+			}
+			break
+		}
+	}
+
+	if cerr := c.close(handle); err == nil {
+		err = cerr
+	}
+
+	return err
+}
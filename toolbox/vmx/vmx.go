@@ -0,0 +1,411 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package vmx provides an in-process emulation of the VMX side of the backdoor RPC channels,
+// such that a toolbox.Service can be tested without a VM.
+package vmx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/toolbox"
+)
+
+// Defs from: open-vm-tools/lib/include/guestInfo.h
+const (
+	infoDisks       = 3
+	infoIPAddressV3 = 9
+)
+
+var (
+	rpciOK  = []byte{'1', ' '}
+	rpciERR = []byte{'0', ' '}
+
+	tcloOK = []byte("OK ")
+
+	// ErrStopped is returned by Request when the guest stops the TCLO channel with a request in progress
+	ErrStopped = errors.New("vmx: channel stopped")
+
+	// pollTimeout is the maximum time the TCLO Receive method blocks waiting for a request
+	pollTimeout = 100 * time.Millisecond
+)
+
+// Handler is given the raw argument portion of an RPCI request from the guest and returns a response
+type Handler func([]byte) ([]byte, error)
+
+type request struct {
+	data  []byte
+	reply chan []byte
+}
+
+// VMX emulates the VMX side of the guest RPC channels:
+// TCLO, where the VMX sends requests to the guest (In) and RPCI, where the guest sends requests to the VMX (Out).
+// The guest state reported via RPCI, such as guestinfo variables, nic and disk info, is recorded by the VMX.
+type VMX struct {
+	requests chan *request
+	handlers map[string]Handler
+
+	mu      sync.Mutex
+	current *request
+	rpci    []byte
+
+	vars   map[string]string
+	caps   map[string]string
+	nics   *toolbox.GuestNicInfo
+	disks  []toolbox.GuestDisk
+	states []string
+}
+
+// New creates a VMX instance with the default RPCI handlers
+func New() *VMX {
+	v := &VMX{
+		requests: make(chan *request),
+		handlers: make(map[string]Handler),
+		vars:     make(map[string]string),
+		caps:     make(map[string]string),
+	}
+
+	v.RegisterHandler("info-set", v.InfoSet)
+	v.RegisterHandler("info-get", v.InfoGet)
+	v.RegisterHandler("SetGuestInfo", v.SetGuestInfo)
+	v.RegisterHandler("tools.set.version", v.capability("tools.set.version"))
+	v.RegisterHandler("tools.os.statechange.status", v.StateChangeStatus)
+
+	for _, name := range []string{"tools.capability.statechange", "tools.capability.hgfs_server"} {
+		v.RegisterHandler(name, v.capability(name))
+	}
+
+	// State updates we accept, but do not currently record
+	for _, name := range []string{"deployPkg.update.state", "vmbackup.eventSet"} {
+		v.RegisterHandler(name, ignore)
+	}
+
+	return v
+}
+
+// RegisterHandler for the given RPCI request name
+func (v *VMX) RegisterHandler(name string, handler Handler) {
+	v.handlers[name] = handler
+}
+
+// In returns the TCLO channel, used by the guest to receive requests from the VMX
+func (v *VMX) In() toolbox.Channel {
+	return &channelIn{v}
+}
+
+// Out returns the RPCI channel, used by the guest to send requests to the VMX
+func (v *VMX) Out() toolbox.Channel {
+	return &channelOut{v}
+}
+
+// NewService creates a toolbox.Service connected to the VMX channels
+func (v *VMX) NewService() *toolbox.Service {
+	return toolbox.NewService(v.In(), v.Out())
+}
+
+// Request sends the given TCLO request to the guest and waits for the reply.
+// An error is returned if the guest replies with an error or if ctx is done before the reply is received.
+func (v *VMX) Request(ctx context.Context, msg []byte) ([]byte, error) {
+	req := &request{
+		data:  msg,
+		reply: make(chan []byte, 1),
+	}
+
+	select {
+	case v.requests <- req:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	select {
+	case reply, ok := <-req.reply:
+		if !ok {
+			return nil, ErrStopped
+		}
+		if bytes.HasPrefix(reply, tcloOK) {
+			return reply[len(tcloOK):], nil
+		}
+		return nil, &toolbox.RequestError{Request: msg, Reply: reply}
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Reset sends the sequence of requests the VMX sends after the guest tools start:
+// reset, ping, Capabilities_Register and Set_Option broadcastIP, which triggers the guest to send nic and disk info.
+func (v *VMX) Reset(ctx context.Context) error {
+	reqs := []string{"reset", "ping", "Capabilities_Register", "Set_Option broadcastIP 1"}
+
+	for _, req := range reqs {
+		if _, err := v.Request(ctx, []byte(req)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Halt sends the guest an OS_Halt request, returning an error if the guest does not report success
+func (v *VMX) Halt(ctx context.Context) error {
+	return v.power(ctx, "OS_Halt")
+}
+
+// Reboot sends the guest an OS_Reboot request, returning an error if the guest does not report success
+func (v *VMX) Reboot(ctx context.Context) error {
+	return v.power(ctx, "OS_Reboot")
+}
+
+func (v *VMX) power(ctx context.Context, name string) error {
+	v.mu.Lock()
+	n := len(v.states)
+	v.mu.Unlock()
+
+	if _, err := v.Request(ctx, []byte(name)); err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	// The guest sends tools.os.statechange.status before replying
+	if len(v.states) == n {
+		return errors.New(name + ": no status reported")
+	}
+
+	status := v.states[len(v.states)-1]
+	if !bytes.HasPrefix([]byte(status), rpciOK) {
+		return errors.New(name + ": failed")
+	}
+
+	return nil
+}
+
+// Var returns the value of the given guestinfo variable, where key may omit the "guestinfo." prefix.
+func (v *VMX) Var(key string) (string, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	val, ok := v.vars[toolbox.GuestInfoKey(key)]
+	return val, ok
+}
+
+// SetVar sets the value of the given guestinfo variable, as the VMX does for VirtualMachine extraConfig "guestinfo." keys.
+func (v *VMX) SetVar(key, val string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.vars[toolbox.GuestInfoKey(key)] = val
+}
+
+// Capability returns the value of the given capability registered by the guest
+func (v *VMX) Capability(name string) (string, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	val, ok := v.caps[name]
+	return val, ok
+}
+
+// NicInfo returns the nic info last reported by the guest, nil if none has been reported
+func (v *VMX) NicInfo() *toolbox.GuestNicInfo {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.nics
+}
+
+// Disks returns the disk info last reported by the guest
+func (v *VMX) Disks() []toolbox.GuestDisk {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.disks
+}
+
+// dispatch an RPCI request from the guest to a Handler
+func (v *VMX) dispatch(msg []byte) []byte {
+	name, args, _ := bytes.Cut(msg, []byte{' '})
+
+	// Trim NULL byte terminator
+	name = bytes.TrimRight(name, "\x00")
+
+	handler, ok := v.handlers[string(name)]
+	if !ok {
+		return append(rpciERR, "Unknown command"...)
+	}
+
+	res, err := handler(args)
+	if err != nil {
+		return append(rpciERR, err.Error()...)
+	}
+
+	return append(rpciOK, res...)
+}
+
+// InfoSet is the default Handler for info-set requests
+func (v *VMX) InfoSet(args []byte) ([]byte, error) {
+	key, val, _ := bytes.Cut(args, []byte{' '})
+
+	v.mu.Lock()
+	v.vars[string(key)] = string(val)
+	v.mu.Unlock()
+
+	return nil, nil
+}
+
+// InfoGet is the default Handler for info-get requests
+func (v *VMX) InfoGet(args []byte) ([]byte, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	val, ok := v.vars[string(args)]
+	if !ok {
+		return nil, errors.New("No value found")
+	}
+
+	return []byte(val), nil
+}
+
+// SetGuestInfo is the default Handler for SetGuestInfo requests, recording nic and disk info
+func (v *VMX) SetGuestInfo(args []byte) ([]byte, error) {
+	kind, data, _ := bytes.Cut(bytes.TrimLeft(args, " "), []byte{' '})
+
+	n, err := strconv.Atoi(string(kind))
+	if err != nil {
+		return nil, err
+	}
+
+	switch n {
+	case infoIPAddressV3:
+		info := new(toolbox.GuestNicInfo)
+		if err = toolbox.DecodeXDR(data, info); err != nil {
+			return nil, err
+		}
+
+		v.mu.Lock()
+		v.nics = info
+		v.mu.Unlock()
+	case infoDisks:
+		disks, err := toolbox.DecodeGuestDiskInfo(data)
+		if err != nil {
+			return nil, err
+		}
+
+		v.mu.Lock()
+		v.disks = disks
+		v.mu.Unlock()
+	}
+
+	return nil, nil
+}
+
+// StateChangeStatus is the default Handler for tools.os.statechange.status requests
+func (v *VMX) StateChangeStatus(args []byte) ([]byte, error) {
+	v.mu.Lock()
+	v.states = append(v.states, string(bytes.TrimRight(args, "\x00")))
+	v.mu.Unlock()
+
+	return nil, nil
+}
+
+func (v *VMX) capability(name string) Handler {
+	return func(args []byte) ([]byte, error) {
+		v.mu.Lock()
+		v.caps[name] = string(args)
+		v.mu.Unlock()
+
+		return nil, nil
+	}
+}
+
+func ignore([]byte) ([]byte, error) {
+	return nil, nil
+}
+
+// channelIn implements the guest side of the TCLO channel
+type channelIn struct {
+	*VMX
+}
+
+func (c *channelIn) Start() error {
+	return nil
+}
+
+// Stop fails any request in progress
+func (c *channelIn) Stop() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.current != nil {
+		close(c.current.reply)
+		c.current = nil
+	}
+
+	return nil
+}
+
+// Send delivers the guest's reply to the request in progress, if any
+func (c *channelIn) Send(buf []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.current != nil {
+		c.current.reply <- buf
+		c.current = nil
+	}
+
+	return nil
+}
+
+// Receive waits for a request from the VMX, returning nil if none is sent within pollTimeout
+func (c *channelIn) Receive() ([]byte, error) {
+	select {
+	case req := <-c.requests:
+		c.mu.Lock()
+		c.current = req
+		c.mu.Unlock()
+		return req.data, nil
+	case <-time.After(pollTimeout):
+		return nil, nil
+	}
+}
+
+// channelOut implements the guest side of the RPCI channel
+type channelOut struct {
+	*VMX
+}
+
+func (c *channelOut) Start() error {
+	return nil
+}
+
+func (c *channelOut) Stop() error {
+	return nil
+}
+
+// Send dispatches the guest's request, the reply is returned by the next call to Receive
+func (c *channelOut) Send(buf []byte) error {
+	reply := c.dispatch(buf)
+
+	c.mu.Lock()
+	c.rpci = reply
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (c *channelOut) Receive() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reply := c.rpci
+	c.rpci = nil
+
+	return reply, nil
+}
@@ -0,0 +1,169 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package vmx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/toolbox"
+	"github.com/vmware/govmomi/toolbox/vix"
+)
+
+func startService(t *testing.T) (*VMX, *toolbox.Service) {
+	v := New()
+	service := v.NewService()
+
+	service.PrimaryIP = func() string {
+		return "10.0.0.42"
+	}
+	service.Disks = func() []toolbox.GuestDisk {
+		return []toolbox.GuestDisk{{Name: "/", Total: 1024, Free: 512, FSType: "ext4"}}
+	}
+
+	if err := service.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		service.Stop()
+		service.Wait()
+	})
+
+	return v, service
+}
+
+func TestVMX(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	v, service := startService(t)
+
+	if err := v.Reset(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := v.Capability("tools.capability.statechange"); !ok {
+		t.Error("statechange capability not registered")
+	}
+
+	if ip, _ := v.Var("ip"); ip != "10.0.0.42" {
+		t.Errorf("ip=%q", ip)
+	}
+
+	if v.NicInfo() == nil {
+		t.Error("nic info not reported")
+	}
+
+	disks := v.Disks()
+	if len(disks) != 1 || disks[0].Name != "/" || disks[0].Free != 512 {
+		t.Errorf("disks=%#v", disks)
+	}
+
+	v.SetVar("foo", "bar")
+	info := toolbox.NewGuestInfo(v.Out())
+
+	val, err := info.Get("foo")
+	if err != nil || val != "bar" {
+		t.Errorf("val=%q, err=%v", val, err)
+	}
+
+	if err = info.Set("baz", "qux"); err != nil {
+		t.Fatal(err)
+	}
+
+	if val, _ = v.Var("guestinfo.baz"); val != "qux" {
+		t.Errorf("val=%q", val)
+	}
+
+	if _, err = info.Get("enoent"); err != toolbox.ErrGuestInfoNotFound {
+		t.Errorf("err=%v", err)
+	}
+
+	if _, err = v.Request(ctx, []byte("enoent")); err == nil {
+		t.Error("expected error")
+	}
+
+	// No Handler, the guest reports failure
+	if err = v.Halt(ctx); err == nil {
+		t.Error("expected error")
+	}
+
+	halt := 0
+	service.Power.Halt.Handler = func() error {
+		halt++
+		return nil
+	}
+
+	if err = v.Halt(ctx); err != nil || halt != 1 {
+		t.Errorf("halt=%d, err=%v", halt, err)
+	}
+}
+
+func TestVMXVix(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	v, _ := startService(t)
+
+	creds := &vix.UserCredentialNamePassword{Name: "user", Password: "pass"}
+	dir := t.TempDir()
+
+	mkdir := &vix.DirRequest{GuestPathName: filepath.Join(dir, "sub")}
+	if _, err := v.Vix(ctx, creds, vix.CommandCreateDirectoryEx, mkdir); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := v.Vix(ctx, creds, vix.CommandCreateDirectoryEx, mkdir)
+	var verr vix.Error
+	if !errors.As(err, &verr) || int(verr) != vix.FileAlreadyExists {
+		t.Errorf("err=%v", err)
+	}
+
+	ls := &vix.ListFilesRequest{GuestPathName: dir}
+	res, err := v.Vix(ctx, creds, vix.CommandListFiles, ls)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(res), "<Name>sub</Name>") {
+		t.Errorf("res=%s", res)
+	}
+
+	name := filepath.Join(dir, "file")
+	data := bytes.Repeat([]byte("toolbox"), hgfsChunkSize/2)
+
+	if err = v.Upload(ctx, creds, name, bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(b, data) {
+		t.Errorf("uploaded %d bytes, file has %d bytes", len(data), len(b))
+	}
+
+	var buf bytes.Buffer
+	if err = v.Download(ctx, creds, name, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Errorf("uploaded %d bytes, downloaded %d bytes", len(data), buf.Len())
+	}
+
+	if err = v.Download(ctx, creds, filepath.Join(dir, "enoent"), &buf); err == nil {
+		t.Error("expected error")
+	}
+}
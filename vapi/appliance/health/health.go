@@ -0,0 +1,78 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+const Path = "/api/appliance/health"
+
+// Appliance health items, each is a sub-path of Path.
+const (
+	ApplMgmt         = "applmgmt"
+	DatabaseStorage  = "database-storage"
+	Load             = "load"
+	Mem              = "mem"
+	SoftwarePackages = "software-packages"
+	Storage          = "storage"
+	Swap             = "swap"
+	System           = "system"
+)
+
+// Items is the list of appliance health items.
+var Items = []string{
+	System,
+	ApplMgmt,
+	DatabaseStorage,
+	Load,
+	Mem,
+	SoftwarePackages,
+	Storage,
+	Swap,
+}
+
+// Level is the health level of an appliance health item.
+type Level string
+
+const (
+	LevelGreen  = Level("green")
+	LevelYellow = Level("yellow")
+	LevelOrange = Level("orange")
+	LevelRed    = Level("red")
+	LevelGray   = Level("gray")
+)
+
+// Manager provides convenience methods to get appliance health levels.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager with the given client
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Get returns the health level of the given item.
+func (m *Manager) Get(ctx context.Context, item string) (Level, error) {
+	r := m.Resource(Path).WithSubpath(item)
+
+	var level Level
+	return level, m.Do(ctx, r.Request(http.MethodGet), &level)
+}
+
+// LastCheck returns the time of the last appliance health check.
+func (m *Manager) LastCheck(ctx context.Context) (time.Time, error) {
+	r := m.Resource(Path).WithSubpath(System).WithSubpath("lastcheck")
+
+	var t time.Time
+	return t, m.Do(ctx, r.Request(http.MethodGet), &t)
+}
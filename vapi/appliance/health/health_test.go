@@ -0,0 +1,61 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package health_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/appliance/health"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+
+	_ "github.com/vmware/govmomi/vapi/appliance/simulator"
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestHealth(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		require.NoError(t, c.Login(ctx, simulator.DefaultLogin))
+
+		m := health.NewManager(c)
+
+		for _, item := range health.Items {
+			level, err := m.Get(ctx, item)
+			require.NoError(t, err)
+			assert.Equal(t, health.LevelGreen, level, item)
+		}
+
+		_, err := m.Get(ctx, "enoent")
+		require.Error(t, err)
+
+		last, err := m.LastCheck(ctx)
+		require.NoError(t, err)
+		assert.False(t, last.IsZero())
+
+		opts := object.NewOptionManager(vc, *vc.ServiceContent.Setting)
+		err = opts.Update(ctx, []types.BaseOptionValue{
+			&types.OptionValue{Key: "vcsim.appliance.health.storage", Value: "orange"},
+			&types.OptionValue{Key: "vcsim.appliance.health.mem", Value: "yellow"},
+		})
+		require.NoError(t, err)
+
+		level, err := m.Get(ctx, health.Storage)
+		require.NoError(t, err)
+		assert.Equal(t, health.LevelOrange, level)
+
+		// system is the worst level of all items
+		level, err = m.Get(ctx, health.System)
+		require.NoError(t, err)
+		assert.Equal(t, health.LevelOrange, level)
+	})
+}
@@ -0,0 +1,131 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package monitoring
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+const Path = "/api/appliance/monitoring"
+
+// Manager provides convenience methods to list and query appliance monitoring statistics.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager with the given client
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Item describes a monitored statistic.
+type Item struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Units       string `json:"units"`
+	Category    string `json:"category"`
+	Instance    string `json:"instance"`
+	Description string `json:"description"`
+}
+
+// List returns the monitored statistics.
+func (m *Manager) List(ctx context.Context) ([]Item, error) {
+	r := m.Resource(Path)
+
+	var items []Item
+	return items, m.Do(ctx, r.Request(http.MethodGet), &items)
+}
+
+// Get returns the monitored statistic with the given ID.
+func (m *Manager) Get(ctx context.Context, id string) (*Item, error) {
+	r := m.Resource(Path).WithSubpath(url.PathEscape(id))
+
+	var item Item
+	return &item, m.Do(ctx, r.Request(http.MethodGet), &item)
+}
+
+// Interval between data points of a statistic.
+type Interval string
+
+const (
+	IntervalMinutes1  = Interval("MINUTES1")
+	IntervalMinutes5  = Interval("MINUTES5")
+	IntervalMinutes30 = Interval("MINUTES30")
+	IntervalHours2    = Interval("HOURS2")
+	IntervalHours6    = Interval("HOURS6")
+	IntervalDay1      = Interval("DAY1")
+)
+
+// Duration returns the time.Duration of the interval.
+func (i Interval) Duration() time.Duration {
+	switch i {
+	case IntervalMinutes1:
+		return time.Minute
+	case IntervalMinutes5:
+		return 5 * time.Minute
+	case IntervalMinutes30:
+		return 30 * time.Minute
+	case IntervalHours2:
+		return 2 * time.Hour
+	case IntervalHours6:
+		return 6 * time.Hour
+	case IntervalDay1:
+		return 24 * time.Hour
+	}
+	return 0
+}
+
+// Function used to aggregate the data points of a statistic within an interval.
+type Function string
+
+const (
+	FunctionCount = Function("COUNT")
+	FunctionMax   = Function("MAX")
+	FunctionAvg   = Function("AVG")
+	FunctionMin   = Function("MIN")
+)
+
+// Query is used to query monitored statistics.
+type Query struct {
+	Names     []string
+	Interval  Interval
+	Function  Function
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// ItemData contains the data points of a monitored statistic.
+// A data point is empty if no data was collected within the interval.
+type ItemData struct {
+	Name      string    `json:"name"`
+	Interval  Interval  `json:"interval"`
+	Function  Function  `json:"function"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Data      []string  `json:"data"`
+}
+
+// Query returns the data points of the monitored statistics matching the Query.
+func (m *Manager) Query(ctx context.Context, q Query) ([]ItemData, error) {
+	r := m.Resource(Path).WithSubpath("query").
+		WithParam("item.interval", string(q.Interval)).
+		WithParam("item.function", string(q.Function)).
+		WithParam("item.start_time", q.StartTime.UTC().Format(time.RFC3339)).
+		WithParam("item.end_time", q.EndTime.UTC().Format(time.RFC3339))
+
+	for _, name := range q.Names {
+		r.WithParam("item.names", name)
+	}
+
+	var data []ItemData
+	return data, m.Do(ctx, r.Request(http.MethodGet), &data)
+}
@@ -0,0 +1,188 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// Appliance backup REST endpoints
+const (
+	Path          = "/api/appliance/recovery/backup"
+	JobPath       = Path + "/job"
+	PartsPath     = Path + "/parts"
+	SchedulesPath = Path + "/schedules"
+)
+
+// Manager extends rest.Client, adding appliance backup job and schedule methods.
+//
+// See https://developer.broadcom.com/xapis/vsphere-automation-api/latest/appliance/recovery/backup/
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Part is a component of the appliance data that can be included in a backup.
+type Part struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	SelectedByDefault bool   `json:"selected_by_default"`
+	Optional          bool   `json:"optional"`
+}
+
+// ListParts returns the parts that can be included in a backup.
+func (c *Manager) ListParts(ctx context.Context) ([]Part, error) {
+	url := c.Resource(PartsPath)
+	var res []Part
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// LocationType is the protocol used to access the backup location.
+type LocationType string
+
+const (
+	LocationTypeFTP   = LocationType("FTP")
+	LocationTypeFTPS  = LocationType("FTPS")
+	LocationTypeHTTP  = LocationType("HTTP")
+	LocationTypeHTTPS = LocationType("HTTPS")
+	LocationTypeSCP   = LocationType("SCP")
+	LocationTypeSFTP  = LocationType("SFTP")
+	LocationTypeNFS   = LocationType("NFS")
+	LocationTypeSMB   = LocationType("SMB")
+)
+
+// JobState is the state of a backup job.
+type JobState string
+
+const (
+	JobStatePending   = JobState("PENDING")
+	JobStateRunning   = JobState("RUNNING")
+	JobStateBlocked   = JobState("BLOCKED")
+	JobStateSucceeded = JobState("SUCCEEDED")
+	JobStateFailed    = JobState("FAILED")
+)
+
+// JobType indicates if a backup job was started manually or by a schedule.
+type JobType string
+
+const (
+	JobTypeManual    = JobType("MANUAL")
+	JobTypeScheduled = JobType("SCHEDULED")
+)
+
+// JobCreateSpec is used to start a backup job.
+// If Parts is empty, the parts selected by default are included.
+type JobCreateSpec struct {
+	Parts            []string     `json:"parts,omitempty"`
+	BackupPassword   string       `json:"backup_password,omitempty"`
+	LocationType     LocationType `json:"location_type"`
+	Location         string       `json:"location"`
+	LocationUser     string       `json:"location_user,omitempty"`
+	LocationPassword string       `json:"location_password,omitempty"`
+	Comment          string       `json:"comment,omitempty"`
+}
+
+// JobProgress contains the progress of a backup job.
+type JobProgress struct {
+	Total     int64 `json:"total"`
+	Completed int64 `json:"completed"`
+}
+
+// JobInfo contains information about a backup job.
+type JobInfo struct {
+	ID           string                    `json:"id"`
+	Type         JobType                   `json:"type"`
+	State        JobState                  `json:"state"`
+	Cancelable   bool                      `json:"cancelable"`
+	Parts        []string                  `json:"parts"`
+	Location     string                    `json:"location"`
+	LocationType LocationType              `json:"location_type"`
+	LocationUser string                    `json:"location_user,omitempty"`
+	Comment      string                    `json:"comment,omitempty"`
+	StartTime    time.Time                 `json:"start_time"`
+	EndTime      *time.Time                `json:"end_time,omitempty"`
+	Size         int64                     `json:"size"`
+	Progress     JobProgress               `json:"progress"`
+	Error        *rest.LocalizableMessage  `json:"error,omitempty"`
+	Messages     []rest.LocalizableMessage `json:"messages,omitempty"`
+}
+
+// Done returns true if the job is no longer running.
+func (info *JobInfo) Done() bool {
+	return info.State == JobStateSucceeded || info.State == JobStateFailed
+}
+
+// CreateJob starts a backup job, returning the job ID.
+func (c *Manager) CreateJob(ctx context.Context, spec JobCreateSpec) (string, error) {
+	url := c.Resource(JobPath)
+	var res string
+	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+}
+
+// ListJobs returns the IDs of the backup jobs.
+func (c *Manager) ListJobs(ctx context.Context) ([]string, error) {
+	url := c.Resource(JobPath)
+	var res []string
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// GetJob returns information about the given backup job.
+func (c *Manager) GetJob(ctx context.Context, id string) (*JobInfo, error) {
+	url := c.Resource(JobPath).WithSubpath(url.PathEscape(id))
+	var res JobInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// CancelJob cancels the given running backup job.
+func (c *Manager) CancelJob(ctx context.Context, id string) error {
+	url := c.Resource(JobPath).WithSubpath(url.PathEscape(id)).WithParam("action", "cancel")
+	return c.Do(ctx, url.Request(http.MethodPost), nil)
+}
+
+// WaitForJob polls the given backup job until it is done, returning an error if the job failed.
+func (c *Manager) WaitForJob(ctx context.Context, id string) (*JobInfo, error) {
+	delay := 100 * time.Millisecond
+
+	for {
+		info, err := c.GetJob(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if info.Done() {
+			if info.State == JobStateFailed {
+				msg := "backup job failed"
+				if info.Error != nil {
+					msg = info.Error.Error()
+				}
+				return info, errors.New(msg)
+			}
+			return info, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return info, ctx.Err()
+		case <-time.After(delay):
+		}
+
+		if delay < 5*time.Second {
+			delay *= 2
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/appliance/recovery/backup"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/appliance/simulator"
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestBackupJob(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		require.NoError(t, c.Login(ctx, simulator.DefaultLogin))
+
+		m := backup.NewManager(c)
+
+		parts, err := m.ListParts(ctx)
+		require.NoError(t, err)
+		assert.Len(t, parts, 2)
+
+		dir := t.TempDir()
+		location := "file://" + filepath.ToSlash(dir)
+
+		_, err = m.CreateJob(ctx, backup.JobCreateSpec{LocationType: backup.LocationTypeSFTP, Location: location, Parts: []string{"enoent"}})
+		require.Error(t, err)
+
+		id, err := m.CreateJob(ctx, backup.JobCreateSpec{
+			LocationType:   backup.LocationTypeSFTP,
+			Location:       location,
+			BackupPassword: "secret",
+			Comment:        "test",
+		})
+		require.NoError(t, err)
+
+		info, err := m.WaitForJob(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, backup.JobStateSucceeded, info.State)
+		assert.Equal(t, backup.JobTypeManual, info.Type)
+		assert.Equal(t, []string{"common", "seat"}, info.Parts)
+		assert.NotZero(t, info.Size)
+		assert.False(t, info.Cancelable)
+		require.Error(t, m.CancelJob(ctx, id))
+
+		for _, name := range []string{"backup-metadata.json", "common.tar.gz", "seat.tar.gz"} {
+			assert.FileExists(t, filepath.Join(dir, id, name))
+		}
+
+		// backup to an http target
+		var mu sync.Mutex
+		files := map[string]int{}
+		block := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, "/block/") {
+				<-block
+			}
+			user, pass, _ := r.BasicAuth()
+			if r.Method != http.MethodPut || user != "backup" || pass != "pass" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			b, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			files[r.URL.Path] = len(b)
+			mu.Unlock()
+		}))
+		defer server.Close()
+
+		spec := backup.JobCreateSpec{
+			LocationType:     backup.LocationTypeHTTP,
+			Location:         server.URL + "/backups",
+			LocationUser:     "backup",
+			LocationPassword: "pass",
+			Parts:            []string{"common"},
+		}
+		id, err = m.CreateJob(ctx, spec)
+		require.NoError(t, err)
+		_, err = m.WaitForJob(ctx, id)
+		require.NoError(t, err)
+		mu.Lock()
+		assert.Len(t, files, 2)
+		assert.Contains(t, files, "/backups/"+id+"/common.tar.gz")
+		mu.Unlock()
+
+		spec.LocationPassword = "invalid"
+		id, err = m.CreateJob(ctx, spec)
+		require.NoError(t, err)
+		info, err = m.WaitForJob(ctx, id)
+		require.Error(t, err)
+		assert.Equal(t, backup.JobStateFailed, info.State)
+
+		// cancel a running job
+		spec.Location = server.URL + "/block"
+		id, err = m.CreateJob(ctx, spec)
+		require.NoError(t, err)
+		info, err = m.GetJob(ctx, id)
+		require.NoError(t, err)
+		assert.True(t, info.Cancelable)
+		require.NoError(t, m.CancelJob(ctx, id))
+		close(block)
+		info, err = m.WaitForJob(ctx, id)
+		require.Error(t, err)
+		assert.Equal(t, backup.JobStateFailed, info.State)
+
+		jobs, err := m.ListJobs(ctx)
+		require.NoError(t, err)
+		assert.Len(t, jobs, 4)
+
+		_, err = m.GetJob(ctx, "enoent")
+		require.Error(t, err)
+	})
+}
+
+func TestBackupSchedule(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		require.NoError(t, c.Login(ctx, simulator.DefaultLogin))
+
+		m := backup.NewManager(c)
+
+		dir := t.TempDir()
+		spec := backup.ScheduleCreateSpec{
+			Location:       "file://" + filepath.ToSlash(dir),
+			RecurrenceInfo: &backup.RecurrenceInfo{Hour: 25},
+		}
+		require.Error(t, m.CreateSchedule(ctx, "default", spec))
+
+		spec.RecurrenceInfo.Hour = 2
+		spec.RecurrenceInfo.Days = []backup.Weekday{backup.Sunday}
+		spec.RetentionInfo = &backup.RetentionInfo{MaxCount: 1}
+		require.NoError(t, m.CreateSchedule(ctx, "default", spec))
+		require.Error(t, m.CreateSchedule(ctx, "default", spec))
+
+		schedules, err := m.ListSchedules(ctx)
+		require.NoError(t, err)
+		require.Contains(t, schedules, "default")
+		assert.True(t, schedules["default"].Enable)
+
+		require.NoError(t, m.UpdateSchedule(ctx, "default", backup.ScheduleUpdateSpec{Enable: new(bool), Parts: []string{"common"}}))
+		info, err := m.GetSchedule(ctx, "default")
+		require.NoError(t, err)
+		assert.False(t, info.Enable)
+		assert.Equal(t, []string{"common"}, info.Parts)
+		assert.Equal(t, int64(2), info.RecurrenceInfo.Hour)
+
+		var ids []string
+		for range 2 {
+			id, err := m.RunSchedule(ctx, "default", "scheduled")
+			require.NoError(t, err)
+			job, err := m.WaitForJob(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, backup.JobTypeScheduled, job.Type)
+			ids = append(ids, id)
+		}
+
+		// retention max_count of 1 keeps the latest backup only
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, ids[1], entries[0].Name())
+
+		require.NoError(t, m.DeleteSchedule(ctx, "default"))
+		_, err = m.GetSchedule(ctx, "default")
+		require.Error(t, err)
+		_, err = m.RunSchedule(ctx, "default", "")
+		require.Error(t, err)
+	})
+}
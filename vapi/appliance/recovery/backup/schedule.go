@@ -0,0 +1,119 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Weekday on which a scheduled backup runs.
+type Weekday string
+
+const (
+	Monday    = Weekday("MONDAY")
+	Tuesday   = Weekday("TUESDAY")
+	Wednesday = Weekday("WEDNESDAY")
+	Thursday  = Weekday("THURSDAY")
+	Friday    = Weekday("FRIDAY")
+	Saturday  = Weekday("SATURDAY")
+	Sunday    = Weekday("SUNDAY")
+)
+
+// RecurrenceInfo defines when a scheduled backup runs.
+// If Days is empty, the backup runs daily.
+type RecurrenceInfo struct {
+	Minute int64     `json:"minute"`
+	Hour   int64     `json:"hour"`
+	Days   []Weekday `json:"days,omitempty"`
+}
+
+// RetentionInfo defines the number of scheduled backups kept at the backup location.
+type RetentionInfo struct {
+	MaxCount int64 `json:"max_count"`
+}
+
+// ScheduleInfo contains information about a backup schedule.
+type ScheduleInfo struct {
+	Parts          []string        `json:"parts"`
+	Location       string          `json:"location"`
+	LocationUser   string          `json:"location_user,omitempty"`
+	Enable         bool            `json:"enable"`
+	RecurrenceInfo *RecurrenceInfo `json:"recurrence_info,omitempty"`
+	RetentionInfo  *RetentionInfo  `json:"retention_info,omitempty"`
+}
+
+// ScheduleCreateSpec is used to create a backup schedule.
+// If Parts is empty, the parts selected by default are included.
+// If Enable is nil, the schedule is enabled.
+type ScheduleCreateSpec struct {
+	Parts            []string        `json:"parts,omitempty"`
+	BackupPassword   string          `json:"backup_password,omitempty"`
+	Location         string          `json:"location"`
+	LocationUser     string          `json:"location_user,omitempty"`
+	LocationPassword string          `json:"location_password,omitempty"`
+	Enable           *bool           `json:"enable,omitempty"`
+	RecurrenceInfo   *RecurrenceInfo `json:"recurrence_info,omitempty"`
+	RetentionInfo    *RetentionInfo  `json:"retention_info,omitempty"`
+}
+
+// ScheduleUpdateSpec is used to update a backup schedule, fields that are not set are left unchanged.
+type ScheduleUpdateSpec struct {
+	Parts            []string        `json:"parts,omitempty"`
+	BackupPassword   string          `json:"backup_password,omitempty"`
+	Location         string          `json:"location,omitempty"`
+	LocationUser     string          `json:"location_user,omitempty"`
+	LocationPassword string          `json:"location_password,omitempty"`
+	Enable           *bool           `json:"enable,omitempty"`
+	RecurrenceInfo   *RecurrenceInfo `json:"recurrence_info,omitempty"`
+	RetentionInfo    *RetentionInfo  `json:"retention_info,omitempty"`
+}
+
+func (c *Manager) schedule(id string) string {
+	return SchedulesPath + "/" + url.PathEscape(id)
+}
+
+// ListSchedules returns the backup schedules, keyed by schedule ID.
+func (c *Manager) ListSchedules(ctx context.Context) (map[string]ScheduleInfo, error) {
+	url := c.Resource(SchedulesPath)
+	var res map[string]ScheduleInfo
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// GetSchedule returns information about the given backup schedule.
+func (c *Manager) GetSchedule(ctx context.Context, id string) (*ScheduleInfo, error) {
+	url := c.Resource(c.schedule(id))
+	var res ScheduleInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// CreateSchedule creates a backup schedule with the given ID.
+func (c *Manager) CreateSchedule(ctx context.Context, id string, spec ScheduleCreateSpec) error {
+	url := c.Resource(c.schedule(id))
+	return c.Do(ctx, url.Request(http.MethodPost, spec), nil)
+}
+
+// UpdateSchedule updates the given backup schedule.
+func (c *Manager) UpdateSchedule(ctx context.Context, id string, spec ScheduleUpdateSpec) error {
+	url := c.Resource(c.schedule(id))
+	return c.Do(ctx, url.Request(http.MethodPatch, spec), nil)
+}
+
+// DeleteSchedule deletes the given backup schedule.
+func (c *Manager) DeleteSchedule(ctx context.Context, id string) error {
+	url := c.Resource(c.schedule(id))
+	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+}
+
+// RunSchedule starts a backup job using the given schedule, returning the job ID.
+func (c *Manager) RunSchedule(ctx context.Context, id string, comment string) (string, error) {
+	url := c.Resource(c.schedule(id)).WithParam("action", "run")
+	spec := struct {
+		Comment string `json:"comment,omitempty"`
+	}{comment}
+	var res string
+	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+}
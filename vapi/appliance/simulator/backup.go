@@ -0,0 +1,527 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vmware/govmomi/vapi/appliance/recovery/backup"
+	"github.com/vmware/govmomi/vapi/rest"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+)
+
+// backupParts are the parts included in simulator backups, "common" is always included.
+var backupParts = []backup.Part{
+	{
+		ID:                "common",
+		Name:              "Inventory and configuration",
+		Description:       "Inventory and configuration data",
+		SelectedByDefault: true,
+	},
+	{
+		ID:                "seat",
+		Name:              "Stats, Events, and Tasks",
+		Description:       "Historical performance statistics, events and tasks",
+		SelectedByDefault: true,
+		Optional:          true,
+	},
+}
+
+type backupJob struct {
+	backup.JobInfo
+
+	schedule string
+	password string
+	cancel   context.CancelFunc
+}
+
+type backupSchedule struct {
+	backup.ScheduleInfo
+
+	backupPassword   string
+	locationPassword string
+}
+
+// selectParts validates the given part IDs, returning the parts selected by default if none are given.
+func selectParts(ids []string) ([]string, error) {
+	parts := []string{"common"}
+
+	for _, p := range backupParts {
+		if len(ids) == 0 {
+			if p.SelectedByDefault && p.Optional {
+				parts = append(parts, p.ID)
+			}
+		} else if p.Optional && slices.Contains(ids, p.ID) {
+			parts = append(parts, p.ID)
+		}
+	}
+
+	for _, id := range ids {
+		if !slices.Contains(parts, id) {
+			return nil, fmt.Errorf("invalid part %q", id)
+		}
+	}
+
+	return parts, nil
+}
+
+func parseLocation(location string) (*url.URL, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Path == "" {
+		return nil, fmt.Errorf("invalid location %q", location)
+	}
+	return u, nil
+}
+
+func (h *Handler) backupParts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	vapi.StatusOK(w, backupParts)
+}
+
+// startBackup creates a backup job and writes the backup archive in the background.
+// Must be called with h.mu held.
+func (h *Handler) startBackup(spec backup.JobCreateSpec, schedule string) (string, error) {
+	u, err := parseLocation(spec.Location)
+	if err != nil || spec.LocationType == "" {
+		return "", errors.New("invalid location")
+	}
+
+	parts, err := selectParts(spec.Parts)
+	if err != nil {
+		return "", err
+	}
+
+	kind := backup.JobTypeManual
+	if schedule != "" {
+		kind = backup.JobTypeScheduled
+	}
+
+	now := time.Now().UTC()
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &backupJob{
+		JobInfo: backup.JobInfo{
+			ID:           now.Format("20060102-150405") + "-" + uuid.New().String()[:8],
+			Type:         kind,
+			State:        backup.JobStateRunning,
+			Cancelable:   true,
+			Parts:        parts,
+			Location:     spec.Location,
+			LocationType: spec.LocationType,
+			LocationUser: spec.LocationUser,
+			Comment:      spec.Comment,
+			StartTime:    now,
+			Progress:     backup.JobProgress{Total: int64(len(parts) + 1)},
+		},
+		schedule: schedule,
+		password: spec.LocationPassword,
+		cancel:   cancel,
+	}
+
+	if h.backupJobs == nil {
+		h.backupJobs = make(map[string]*backupJob)
+	}
+	h.backupJobs[job.ID] = job
+
+	go h.runBackup(ctx, job, u, spec.BackupPassword != "")
+
+	return job.ID, nil
+}
+
+// archive returns a gzip compressed tar file with fake content for the given part.
+func archive(part string) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	data := []byte(fmt.Sprintf("govmomi simulator backup part %q\n", part))
+	err := tw.WriteHeader(&tar.Header{
+		Name:    path.Join(part, "data"),
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	})
+	if err == nil {
+		_, err = tw.Write(data)
+	}
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil {
+		err = gz.Close()
+	}
+
+	return buf.Bytes(), err
+}
+
+// writeBackupFile writes a backup file to the job's directory at the given location.
+// The simulator supports "file" locations on the local file system and "http" or "https" locations using PUT.
+func writeBackupFile(ctx context.Context, job *backupJob, u *url.URL, name string, data []byte) error {
+	switch u.Scheme {
+	case "file":
+		dir := filepath.Join(filepath.FromSlash(u.Path), job.ID)
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dir, name), data, 0600)
+	case "http", "https":
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.JoinPath(job.ID, name).String(), bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		if job.LocationUser != "" {
+			req.SetBasicAuth(job.LocationUser, job.password)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = res.Body.Close()
+		if res.StatusCode >= http.StatusMultipleChoices {
+			return fmt.Errorf("PUT %s: %s", req.URL, res.Status)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported location scheme %q", u.Scheme)
+	}
+}
+
+func (h *Handler) runBackup(ctx context.Context, job *backupJob, u *url.URL, encrypted bool) {
+	err := func() error {
+		for _, part := range job.Parts {
+			data, err := archive(part)
+			if err != nil {
+				return err
+			}
+			if err = writeBackupFile(ctx, job, u, part+".tar.gz", data); err != nil {
+				return err
+			}
+			h.mu.Lock()
+			job.Size += int64(len(data))
+			job.Progress.Completed++
+			h.mu.Unlock()
+		}
+
+		metadata, err := json.MarshalIndent(map[string]any{
+			"id":         job.ID,
+			"type":       job.Type,
+			"comment":    job.Comment,
+			"parts":      job.Parts,
+			"encrypted":  encrypted,
+			"start_time": job.StartTime,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writeBackupFile(ctx, job, u, "backup-metadata.json", metadata)
+	}()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now().UTC()
+	job.EndTime = &now
+	job.Cancelable = false
+
+	if err != nil {
+		if ctx.Err() != nil {
+			err = errors.New("backup job canceled")
+		}
+		job.State = backup.JobStateFailed
+		job.Error = &rest.LocalizableMessage{
+			ID:             "com.vmware.applmgmt.backup.job.failed",
+			DefaultMessage: err.Error(),
+		}
+		return
+	}
+
+	job.State = backup.JobStateSucceeded
+	job.Progress.Completed = job.Progress.Total
+	h.pruneBackups(job, u)
+}
+
+// pruneBackups removes the oldest backups of a schedule that exceed its retention count.
+// Only "file" locations are pruned.
+// Must be called with h.mu held.
+func (h *Handler) pruneBackups(job *backupJob, u *url.URL) {
+	s, ok := h.backupSchedules[job.schedule]
+	if !ok || s.RetentionInfo == nil || s.RetentionInfo.MaxCount <= 0 || u.Scheme != "file" {
+		return
+	}
+
+	var jobs []*backupJob
+	for _, j := range h.backupJobs {
+		if j.schedule == job.schedule && j.Location == job.Location && j.State == backup.JobStateSucceeded {
+			jobs = append(jobs, j)
+		}
+	}
+
+	slices.SortFunc(jobs, func(a, b *backupJob) int {
+		return a.StartTime.Compare(b.StartTime)
+	})
+
+	for len(jobs) > int(s.RetentionInfo.MaxCount) {
+		_ = os.RemoveAll(filepath.Join(filepath.FromSlash(u.Path), jobs[0].ID))
+		jobs = jobs[1:]
+	}
+}
+
+func (h *Handler) backupJobList(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		ids := []string{}
+		for id := range h.backupJobs {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+		vapi.StatusOK(w, ids)
+	case http.MethodPost:
+		var spec backup.JobCreateSpec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+
+		id, err := h.startBackup(spec, "")
+		if err != nil {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+
+		vapi.StatusOK(w, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) backupJob(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	job, ok := h.backupJobs[path.Base(r.URL.Path)]
+	if !ok {
+		vapi.ApiErrorNotFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, job.JobInfo)
+	case http.MethodPost:
+		if r.URL.Query().Get("action") != "cancel" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if !job.Cancelable {
+			vapi.ApiErrorNotAllowedInCurrentState(w)
+			return
+		}
+
+		job.Cancelable = false
+		job.cancel()
+
+		vapi.StatusOK(w, struct {
+			Status string `json:"status"`
+		}{"OK"})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func validSchedule(location string, recurrence *backup.RecurrenceInfo, retention *backup.RetentionInfo) bool {
+	if location != "" {
+		if _, err := parseLocation(location); err != nil {
+			return false
+		}
+	}
+
+	if recurrence != nil {
+		if recurrence.Hour < 0 || recurrence.Hour > 23 || recurrence.Minute < 0 || recurrence.Minute > 59 {
+			return false
+		}
+	}
+
+	if retention != nil && retention.MaxCount < 0 {
+		return false
+	}
+
+	return true
+}
+
+func (h *Handler) backupScheduleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	res := make(map[string]backup.ScheduleInfo)
+	for id, s := range h.backupSchedules {
+		res[id] = s.ScheduleInfo
+	}
+
+	vapi.StatusOK(w, res)
+}
+
+// backupSchedule handles the backup schedule API.
+// The simulator does not run backup schedules on their recurrence, jobs are started using the "run" action.
+func (h *Handler) backupSchedule(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	id := path.Base(r.URL.Path)
+	s, ok := h.backupSchedules[id]
+
+	if r.Method == http.MethodPost && r.URL.Query().Get("action") == "" {
+		var spec backup.ScheduleCreateSpec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+
+		if ok {
+			vapi.ApiErrorAlreadyExists(w)
+			return
+		}
+
+		parts, err := selectParts(spec.Parts)
+		if err != nil || spec.Location == "" || !validSchedule(spec.Location, spec.RecurrenceInfo, spec.RetentionInfo) {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+
+		if h.backupSchedules == nil {
+			h.backupSchedules = make(map[string]*backupSchedule)
+		}
+
+		h.backupSchedules[id] = &backupSchedule{
+			ScheduleInfo: backup.ScheduleInfo{
+				Parts:          parts,
+				Location:       spec.Location,
+				LocationUser:   spec.LocationUser,
+				Enable:         spec.Enable == nil || *spec.Enable,
+				RecurrenceInfo: spec.RecurrenceInfo,
+				RetentionInfo:  spec.RetentionInfo,
+			},
+			backupPassword:   spec.BackupPassword,
+			locationPassword: spec.LocationPassword,
+		}
+
+		vapi.StatusOK(w)
+		return
+	}
+
+	if !ok {
+		vapi.ApiErrorNotFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, s.ScheduleInfo)
+	case http.MethodPatch:
+		var spec backup.ScheduleUpdateSpec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+
+		if !validSchedule(spec.Location, spec.RecurrenceInfo, spec.RetentionInfo) {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+
+		if len(spec.Parts) != 0 {
+			parts, err := selectParts(spec.Parts)
+			if err != nil {
+				vapi.ApiErrorInvalidArgument(w)
+				return
+			}
+			s.Parts = parts
+		}
+		if spec.BackupPassword != "" {
+			s.backupPassword = spec.BackupPassword
+		}
+		if spec.Location != "" {
+			s.Location = spec.Location
+		}
+		if spec.LocationUser != "" {
+			s.LocationUser = spec.LocationUser
+		}
+		if spec.LocationPassword != "" {
+			s.locationPassword = spec.LocationPassword
+		}
+		if spec.Enable != nil {
+			s.Enable = *spec.Enable
+		}
+		if spec.RecurrenceInfo != nil {
+			s.RecurrenceInfo = spec.RecurrenceInfo
+		}
+		if spec.RetentionInfo != nil {
+			s.RetentionInfo = spec.RetentionInfo
+		}
+
+		vapi.StatusOK(w)
+	case http.MethodDelete:
+		delete(h.backupSchedules, id)
+		vapi.StatusOK(w)
+	case http.MethodPost:
+		if r.URL.Query().Get("action") != "run" {
+			http.NotFound(w, r)
+			return
+		}
+
+		var spec struct {
+			Comment string `json:"comment,omitempty"`
+		}
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+
+		u, _ := parseLocation(s.Location)
+		job, err := h.startBackup(backup.JobCreateSpec{
+			Parts:            s.Parts,
+			BackupPassword:   s.backupPassword,
+			LocationType:     backup.LocationType(strings.ToUpper(u.Scheme)),
+			Location:         s.Location,
+			LocationUser:     s.LocationUser,
+			LocationPassword: s.locationPassword,
+			Comment:          spec.Comment,
+		}, id)
+		if err != nil {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+
+		vapi.StatusOK(w, job)
+	default:
+		http.NotFound(w, r)
+	}
+}
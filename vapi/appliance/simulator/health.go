@@ -0,0 +1,107 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/appliance/health"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// healthOptionPrefix is the OptionManager key prefix used to configure the level of a health item,
+// for example: govc option.set vcsim.appliance.health.storage red
+const healthOptionPrefix = "vcsim.appliance.health."
+
+// option returns the value of the given OptionManager setting, or an empty string if not set.
+func (h *Handler) option(key string) string {
+	ctx := &simulator.Context{
+		Context: context.Background(),
+		Session: &simulator.Session{
+			UserSession: types.UserSession{
+				Key: uuid.New().String(),
+			},
+			Registry: h.registry,
+		},
+		Map: h.registry,
+	}
+
+	var val string
+	m := h.registry.OptionManager()
+
+	h.registry.WithLock(ctx, m.Reference(), func() {
+		for _, opt := range m.Setting {
+			setting := opt.GetOptionValue()
+			if setting.Key == key {
+				val = fmt.Sprint(setting.Value)
+				break
+			}
+		}
+	})
+
+	return val
+}
+
+// healthLevel returns the configured level of the given item, defaulting to green.
+// The system level is the worst level of all other items.
+func (h *Handler) healthLevel(item string) health.Level {
+	levels := []health.Level{
+		health.LevelGreen,
+		health.LevelGray,
+		health.LevelYellow,
+		health.LevelOrange,
+		health.LevelRed,
+	}
+
+	level := health.Level(strings.ToLower(h.option(healthOptionPrefix + item)))
+	if !slices.Contains(levels, level) {
+		level = health.LevelGreen
+	}
+
+	if item == health.System {
+		for _, name := range health.Items {
+			if name == health.System {
+				continue
+			}
+			l := h.healthLevel(name)
+			if slices.Index(levels, l) > slices.Index(levels, level) {
+				level = l
+			}
+		}
+	}
+
+	return level
+}
+
+func (h *Handler) health(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	item := strings.TrimPrefix(r.URL.Path, health.Path+"/")
+
+	if item == path.Join(health.System, "lastcheck") {
+		vapi.StatusOK(w, time.Now().UTC().Truncate(time.Minute))
+		return
+	}
+
+	if !slices.Contains(health.Items, item) {
+		vapi.ApiErrorNotFound(w)
+		return
+	}
+
+	vapi.StatusOK(w, h.healthLevel(item))
+}
@@ -0,0 +1,183 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"math"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/appliance/monitoring"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+)
+
+// monitoringItems are the statistics reported by the simulator.
+var monitoringItems = []monitoring.Item{
+	{
+		ID:          "cpu.util",
+		Name:        "com.vmware.applmgmt.mon.name.cpu.util",
+		Units:       "com.vmware.applmgmt.mon.unit.percent",
+		Category:    "com.vmware.applmgmt.mon.cat.cpu",
+		Description: "CPU utilization",
+	},
+	{
+		ID:          "mem.usage",
+		Name:        "com.vmware.applmgmt.mon.name.mem.usage",
+		Units:       "com.vmware.applmgmt.mon.unit.kb",
+		Category:    "com.vmware.applmgmt.mon.cat.memory",
+		Description: "Memory usage",
+	},
+	{
+		ID:          "mem.total",
+		Name:        "com.vmware.applmgmt.mon.name.mem.total",
+		Units:       "com.vmware.applmgmt.mon.unit.kb",
+		Category:    "com.vmware.applmgmt.mon.cat.memory",
+		Description: "Total memory",
+	},
+	{
+		ID:          "mem.util",
+		Name:        "com.vmware.applmgmt.mon.name.mem.util",
+		Units:       "com.vmware.applmgmt.mon.unit.percent",
+		Category:    "com.vmware.applmgmt.mon.cat.memory",
+		Description: "Memory utilization",
+	},
+	{
+		ID:          "swap.util",
+		Name:        "com.vmware.applmgmt.mon.name.swap.util",
+		Units:       "com.vmware.applmgmt.mon.unit.percent",
+		Category:    "com.vmware.applmgmt.mon.cat.memory",
+		Description: "Swap utilization",
+	},
+	{
+		ID:          "storage.util.filesystem.root",
+		Name:        "com.vmware.applmgmt.mon.name.storage.util.filesystem.root",
+		Units:       "com.vmware.applmgmt.mon.unit.percent",
+		Category:    "com.vmware.applmgmt.mon.cat.storage",
+		Instance:    "root",
+		Description: "Root filesystem utilization",
+	},
+}
+
+// maxMonitoringPoints limits the number of data points returned for each statistic.
+const maxMonitoringPoints = 1024
+
+// monitoringValue returns a fake, but stable, value of the given statistic at time t.
+func monitoringValue(id string, t time.Time) string {
+	const total = 16 * 1024 * 1024
+
+	wave := (math.Sin(float64(t.Unix())/3600) + 1) / 2
+
+	switch id {
+	case "mem.total":
+		return strconv.Itoa(total)
+	case "mem.usage":
+		return strconv.Itoa(int(total * (0.4 + 0.2*wave)))
+	case "mem.util":
+		return strconv.FormatFloat(100*(0.4+0.2*wave), 'f', 2, 64)
+	case "swap.util":
+		return "0.00"
+	case "storage.util.filesystem.root":
+		return "42.00"
+	default:
+		return strconv.FormatFloat(100*(0.05+0.25*wave), 'f', 2, 64)
+	}
+}
+
+func (h *Handler) monitoringList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	vapi.StatusOK(w, monitoringItems)
+}
+
+func (h *Handler) monitoringItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	id := path.Base(r.URL.Path)
+	if id == "query" {
+		h.monitoringQuery(w, r)
+		return
+	}
+
+	for _, item := range monitoringItems {
+		if item.ID == id {
+			vapi.StatusOK(w, item)
+			return
+		}
+	}
+
+	vapi.ApiErrorNotFound(w)
+}
+
+func (h *Handler) monitoringQuery(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	interval := monitoring.Interval(q.Get("item.interval"))
+	function := monitoring.Function(q.Get("item.function"))
+	start, serr := time.Parse(time.RFC3339, q.Get("item.start_time"))
+	end, eerr := time.Parse(time.RFC3339, q.Get("item.end_time"))
+	names := q["item.names"]
+
+	functions := []monitoring.Function{
+		monitoring.FunctionCount,
+		monitoring.FunctionMax,
+		monitoring.FunctionAvg,
+		monitoring.FunctionMin,
+	}
+
+	step := interval.Duration()
+	if step == 0 || !slices.Contains(functions, function) || serr != nil || eerr != nil || end.Before(start) || len(names) == 0 {
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	for _, name := range names {
+		if !slices.ContainsFunc(monitoringItems, func(item monitoring.Item) bool { return item.ID == name }) {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+	}
+
+	start = start.Truncate(step)
+	if n := end.Sub(start) / step; n > maxMonitoringPoints {
+		start = end.Add(-maxMonitoringPoints * step).Truncate(step)
+	}
+	now := time.Now()
+
+	res := []monitoring.ItemData{}
+	for _, name := range names {
+		data := monitoring.ItemData{
+			Name:      name,
+			Interval:  interval,
+			Function:  function,
+			StartTime: start.UTC(),
+			EndTime:   end.UTC(),
+			Data:      []string{},
+		}
+
+		for t := start; !t.After(end); t = t.Add(step) {
+			switch {
+			case t.After(now):
+				data.Data = append(data.Data, "") // no data collected yet
+			case function == monitoring.FunctionCount:
+				data.Data = append(data.Data, strconv.Itoa(int(step/time.Minute)))
+			default:
+				data.Data = append(data.Data, monitoringValue(name, t))
+			}
+		}
+
+		res = append(res, data)
+	}
+
+	vapi.StatusOK(w, res)
+}
@@ -9,6 +9,7 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/vmware/govmomi/simulator"
@@ -16,7 +17,11 @@ import (
 	"github.com/vmware/govmomi/vapi/appliance/access/dcui"
 	"github.com/vmware/govmomi/vapi/appliance/access/shell"
 	"github.com/vmware/govmomi/vapi/appliance/access/ssh"
+	"github.com/vmware/govmomi/vapi/appliance/health"
+	"github.com/vmware/govmomi/vapi/appliance/monitoring"
+	"github.com/vmware/govmomi/vapi/appliance/recovery/backup"
 	"github.com/vmware/govmomi/vapi/appliance/shutdown"
+	"github.com/vmware/govmomi/vapi/appliance/update"
 	vapi "github.com/vmware/govmomi/vapi/simulator"
 )
 
@@ -34,6 +39,13 @@ type Handler struct {
 	ssh            ssh.Access
 	shell          shell.Access
 	shutdownConfig shutdown.Config
+
+	mu              sync.Mutex
+	backupJobs      map[string]*backupJob
+	backupSchedules map[string]*backupSchedule
+
+	registry *simulator.Registry
+	update   applianceUpdate
 }
 
 // New creates a Handler instance
@@ -55,6 +67,20 @@ func (h *Handler) Register(s *simulator.Service, r *simulator.Registry) {
 	s.HandleFunc(ssh.Path, h.sshAccess)
 	s.HandleFunc(shell.Path, h.shellAccess)
 	s.HandleFunc(shutdown.Path, h.shutdown)
+	s.HandleFunc(backup.PartsPath, h.backupParts)
+	s.HandleFunc(backup.JobPath, h.backupJobList)
+	s.HandleFunc(backup.JobPath+"/", h.backupJob)
+	s.HandleFunc(backup.SchedulesPath, h.backupScheduleList)
+	s.HandleFunc(backup.SchedulesPath+"/", h.backupSchedule)
+
+	h.registry = r
+	s.HandleFunc(health.Path+"/", h.health)
+	s.HandleFunc(monitoring.Path, h.monitoringList)
+	s.HandleFunc(monitoring.Path+"/", h.monitoringItem)
+	s.HandleFunc(update.Path, h.updateInfo)
+	s.HandleFunc(update.PendingPath, h.updatePendingList)
+	s.HandleFunc(update.PendingPath+"/", h.updatePending)
+	s.HandleFunc(update.StagedPath, h.updateStaged)
 }
 
 func (h *Handler) decode(r *http.Request, w http.ResponseWriter, val any) bool {
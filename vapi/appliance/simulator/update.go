@@ -0,0 +1,286 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/appliance/update"
+	tasks "github.com/vmware/govmomi/vapi/cis/tasks/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+)
+
+const (
+	// updateService is the vAPI service name of the simulator update tasks.
+	updateService = "com.vmware.appliance.update.pending"
+
+	// updateFailOption is the OptionManager key used to make update precheck and install fail,
+	// for example: govc option.set vcsim.appliance.update.fail true
+	updateFailOption = "vcsim.appliance.update.fail"
+
+	// updateVersion is the version of the simulated appliance before any update is installed.
+	updateVersion = "8.0.3.00100"
+)
+
+// updateStepDelay is the duration of each step of the stage and install tasks.
+var updateStepDelay = 100 * time.Millisecond
+
+// pendingUpdates are the updates available to the simulated appliance.
+var pendingUpdates = []update.Summary{
+	{
+		Version:        "8.0.3.00200",
+		Name:           "VC-8.0U3b",
+		Description:    "vCenter Server 8.0 Update 3b",
+		Priority:       update.PriorityHigh,
+		Severity:       update.SeverityCritical,
+		UpdateType:     update.CategorySecurity,
+		ReleaseDate:    time.Date(2024, time.September, 17, 0, 0, 0, 0, time.UTC),
+		RebootRequired: true,
+		Size:           6442,
+	},
+	{
+		Version:        "8.0.3.00300",
+		Name:           "VC-8.0U3c",
+		Description:    "vCenter Server 8.0 Update 3c",
+		Priority:       update.PriorityMedium,
+		Severity:       update.SeverityImportant,
+		UpdateType:     update.CategoryFix,
+		ReleaseDate:    time.Date(2024, time.October, 9, 0, 0, 0, 0, time.UTC),
+		RebootRequired: true,
+		Size:           6530,
+	},
+}
+
+// applianceUpdate is the state of the simulated update workflow, guarded by Handler.mu.
+type applianceUpdate struct {
+	version   string
+	staged    string
+	staging   bool
+	busy      update.State
+	failed    bool
+	queryTime *time.Time
+}
+
+// updateStatus returns the update info, must be called with h.mu held.
+func (h *Handler) updateStatus() update.Info {
+	u := &h.update
+	if u.version == "" {
+		u.version = updateVersion
+	}
+
+	info := update.Info{
+		State:           update.StateUpToDate,
+		Version:         u.version,
+		LatestQueryTime: u.queryTime,
+	}
+
+	switch {
+	case u.busy != "":
+		info.State = u.busy
+	case u.failed:
+		info.State = update.StateInstallFailed
+	case len(h.updatesPending()) != 0:
+		info.State = update.StateUpdatesPending
+	}
+
+	return info
+}
+
+// updatesPending returns the updates newer than the installed version, must be called with h.mu held.
+func (h *Handler) updatesPending() []update.Summary {
+	var res []update.Summary
+	for _, p := range pendingUpdates {
+		if p.Version > h.update.version {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+func (h *Handler) updateInfo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	vapi.StatusOK(w, h.updateStatus())
+}
+
+func (h *Handler) updatePendingList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.updateStatus()
+
+	switch update.SourceType(r.URL.Query().Get("source_type")) {
+	case update.SourceTypeLastCheck, update.SourceTypeLocal:
+	case update.SourceTypeLocalAndOnline:
+		now := time.Now().UTC()
+		h.update.queryTime = &now
+	default:
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	res := h.updatesPending()
+	if res == nil {
+		res = []update.Summary{}
+	}
+
+	vapi.StatusOK(w, res)
+}
+
+func (h *Handler) updatePending(w http.ResponseWriter, r *http.Request) {
+	fail := h.option(updateFailOption) == "true"
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state := h.updateStatus().State
+
+	version := path.Base(r.URL.Path)
+	i := slices.IndexFunc(h.updatesPending(), func(s update.Summary) bool { return s.Version == version })
+	if i == -1 {
+		vapi.ApiErrorNotFound(w)
+		return
+	}
+	summary := h.updatesPending()[i]
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, update.PendingInfo{
+			Summary:       summary,
+			Staged:        h.update.staged == version && !h.update.staging,
+			KnowledgeBase: "https://knowledge.broadcom.com/external/article?legacyId=" + strconv.Itoa(88000+i),
+		})
+		return
+	case http.MethodPost:
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	busy := state == update.StateStageInProgress || state == update.StateInstallInProgress
+
+	switch r.URL.Query().Get("action") {
+	case "precheck":
+		res := update.PrecheckResult{
+			CheckTime:                time.Now().UTC(),
+			EstimatedTimeToInstall:   45,
+			EstimatedTimeForRollback: 30,
+			RebootRequired:           summary.RebootRequired,
+			Issues:                   &update.Notifications{},
+		}
+		if fail {
+			res.Issues.Errors = append(res.Issues.Errors, update.Notification{
+				ID:      "com.vmware.appliance.update.precheck.failed",
+				Message: rest.LocalizableMessage{DefaultMessage: "Precheck failed: " + updateFailOption + " is set."},
+			})
+		}
+		vapi.StatusOK(w, res)
+	case "stage":
+		if busy {
+			vapi.ApiErrorNotAllowedInCurrentState(w)
+			return
+		}
+
+		h.update.staged = version
+		h.update.staging = true
+		h.update.busy = update.StateStageInProgress
+
+		task := tasks.NewTask(updateService, "stage")
+		go h.runUpdate(task, "Downloading update packages", func() error {
+			h.update.staging = false
+			return nil
+		})
+
+		vapi.StatusOK(w, task.ID())
+	case "install":
+		if busy || h.update.staged != version {
+			vapi.ApiErrorNotAllowedInCurrentState(w)
+			return
+		}
+
+		h.update.busy = update.StateInstallInProgress
+		h.update.failed = false
+
+		task := tasks.NewTask(updateService, "install")
+		go h.runUpdate(task, "Installing update packages", func() error {
+			if fail {
+				h.update.failed = true
+				return errors.New("installation of " + version + " failed")
+			}
+			h.update.version = version
+			h.update.staged = ""
+			return nil
+		})
+
+		vapi.StatusOK(w, task.ID())
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// runUpdate reports task progress for each step, then completes the task with the result of done,
+// which is called with h.mu held.
+func (h *Handler) runUpdate(task *tasks.Task, message string, done func() error) {
+	const steps = 4
+
+	for i := range steps {
+		task.Progress(int64(i*100/steps), message)
+		time.Sleep(updateStepDelay)
+	}
+
+	h.mu.Lock()
+	err := done()
+	h.update.busy = ""
+	h.mu.Unlock()
+
+	task.Done(nil, err)
+}
+
+func (h *Handler) updateStaged(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.updateStatus()
+
+	version := h.update.staged
+	if version == "" {
+		vapi.ApiErrorNotFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		i := slices.IndexFunc(pendingUpdates, func(s update.Summary) bool { return s.Version == version })
+		vapi.StatusOK(w, update.StagedInfo{
+			Summary:         pendingUpdates[i],
+			StagingComplete: !h.update.staging,
+		})
+	case http.MethodDelete:
+		if h.update.busy != "" {
+			vapi.ApiErrorNotAllowedInCurrentState(w)
+			return
+		}
+		h.update.staged = ""
+	default:
+		http.NotFound(w, r)
+	}
+}
@@ -0,0 +1,214 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package update
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+const (
+	Path        = "/api/appliance/update"
+	PendingPath = Path + "/pending"
+	StagedPath  = Path + "/staged"
+)
+
+// Manager provides convenience methods to query, stage and install appliance updates.
+// Stage and Install return a task ID, see the vapi/cis/tasks package to wait for task completion.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager with the given client
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// State of the appliance update process.
+type State string
+
+const (
+	StateUpToDate           = State("UP_TO_DATE")
+	StateUpdatesPending     = State("UPDATES_PENDING")
+	StateStageInProgress    = State("STAGE_IN_PROGRESS")
+	StateInstallInProgress  = State("INSTALL_IN_PROGRESS")
+	StateInstallFailed      = State("INSTALL_FAILED")
+	StateRollbackInProgress = State("ROLLBACK_IN_PROGRESS")
+)
+
+// Info contains the state of the appliance update process.
+type Info struct {
+	State           State      `json:"state"`
+	Version         string     `json:"version"`
+	LatestQueryTime *time.Time `json:"latest_query_time,omitempty"`
+}
+
+// Get returns the state of the appliance update process.
+func (m *Manager) Get(ctx context.Context) (*Info, error) {
+	r := m.Resource(Path)
+
+	var info Info
+	return &info, m.Do(ctx, r.Request(http.MethodGet), &info)
+}
+
+// SourceType of the pending updates.
+type SourceType string
+
+const (
+	SourceTypeLastCheck      = SourceType("LAST_CHECK")
+	SourceTypeLocal          = SourceType("LOCAL")
+	SourceTypeLocalAndOnline = SourceType("LOCAL_AND_ONLINE")
+)
+
+// Priority of an update.
+type Priority string
+
+const (
+	PriorityHigh   = Priority("HIGH")
+	PriorityMedium = Priority("MEDIUM")
+	PriorityLow    = Priority("LOW")
+)
+
+// Severity of the issues fixed by an update.
+type Severity string
+
+const (
+	SeverityCritical  = Severity("CRITICAL")
+	SeverityImportant = Severity("IMPORTANT")
+	SeverityModerate  = Severity("MODERATE")
+	SeverityLow       = Severity("LOW")
+)
+
+// Category of an update.
+type Category string
+
+const (
+	CategorySecurity = Category("SECURITY")
+	CategoryFix      = Category("FIX")
+	CategoryUpdate   = Category("UPDATE")
+)
+
+// Summary contains commonly used information about an update.
+type Summary struct {
+	Version        string    `json:"version"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description"`
+	Priority       Priority  `json:"priority"`
+	Severity       Severity  `json:"severity"`
+	UpdateType     Category  `json:"update_type"`
+	ReleaseDate    time.Time `json:"release_date"`
+	RebootRequired bool      `json:"reboot_required"`
+	Size           int64     `json:"size"`
+}
+
+// PendingInfo contains information about a pending update.
+type PendingInfo struct {
+	Summary
+
+	Staged        bool   `json:"staged"`
+	KnowledgeBase string `json:"knowledge_base,omitempty"`
+}
+
+// ListPending returns the updates available from the given source.
+func (m *Manager) ListPending(ctx context.Context, source SourceType) ([]Summary, error) {
+	r := m.Resource(PendingPath).WithParam("source_type", string(source))
+
+	var res []Summary
+	return res, m.Do(ctx, r.Request(http.MethodGet), &res)
+}
+
+// GetPending returns information about the given pending update version.
+func (m *Manager) GetPending(ctx context.Context, version string) (*PendingInfo, error) {
+	r := m.Resource(PendingPath).WithSubpath(url.PathEscape(version))
+
+	var info PendingInfo
+	return &info, m.Do(ctx, r.Request(http.MethodGet), &info)
+}
+
+// Notification is an issue reported by a precheck.
+type Notification struct {
+	ID         string                   `json:"id"`
+	Time       *time.Time               `json:"time,omitempty"`
+	Message    rest.LocalizableMessage  `json:"message"`
+	Resolution *rest.LocalizableMessage `json:"resolution,omitempty"`
+}
+
+// Notifications are grouped by severity.
+type Notifications struct {
+	Errors   []Notification `json:"errors,omitempty"`
+	Warnings []Notification `json:"warnings,omitempty"`
+	Info     []Notification `json:"info,omitempty"`
+}
+
+// PrecheckResult is the result of an update precheck.
+// Estimated times are in minutes.
+type PrecheckResult struct {
+	CheckTime                time.Time      `json:"check_time"`
+	EstimatedTimeToInstall   int64          `json:"estimated_time_to_install,omitempty"`
+	EstimatedTimeForRollback int64          `json:"estimated_time_for_rollback,omitempty"`
+	RebootRequired           bool           `json:"reboot_required"`
+	Issues                   *Notifications `json:"issues,omitempty"`
+}
+
+// Precheck runs the checks required before installing the given update version.
+func (m *Manager) Precheck(ctx context.Context, version string) (*PrecheckResult, error) {
+	r := m.Resource(PendingPath).WithSubpath(url.PathEscape(version)).WithParam("action", "precheck")
+
+	var res PrecheckResult
+	return &res, m.Do(ctx, r.Request(http.MethodPost), &res)
+}
+
+// Stage downloads the given update version, returning the task ID.
+func (m *Manager) Stage(ctx context.Context, version string) (string, error) {
+	r := m.Resource(PendingPath).WithSubpath(url.PathEscape(version)).
+		WithParam("action", "stage").
+		WithParam("vmw-tasks", "true")
+
+	var task string
+	return task, m.Do(ctx, r.Request(http.MethodPost), &task)
+}
+
+// Install installs the given staged update version, returning the task ID.
+// The userData map contains answers to the questions reported by Precheck.
+func (m *Manager) Install(ctx context.Context, version string, userData map[string]string) (string, error) {
+	r := m.Resource(PendingPath).WithSubpath(url.PathEscape(version)).
+		WithParam("action", "install").
+		WithParam("vmw-tasks", "true")
+
+	spec := struct {
+		UserData map[string]string `json:"user_data,omitempty"`
+	}{userData}
+
+	var task string
+	return task, m.Do(ctx, r.Request(http.MethodPost, spec), &task)
+}
+
+// StagedInfo contains information about the staged update.
+type StagedInfo struct {
+	Summary
+
+	StagingComplete bool `json:"staging_complete"`
+}
+
+// GetStaged returns information about the staged update.
+func (m *Manager) GetStaged(ctx context.Context) (*StagedInfo, error) {
+	r := m.Resource(StagedPath)
+
+	var info StagedInfo
+	return &info, m.Do(ctx, r.Request(http.MethodGet), &info)
+}
+
+// DeleteStaged deletes the staged update.
+func (m *Manager) DeleteStaged(ctx context.Context) error {
+	r := m.Resource(StagedPath)
+
+	return m.Do(ctx, r.Request(http.MethodDelete), nil)
+}
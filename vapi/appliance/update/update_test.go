@@ -0,0 +1,140 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package update_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/appliance/update"
+	"github.com/vmware/govmomi/vapi/cis/tasks"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+
+	_ "github.com/vmware/govmomi/vapi/appliance/simulator"
+	_ "github.com/vmware/govmomi/vapi/cis/tasks/simulator"
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestUpdate(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		require.NoError(t, c.Login(ctx, simulator.DefaultLogin))
+
+		m := update.NewManager(c)
+		tm := tasks.NewManagerWithCustomInterval(c, 1)
+
+		info, err := m.Get(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, update.StateUpdatesPending, info.State)
+		assert.Nil(t, info.LatestQueryTime)
+		current := info.Version
+
+		_, err = m.ListPending(ctx, "enoent")
+		require.Error(t, err)
+
+		pending, err := m.ListPending(ctx, update.SourceTypeLocalAndOnline)
+		require.NoError(t, err)
+		require.NotEmpty(t, pending)
+		version := pending[0].Version
+
+		info, err = m.Get(ctx)
+		require.NoError(t, err)
+		assert.NotNil(t, info.LatestQueryTime)
+
+		_, err = m.GetPending(ctx, "enoent")
+		require.Error(t, err)
+
+		res, err := m.Precheck(ctx, version)
+		require.NoError(t, err)
+		assert.Empty(t, res.Issues.Errors)
+
+		_, err = m.GetStaged(ctx)
+		require.Error(t, err)
+
+		// install requires the update to be staged
+		_, err = m.Install(ctx, version, nil)
+		require.Error(t, err)
+
+		id, err := m.Stage(ctx, version)
+		require.NoError(t, err)
+
+		info, err = m.Get(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, update.StateStageInProgress, info.State)
+
+		_, err = m.Stage(ctx, version)
+		require.Error(t, err)
+
+		status, err := tm.WaitForCompletion(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, string(tasks.StatusSucceeded), status)
+
+		staged, err := m.GetStaged(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, version, staged.Version)
+		assert.True(t, staged.StagingComplete)
+
+		p, err := m.GetPending(ctx, version)
+		require.NoError(t, err)
+		assert.True(t, p.Staged)
+
+		// install failure is configured via the OptionManager
+		opts := object.NewOptionManager(vc, *vc.ServiceContent.Setting)
+		fail := func(val string) {
+			require.NoError(t, opts.Update(ctx, []types.BaseOptionValue{
+				&types.OptionValue{Key: "vcsim.appliance.update.fail", Value: val},
+			}))
+		}
+
+		fail("true")
+		res, err = m.Precheck(ctx, version)
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.Issues.Errors)
+
+		id, err = m.Install(ctx, version, nil)
+		require.NoError(t, err)
+		status, err = tm.WaitForCompletion(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, string(tasks.StatusFailed), status)
+
+		task, err := tm.Get(ctx, id)
+		require.NoError(t, err)
+		require.NotNil(t, task.Error)
+		assert.Equal(t, "install", task.Operation)
+
+		info, err = m.Get(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, update.StateInstallFailed, info.State)
+		assert.Equal(t, current, info.Version)
+
+		fail("false")
+		id, err = m.Install(ctx, version, map[string]string{"vmdir.password": "secret"})
+		require.NoError(t, err)
+		status, err = tm.WaitForCompletion(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, string(tasks.StatusSucceeded), status)
+
+		task, err = tm.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, task.Progress.Total, task.Progress.Completed)
+
+		info, err = m.Get(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, version, info.Version)
+
+		_, err = m.GetStaged(ctx)
+		require.Error(t, err)
+
+		_, err = m.GetPending(ctx, version)
+		require.Error(t, err)
+	})
+}
@@ -5,11 +5,18 @@
 package simulator
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/url"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/vmware/govmomi/simulator"
 	"github.com/vmware/govmomi/vapi/cis/tasks"
+	"github.com/vmware/govmomi/vapi/rest"
 	vapi "github.com/vmware/govmomi/vapi/simulator"
 )
 
@@ -36,9 +43,95 @@ func (h *Handler) Register(s *simulator.Service, r *simulator.Registry) {
 	}
 }
 
+// Task is a vAPI task created by a simulator handler.
+type Task struct {
+	mu   sync.Mutex
+	id   string
+	info tasks.Info
+}
+
+// store holds the tasks created by NewTask, task IDs are unique across simulator instances.
+// Completed tasks are removed after taskRetention.
+var store sync.Map
+
+// taskRetention is how long a completed task can be queried, as vCenter expires completed tasks.
+var taskRetention = time.Hour
+
+// NewTask creates a running task for the given service operation, returning the task.
+// The task ID is used as the response of vAPI methods invoked with the "vmw-tasks=true" parameter.
+func NewTask(service, operation string) *Task {
+	now := time.Now().UTC()
+	t := &Task{
+		id: uuid.New().String() + ":" + service,
+		info: tasks.Info{
+			Service:    service,
+			Operation:  operation,
+			Status:     tasks.StatusRunning,
+			StartTime:  &now,
+			Progress:   &tasks.Progress{Total: 100},
+			Cancelable: false,
+		},
+	}
+	store.Store(t.id, t)
+	return t
+}
+
+// ID returns the task ID.
+func (t *Task) ID() string {
+	return t.id
+}
+
+// Progress updates the completed percentage and progress message of the task.
+func (t *Task) Progress(completed int64, message string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.info.Progress.Completed = completed
+	t.info.Progress.Message = rest.LocalizableMessage{DefaultMessage: message}
+}
+
+// Done completes the task with the given result, or as failed if err is not nil.
+func (t *Task) Done(result any, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now().UTC()
+	t.info.EndTime = &now
+
+	time.AfterFunc(taskRetention, func() {
+		store.Delete(t.id)
+	})
+
+	if err != nil {
+		t.info.Status = tasks.StatusFailed
+		t.info.Error = &tasks.Error{
+			ErrorType: "ERROR",
+			Messages:  []rest.LocalizableMessage{{DefaultMessage: err.Error()}},
+		}
+		return
+	}
+
+	t.info.Status = tasks.StatusSucceeded
+	t.info.Progress.Completed = t.info.Progress.Total
+	if result != nil {
+		t.info.Result, _ = json.Marshal(result)
+	}
+}
+
 func (h *Handler) depotsOffline(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
+		if t, ok := store.Load(path.Base(r.URL.Path)); ok {
+			task := t.(*Task)
+			task.mu.Lock()
+			info := task.info
+			progress := *info.Progress
+			info.Progress = &progress
+			task.mu.Unlock()
+			vapi.StatusOK(w, info)
+			return
+		}
+		// tasks not created by NewTask are reported as succeeded
 		task := make(map[string]string)
 		task["status"] = "SUCCEEDED"
 		vapi.StatusOK(w, task)
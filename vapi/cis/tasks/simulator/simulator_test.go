@@ -0,0 +1,32 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaskRetention(t *testing.T) {
+	retention := taskRetention
+	taskRetention = time.Millisecond
+	defer func() { taskRetention = retention }()
+
+	task := NewTask("com.vmware.test", "run")
+	if _, ok := store.Load(task.ID()); !ok {
+		t.Fatal("task not found")
+	}
+
+	task.Done(nil, nil)
+
+	for i := 0; i < 100; i++ {
+		if _, ok := store.Load(task.ID()); !ok {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Error("completed task was not removed")
+}
@@ -6,6 +6,8 @@ package tasks
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -43,15 +45,20 @@ func NewManagerWithCustomInterval(client *rest.Client, pollingInterval int) *Man
 
 func (c *Manager) WaitForCompletion(ctx context.Context, taskId string) (string, error) {
 	ticker := time.NewTicker(time.Second * time.Duration(c.pollingInterval))
+	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
+			return "", ctx.Err()
 		case <-ticker.C:
 			taskInfo, err := c.getTaskInfo(taskId)
-			status := taskInfo["status"].(string)
 			if err != nil {
-				return status, err
+				return "", err
+			}
+			status, ok := taskInfo["status"].(string)
+			if !ok {
+				return "", fmt.Errorf("task %s: invalid status %v", taskId, taskInfo["status"])
 			}
 
 			if status != "RUNNING" {
@@ -67,3 +74,56 @@ func (c *Manager) getTaskInfo(taskId string) (map[string]any, error) {
 	var res map[string]any
 	return res, c.Do(context.Background(), req, &res)
 }
+
+// Status of a task.
+type Status string
+
+const (
+	StatusPending   = Status("PENDING")
+	StatusRunning   = Status("RUNNING")
+	StatusBlocked   = Status("BLOCKED")
+	StatusSucceeded = Status("SUCCEEDED")
+	StatusFailed    = Status("FAILED")
+)
+
+// Progress of a task.
+type Progress struct {
+	Total     int64                   `json:"total"`
+	Completed int64                   `json:"completed"`
+	Message   rest.LocalizableMessage `json:"message"`
+}
+
+// Error of a failed task.
+type Error struct {
+	ErrorType string                    `json:"error_type"`
+	Messages  []rest.LocalizableMessage `json:"messages,omitempty"`
+}
+
+func (e *Error) Error() string {
+	if len(e.Messages) != 0 {
+		return e.Messages[0].DefaultMessage
+	}
+	return e.ErrorType
+}
+
+// Info contains information about a task.
+type Info struct {
+	Description *rest.LocalizableMessage `json:"description,omitempty"`
+	Service     string                   `json:"service"`
+	Operation   string                   `json:"operation"`
+	Status      Status                   `json:"status"`
+	Cancelable  bool                     `json:"cancelable"`
+	Progress    *Progress                `json:"progress,omitempty"`
+	Error       *Error                   `json:"error,omitempty"`
+	StartTime   *time.Time               `json:"start_time,omitempty"`
+	EndTime     *time.Time               `json:"end_time,omitempty"`
+	User        string                   `json:"user,omitempty"`
+	Result      json.RawMessage          `json:"result,omitempty"`
+}
+
+// Get returns information about the given task.
+func (c *Manager) Get(ctx context.Context, taskId string) (*Info, error) {
+	url := c.Resource(TasksPath).WithSubpath(taskId)
+	var res Info
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
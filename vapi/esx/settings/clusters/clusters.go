@@ -21,8 +21,16 @@ const (
 	SoftwareComponentsPath = SoftwareDraftsPath + "/%s/software/components"
 	// BaseImagePath The endpoint for retrieving the base image of a software draft
 	BaseImagePath = SoftwareDraftsPath + "/%s/software/base-image"
+	// HardwareSupportPath The endpoint for the hardware support packages of a software draft
+	HardwareSupportPath = SoftwareDraftsPath + "/%s/software/hardware-support"
+	// HardwareSupportManagersPath The endpoint for listing the registered hardware support managers
+	HardwareSupportManagersPath = basePath + "/hardware-support/managers"
 	// SoftwareEnablementPath The endpoint for retrieving the vLCM status (enabled/disabled) of a cluster
 	SoftwareEnablementPath = basePath + "/clusters/%s/enablement/software"
+	// SoftwarePath The endpoint for the desired software specification of a cluster
+	SoftwarePath = basePath + "/clusters/%s/software"
+	// SoftwareCompliancePath The endpoint for retrieving the software compliance of a cluster
+	SoftwareCompliancePath = SoftwarePath + "/compliance"
 )
 
 // Manager extends rest.Client, adding Software Drafts related methods.
@@ -133,6 +141,24 @@ type SettingsHardwareSupportInfo struct {
 	Packages map[string]SettingsHardwareSupportPackageInfo `json:"packages"`
 }
 
+// SettingsHardwareSupportManagerInfo is a type mapping for
+// https://developer.vmware.com/apis/vsphere-automation/latest/esx/data-structures/Settings/HardwareSupport/ManagerInfo/
+type SettingsHardwareSupportManagerInfo struct {
+	Manager     string `json:"manager"`
+	Description string `json:"description"`
+	DisplayName string `json:"display_name"`
+	Vendor      string `json:"vendor"`
+}
+
+// SettingsHardwareSupportManagersPackagesSummary is a type mapping for
+// https://developer.vmware.com/apis/vsphere-automation/latest/esx/data-structures/Settings/HardwareSupport/Managers/Packages/PackageInfo/
+type SettingsHardwareSupportManagersPackagesSummary struct {
+	Pkg               string   `json:"pkg"`
+	Version           string   `json:"version"`
+	Description       string   `json:"description"`
+	SupportedReleases []string `json:"supported_releases"`
+}
+
 // SettingsSoftwareInfo is a type mapping for
 // https://developer.vmware.com/apis/vsphere-automation/latest/esx/data-structures/Settings/SoftwareInfo/
 type SettingsSoftwareInfo struct {
@@ -181,6 +207,95 @@ type SoftwareManagementInfo struct {
 	Enabled bool `json:"enabled"`
 }
 
+// SettingsClustersSoftwareApplySpec is a type mapping for
+// https://developer.vmware.com/apis/vsphere-automation/latest/esx/data-structures/Settings/Clusters/Software/ApplySpec/
+type SettingsClustersSoftwareApplySpec struct {
+	Commit     string   `json:"commit,omitempty"`
+	Hosts      []string `json:"hosts,omitempty"`
+	AcceptEula bool     `json:"accept_eula,omitempty"`
+}
+
+// Compliance status values of a cluster, host, base image or component
+const (
+	ComplianceStatusCompliant    = "COMPLIANT"
+	ComplianceStatusNonCompliant = "NON_COMPLIANT"
+	ComplianceStatusIncompatible = "INCOMPATIBLE"
+	ComplianceStatusUnavailable  = "UNAVAILABLE"
+)
+
+// SettingsBaseImageCompliance is a type mapping for
+// https://developer.vmware.com/apis/vsphere-automation/latest/esx/data-structures/Settings/BaseImageCompliance/
+type SettingsBaseImageCompliance struct {
+	Status  string                `json:"status"`
+	Current SettingsBaseImageInfo `json:"current"`
+	Target  SettingsBaseImageInfo `json:"target"`
+}
+
+// SettingsComponentCompliance is a type mapping for
+// https://developer.vmware.com/apis/vsphere-automation/latest/esx/data-structures/Settings/ComponentCompliance/
+type SettingsComponentCompliance struct {
+	Status  string                 `json:"status"`
+	Current *SettingsComponentInfo `json:"current,omitempty"`
+	Target  *SettingsComponentInfo `json:"target,omitempty"`
+}
+
+// SettingsHardwareSupportPackageCompliance is a type mapping for
+// https://developer.vmware.com/apis/vsphere-automation/latest/esx/data-structures/Settings/HardwareSupportPackageCompliance/
+type SettingsHardwareSupportPackageCompliance struct {
+	Status  string                              `json:"status"`
+	Current *SettingsHardwareSupportPackageInfo `json:"current,omitempty"`
+	Target  *SettingsHardwareSupportPackageInfo `json:"target,omitempty"`
+}
+
+// SettingsHostCompliance is a type mapping for
+// https://developer.vmware.com/apis/vsphere-automation/latest/esx/data-structures/Settings/HostCompliance/
+type SettingsHostCompliance struct {
+	Status          string                                              `json:"status"`
+	ScanTime        string                                              `json:"scan_time"`
+	BaseImage       SettingsBaseImageCompliance                         `json:"base_image"`
+	Components      map[string]SettingsComponentCompliance              `json:"components"`
+	HardwareSupport map[string]SettingsHardwareSupportPackageCompliance `json:"hardware_support,omitempty"`
+}
+
+// SettingsClusterCompliance is a type mapping for
+// https://developer.vmware.com/apis/vsphere-automation/latest/esx/data-structures/Settings/ClusterCompliance/
+type SettingsClusterCompliance struct {
+	Status            string                            `json:"status"`
+	ScanTime          string                            `json:"scan_time"`
+	Commit            string                            `json:"commit,omitempty"`
+	Hosts             map[string]SettingsHostCompliance `json:"hosts"`
+	CompliantHosts    []string                          `json:"compliant_hosts"`
+	NonCompliantHosts []string                          `json:"non_compliant_hosts"`
+	IncompatibleHosts []string                          `json:"incompatible_hosts"`
+	UnavailableHosts  []string                          `json:"unavailable_hosts"`
+}
+
+// Apply status values of a cluster or host
+const (
+	ApplyStatusOK      = "OK"
+	ApplyStatusSkipped = "SKIPPED"
+	ApplyStatusError   = "ERROR"
+)
+
+// SettingsClustersSoftwareApplyStatus is a type mapping for
+// https://developer.vmware.com/apis/vsphere-automation/latest/esx/data-structures/Settings/Clusters/Software/ApplyStatus/
+type SettingsClustersSoftwareApplyStatus struct {
+	Status    string `json:"status"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+// SettingsClustersSoftwareApplyResult is a type mapping for
+// https://developer.vmware.com/apis/vsphere-automation/latest/esx/data-structures/Settings/Clusters/Software/ApplyResult/
+type SettingsClustersSoftwareApplyResult struct {
+	Status          SettingsClustersSoftwareApplyStatus            `json:"status"`
+	Commit          string                                         `json:"commit"`
+	HostStatus      map[string]SettingsClustersSoftwareApplyStatus `json:"host_status"`
+	SuccessfulHosts []string                                       `json:"successful_hosts"`
+	FailedHosts     []string                                       `json:"failed_hosts"`
+	SkippedHosts    []string                                       `json:"skipped_hosts"`
+}
+
 // ListSoftwareDrafts retrieves the software drafts for a cluster
 // https://developer.vmware.com/apis/vsphere-automation/latest/esx/api/esx/settings/clusters/cluster/software/drafts/get/
 func (c *Manager) ListSoftwareDrafts(clusterId string, owners *[]string) (map[string]SettingsClustersSoftwareDraftsMetadata, error) {
@@ -281,6 +396,49 @@ func (c *Manager) SetSoftwareDraftBaseImage(clusterId, draftId, version string)
 	return c.Do(context.Background(), req, nil)
 }
 
+// GetSoftwareDraftHardwareSupport returns the hardware support packages of the specified draft, by manager
+// https://developer.vmware.com/apis/vsphere-automation/latest/esx/api/esx/settings/clusters/cluster/software/drafts/draft/software/hardware-support/get/
+func (c *Manager) GetSoftwareDraftHardwareSupport(clusterId, draftId string) (SettingsHardwareSupportInfo, error) {
+	path := c.Resource(fmt.Sprintf(HardwareSupportPath, clusterId, draftId))
+	req := path.Request(http.MethodGet)
+	var res SettingsHardwareSupportInfo
+	return res, c.Do(context.Background(), req, &res)
+}
+
+// SetSoftwareDraftHardwareSupport sets the hardware support package of the given manager on the specified draft
+// https://developer.vmware.com/apis/vsphere-automation/latest/esx/api/esx/settings/clusters/cluster/software/drafts/draft/software/hardware-support/managers/manager/put/
+func (c *Manager) SetSoftwareDraftHardwareSupport(clusterId, draftId, manager string, spec SettingsHardwareSupportPackageInfo) error {
+	path := c.Resource(fmt.Sprintf(HardwareSupportPath, clusterId, draftId)).WithSubpath("managers").WithSubpath(manager)
+	req := path.Request(http.MethodPut, spec)
+	return c.Do(context.Background(), req, nil)
+}
+
+// RemoveSoftwareDraftHardwareSupport removes the hardware support package of the given manager from the specified draft
+// https://developer.vmware.com/apis/vsphere-automation/latest/esx/api/esx/settings/clusters/cluster/software/drafts/draft/software/hardware-support/managers/manager/delete/
+func (c *Manager) RemoveSoftwareDraftHardwareSupport(clusterId, draftId, manager string) error {
+	path := c.Resource(fmt.Sprintf(HardwareSupportPath, clusterId, draftId)).WithSubpath("managers").WithSubpath(manager)
+	req := path.Request(http.MethodDelete)
+	return c.Do(context.Background(), req, nil)
+}
+
+// ListHardwareSupportManagers returns the registered hardware support managers
+// https://developer.vmware.com/apis/vsphere-automation/latest/esx/api/esx/settings/hardware-support/managers/get/
+func (c *Manager) ListHardwareSupportManagers() ([]SettingsHardwareSupportManagerInfo, error) {
+	path := c.Resource(HardwareSupportManagersPath)
+	req := path.Request(http.MethodGet)
+	var res []SettingsHardwareSupportManagerInfo
+	return res, c.Do(context.Background(), req, &res)
+}
+
+// ListHardwareSupportPackages returns the hardware support packages provided by the given manager
+// https://developer.vmware.com/apis/vsphere-automation/latest/esx/api/esx/settings/hardware-support/managers/manager/packages/get/
+func (c *Manager) ListHardwareSupportPackages(manager string) ([]SettingsHardwareSupportManagersPackagesSummary, error) {
+	path := c.Resource(HardwareSupportManagersPath).WithSubpath(manager).WithSubpath("packages")
+	req := path.Request(http.MethodGet)
+	var res []SettingsHardwareSupportManagersPackagesSummary
+	return res, c.Do(context.Background(), req, &res)
+}
+
 // EnableSoftwareManagement enables vLCM on the cluster
 // https://developer.vmware.com/apis/vsphere-automation/latest/esx/settings/clusters.enablement.software/put
 func (c *Manager) EnableSoftwareManagement(clusterId string, skipCheck bool) (string, error) {
@@ -298,3 +456,41 @@ func (c *Manager) GetSoftwareManagement(clusterId string) (SoftwareManagementInf
 	var res SoftwareManagementInfo
 	return res, c.Do(context.Background(), req, &res)
 }
+
+// GetSoftware returns the desired software specification of the cluster
+// https://developer.vmware.com/apis/vsphere-automation/latest/esx/api/esx/settings/clusters/cluster/software/get/
+func (c *Manager) GetSoftware(clusterId string) (SettingsSoftwareInfo, error) {
+	path := c.Resource(fmt.Sprintf(SoftwarePath, clusterId))
+	req := path.Request(http.MethodGet)
+	var res SettingsSoftwareInfo
+	return res, c.Do(context.Background(), req, &res)
+}
+
+// ScanCluster scans the hosts of the cluster against the desired software specification.
+// The task result is a SettingsClusterCompliance.
+// https://developer.vmware.com/apis/vsphere-automation/latest/esx/api/esx/settings/clusters/cluster/softwareactionscanvmw-taskstrue/post/
+func (c *Manager) ScanCluster(clusterId string) (string, error) {
+	path := c.Resource(fmt.Sprintf(SoftwarePath, clusterId)).WithParam("action", "scan").WithParam("vmw-tasks", "true")
+	req := path.Request(http.MethodPost)
+	var res string
+	return res, c.Do(context.Background(), req, &res)
+}
+
+// CheckCompliance returns the compliance of the cluster from the last scan
+// https://developer.vmware.com/apis/vsphere-automation/latest/esx/api/esx/settings/clusters/cluster/software/compliance/get/
+func (c *Manager) CheckCompliance(clusterId string) (SettingsClusterCompliance, error) {
+	path := c.Resource(fmt.Sprintf(SoftwareCompliancePath, clusterId))
+	req := path.Request(http.MethodGet)
+	var res SettingsClusterCompliance
+	return res, c.Do(context.Background(), req, &res)
+}
+
+// ApplySoftware remediates the hosts of the cluster to the desired software specification.
+// The task result is a SettingsClustersSoftwareApplyResult.
+// https://developer.vmware.com/apis/vsphere-automation/latest/esx/api/esx/settings/clusters/cluster/softwareactionapplyvmw-taskstrue/post/
+func (c *Manager) ApplySoftware(clusterId string, spec SettingsClustersSoftwareApplySpec) (string, error) {
+	path := c.Resource(fmt.Sprintf(SoftwarePath, clusterId)).WithParam("action", "apply").WithParam("vmw-tasks", "true")
+	req := path.Request(http.MethodPost, spec)
+	var res string
+	return res, c.Do(context.Background(), req, &res)
+}
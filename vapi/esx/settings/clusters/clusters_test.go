@@ -0,0 +1,194 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusters_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/cis/tasks"
+	"github.com/vmware/govmomi/vapi/esx/settings/clusters"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/cis/tasks/simulator"
+	_ "github.com/vmware/govmomi/vapi/esx/settings/simulator"
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestSoftwareCompliance(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		require.NoError(t, c.Login(ctx, simulator.DefaultLogin))
+
+		cluster, err := find.NewFinder(vc).DefaultClusterComputeResource(ctx)
+		require.NoError(t, err)
+		id := cluster.Reference().Value
+
+		m := clusters.NewManager(c)
+		tm := tasks.NewManagerWithCustomInterval(c, 1)
+
+		_, err = m.ScanCluster("domain-c0")
+		require.Error(t, err)
+
+		_, err = m.CheckCompliance(id)
+		require.Error(t, err) // not scanned yet
+
+		taskId, err := m.ScanCluster(id)
+		require.NoError(t, err)
+		task, err := tm.Get(ctx, taskId)
+		require.NoError(t, err)
+		assert.Equal(t, tasks.StatusSucceeded, task.Status)
+
+		var compliance clusters.SettingsClusterCompliance
+		require.NoError(t, json.Unmarshal(task.Result, &compliance))
+		assert.Equal(t, clusters.ComplianceStatusCompliant, compliance.Status)
+		assert.Len(t, compliance.CompliantHosts, 3)
+
+		// commit a draft with a new component and base image
+		draft, err := m.CreateSoftwareDraft(id)
+		require.NoError(t, err)
+		require.NoError(t, m.UpdateSoftwareDraftComponents(id, draft, clusters.SoftwareComponentsUpdateSpec{
+			ComponentsToSet: map[string]string{"comp-id": "1.2.3.4"},
+		}))
+		require.NoError(t, m.SetSoftwareDraftBaseImage(id, draft, "0.0.2"))
+		_, err = m.CommitSoftwareDraft(id, draft, clusters.SettingsClustersSoftwareDraftsCommitSpec{})
+		require.NoError(t, err)
+
+		software, err := m.GetSoftware(id)
+		require.NoError(t, err)
+		assert.Equal(t, "0.0.2", software.BaseImage.Version)
+		assert.Contains(t, software.Components, "comp-id")
+
+		_, err = m.ScanCluster(id)
+		require.NoError(t, err)
+		compliance, err = m.CheckCompliance(id)
+		require.NoError(t, err)
+		assert.Equal(t, clusters.ComplianceStatusNonCompliant, compliance.Status)
+		require.Len(t, compliance.NonCompliantHosts, 3)
+
+		host := compliance.NonCompliantHosts[0]
+		hc := compliance.Hosts[host]
+		assert.Equal(t, clusters.ComplianceStatusNonCompliant, hc.BaseImage.Status)
+		assert.Nil(t, hc.Components["comp-id"].Current)
+		assert.Equal(t, "1.2.3.4", hc.Components["comp-id"].Target.Version)
+
+		// apply requires vLCM enabled
+		_, err = m.ApplySoftware(id, clusters.SettingsClustersSoftwareApplySpec{})
+		require.Error(t, err)
+		_, err = m.EnableSoftwareManagement(id, true)
+		require.NoError(t, err)
+
+		_, err = m.ApplySoftware(id, clusters.SettingsClustersSoftwareApplySpec{Commit: "enoent"})
+		require.Error(t, err)
+		_, err = m.ApplySoftware(id, clusters.SettingsClustersSoftwareApplySpec{Hosts: []string{"host-0"}})
+		require.Error(t, err)
+
+		// remediate a single host
+		taskId, err = m.ApplySoftware(id, clusters.SettingsClustersSoftwareApplySpec{Commit: compliance.Commit, Hosts: []string{host}})
+		require.NoError(t, err)
+		task, err = tm.Get(ctx, taskId)
+		require.NoError(t, err)
+
+		var result clusters.SettingsClustersSoftwareApplyResult
+		require.NoError(t, json.Unmarshal(task.Result, &result))
+		assert.Equal(t, []string{host}, result.SuccessfulHosts)
+
+		compliance, err = m.CheckCompliance(id)
+		require.NoError(t, err)
+		assert.Equal(t, []string{host}, compliance.CompliantHosts)
+		assert.Len(t, compliance.NonCompliantHosts, 2)
+
+		// remediate all hosts
+		_, err = m.ApplySoftware(id, clusters.SettingsClustersSoftwareApplySpec{})
+		require.NoError(t, err)
+		compliance, err = m.CheckCompliance(id)
+		require.NoError(t, err)
+		assert.Equal(t, clusters.ComplianceStatusCompliant, compliance.Status)
+		assert.Len(t, compliance.CompliantHosts, 3)
+	})
+}
+
+func TestHardwareSupport(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		require.NoError(t, c.Login(ctx, simulator.DefaultLogin))
+
+		cluster, err := find.NewFinder(vc).DefaultClusterComputeResource(ctx)
+		require.NoError(t, err)
+		id := cluster.Reference().Value
+
+		m := clusters.NewManager(c)
+
+		managers, err := m.ListHardwareSupportManagers()
+		require.NoError(t, err)
+		require.NotEmpty(t, managers)
+		hsm := managers[0].Manager
+
+		_, err = m.ListHardwareSupportPackages("enoent")
+		require.Error(t, err)
+
+		pkgs, err := m.ListHardwareSupportPackages(hsm)
+		require.NoError(t, err)
+		require.NotEmpty(t, pkgs)
+		pkg := clusters.SettingsHardwareSupportPackageInfo{Pkg: pkgs[0].Pkg, Version: pkgs[0].Version}
+
+		draft, err := m.CreateSoftwareDraft(id)
+		require.NoError(t, err)
+
+		err = m.SetSoftwareDraftHardwareSupport(id, draft, hsm, clusters.SettingsHardwareSupportPackageInfo{Pkg: pkg.Pkg, Version: "enoent"})
+		require.Error(t, err)
+		require.NoError(t, m.SetSoftwareDraftHardwareSupport(id, draft, hsm, pkg))
+
+		info, err := m.GetSoftwareDraftHardwareSupport(id, draft)
+		require.NoError(t, err)
+		assert.Equal(t, pkg, info.Packages[hsm])
+
+		_, err = m.CommitSoftwareDraft(id, draft, clusters.SettingsClustersSoftwareDraftsCommitSpec{})
+		require.NoError(t, err)
+
+		software, err := m.GetSoftware(id)
+		require.NoError(t, err)
+		assert.Equal(t, pkg, software.HardwareSupport.Packages[hsm])
+
+		_, err = m.ScanCluster(id)
+		require.NoError(t, err)
+		compliance, err := m.CheckCompliance(id)
+		require.NoError(t, err)
+		require.Len(t, compliance.NonCompliantHosts, 3)
+		hc := compliance.Hosts[compliance.NonCompliantHosts[0]].HardwareSupport[hsm]
+		assert.Equal(t, clusters.ComplianceStatusNonCompliant, hc.Status)
+		assert.Nil(t, hc.Current)
+		assert.Equal(t, pkg, *hc.Target)
+
+		_, err = m.EnableSoftwareManagement(id, true)
+		require.NoError(t, err)
+		_, err = m.ApplySoftware(id, clusters.SettingsClustersSoftwareApplySpec{})
+		require.NoError(t, err)
+		compliance, err = m.CheckCompliance(id)
+		require.NoError(t, err)
+		assert.Equal(t, clusters.ComplianceStatusCompliant, compliance.Status)
+
+		// removing the package from the desired software makes the remediated hosts non-compliant
+		draft, err = m.CreateSoftwareDraft(id)
+		require.NoError(t, err)
+		require.NoError(t, m.RemoveSoftwareDraftHardwareSupport(id, draft, hsm))
+		require.Error(t, m.RemoveSoftwareDraftHardwareSupport(id, draft, hsm))
+		_, err = m.CommitSoftwareDraft(id, draft, clusters.SettingsClustersSoftwareDraftsCommitSpec{})
+		require.NoError(t, err)
+
+		_, err = m.ScanCluster(id)
+		require.NoError(t, err)
+		compliance, err = m.CheckCompliance(id)
+		require.NoError(t, err)
+		assert.Equal(t, clusters.ComplianceStatusNonCompliant, compliance.Status)
+	})
+}
@@ -8,9 +8,12 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/vmware/govmomi/simulator"
+	tasks "github.com/vmware/govmomi/vapi/cis/tasks/simulator"
 	"github.com/vmware/govmomi/vapi/esx/settings/clusters"
 	"github.com/vmware/govmomi/vapi/esx/settings/depots"
 	vapi "github.com/vmware/govmomi/vapi/simulator"
@@ -32,11 +35,21 @@ type Handler struct {
 	SoftwareDrafts     map[string]clusters.SettingsClustersSoftwareDraftsMetadata
 	SoftwareComponents map[string]clusters.SettingsComponentInfo
 	ClusterImage       *clusters.SettingsBaseImageInfo
+	HardwareSupport    map[string]clusters.SettingsHardwareSupportPackageInfo
+
+	HardwareSupportManagers []clusters.SettingsHardwareSupportManagerInfo
+	HardwareSupportPackages map[string][]clusters.SettingsHardwareSupportManagersPackagesSummary
 
 	depotCounter int
 	draftCounter int
 
 	vlcmEnabled bool
+
+	registry        *simulator.Registry
+	mu              sync.Mutex
+	commitCounter   int
+	clusterSoftware map[string]*clusterSoftware
+	hostSoftware    map[string]*clusters.SettingsSoftwareInfo
 }
 
 // New creates a Handler instance
@@ -48,16 +61,25 @@ func New(u *url.URL) *Handler {
 		BaseImages:         createMockBaseImages(),
 		SoftwareDrafts:     make(map[string]clusters.SettingsClustersSoftwareDraftsMetadata),
 		SoftwareComponents: make(map[string]clusters.SettingsComponentInfo),
+		HardwareSupport:    make(map[string]clusters.SettingsHardwareSupportPackageInfo),
 		depotCounter:       0,
 		vlcmEnabled:        false,
+		clusterSoftware:    make(map[string]*clusterSoftware),
+		hostSoftware:       make(map[string]*clusters.SettingsSoftwareInfo),
+
+		HardwareSupportManagers: createMockHardwareSupportManagers(),
+		HardwareSupportPackages: createMockHardwareSupportPackages(),
 	}
 }
 
 func (h *Handler) Register(s *simulator.Service, r *simulator.Registry) {
 	if r.IsVPX() {
+		h.registry = r
 		s.HandleFunc(depots.DepotsOfflinePath, h.depotsOffline)
 		s.HandleFunc(depots.DepotsOfflinePath+"/", h.depotsOffline)
 		s.HandleFunc(depots.BaseImagesPath, h.baseImages)
+		s.HandleFunc(clusters.HardwareSupportManagersPath, h.hardwareSupportManagers)
+		s.HandleFunc(clusters.HardwareSupportManagersPath+"/", h.hardwareSupportManagers)
 		s.HandleFunc("/api/esx/settings/clusters/", h.clusters)
 	}
 }
@@ -133,6 +155,7 @@ func (h *Handler) clusters(w http.ResponseWriter, r *http.Request) {
 	segments := strings.Split(subpath, "/")
 
 	if len(segments) > 3 && segments[2] == "software" && segments[3] == "drafts" {
+		clusterId := segments[1]
 		segments = segments[4:]
 		if len(segments) > 2 && segments[1] == "software" && segments[2] == "components" {
 			h.clustersSoftwareDraftsComponents(w, r, segments)
@@ -140,19 +163,25 @@ func (h *Handler) clusters(w http.ResponseWriter, r *http.Request) {
 		} else if len(segments) > 2 && segments[1] == "software" && segments[2] == "base-image" {
 			h.clustersSoftwareDraftsBaseImage(w, r)
 			return
+		} else if len(segments) > 2 && segments[1] == "software" && segments[2] == "hardware-support" {
+			h.clustersSoftwareDraftsHardwareSupport(w, r, segments[3:])
+			return
 		} else {
-			h.clustersSoftwareDrafts(w, r, segments)
+			h.clustersSoftwareDrafts(w, r, clusterId, segments)
 			return
 		}
 	} else if len(segments) > 3 && segments[2] == "enablement" && segments[3] == "software" {
 		h.clustersSoftwareEnablement(w, r)
 		return
+	} else if len(segments) > 2 && segments[2] == "software" {
+		h.clustersSoftware(w, r, segments[1], segments[3:])
+		return
 	}
 
 	vapi.ApiErrorUnsupported(w)
 }
 
-func (h *Handler) clustersSoftwareDrafts(w http.ResponseWriter, r *http.Request, subpath []string) {
+func (h *Handler) clustersSoftwareDrafts(w http.ResponseWriter, r *http.Request, clusterId string, subpath []string) {
 	var draftId *string
 	if len(subpath) > 0 {
 		draftId = &subpath[0]
@@ -188,9 +217,13 @@ func (h *Handler) clustersSoftwareDrafts(w http.ResponseWriter, r *http.Request,
 					return
 				} else {
 					delete(h.SoftwareDrafts, *draftId)
-					vapi.StatusOK(w)
+					h.commitSoftware(clusterId)
+					task := tasks.NewTask("com.vmware.esx.settings.clusters.software.drafts", "commit")
+					task.Done(nil, nil)
+					vapi.StatusOK(w, task.ID())
 				}
 			}
+			return
 		}
 		// Only one active draft is permitted
 		if len(h.SoftwareDrafts) > 0 {
@@ -256,13 +289,92 @@ func (h *Handler) clustersSoftwareDraftsBaseImage(w http.ResponseWriter, r *http
 	}
 }
 
+func (h *Handler) clustersSoftwareDraftsHardwareSupport(w http.ResponseWriter, r *http.Request, subpath []string) {
+	if len(subpath) == 0 {
+		if r.Method == http.MethodGet {
+			vapi.StatusOK(w, clusters.SettingsHardwareSupportInfo{Packages: h.HardwareSupport})
+		} else {
+			vapi.ApiErrorUnsupported(w)
+		}
+		return
+	}
+
+	if len(subpath) != 2 || subpath[0] != "managers" {
+		vapi.ApiErrorUnsupported(w)
+		return
+	}
+
+	manager := subpath[1]
+
+	switch r.Method {
+	case http.MethodGet:
+		if pkg, contains := h.HardwareSupport[manager]; contains {
+			vapi.StatusOK(w, pkg)
+		} else {
+			vapi.ApiErrorNotFound(w)
+		}
+	case http.MethodPut:
+		var spec clusters.SettingsHardwareSupportPackageInfo
+		if vapi.Decode(r, w, &spec) {
+			if !slices.ContainsFunc(h.HardwareSupportPackages[manager], func(p clusters.SettingsHardwareSupportManagersPackagesSummary) bool {
+				return p.Pkg == spec.Pkg && p.Version == spec.Version
+			}) {
+				vapi.ApiErrorInvalidArgument(w)
+				return
+			}
+			h.HardwareSupport[manager] = spec
+			vapi.StatusOK(w)
+		}
+	case http.MethodDelete:
+		if _, contains := h.HardwareSupport[manager]; contains {
+			delete(h.HardwareSupport, manager)
+			vapi.StatusOK(w)
+		} else {
+			vapi.ApiErrorNotFound(w)
+		}
+	default:
+		vapi.ApiErrorUnsupported(w)
+	}
+}
+
+func (h *Handler) hardwareSupportManagers(w http.ResponseWriter, r *http.Request) {
+	subpath := strings.Trim(r.URL.Path[len(clusters.HardwareSupportManagersPath):], "/")
+
+	if r.Method != http.MethodGet {
+		vapi.ApiErrorUnsupported(w)
+		return
+	}
+
+	if subpath == "" {
+		vapi.StatusOK(w, h.HardwareSupportManagers)
+		return
+	}
+
+	segments := strings.Split(subpath, "/")
+	if len(segments) != 2 || segments[1] != "packages" {
+		vapi.ApiErrorUnsupported(w)
+		return
+	}
+
+	if pkgs, contains := h.HardwareSupportPackages[segments[0]]; contains {
+		vapi.StatusOK(w, pkgs)
+	} else {
+		vapi.ApiErrorNotFound(w)
+	}
+}
+
 func (h *Handler) clustersSoftwareEnablement(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	switch r.Method {
 	case http.MethodGet:
 		vapi.StatusOK(w, clusters.SoftwareManagementInfo{Enabled: h.vlcmEnabled})
 	case http.MethodPut:
 		h.vlcmEnabled = true
-		vapi.StatusOK(w)
+		task := tasks.NewTask("com.vmware.esx.settings.clusters.enablement.software", "enable")
+		task.Done(nil, nil)
+		vapi.StatusOK(w, task.ID())
 	}
 }
 
@@ -274,3 +386,23 @@ func createMockBaseImages() []depots.BaseImagesSummary {
 	}
 	return []depots.BaseImagesSummary{baseImage}
 }
+
+func createMockHardwareSupportManagers() []clusters.SettingsHardwareSupportManagerInfo {
+	return []clusters.SettingsHardwareSupportManagerInfo{{
+		Manager:     "com.example.hsm",
+		Description: "Dummy hardware support manager",
+		DisplayName: "DummyHSM",
+		Vendor:      "Example",
+	}}
+}
+
+func createMockHardwareSupportPackages() map[string][]clusters.SettingsHardwareSupportManagersPackagesSummary {
+	return map[string][]clusters.SettingsHardwareSupportManagersPackagesSummary{
+		"com.example.hsm": {{
+			Pkg:               "DummyFirmware",
+			Version:           "1.0.0",
+			Description:       "Dummy firmware and drivers",
+			SupportedReleases: []string{"0.0.1", "0.0.2"},
+		}},
+	}
+}
@@ -0,0 +1,380 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vmware/govmomi/simulator"
+	tasks "github.com/vmware/govmomi/vapi/cis/tasks/simulator"
+	"github.com/vmware/govmomi/vapi/esx/settings/clusters"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+const softwareService = "com.vmware.esx.settings.clusters.software"
+
+// clusterSoftware is the desired software specification of a cluster and the result of the last scan.
+type clusterSoftware struct {
+	commit     string
+	software   clusters.SettingsSoftwareInfo
+	compliance *clusters.SettingsClusterCompliance
+}
+
+// hostState is the state of a cluster host used to compute compliance.
+type hostState struct {
+	id        string
+	connected bool
+}
+
+// copySoftware returns a copy of the given software specification.
+func copySoftware(s clusters.SettingsSoftwareInfo) clusters.SettingsSoftwareInfo {
+	s.Components = maps.Clone(s.Components)
+	if s.Components == nil {
+		s.Components = make(map[string]clusters.SettingsComponentInfo)
+	}
+	s.HardwareSupport.Packages = maps.Clone(s.HardwareSupport.Packages)
+	return s
+}
+
+// defaultSoftware returns the software installed on hosts that have not been remediated.
+func (h *Handler) defaultSoftware() clusters.SettingsSoftwareInfo {
+	var base clusters.SettingsBaseImageInfo
+	if len(h.BaseImages) != 0 {
+		image := h.BaseImages[0]
+		base = clusters.SettingsBaseImageInfo{
+			Version: image.Version,
+			Details: clusters.SettingsBaseImageDetails{
+				DisplayName:    image.DisplayName,
+				DisplayVersion: image.DisplayVersion,
+			},
+		}
+	}
+
+	return copySoftware(clusters.SettingsSoftwareInfo{BaseImage: base})
+}
+
+// desiredSoftware returns the desired software of the given cluster, must be called with h.mu held.
+func (h *Handler) desiredSoftware(clusterId string) *clusterSoftware {
+	cs, ok := h.clusterSoftware[clusterId]
+	if !ok {
+		cs = &clusterSoftware{software: h.defaultSoftware()}
+		h.clusterSoftware[clusterId] = cs
+	}
+	return cs
+}
+
+// installedSoftware returns the software installed on the given host, must be called with h.mu held.
+func (h *Handler) installedSoftware(hostId string) *clusters.SettingsSoftwareInfo {
+	s, ok := h.hostSoftware[hostId]
+	if !ok {
+		software := h.defaultSoftware()
+		s = &software
+		h.hostSoftware[hostId] = s
+	}
+	return s
+}
+
+// commitSoftware sets the desired software of the given cluster to the base image and components of the draft.
+func (h *Handler) commitSoftware(clusterId string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cs := h.desiredSoftware(clusterId)
+
+	h.commitCounter++
+	cs.commit = strconv.Itoa(h.commitCounter)
+	software := cs.software
+	if h.ClusterImage != nil {
+		software.BaseImage = *h.ClusterImage
+	}
+	software.Components = h.SoftwareComponents
+	software.HardwareSupport.Packages = h.HardwareSupport
+	cs.software = copySoftware(software)
+	cs.compliance = nil
+}
+
+// clusterHosts returns the hosts of the given cluster, false if the cluster does not exist.
+func (h *Handler) clusterHosts(clusterId string) ([]hostState, bool) {
+	ref := types.ManagedObjectReference{Type: "ClusterComputeResource", Value: clusterId}
+	cluster, ok := h.registry.Get(ref).(*simulator.ClusterComputeResource)
+	if !ok {
+		return nil, false
+	}
+
+	ctx := &simulator.Context{
+		Context: context.Background(),
+		Session: &simulator.Session{
+			UserSession: types.UserSession{
+				Key: uuid.New().String(),
+			},
+			Registry: h.registry,
+		},
+		Map: h.registry,
+	}
+
+	var refs []types.ManagedObjectReference
+	h.registry.WithLock(ctx, cluster, func() {
+		refs = slices.Clone(cluster.Host)
+	})
+
+	var hosts []hostState
+	for _, ref := range refs {
+		host, ok := h.registry.Get(ref).(*simulator.HostSystem)
+		if !ok {
+			continue
+		}
+		h.registry.WithLock(ctx, host, func() {
+			hosts = append(hosts, hostState{
+				id:        ref.Value,
+				connected: host.Runtime.ConnectionState == types.HostSystemConnectionStateConnected,
+			})
+		})
+	}
+
+	return hosts, true
+}
+
+// hostCompliance compares the software installed on the host with the desired software.
+func hostCompliance(current, target clusters.SettingsSoftwareInfo, now string) clusters.SettingsHostCompliance {
+	hc := clusters.SettingsHostCompliance{
+		Status:   clusters.ComplianceStatusCompliant,
+		ScanTime: now,
+		BaseImage: clusters.SettingsBaseImageCompliance{
+			Status:  clusters.ComplianceStatusCompliant,
+			Current: current.BaseImage,
+			Target:  target.BaseImage,
+		},
+		Components: make(map[string]clusters.SettingsComponentCompliance),
+	}
+
+	if current.BaseImage.Version != target.BaseImage.Version {
+		hc.BaseImage.Status = clusters.ComplianceStatusNonCompliant
+		hc.Status = clusters.ComplianceStatusNonCompliant
+	}
+
+	names := slices.Collect(maps.Keys(current.Components))
+	for name := range target.Components {
+		if _, ok := current.Components[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		cc := clusters.SettingsComponentCompliance{Status: clusters.ComplianceStatusCompliant}
+		if c, ok := current.Components[name]; ok {
+			cc.Current = &c
+		}
+		if t, ok := target.Components[name]; ok {
+			cc.Target = &t
+		}
+		if cc.Current == nil || cc.Target == nil || cc.Current.Version != cc.Target.Version {
+			cc.Status = clusters.ComplianceStatusNonCompliant
+			hc.Status = clusters.ComplianceStatusNonCompliant
+		}
+		hc.Components[name] = cc
+	}
+
+	managers := slices.Collect(maps.Keys(current.HardwareSupport.Packages))
+	for name := range target.HardwareSupport.Packages {
+		if _, ok := current.HardwareSupport.Packages[name]; !ok {
+			managers = append(managers, name)
+		}
+	}
+
+	for _, name := range managers {
+		if hc.HardwareSupport == nil {
+			hc.HardwareSupport = make(map[string]clusters.SettingsHardwareSupportPackageCompliance)
+		}
+		pc := clusters.SettingsHardwareSupportPackageCompliance{Status: clusters.ComplianceStatusCompliant}
+		if c, ok := current.HardwareSupport.Packages[name]; ok {
+			pc.Current = &c
+		}
+		if t, ok := target.HardwareSupport.Packages[name]; ok {
+			pc.Target = &t
+		}
+		if pc.Current == nil || pc.Target == nil || *pc.Current != *pc.Target {
+			pc.Status = clusters.ComplianceStatusNonCompliant
+			hc.Status = clusters.ComplianceStatusNonCompliant
+		}
+		hc.HardwareSupport[name] = pc
+	}
+
+	return hc
+}
+
+// scan computes the compliance of the cluster hosts, must be called with h.mu held.
+func (h *Handler) scan(cs *clusterSoftware, hosts []hostState) clusters.SettingsClusterCompliance {
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	res := clusters.SettingsClusterCompliance{
+		ScanTime:          now,
+		Commit:            cs.commit,
+		Hosts:             make(map[string]clusters.SettingsHostCompliance),
+		CompliantHosts:    []string{},
+		NonCompliantHosts: []string{},
+		IncompatibleHosts: []string{},
+		UnavailableHosts:  []string{},
+	}
+
+	for _, host := range hosts {
+		if !host.connected {
+			res.Hosts[host.id] = clusters.SettingsHostCompliance{
+				Status:   clusters.ComplianceStatusUnavailable,
+				ScanTime: now,
+			}
+			res.UnavailableHosts = append(res.UnavailableHosts, host.id)
+			continue
+		}
+
+		hc := hostCompliance(*h.installedSoftware(host.id), cs.software, now)
+		res.Hosts[host.id] = hc
+		if hc.Status == clusters.ComplianceStatusCompliant {
+			res.CompliantHosts = append(res.CompliantHosts, host.id)
+		} else {
+			res.NonCompliantHosts = append(res.NonCompliantHosts, host.id)
+		}
+	}
+
+	switch {
+	case len(res.NonCompliantHosts) != 0:
+		res.Status = clusters.ComplianceStatusNonCompliant
+	case len(res.UnavailableHosts) != 0 && len(res.CompliantHosts) == 0:
+		res.Status = clusters.ComplianceStatusUnavailable
+	default:
+		res.Status = clusters.ComplianceStatusCompliant
+	}
+
+	return res
+}
+
+// apply installs the desired software on the given cluster hosts, must be called with h.mu held.
+func (h *Handler) apply(cs *clusterSoftware, hosts []hostState) clusters.SettingsClustersSoftwareApplyResult {
+	start := time.Now().UTC().Format(time.RFC3339)
+
+	res := clusters.SettingsClustersSoftwareApplyResult{
+		Commit:          cs.commit,
+		HostStatus:      make(map[string]clusters.SettingsClustersSoftwareApplyStatus),
+		SuccessfulHosts: []string{},
+		FailedHosts:     []string{},
+		SkippedHosts:    []string{},
+	}
+
+	for _, host := range hosts {
+		status := clusters.SettingsClustersSoftwareApplyStatus{
+			Status:    clusters.ApplyStatusOK,
+			StartTime: start,
+		}
+
+		if host.connected {
+			software := copySoftware(cs.software)
+			h.hostSoftware[host.id] = &software
+			res.SuccessfulHosts = append(res.SuccessfulHosts, host.id)
+		} else {
+			status.Status = clusters.ApplyStatusSkipped
+			res.SkippedHosts = append(res.SkippedHosts, host.id)
+		}
+
+		status.EndTime = time.Now().UTC().Format(time.RFC3339)
+		res.HostStatus[host.id] = status
+	}
+
+	res.Status = clusters.SettingsClustersSoftwareApplyStatus{
+		Status:    clusters.ApplyStatusOK,
+		StartTime: start,
+		EndTime:   time.Now().UTC().Format(time.RFC3339),
+	}
+
+	return res
+}
+
+func (h *Handler) clustersSoftware(w http.ResponseWriter, r *http.Request, clusterId string, subpath []string) {
+	hosts, ok := h.clusterHosts(clusterId)
+	if !ok {
+		vapi.ApiErrorNotFound(w)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cs := h.desiredSoftware(clusterId)
+
+	if len(subpath) == 1 && subpath[0] == "compliance" && r.Method == http.MethodGet {
+		if cs.compliance == nil {
+			vapi.ApiErrorNotFound(w)
+			return
+		}
+		vapi.StatusOK(w, cs.compliance)
+		return
+	}
+
+	if len(subpath) != 0 {
+		vapi.ApiErrorUnsupported(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, cs.software)
+	case http.MethodPost:
+		switch r.URL.Query().Get("action") {
+		case "scan":
+			compliance := h.scan(cs, hosts)
+			cs.compliance = &compliance
+
+			task := tasks.NewTask(softwareService, "scan")
+			task.Done(compliance, nil)
+			vapi.StatusOK(w, task.ID())
+		case "apply":
+			var spec clusters.SettingsClustersSoftwareApplySpec
+			if !vapi.Decode(r, w, &spec) {
+				return
+			}
+
+			if !h.vlcmEnabled {
+				vapi.ApiErrorNotAllowedInCurrentState(w)
+				return
+			}
+
+			if spec.Commit != "" && spec.Commit != cs.commit {
+				vapi.ApiErrorInvalidArgument(w)
+				return
+			}
+
+			targets := hosts
+			if len(spec.Hosts) != 0 {
+				targets = nil
+				for _, id := range spec.Hosts {
+					i := slices.IndexFunc(hosts, func(host hostState) bool { return host.id == id })
+					if i == -1 {
+						vapi.ApiErrorInvalidArgument(w)
+						return
+					}
+					targets = append(targets, hosts[i])
+				}
+			}
+
+			result := h.apply(cs, targets)
+			compliance := h.scan(cs, hosts)
+			cs.compliance = &compliance
+
+			task := tasks.NewTask(softwareService, "apply")
+			task.Done(result, nil)
+			vapi.StatusOK(w, task.ID())
+		default:
+			vapi.ApiErrorUnsupported(w)
+		}
+	default:
+		vapi.ApiErrorUnsupported(w)
+	}
+}
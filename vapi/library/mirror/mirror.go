@@ -0,0 +1,527 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package mirror synchronizes a local directory tree to a content library,
+// transferring only the library item files that differ from the local files.
+package mirror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/ovf"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vim25/progress"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+// Change operations
+const (
+	CreateItem = "create" // Create a library item
+	DeleteItem = "delete" // Delete a library item
+	UploadFile = "upload" // Upload a new or changed file
+	RemoveFile = "remove" // Remove a file from a library item
+)
+
+// Change is a single step of a Plan.
+type Change struct {
+	Op       string            `json:"op"`
+	Item     string            `json:"item"`
+	ItemID   string            `json:"itemId,omitempty"`
+	File     string            `json:"file,omitempty"`
+	Path     string            `json:"path,omitempty"`
+	Size     int64             `json:"size,omitempty"`
+	Checksum *library.Checksum `json:"checksum,omitempty"`
+}
+
+func (c Change) String() string {
+	switch c.Op {
+	case CreateItem, DeleteItem:
+		return fmt.Sprintf("%s %s", c.Op, c.Item)
+	default:
+		return fmt.Sprintf("%s %s/%s", c.Op, c.Item, c.File)
+	}
+}
+
+// Mirror makes the items of a library match those of a local directory.
+//
+// Each subdirectory of the local directory corresponds to a library item of
+// the same name, containing the regular files within that subdirectory.
+// Each regular file in the local directory corresponds to a library item
+// named by the file name without extension, as with govc library.import.
+// Hidden files are ignored.
+//
+// Local and library item files are compared by checksum, using the algorithm
+// of the checksum reported by the library. Files without a checksum are always uploaded.
+type Mirror struct {
+	*library.Manager
+
+	// Delete library items that do not exist in the local directory,
+	// and remove library item files that do not exist in the local item.
+	// When false, library items and files are only created or updated.
+	Delete bool
+
+	// Algorithm used to verify uploaded files, defaults to SHA256.
+	// Files that exist in the library are verified with the algorithm reported by the library.
+	Algorithm string
+
+	// Progress, if set, is called to create a progress sinker for each file upload.
+	Progress func(Change) *progress.ProgressLogger
+}
+
+// New returns a Mirror for the given library Manager.
+func New(m *library.Manager) *Mirror {
+	return &Mirror{Manager: m}
+}
+
+// Checksum returns the checksum of the given file using the given algorithm.
+func Checksum(name, algorithm string) (*library.Checksum, error) {
+	alg, ok := ovf.DigestAlgorithm(algorithm)
+	if !ok {
+		return nil, fmt.Errorf("unsupported checksum algorithm %q", algorithm)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := alg.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return nil, err
+	}
+
+	return &library.Checksum{
+		Algorithm: strings.ToUpper(algorithm),
+		Checksum:  fmt.Sprintf("%x", h.Sum(nil)),
+	}, nil
+}
+
+type localFile struct {
+	name string
+	path string
+	size int64
+}
+
+func isHidden(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
+
+// local returns the library items of the given directory: item name -> files
+func local(dir string) (map[string][]localFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[string][]localFile)
+
+	add := func(item, file string, info os.FileInfo) {
+		items[item] = append(items[item], localFile{info.Name(), file, info.Size()})
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if isHidden(name) {
+			continue
+		}
+
+		fpath := filepath.Join(dir, name)
+
+		if entry.Type().IsRegular() {
+			ext := filepath.Ext(name)
+			if strings.EqualFold(ext, ".ova") {
+				return nil, fmt.Errorf("%s: OVA files are not supported, use 'govc ovf.unpack'", fpath)
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return nil, err
+			}
+			item := strings.TrimSuffix(name, ext)
+			if _, ok := items[item]; ok {
+				return nil, fmt.Errorf("%s: duplicate item name %q", fpath, item)
+			}
+			add(item, fpath, info)
+			continue
+		}
+
+		if !entry.IsDir() {
+			continue
+		}
+
+		files, err := os.ReadDir(fpath)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := items[name]; ok {
+			return nil, fmt.Errorf("%s: duplicate item name %q", fpath, name)
+		}
+		items[name] = nil
+
+		for _, file := range files {
+			if isHidden(file.Name()) || !file.Type().IsRegular() {
+				continue
+			}
+			info, err := file.Info()
+			if err != nil {
+				return nil, err
+			}
+			add(name, filepath.Join(fpath, file.Name()), info)
+		}
+
+		if len(items[name]) == 0 {
+			delete(items, name)
+		}
+	}
+
+	return items, nil
+}
+
+// uploadOrder sorts .ovf files first, as the descriptor must be uploaded before the files it references.
+func uploadOrder(files []localFile) {
+	sort.SliceStable(files, func(i, j int) bool {
+		oi := strings.EqualFold(filepath.Ext(files[i].name), ".ovf")
+		oj := strings.EqualFold(filepath.Ext(files[j].name), ".ovf")
+		if oi != oj {
+			return oi
+		}
+		return files[i].name < files[j].name
+	})
+}
+
+func (m *Mirror) upload(item string, id string, file localFile, cs *library.Checksum) (Change, error) {
+	if cs == nil {
+		alg := m.Algorithm
+		if alg == "" {
+			alg = "SHA256"
+		}
+		var err error
+		cs, err = Checksum(file.path, alg)
+		if err != nil {
+			return Change{}, err
+		}
+	}
+
+	return Change{
+		Op:       UploadFile,
+		Item:     item,
+		ItemID:   id,
+		File:     file.name,
+		Path:     file.path,
+		Size:     file.size,
+		Checksum: cs,
+	}, nil
+}
+
+// changed reports whether the given local file differs from the library item file.
+// The returned checksum is that of the local file, when one was computed.
+// Sizes are not compared, as the size of a stored file may differ from that
+// of the uploaded file (e.g. a streamOptimized vmdk).
+func changed(file localFile, lf library.File) (bool, *library.Checksum, error) {
+	if lf.Checksum == nil || lf.Checksum.Checksum == "" {
+		return true, nil, nil // no way to tell if content changed
+	}
+
+	cs, err := Checksum(file.path, lf.Checksum.Algorithm)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return !strings.EqualFold(cs.Checksum, lf.Checksum.Checksum), cs, nil
+}
+
+// Plan returns the changes needed to make the given library match the local directory.
+func (m *Mirror) Plan(ctx context.Context, lib *library.Library, dir string) ([]Change, error) {
+	if lib.Type == "SUBSCRIBED" {
+		return nil, fmt.Errorf("library %q is a subscribed library", lib.Name)
+	}
+
+	litems, err := local(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := m.GetLibraryItems(ctx, lib.ID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+
+	var changes []Change
+	seen := make(map[string]bool)
+
+	for _, item := range items {
+		files, ok := litems[item.Name]
+		if !ok || seen[item.Name] {
+			if m.Delete {
+				changes = append(changes, Change{Op: DeleteItem, Item: item.Name, ItemID: item.ID})
+			}
+			continue
+		}
+		seen[item.Name] = true
+
+		lfiles, err := m.ListLibraryItemFiles(ctx, item.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		remote := make(map[string]library.File, len(lfiles))
+		for _, lf := range lfiles {
+			remote[lf.Name] = lf
+		}
+
+		uploadOrder(files)
+
+		for _, file := range files {
+			var cs *library.Checksum
+			if lf, ok := remote[file.name]; ok {
+				delete(remote, file.name)
+				var diff bool
+				if diff, cs, err = changed(file, lf); err != nil {
+					return nil, err
+				}
+				if !diff {
+					continue
+				}
+			}
+
+			change, err := m.upload(item.Name, item.ID, file, cs)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, change)
+		}
+
+		if !m.Delete {
+			continue
+		}
+
+		var removed []string
+		for name := range remote {
+			removed = append(removed, name)
+		}
+		sort.Strings(removed)
+
+		for _, name := range removed {
+			changes = append(changes, Change{Op: RemoveFile, Item: item.Name, ItemID: item.ID, File: name})
+		}
+	}
+
+	var created []string
+	for name := range litems {
+		if !seen[name] {
+			created = append(created, name)
+		}
+	}
+	sort.Strings(created)
+
+	for _, name := range created {
+		changes = append(changes, Change{Op: CreateItem, Item: name})
+
+		files := litems[name]
+		uploadOrder(files)
+
+		for _, file := range files {
+			change, err := m.upload(name, "", file, nil)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
+}
+
+// itemType returns the library item type for the given files.
+func itemType(changes []Change) string {
+	var iso int
+	for _, c := range changes {
+		switch strings.ToLower(filepath.Ext(c.File)) {
+		case ".ovf":
+			return library.ItemTypeOVF
+		case ".iso":
+			iso++
+		}
+	}
+	if iso == 1 && len(changes) == 1 {
+		return library.ItemTypeISO
+	}
+	return ""
+}
+
+// sameItem reports whether the file change c applies to the same item as the item change.
+func sameItem(item, c Change) bool {
+	if item.Op == DeleteItem || c.Op == CreateItem || c.Op == DeleteItem {
+		return false
+	}
+	return item.Item == c.Item && item.ItemID == c.ItemID
+}
+
+// Apply applies the given Plan changes to the library.
+func (m *Mirror) Apply(ctx context.Context, lib *library.Library, changes []Change) error {
+	var item []Change
+
+	for i, c := range changes {
+		item = append(item, c)
+		if i+1 < len(changes) && sameItem(item[0], changes[i+1]) {
+			continue
+		}
+
+		if err := m.apply(ctx, lib, item); err != nil {
+			return err
+		}
+		item = nil
+	}
+
+	return nil
+}
+
+// apply changes to a single library item
+func (m *Mirror) apply(ctx context.Context, lib *library.Library, changes []Change) error {
+	id := changes[0].ItemID
+
+	switch changes[0].Op {
+	case DeleteItem:
+		return m.DeleteLibraryItem(ctx, &library.Item{ID: id})
+	case CreateItem:
+		var err error
+		id, err = m.CreateLibraryItem(ctx, library.Item{
+			Name:      changes[0].Item,
+			LibraryID: lib.ID,
+			Type:      itemType(changes[1:]),
+		})
+		if err != nil {
+			return err
+		}
+		changes = changes[1:]
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	session, err := m.CreateLibraryItemUpdateSession(ctx, library.Session{LibraryItemID: id})
+	if err != nil {
+		return err
+	}
+
+	fail := func(err error) error {
+		_ = m.FailLibraryItemUpdateSession(ctx, session)
+		return err
+	}
+
+	for _, c := range changes {
+		switch c.Op {
+		case RemoveFile:
+			if err = m.RemoveLibraryItemUpdateSessionFile(ctx, session, c.File); err != nil {
+				return fail(err)
+			}
+		case UploadFile:
+			if err = m.uploadFile(ctx, session, c); err != nil {
+				return fail(err)
+			}
+		}
+	}
+
+	if err = m.CompleteLibraryItemUpdateSession(ctx, session); err != nil {
+		return err
+	}
+
+	if err = m.WaitOnLibraryItemUpdateSession(ctx, session, time.Second, nil); err != nil {
+		return err
+	}
+
+	return m.verify(ctx, id, changes)
+}
+
+func (m *Mirror) uploadFile(ctx context.Context, session string, c Change) error {
+	f, err := os.Open(c.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	update, err := m.AddLibraryItemFile(ctx, session, library.UpdateFile{
+		Name:       c.File,
+		SourceType: "PUSH",
+		Checksum:   c.Checksum,
+		Size:       c.Size,
+	})
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(update.UploadEndpoint.URI)
+	if err != nil {
+		return err
+	}
+
+	p := soap.DefaultUpload
+	p.ContentLength = c.Size
+	if m.Progress != nil {
+		logger := m.Progress(c)
+		p.Progress = logger
+		defer logger.Wait()
+	}
+
+	return m.Client.Upload(ctx, f, u, &p)
+}
+
+// verify the library item file checksums match those of the uploaded files.
+func (m *Mirror) verify(ctx context.Context, id string, changes []Change) error {
+	files, err := m.ListLibraryItemFiles(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	remote := make(map[string]library.File, len(files))
+	for _, f := range files {
+		remote[f.Name] = f
+	}
+
+	var errs []error
+
+	for _, c := range changes {
+		if c.Op != UploadFile {
+			continue
+		}
+
+		f, ok := remote[c.File]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s/%s: not found after upload", c.Item, c.File))
+			continue
+		}
+
+		if f.Checksum == nil || f.Checksum.Checksum == "" {
+			continue
+		}
+
+		cs := c.Checksum
+		if cs == nil || !strings.EqualFold(cs.Algorithm, f.Checksum.Algorithm) {
+			if cs, err = Checksum(c.Path, f.Checksum.Algorithm); err != nil {
+				return err
+			}
+		}
+
+		if !strings.EqualFold(cs.Checksum, f.Checksum.Checksum) {
+			errs = append(errs, fmt.Errorf("%s/%s: %s checksum mismatch: local=%s library=%s",
+				c.Item, c.File, cs.Algorithm, cs.Checksum, f.Checksum.Checksum))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,137 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package mirror_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/library/mirror"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestMirror(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, content string) {
+		name = filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), 0750))
+		require.NoError(t, os.WriteFile(name, []byte(content), 0600))
+	}
+
+	ops := func(changes []mirror.Change) []string {
+		var res []string
+		for _, c := range changes {
+			res = append(res, c.String())
+		}
+		return res
+	}
+
+	testdata := filepath.Join("..", "testdata")
+	name := "ttylinux-pc_i486-16.1"
+	for _, ext := range []string{".ovf", ".mf", "-disk1.vmdk"} {
+		b, err := os.ReadFile(filepath.Join(testdata, name+ext))
+		require.NoError(t, err)
+		write(filepath.Join("ttylinux", name+ext), string(b))
+	}
+	write("notes.txt", "hello")
+	write("misc/a.txt", "a")
+	write("misc/b.txt", "b")
+
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		require.NoError(t, c.Login(ctx, simulator.DefaultLogin))
+
+		ds, err := find.NewFinder(vc).DefaultDatastore(ctx)
+		require.NoError(t, err)
+
+		lm := library.NewManager(c)
+
+		id, err := lm.CreateLibrary(ctx, library.Library{
+			Name: "mirror",
+			Type: "LOCAL",
+			Storage: []library.StorageBacking{{
+				DatastoreID: ds.Reference().Value,
+				Type:        "DATASTORE",
+			}},
+		})
+		require.NoError(t, err)
+
+		lib, err := lm.GetLibraryByID(ctx, id)
+		require.NoError(t, err)
+
+		m := mirror.New(lm)
+
+		sync := func(expect ...string) {
+			changes, err := m.Plan(ctx, lib, dir)
+			require.NoError(t, err)
+			assert.Equal(t, expect, ops(changes))
+			require.NoError(t, m.Apply(ctx, lib, changes))
+
+			changes, err = m.Plan(ctx, lib, dir)
+			require.NoError(t, err)
+			assert.Empty(t, changes)
+		}
+
+		sync(
+			"create misc",
+			"upload misc/a.txt",
+			"upload misc/b.txt",
+			"create notes",
+			"upload notes/notes.txt",
+			"create ttylinux",
+			"upload ttylinux/"+name+".ovf",
+			"upload ttylinux/"+name+"-disk1.vmdk",
+			"upload ttylinux/"+name+".mf",
+		)
+
+		items, err := lm.FindLibraryItems(ctx, library.FindItem{LibraryID: id, Name: "ttylinux"})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		item, err := lm.GetLibraryItem(ctx, items[0])
+		require.NoError(t, err)
+		assert.Equal(t, library.ItemTypeOVF, item.Type)
+
+		// Same size, different content
+		write("misc/a.txt", "A")
+		require.NoError(t, os.Remove(filepath.Join(dir, "misc", "b.txt")))
+		write("misc/c.txt", "c")
+		sync(
+			"upload misc/a.txt",
+			"upload misc/c.txt",
+		)
+
+		files, err := lm.ListLibraryItemFiles(ctx, items[0])
+		require.NoError(t, err)
+		assert.Len(t, files, 3)
+
+		require.NoError(t, os.Remove(filepath.Join(dir, "notes.txt")))
+
+		changes, err := m.Plan(ctx, lib, dir)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+
+		m.Delete = true
+		sync(
+			"remove misc/b.txt",
+			"delete notes",
+		)
+
+		items, err = lm.ListLibraryItems(ctx, id)
+		require.NoError(t, err)
+		assert.Len(t, items, 2)
+	})
+}
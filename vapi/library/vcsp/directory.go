@@ -0,0 +1,326 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package vcsp
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultUsername is the user name vCenter uses for publications with basic authentication.
+const DefaultUsername = "vcsp"
+
+// Directory publishes a local directory as a content library.
+//
+// Each top-level subdirectory is published as a library item containing the
+// files within that directory. A subdirectory containing an .ovf file is
+// published as an OVF item, a subdirectory with a single .iso file as an ISO
+// item and any other subdirectory as a generic item. Each top-level regular
+// file is published as a single-file item. Hidden files are not published.
+//
+// The directory is scanned on each request for lib.json, items.json and
+// item.json, such that changes are picked up by subscribers on their next sync.
+type Directory struct {
+	// Path of the directory to publish
+	Path string
+	// Name of the library, defaults to the base name of Path
+	Name string
+
+	// Username and Password enable basic authentication when Password is set.
+	// Username defaults to DefaultUsername.
+	Username string
+	Password string
+}
+
+type dirItem struct {
+	Item
+	path     string
+	modified time.Time
+	files    map[string]string
+}
+
+type catalog struct {
+	lib   Library
+	items []*dirItem
+}
+
+func isHidden(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
+
+func version(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func etag(info os.FileInfo) string {
+	return fmt.Sprintf("%x-%x", info.ModTime().UnixNano(), info.Size())
+}
+
+func hrefJoin(elem ...string) string {
+	href := make([]string, len(elem))
+	for i := range elem {
+		href[i] = url.PathEscape(elem[i])
+	}
+	return path.Join(href...)
+}
+
+func (d *Directory) urn(name ...string) string {
+	space := uuid.NewSHA1(uuid.NameSpaceURL, []byte("file://"+filepath.ToSlash(d.Path)))
+	if len(name) == 0 {
+		return "urn:uuid:" + space.String()
+	}
+	return "urn:uuid:" + uuid.NewSHA1(space, []byte(name[0])).String()
+}
+
+func (d *Directory) newItem(name string, info os.FileInfo) *dirItem {
+	return &dirItem{
+		Item: Item{
+			Created:  info.ModTime().UTC(),
+			Version:  version(info.ModTime()),
+			ID:       d.urn(name),
+			Name:     name,
+			SelfHref: hrefJoin(name, ItemFile),
+			Type:     TypeOther,
+		},
+		path:     filepath.Join(d.Path, name),
+		modified: info.ModTime(),
+		files:    make(map[string]string),
+	}
+}
+
+// add a file to the item, where elem is the file path relative to the publication root.
+func (item *dirItem) add(file string, info os.FileInfo, elem ...string) {
+	item.Files = append(item.Files, File{
+		Name:  info.Name(),
+		Size:  info.Size(),
+		Hrefs: []string{hrefJoin(elem...)},
+		Etag:  etag(info),
+	})
+	item.files[path.Join(elem...)] = file
+
+	if info.ModTime().After(item.modified) {
+		item.modified = info.ModTime()
+	}
+	item.Version = version(item.modified)
+	item.ContentVersion = item.Version
+}
+
+func (d *Directory) scanItem(name string, info os.FileInfo) (*dirItem, error) {
+	item := d.newItem(name, info)
+
+	if !info.IsDir() {
+		if strings.EqualFold(filepath.Ext(name), ".iso") {
+			item.Type = TypeISO
+		}
+		item.add(item.path, info, name)
+		return item, nil
+	}
+
+	entries, err := os.ReadDir(item.path)
+	if err != nil {
+		return nil, err
+	}
+
+	isos := 0
+
+	for _, entry := range entries {
+		if isHidden(entry.Name()) || !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".ovf":
+			item.Type = TypeOVF
+		case ".iso":
+			isos++
+		}
+
+		item.add(filepath.Join(item.path, entry.Name()), info, name, entry.Name())
+	}
+
+	if item.Type == TypeOther && isos == 1 && len(item.Files) == 1 {
+		item.Type = TypeISO
+	}
+
+	return item, nil
+}
+
+func (d *Directory) scan() (*catalog, error) {
+	info, err := os.Stat(d.Path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s: not a directory", d.Path)
+	}
+
+	name := d.Name
+	if name == "" {
+		name = filepath.Base(d.Path)
+	}
+
+	c := &catalog{
+		lib: Library{
+			VcspVersion: Version,
+			Name:        name,
+			ID:          d.urn(),
+			Created:     info.ModTime().UTC(),
+			Capabilities: Capabilities{
+				TransferIn:  []string{"httpGet"},
+				TransferOut: []string{"httpGet"},
+			},
+			ItemsHref: ItemsFile,
+		},
+	}
+
+	entries, err := os.ReadDir(d.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	modified := info.ModTime()
+
+	for _, entry := range entries {
+		if isHidden(entry.Name()) || !(entry.IsDir() || entry.Type().IsRegular()) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		item, err := d.scanItem(entry.Name(), info)
+		if err != nil {
+			return nil, err
+		}
+		if len(item.Files) == 0 {
+			continue
+		}
+
+		if item.modified.After(modified) {
+			modified = item.modified
+		}
+
+		c.items = append(c.items, item)
+	}
+
+	c.lib.Version = version(modified)
+	c.lib.ContentVersion = c.lib.Version
+
+	return c, nil
+}
+
+// Library returns the lib.json content of the directory.
+func (d *Directory) Library() (*Library, error) {
+	c, err := d.scan()
+	if err != nil {
+		return nil, err
+	}
+	return &c.lib, nil
+}
+
+// Items returns the items.json content of the directory.
+func (d *Directory) Items() ([]Item, error) {
+	c, err := d.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, len(c.items))
+	for i := range c.items {
+		items[i] = c.items[i].Item
+	}
+	return items, nil
+}
+
+func (d *Directory) authorized(r *http.Request) bool {
+	if d.Password == "" {
+		return true
+	}
+
+	username := d.Username
+	if username == "" {
+		username = DefaultUsername
+	}
+
+	u, p, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(u), []byte(username)) == 1 &&
+		subtle.ConstantTimeCompare([]byte(p), []byte(d.Password)) == 1
+}
+
+func encode(w http.ResponseWriter, val any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(val)
+}
+
+// ServeHTTP implements http.Handler, serving the publication relative to the request path's root.
+// Only the metadata documents and files of published items are served.
+func (d *Directory) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !d.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="vcsp"`)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	c, err := d.scan()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	href := strings.TrimPrefix(path.Clean(r.URL.Path), "/")
+
+	switch href {
+	case LibraryFile:
+		encode(w, c.lib)
+		return
+	case ItemsFile:
+		items := Items{Items: make([]Item, len(c.items))}
+		for i := range c.items {
+			items.Items[i] = c.items[i].Item
+		}
+		encode(w, items)
+		return
+	}
+
+	for _, item := range c.items {
+		if href == path.Join(item.Name, ItemFile) {
+			encode(w, item.Item)
+			return
+		}
+
+		if file, ok := item.files[href]; ok {
+			http.ServeFile(w, r, file)
+			return
+		}
+	}
+
+	http.NotFound(w, r)
+}
@@ -0,0 +1,221 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package vcsp implements the vCenter Content Subscription Protocol (VCSP)
+// used by subscribed content libraries to synchronize with a published library.
+// A publication consists of a lib.json document, which references an
+// items.json document listing the library items, each with an item.json
+// document and set of files, all of which are fetched over HTTP.
+package vcsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/library"
+)
+
+const (
+	// LibraryFile is the name of the library metadata document.
+	LibraryFile = "lib.json"
+	// ItemsFile is the name of the document listing all library items.
+	ItemsFile = "items.json"
+	// ItemFile is the name of an individual library item's metadata document.
+	ItemFile = "item.json"
+
+	// Version of the protocol implemented by this package.
+	Version = "2"
+)
+
+// Item types as defined by the protocol.
+const (
+	TypeOVF   = "vcsp.ovf"
+	TypeISO   = "vcsp.iso"
+	TypeOther = "vcsp.other"
+)
+
+// Capabilities of the publisher.
+type Capabilities struct {
+	TransferIn  []string `json:"transferIn"`
+	TransferOut []string `json:"transferOut"`
+}
+
+// Library is the content of lib.json
+type Library struct {
+	VcspVersion    string       `json:"vcspVersion"`
+	Version        string       `json:"version"`
+	ContentVersion string       `json:"contentVersion,omitempty"`
+	Name           string       `json:"name"`
+	ID             string       `json:"id"`
+	Created        time.Time    `json:"created"`
+	Capabilities   Capabilities `json:"capabilities"`
+	ItemsHref      string       `json:"itemsHref"`
+}
+
+// File is a library item file.
+// Hrefs are relative to the location of lib.json.
+type File struct {
+	Name  string   `json:"name"`
+	Size  int64    `json:"size"`
+	Hrefs []string `json:"hrefs"`
+	Etag  string   `json:"etag,omitempty"`
+}
+
+// Item is the content of item.json and an element of items.json
+type Item struct {
+	Created        time.Time         `json:"created"`
+	Description    string            `json:"description,omitempty"`
+	Version        string            `json:"version"`
+	ContentVersion string            `json:"contentVersion,omitempty"`
+	Files          []File            `json:"files"`
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	Properties     map[string]string `json:"properties,omitempty"`
+	SelfHref       string            `json:"selfHref"`
+	Type           string            `json:"type"`
+}
+
+// Items is the content of items.json
+type Items struct {
+	Items []Item `json:"items"`
+}
+
+// LibraryItemType maps the given VCSP item type to a content library item type.
+func LibraryItemType(kind string) string {
+	switch kind {
+	case TypeOVF:
+		return library.ItemTypeOVF
+	case TypeISO:
+		return library.ItemTypeISO
+	default:
+		return ""
+	}
+}
+
+// Client is used to subscribe to a published library.
+type Client struct {
+	// URL of the publication's lib.json
+	URL *url.URL
+
+	Username string
+	Password string
+
+	// HTTP client used for requests, defaults to http.DefaultClient
+	HTTP *http.Client
+}
+
+// NewClient returns a Client for the given publication URL.
+// If the URL path does not end with lib.json, it is appended.
+func NewClient(u string) (*Client, error) {
+	pub, err := url.Parse(u)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(pub.Path, "/"+LibraryFile) {
+		pub.Path = strings.TrimSuffix(pub.Path, "/") + "/" + LibraryFile
+	}
+
+	c := &Client{URL: pub}
+
+	if pub.User != nil {
+		c.Username = pub.User.Username()
+		c.Password, _ = pub.User.Password()
+		pub.User = nil
+	}
+
+	return c, nil
+}
+
+// Resolve returns the absolute URL of an href relative to the publication.
+func (c *Client) Resolve(href string) (*url.URL, error) {
+	ref, err := url.Parse(href)
+	if err != nil {
+		return nil, err
+	}
+	return c.URL.ResolveReference(ref), nil
+}
+
+// Open returns the response body of a GET request for the given href.
+// The caller must close the returned reader.
+func (c *Client) Open(ctx context.Context, href string) (io.ReadCloser, int64, error) {
+	u, err := c.Resolve(href)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	hc := c.HTTP
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	res, err := hc.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		_ = res.Body.Close()
+		return nil, 0, fmt.Errorf("GET %s: %s", u, res.Status)
+	}
+
+	return res.Body, res.ContentLength, nil
+}
+
+func (c *Client) get(ctx context.Context, href string, val any) error {
+	body, _, err := c.Open(ctx, href)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return json.NewDecoder(body).Decode(val)
+}
+
+// Library returns the publication's lib.json content.
+func (c *Client) Library(ctx context.Context) (*Library, error) {
+	var lib Library
+	if err := c.get(ctx, LibraryFile, &lib); err != nil {
+		return nil, err
+	}
+	return &lib, nil
+}
+
+// Items returns the publication's items.json content.
+func (c *Client) Items(ctx context.Context, lib *Library) ([]Item, error) {
+	href := ItemsFile
+	if lib != nil && lib.ItemsHref != "" {
+		href = lib.ItemsHref
+	}
+
+	var items Items
+	if err := c.get(ctx, href, &items); err != nil {
+		return nil, err
+	}
+	return items.Items, nil
+}
+
+// Item returns the item.json content of the given item.
+func (c *Client) Item(ctx context.Context, item Item) (*Item, error) {
+	var res Item
+	if err := c.get(ctx, item.SelfHref, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
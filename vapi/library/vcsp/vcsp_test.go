@@ -0,0 +1,243 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package vcsp_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/library/vcsp"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestDirectory(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	write := func(name, content string) {
+		name = filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), 0750))
+		require.NoError(t, os.WriteFile(name, []byte(content), 0600))
+	}
+
+	write("ttylinux/ttylinux.ovf", "<Envelope/>")
+	write("ttylinux/ttylinux-disk1.vmdk", "disk")
+	write("tools/my tools.iso", "iso")
+	write("misc/a.txt", "a")
+	write("misc/b.txt", "b")
+	write("README.md", "readme")
+	write(".hidden", "secret")
+
+	pub := &vcsp.Directory{Path: dir, Name: "local", Password: "pass"}
+	s := httptest.NewServer(pub)
+	defer s.Close()
+
+	c, err := vcsp.NewClient(s.URL)
+	require.NoError(t, err)
+
+	_, err = c.Library(ctx)
+	assert.ErrorContains(t, err, "401")
+
+	c.Username = vcsp.DefaultUsername
+	c.Password = "pass"
+
+	lib, err := c.Library(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "local", lib.Name)
+	assert.Equal(t, vcsp.Version, lib.VcspVersion)
+	assert.Equal(t, vcsp.ItemsFile, lib.ItemsHref)
+
+	items, err := c.Items(ctx, lib)
+	require.NoError(t, err)
+	require.Len(t, items, 4)
+
+	kinds := map[string]string{}
+	for _, item := range items {
+		kinds[item.Name] = item.Type
+
+		res, err := c.Item(ctx, item)
+		require.NoError(t, err)
+		assert.Equal(t, item.ID, res.ID)
+		assert.Equal(t, item.Version, res.Version)
+
+		for _, file := range item.Files {
+			r, n, err := c.Open(ctx, file.Hrefs[0])
+			require.NoError(t, err)
+			b, err := io.ReadAll(r)
+			require.NoError(t, err)
+			_ = r.Close()
+			assert.Equal(t, file.Size, n)
+			assert.Equal(t, file.Size, int64(len(b)))
+		}
+	}
+
+	assert.Equal(t, map[string]string{
+		"ttylinux":  vcsp.TypeOVF,
+		"tools":     vcsp.TypeISO,
+		"misc":      vcsp.TypeOther,
+		"README.md": vcsp.TypeOther,
+	}, kinds)
+
+	_, _, err = c.Open(ctx, ".hidden")
+	assert.ErrorContains(t, err, "404")
+
+	// IDs are stable across scans
+	again, err := c.Items(ctx, lib)
+	require.NoError(t, err)
+	assert.Equal(t, items, again)
+}
+
+func TestSubscribe(t *testing.T) {
+	dir := t.TempDir()
+
+	copyFile := func(src, dst string) {
+		b, err := os.ReadFile(filepath.Join("..", "testdata", src))
+		require.NoError(t, err)
+		dst = filepath.Join(dir, dst)
+		require.NoError(t, os.MkdirAll(filepath.Dir(dst), 0750))
+		require.NoError(t, os.WriteFile(dst, b, 0600))
+	}
+
+	name := "ttylinux-pc_i486-16.1"
+	for _, ext := range []string{".ovf", ".mf", "-disk1.vmdk"} {
+		copyFile(name+ext, filepath.Join("ttylinux", name+ext))
+	}
+	copyFile(name+".iso", filepath.Join("iso", name+".iso"))
+
+	pub := &vcsp.Directory{Path: dir, Password: "pass"}
+	s := httptest.NewServer(pub)
+	defer s.Close()
+
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		require.NoError(t, c.Login(ctx, simulator.DefaultLogin))
+
+		ds, err := find.NewFinder(vc).DefaultDatastore(ctx)
+		require.NoError(t, err)
+
+		m := library.NewManager(c)
+
+		id, err := m.CreateLibrary(ctx, library.Library{
+			Name: "sub",
+			Type: "SUBSCRIBED",
+			Storage: []library.StorageBacking{{
+				DatastoreID: ds.Reference().Value,
+				Type:        "DATASTORE",
+			}},
+			Subscription: &library.Subscription{
+				AuthenticationMethod: "BASIC",
+				Password:             "pass",
+				SubscriptionURL:      s.URL + "/" + vcsp.LibraryFile,
+				OnDemand:             types.New(false),
+			},
+		})
+		require.NoError(t, err)
+
+		items, err := m.GetLibraryItems(ctx, id)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+
+		kinds := map[string]string{}
+		for _, item := range items {
+			kinds[item.Name] = item.Type
+			assert.NotEmpty(t, item.SourceID)
+
+			files, err := m.ListLibraryItemFiles(ctx, item.ID)
+			require.NoError(t, err)
+			for _, file := range files {
+				assert.True(t, *file.Cached, file.Name)
+			}
+			if item.Type == library.ItemTypeOVF {
+				assert.Len(t, files, 3)
+			}
+		}
+
+		assert.Equal(t, map[string]string{
+			"ttylinux": library.ItemTypeOVF,
+			"iso":      library.ItemTypeISO,
+		}, kinds)
+
+		// Removing an item from the publication removes it from the subscribed library on sync
+		require.NoError(t, os.RemoveAll(filepath.Join(dir, "iso")))
+
+		lib, err := m.GetLibraryByID(ctx, id)
+		require.NoError(t, err)
+		require.NoError(t, m.SyncLibrary(ctx, lib))
+
+		items, err = m.GetLibraryItems(ctx, id)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, "ttylinux", items[0].Name)
+	})
+}
+
+func TestSubscribeSlowPublication(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "slow.iso"), []byte("iso"), 0600))
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	pub := &vcsp.Directory{Path: dir}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".iso") {
+			close(started)
+			<-release
+		}
+		pub.ServeHTTP(w, r)
+	}))
+	defer s.Close()
+
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		require.NoError(t, c.Login(ctx, simulator.DefaultLogin))
+
+		ds, err := find.NewFinder(vc).DefaultDatastore(ctx)
+		require.NoError(t, err)
+
+		m := library.NewManager(c)
+
+		created := make(chan error, 1)
+		go func() {
+			_, err := m.CreateLibrary(ctx, library.Library{
+				Name: "sub",
+				Type: "SUBSCRIBED",
+				Storage: []library.StorageBacking{{
+					DatastoreID: ds.Reference().Value,
+					Type:        "DATASTORE",
+				}},
+				Subscription: &library.Subscription{
+					SubscriptionURL: s.URL + "/" + vcsp.LibraryFile,
+					OnDemand:        types.New(false),
+				},
+			})
+			created <- err
+		}()
+
+		<-started
+
+		// Other requests are served while the file is fetched from the publication
+		_, err = m.ListLibraries(ctx)
+		assert.NoError(t, err)
+
+		close(release)
+		require.NoError(t, <-created)
+	})
+}
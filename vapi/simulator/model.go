@@ -0,0 +1,144 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/simulator/model"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// readModel decodes the given file of the model's vapi directory into val.
+// A missing file is not an error, val is left unchanged in that case.
+func readModel(dir, name string, val any) error {
+	f, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	if err = json.NewDecoder(f).Decode(val); err != nil {
+		return fmt.Errorf("%s: %s", f.Name(), err)
+	}
+
+	return nil
+}
+
+// load restores categories, tags, associations and content library metadata
+// saved to the model directory by govc object.save.
+// Associations with objects that are not in the inventory are ignored,
+// as are libraries backed by a datastore that is not in the inventory.
+func (s *handler) load(dir string) error {
+	dir = filepath.Join(dir, model.Subdir)
+
+	var (
+		categories   []tags.Category
+		tagged       []tags.Tag
+		associations []tags.AttachedObjects
+		libraries    []library.Library
+		items        []library.Item
+	)
+
+	files := []struct {
+		name string
+		val  any
+	}{
+		{model.CategoriesFile, &categories},
+		{model.TagsFile, &tagged},
+		{model.AssociationsFile, &associations},
+		{model.LibrariesFile, &libraries},
+		{model.LibraryItemsFile, &items},
+	}
+
+	for _, file := range files {
+		if err := readModel(dir, file.name, file.val); err != nil {
+			return err
+		}
+	}
+
+	for i := range categories {
+		category := &categories[i]
+		s.Category[category.ID] = category
+	}
+
+	for i := range tagged {
+		tag := &tagged[i]
+		if _, ok := s.Category[tag.CategoryID]; !ok {
+			continue
+		}
+		s.Tag[tag.ID] = tag
+		s.Association[tag.ID] = make(map[internal.AssociatedObject]bool)
+	}
+
+	for _, a := range associations {
+		objs, ok := s.Association[a.TagID]
+		if !ok {
+			continue
+		}
+		for _, obj := range a.ObjectIDs {
+			ref := obj.Reference()
+			if s.Map.Get(ref) == nil {
+				continue
+			}
+			objs[internal.AssociatedObject{Type: ref.Type, Value: ref.Value}] = true
+		}
+	}
+
+	for i := range libraries {
+		l := &libraries[i]
+		if len(l.Storage) == 0 {
+			continue
+		}
+		ds := types.ManagedObjectReference{Type: "Datastore", Value: l.Storage[0].DatastoreID}
+		if _, ok := s.Map.Get(ds).(*simulator.Datastore); !ok {
+			continue
+		}
+		if err := os.MkdirAll(s.libraryPath(l, ""), 0750); err != nil {
+			return err
+		}
+
+		pub := l.Publication
+		if pub != nil && pub.Published != nil && *pub.Published {
+			// Rewrite PublishURL to this simulator instance
+			pub.PublishURL = (&url.URL{
+				Scheme: s.URL.Scheme,
+				Host:   s.URL.Host,
+				Path:   "/cls/vcsp/lib/" + l.ID,
+			}).String()
+		}
+
+		s.Library[l.ID] = &content{
+			Library: l,
+			Item:    make(map[string]*item),
+			Subs:    make(map[string]*library.Subscriber),
+			VMTX:    make(map[string]*types.ManagedObjectReference),
+		}
+	}
+
+	for i := range items {
+		i := &items[i]
+		l, ok := s.Library[i.LibraryID]
+		if !ok {
+			continue
+		}
+		l.Item[i.ID] = &item{Item: i}
+	}
+
+	return nil
+}
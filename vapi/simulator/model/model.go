@@ -0,0 +1,21 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package model defines the layout of the vapi simulator state within a vcsim model directory.
+The files are written by govc object.save and loaded by the vapi simulator.
+This package has no dependencies, such that clients can use it without linking the simulator.
+*/
+package model
+
+// The files are located in the Subdir subdirectory of the model directory and contain
+// the JSON encoded results of the corresponding "get" methods.
+const (
+	Subdir           = "vapi"
+	CategoriesFile   = "categories.json"
+	TagsFile         = "tags.json"
+	AssociationsFile = "associations.json"
+	LibrariesFile    = "libraries.json"
+	LibraryItemsFile = "library-items.json"
+)
@@ -0,0 +1,251 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/vcenter/compute/policies"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// policyCapabilities are the compute policy capabilities supported by the simulator.
+var policyCapabilities = []policies.CapabilitySummary{
+	{
+		Capability:  policies.CapabilityVMHostAffinity,
+		Name:        "VM-Host affinity",
+		Description: "Virtual machines with the VM tag are placed on hosts with the host tag.",
+	},
+	{
+		Capability:  policies.CapabilityVMHostAntiAffinity,
+		Name:        "VM-Host anti affinity",
+		Description: "Virtual machines with the VM tag are not placed on hosts with the host tag.",
+	},
+	{
+		Capability:  policies.CapabilityVMVMAffinity,
+		Name:        "VM-VM affinity",
+		Description: "Virtual machines with the VM tag are placed on the same host.",
+	},
+	{
+		Capability:  policies.CapabilityVMVMAntiAffinity,
+		Name:        "VM-VM anti affinity",
+		Description: "Virtual machines with the VM tag are placed on different hosts.",
+	},
+	{
+		Capability:  policies.CapabilityDisableDRSVMotion,
+		Name:        "Disable DRS vMotion",
+		Description: "Virtual machines with the VM tag are not migrated by DRS.",
+	},
+	{
+		Capability:  policies.CapabilityVMEvacuation,
+		Name:        "Evacuate VMs on maintenance",
+		Description: "Virtual machines with the VM tag are not placed on hosts in maintenance mode.",
+	},
+}
+
+// hasHostTag returns true if the capability requires a host tag.
+func hasHostTag(capability string) bool {
+	return capability == policies.CapabilityVMHostAffinity || capability == policies.CapabilityVMHostAntiAffinity
+}
+
+// isTagged returns true if the given tag is attached to the given object.
+func (s *handler) isTagged(id string, ref types.ManagedObjectReference) bool {
+	return s.Association[id][internal.AssociatedObject{Type: ref.Type, Value: ref.Value}]
+}
+
+// vmHost returns the host of the given VM, nil if the VM does not exist or is not placed on a host.
+func (s *handler) vmHost(ref types.ManagedObjectReference) *types.ManagedObjectReference {
+	vm, ok := s.Map.Get(ref).(*simulator.VirtualMachine)
+	if !ok {
+		return nil
+	}
+	return vm.Runtime.Host
+}
+
+// taggedVMHosts returns the hosts of the VMs other than vm with the given tag attached.
+func (s *handler) taggedVMHosts(id string, vm types.ManagedObjectReference) []types.ManagedObjectReference {
+	var hosts []types.ManagedObjectReference
+	for obj := range s.Association[id] {
+		ref := obj.Reference()
+		if ref.Type != "VirtualMachine" || ref == vm {
+			continue
+		}
+		if host := s.vmHost(ref); host != nil && !slices.Contains(hosts, *host) {
+			hosts = append(hosts, *host)
+		}
+	}
+	return hosts
+}
+
+// allowsHost returns true if the given policy allows the VM to be placed on the given host.
+// The policy must apply to the VM, see isTagged.
+func (s *handler) allowsHost(p *policies.Info, vm, host types.ManagedObjectReference) bool {
+	switch p.Capability {
+	case policies.CapabilityVMHostAffinity:
+		return s.isTagged(p.HostTag, host)
+	case policies.CapabilityVMHostAntiAffinity:
+		return !s.isTagged(p.HostTag, host)
+	case policies.CapabilityVMVMAffinity:
+		hosts := s.taggedVMHosts(p.VMTag, vm)
+		return len(hosts) == 0 || slices.Contains(hosts, host)
+	case policies.CapabilityVMVMAntiAffinity:
+		return !slices.Contains(s.taggedVMHosts(p.VMTag, vm), host)
+	case policies.CapabilityVMEvacuation:
+		h, ok := s.Map.Get(host).(*simulator.HostSystem)
+		return ok && !h.Runtime.InMaintenanceMode
+	case policies.CapabilityDisableDRSVMotion:
+		current := s.vmHost(vm)
+		return current == nil || *current == host
+	}
+	return true
+}
+
+// PlacementHosts is meant for internal use via simulator.Registry.policyManager
+func (s *handler) PlacementHosts(vm types.ManagedObjectReference, hosts []types.ManagedObjectReference) []types.ManagedObjectReference {
+	var res []types.ManagedObjectReference
+
+	for _, host := range hosts {
+		allowed := true
+		for _, p := range s.ComputePolicy {
+			if s.isTagged(p.VMTag, vm) && !s.allowsHost(p, vm, host) {
+				allowed = false
+				break
+			}
+		}
+		if allowed {
+			res = append(res, host)
+		}
+	}
+
+	return res
+}
+
+func (s *handler) computePolicies(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		res := []policies.Summary{}
+		for id, p := range s.ComputePolicy {
+			res = append(res, policies.Summary{
+				Policy:      id,
+				Capability:  p.Capability,
+				Name:        p.Name,
+				Description: p.Description,
+			})
+		}
+		slices.SortFunc(res, func(a, b policies.Summary) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		StatusOK(w, res)
+	case http.MethodPost:
+		var spec policies.CreateSpec
+		if !s.decode(r, w, &spec) {
+			return
+		}
+
+		supported := slices.ContainsFunc(policyCapabilities, func(c policies.CapabilitySummary) bool {
+			return c.Capability == spec.Capability
+		})
+		_, vmTag := s.Tag[spec.VMTag]
+		_, hostTag := s.Tag[spec.HostTag]
+		if !hasHostTag(spec.Capability) {
+			hostTag = spec.HostTag == ""
+		}
+
+		if spec.Name == "" || !supported || !vmTag || !hostTag {
+			ApiErrorInvalidArgument(w)
+			return
+		}
+
+		for _, p := range s.ComputePolicy {
+			if p.Name == spec.Name {
+				ApiErrorAlreadyExists(w)
+				return
+			}
+		}
+
+		id := uuid.New().String()
+		s.ComputePolicy[id] = &policies.Info{
+			Capability:  spec.Capability,
+			Name:        spec.Name,
+			Description: spec.Description,
+			VMTag:       spec.VMTag,
+			HostTag:     spec.HostTag,
+		}
+
+		StatusOK(w, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *handler) computePolicyCapabilities(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	StatusOK(w, policyCapabilities)
+}
+
+func (s *handler) computePolicyID(w http.ResponseWriter, r *http.Request) {
+	id := path.Base(r.URL.Path)
+	p, ok := s.ComputePolicy[id]
+	if !ok {
+		ApiErrorNotFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		StatusOK(w, p)
+	case http.MethodDelete:
+		delete(s.ComputePolicy, id)
+		StatusOK(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *handler) vmComputePolicy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	vm := types.ManagedObjectReference{Type: "VirtualMachine", Value: r.PathValue("vm")}
+	if s.Map.Get(vm) == nil {
+		ApiErrorNotFound(w)
+		return
+	}
+
+	p, ok := s.ComputePolicy[r.PathValue("policy")]
+	if !ok {
+		ApiErrorNotFound(w)
+		return
+	}
+
+	res := policies.VMComplianceInfo{Status: policies.ComplianceStatusNotApplicable}
+
+	if s.isTagged(p.VMTag, vm) {
+		host := s.vmHost(vm)
+		switch {
+		case host == nil:
+			res.Status = policies.ComplianceStatusUnknown
+		case s.allowsHost(p, vm, *host):
+			res.Status = policies.ComplianceStatusCompliant
+		default:
+			res.Status = policies.ComplianceStatusNotCompliant
+		}
+	}
+
+	StatusOK(w, res)
+}
@@ -24,6 +24,7 @@ import (
 	"io"
 	"log"
 	"math/big"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -46,12 +47,15 @@ import (
 	"github.com/vmware/govmomi/vapi"
 	"github.com/vmware/govmomi/vapi/internal"
 	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/library/vcsp"
 	"github.com/vmware/govmomi/vapi/rest"
 	"github.com/vmware/govmomi/vapi/tags"
 	"github.com/vmware/govmomi/vapi/vcenter"
+	"github.com/vmware/govmomi/vapi/vcenter/compute/policies"
 	"github.com/vmware/govmomi/view"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/soap"
 	"github.com/vmware/govmomi/vim25/types"
 	vim "github.com/vmware/govmomi/vim25/types"
@@ -63,6 +67,8 @@ type item struct {
 	*library.Item
 	File     []library.File
 	Template *types.ManagedObjectReference
+
+	href map[string]string // VCSP file name -> href, for items of a remote publication
 }
 
 type content struct {
@@ -70,6 +76,8 @@ type content struct {
 	Item map[string]*item
 	Subs map[string]*library.Subscriber
 	VMTX map[string]*types.ManagedObjectReference
+
+	pub *vcsp.Client // set for a remote publication
 }
 
 type update struct {
@@ -77,6 +85,7 @@ type update struct {
 	*library.Session
 	Library *library.Library
 	File    map[string]*library.UpdateFile
+	Remove  map[string]bool // file names to remove on complete
 }
 
 type download struct {
@@ -99,6 +108,8 @@ type handler struct {
 	Download    map[string]download
 	Policies    []library.ContentSecurityPoliciesInfo
 	Trust       map[string]library.TrustedCertificate
+
+	ComputePolicy map[string]*policies.Info
 }
 
 func init() {
@@ -127,6 +138,8 @@ func New(u *url.URL, r *simulator.Registry) ([]string, http.Handler) {
 		Download:    make(map[string]download),
 		Policies:    defaultSecurityPolicies(),
 		Trust:       make(map[string]library.TrustedCertificate),
+
+		ComputePolicy: make(map[string]*policies.Info),
 	}
 
 	handlers := []struct {
@@ -176,6 +189,10 @@ func New(u *url.URL, r *simulator.Registry) ([]string, http.Handler) {
 		{internal.SecurityPoliciesPath, s.librarySecurityPolicies},
 		{internal.TrustedCertificatesPath, s.libraryTrustedCertificates},
 		{internal.TrustedCertificatesPath + "/", s.libraryTrustedCertificatesID},
+		{policies.Path, s.computePolicies},
+		{policies.Path + "/", s.computePolicyID},
+		{policies.CapabilitiesPath, s.computePolicyCapabilities},
+		{policies.VMPath + "/{vm}/compute/policies/{policy}", s.vmComputePolicy},
 	}
 
 	for i := range handlers {
@@ -183,6 +200,12 @@ func New(u *url.URL, r *simulator.Registry) ([]string, http.Handler) {
 		s.HandleFunc(h.p, h.m)
 	}
 
+	if dir := s.Map.ModelDir(); dir != "" {
+		if err := s.load(dir); err != nil {
+			log.Printf("vapi simulator: loading model from %s: %s", dir, err)
+		}
+	}
+
 	return []string{
 		rest.Path, rest.Path + "/",
 		vapi.Path, vapi.Path + "/",
@@ -441,6 +464,12 @@ func ApiErrorAlreadyExists(w http.ResponseWriter) {
 	apiError(w, http.StatusBadRequest, "ALREADY_EXISTS")
 }
 
+// ApiErrorAlreadyInDesiredState responds with a REST error of type "ALREADY_IN_DESIRED_STATE".
+// For use with "/api" endpoints.
+func ApiErrorAlreadyInDesiredState(w http.ResponseWriter) {
+	apiError(w, http.StatusBadRequest, "ALREADY_IN_DESIRED_STATE")
+}
+
 // ApiErrorGeneral responds with a REST error of type "ERROR".
 // For use with "/api" endpoints.
 func ApiErrorGeneral(w http.ResponseWriter) {
@@ -471,6 +500,12 @@ func ApiErrorResourceInUse(w http.ResponseWriter) {
 	apiError(w, http.StatusBadRequest, "RESOURCE_IN_USE")
 }
 
+// ApiErrorServiceUnavailable responds with a REST error of type "SERVICE_UNAVAILABLE".
+// For use with "/api" endpoints.
+func ApiErrorServiceUnavailable(w http.ResponseWriter) {
+	apiError(w, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE")
+}
+
 // ApiErrorUnauthorized responds with a REST error of type "UNAUTHORIZED".
 // For use with "/api" endpoints.
 func ApiErrorUnauthorized(w http.ResponseWriter) {
@@ -524,6 +559,22 @@ func Decode(r *http.Request, w http.ResponseWriter, val any) bool {
 	return true
 }
 
+// Ancestors returns the IDs of the parent folders and datacenter of the given entity.
+func Ancestors(r *simulator.Registry, e mo.Entity) []string {
+	var ids []string
+
+	for p := e.Entity().Parent; p != nil; {
+		ids = append(ids, p.Value)
+		parent, ok := r.Get(*p).(mo.Entity)
+		if !ok {
+			break
+		}
+		p = parent.Entity().Parent
+	}
+
+	return ids
+}
+
 func (s *handler) expiredSession(id string, now time.Time, timeout time.Duration) bool {
 	expired := true
 	s.Lock()
@@ -945,7 +996,7 @@ func (s *handler) association(w http.ResponseWriter, r *http.Request) {
 					Message: fmt.Sprintf("Tagging object %s not found", id),
 				})
 			} else {
-				s.Association[id][*spec.ObjectID] = false
+				delete(s.Association[id], *spec.ObjectID)
 			}
 		}
 
@@ -978,7 +1029,7 @@ func (s *handler) associationID(w http.ResponseWriter, r *http.Request) {
 		if !s.decode(r, w, &spec) {
 			return
 		}
-	case "attach-tag-to-multiple-objects":
+	case "attach-tag-to-multiple-objects", "detach-tag-from-multiple-objects":
 		if !s.decode(r, w, &specs) {
 			return
 		}
@@ -1002,6 +1053,13 @@ func (s *handler) associationID(w http.ResponseWriter, r *http.Request) {
 			s.Association[id][obj] = true
 		}
 		OK(w)
+	case "detach-tag-from-multiple-objects":
+		for _, obj := range specs.ObjectIDs {
+			delete(s.Association[id], obj)
+		}
+		OK(w, struct {
+			Success bool `json:"success"`
+		}{true})
 	}
 }
 
@@ -1067,7 +1125,7 @@ func (s *handler) library(w http.ResponseWriter, r *http.Request) {
 				}).String()
 			}
 
-			s.syncSubLib(s.Library[id])
+			s.syncSubLib(r.Context(), s.Library[id])
 
 			spec.Library.StateInfo = &library.StateInfo{State: "ACTIVE"}
 
@@ -1082,7 +1140,7 @@ func (s *handler) library(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (s *handler) syncSubLib(dstLib *content) error {
+func (s *handler) syncSubLib(ctx context.Context, dstLib *content) error {
 
 	sub := dstLib.Subscription
 	if sub == nil {
@@ -1097,9 +1155,9 @@ func (s *handler) syncSubLib(dstLib *content) error {
 		syncAll = true
 	}
 
-	srcLib, ok := s.Library[path.Base(sub.SubscriptionURL)]
-	if !ok {
-		return nil
+	srcLib, err := s.subscriptionSource(ctx, sub)
+	if err != nil || srcLib == nil {
+		return err
 	}
 
 	if dstLib.Item == nil {
@@ -1113,13 +1171,19 @@ func (s *handler) syncSubLib(dstLib *content) error {
 	handledSrcItems := map[string]struct{}{}
 
 	// Update any items that already exist in the subscribed library.
+	// The items are copied as syncItem may release the handler lock.
+	dstItems := make([]*item, 0, len(dstLib.Item))
 	for _, dstItem := range dstLib.Item {
+		dstItems = append(dstItems, dstItem)
+	}
+	for _, dstItem := range dstItems {
 
 		// Indicate this source item has been seen.
 		handledSrcItems[dstItem.SourceID] = struct{}{}
 
 		// Synchronize the item.
 		if err := s.syncItem(
+			ctx,
 			dstItem,
 			dstLib,
 			srcLib,
@@ -1131,7 +1195,11 @@ func (s *handler) syncSubLib(dstLib *content) error {
 	}
 
 	// Add any new items from the published library.
+	srcItems := make([]*item, 0, len(srcLib.Item))
 	for _, srcItem := range srcLib.Item {
+		srcItems = append(srcItems, srcItem)
+	}
+	for _, srcItem := range srcItems {
 
 		// Skip any source items that were handled above.
 		if _, ok := handledSrcItems[srcItem.ID]; ok {
@@ -1163,6 +1231,7 @@ func (s *handler) syncSubLib(dstLib *content) error {
 
 		// Synchronize the item.
 		if err := s.syncItem(
+			ctx,
 			dstItem,
 			dstLib,
 			srcLib,
@@ -1192,6 +1261,7 @@ func (s *handler) evictItem(item *item) {
 var ovfOrManifestRx = regexp.MustCompile(`(?i)^.+\.(ovf|mf)$`)
 
 func (s *handler) syncItem(
+	ctx context.Context,
 	dstItem *item,
 	dstLib,
 	srcLib *content,
@@ -1214,10 +1284,12 @@ func (s *handler) syncItem(
 		if sub == nil {
 			return nil
 		}
-		var ok bool
-		srcLibID := path.Base(sub.SubscriptionURL)
-		if srcLib, ok = s.Library[srcLibID]; !ok {
-			return fmt.Errorf("cannot find pub library id %q", srcLibID)
+		var err error
+		if srcLib, err = s.subscriptionSource(ctx, sub); err != nil {
+			return err
+		}
+		if srcLib == nil {
+			return fmt.Errorf("cannot find pub library %q", sub.SubscriptionURL)
 		}
 	}
 
@@ -1286,10 +1358,14 @@ func (s *handler) syncItem(
 	}
 
 	// Update the the destination item's files.
-	srcItemPath := s.libraryPath(srcLib.Library, srcItem.ID)
-	for i := range dstItem.File {
+	var srcItemPath string
+	if srcLib.pub == nil {
+		srcItemPath = s.libraryPath(srcLib.Library, srcItem.ID)
+	}
+	files := dstItem.File
+	for i := range files {
 		var (
-			dstFile = &dstItem.File[i]
+			dstFile = &files[i]
 			srcFile = srcItem.File[i]
 		)
 
@@ -1305,7 +1381,7 @@ func (s *handler) syncItem(
 		// .ovf and .mf files are always cached.
 		if ovfOrManifestRx.MatchString(dstFile.Name) {
 			dstFile.Cached = &fileIsCached
-			if err := copyFile(dstFilePath, srcFilePath); err != nil {
+			if err := s.copyItemFile(ctx, srcLib, srcItem, srcFile.Name, dstFilePath, srcFilePath); err != nil {
 				return err
 			}
 			continue
@@ -1327,7 +1403,7 @@ func (s *handler) syncItem(
 			dstFile.Cached = &fileIsNotCached
 			dstFile.Size = &fileZeroSize
 		} else {
-			if err := copyFile(dstFilePath, srcFilePath); err != nil {
+			if err := s.copyItemFile(ctx, srcLib, srcItem, srcFile.Name, dstFilePath, srcFilePath); err != nil {
 				return err
 			}
 
@@ -1339,6 +1415,162 @@ func (s *handler) syncItem(
 	return nil
 }
 
+// subscriptionSource returns the library published at the given subscription's URL.
+// Libraries published by this instance are found by ID, any other http(s) URL is
+// treated as a remote VCSP publication, such as one served by govc library.serve.
+// A nil content is returned if the subscription URL is not a publication.
+func (s *handler) subscriptionSource(ctx context.Context, sub *library.Subscription) (*content, error) {
+	id := path.Base(strings.TrimSuffix(sub.SubscriptionURL, "/"+vcsp.LibraryFile))
+	if l, ok := s.Library[id]; ok {
+		return l, nil
+	}
+
+	u, err := url.Parse(sub.SubscriptionURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, nil
+	}
+	if u.Host == s.URL.Host && strings.HasPrefix(u.Path, "/cls/vcsp/lib/") {
+		return nil, nil // a library of this instance that no longer exists
+	}
+
+	var src *content
+	s.unlocked(func() {
+		src, err = remoteLibrary(ctx, sub)
+	})
+	return src, err
+}
+
+// unlocked calls fn with the handler lock released, for requests to remote publications.
+// The caller must hold the lock and must not depend on handler state read before the call.
+func (s *handler) unlocked(fn func()) {
+	s.Unlock()
+	defer s.Lock()
+	fn()
+}
+
+// copyItemFile copies a file of the given library item, releasing the handler lock
+// while the file is fetched from a remote publication.
+func (s *handler) copyItemFile(ctx context.Context, l *content, i *item, name, dstPath, srcPath string) error {
+	if l.pub == nil {
+		return l.copyFile(ctx, i, name, dstPath, srcPath)
+	}
+
+	var err error
+	s.unlocked(func() {
+		err = l.copyFile(ctx, i, name, dstPath, srcPath)
+	})
+	return err
+}
+
+// remoteClient is used to fetch remote VCSP publications.
+// Connecting and waiting for response headers are bounded by timeouts, reading the
+// (possibly large) response body is bounded only by the request context.
+var remoteClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: time.Minute,
+		TLSClientConfig:       &tls.Config{InsecureSkipVerify: true}, // matches vcsim's default trust of published libraries
+	},
+}
+
+// remoteLibrary returns a content view of a remote VCSP publication.
+// Item IDs are those of the publication, file content is fetched on demand by copyFile.
+func remoteLibrary(ctx context.Context, sub *library.Subscription) (*content, error) {
+	c, err := vcsp.NewClient(sub.SubscriptionURL)
+	if err != nil {
+		return nil, err
+	}
+	if sub.AuthenticationMethod == "BASIC" {
+		c.Username = sub.UserName
+		if c.Username == "" {
+			c.Username = vcsp.DefaultUsername
+		}
+		c.Password = sub.Password
+	}
+	c.HTTP = remoteClient
+
+	lib, err := c.Library(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := c.Items(ctx, lib)
+	if err != nil {
+		return nil, err
+	}
+
+	src := &content{
+		Library: &library.Library{
+			ID:      lib.ID,
+			Name:    lib.Name,
+			Version: lib.Version,
+		},
+		Item: make(map[string]*item, len(items)),
+		pub:  c,
+	}
+
+	for _, vi := range items {
+		created := vi.Created
+		i := &item{
+			Item: &library.Item{
+				ID:              vi.ID,
+				Name:            vi.Name,
+				Description:     &vi.Description,
+				Type:            vcsp.LibraryItemType(vi.Type),
+				CreationTime:    &created,
+				MetadataVersion: vi.Version,
+				ContentVersion:  vi.ContentVersion,
+			},
+			href: make(map[string]string, len(vi.Files)),
+		}
+		if i.ContentVersion == "" {
+			i.ContentVersion = vi.Version
+		}
+		for _, f := range vi.Files {
+			if len(f.Hrefs) == 0 {
+				continue
+			}
+			size := f.Size
+			i.File = append(i.File, library.File{
+				Name:    f.Name,
+				Size:    &size,
+				Version: f.Etag,
+			})
+			i.href[f.Name] = f.Hrefs[0]
+		}
+		src.Item[vi.ID] = i
+	}
+
+	return src, nil
+}
+
+// copyFile copies a file of the given library item, from the local filesystem
+// or the remote publication. Remote file content is streamed to dstPath.
+func (l *content) copyFile(ctx context.Context, i *item, name, dstPath, srcPath string) error {
+	if l.pub == nil {
+		return copyFile(dstPath, srcPath)
+	}
+
+	src, _, err := l.pub.Open(ctx, i.href[name])
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := openFile(src, dstPath, createOrCopyFlags, createOrCopyMode)
+	if err != nil {
+		return fmt.Errorf("failed to create %q: %w", dstPath, err)
+	}
+	defer dst.Close()
+
+	_, err = copyReaderToWriter(dst, dstPath, src, name)
+	return err
+}
+
 const (
 	createOrCopyFlags = os.O_RDWR | os.O_CREATE | os.O_TRUNC
 	createOrCopyMode  = os.FileMode(0664)
@@ -1528,7 +1760,7 @@ func (s *handler) libraryID(w http.ResponseWriter, r *http.Request) {
 		case "sync":
 			if l.Type == "SUBSCRIBED" {
 				l.LastSyncTime = types.NewTime(time.Now())
-				if err := s.syncSubLib(l); err != nil {
+				if err := s.syncSubLib(r.Context(), l); err != nil {
 					BadRequest(w, err.Error())
 				} else {
 					OK(w)
@@ -1809,7 +2041,7 @@ func (s *handler) libraryItemID(w http.ResponseWriter, r *http.Request) {
 						}
 					}
 					if l.Type == "SUBSCRIBED" {
-						if err := s.syncItem(item, l, nil, spec.Force, nil); err != nil {
+						if err := s.syncItem(r.Context(), item, l, nil, spec.Force, nil); err != nil {
 							BadRequest(w, err.Error())
 						} else {
 							OK(w)
@@ -1959,6 +2191,7 @@ func (s *handler) libraryItemUpdateSession(w http.ResponseWriter, r *http.Reques
 				Session:   session,
 				Library:   lib.Library,
 				File:      make(map[string]*library.UpdateFile),
+				Remove:    make(map[string]bool),
 			}
 			OK(w, session.ID)
 		}
@@ -1994,9 +2227,12 @@ func (s *handler) libraryItemUpdateSessionID(w http.ResponseWriter, r *http.Requ
 		case "cancel":
 			done("CANCELED")
 		case "complete":
+			s.removeFiles(up)
 			go func() {
 				up.Wait() // wait for any PULL sources to complete
+				s.Lock()
 				done("DONE")
+				s.Unlock()
 			}()
 		case "fail":
 			done("ERROR")
@@ -2219,8 +2455,13 @@ func (s *handler) libraryItemUpdateSessionFileID(w http.ResponseWriter, r *http.
 			s.error(w, fmt.Errorf("removeFile not allowed in state %s", up.State))
 			return
 		}
-		delete(s.Update, id)
-		OK(w)
+		var spec struct {
+			File string `json:"file_name"`
+		}
+		if s.decode(r, w, &spec) {
+			up.Remove[spec.File] = true
+			OK(w)
+		}
 	case "validate":
 		if up.State != "ACTIVE" {
 			BadRequest(w, "com.vmware.vapi.std.errors.not_allowed_in_current_state")
@@ -2432,14 +2673,19 @@ func (s *handler) libraryItemFileCreate(
 
 		dstFilePath := path.Join(dstItemPath, fileName)
 
-		var h hash.Hash
-
-		if doChecksum {
-			if hasChecksum(cs) {
-				h = checksum[cs.Algorithm]()
-				src = io.TeeReader(src, h)
-			}
+		// The file checksum is always recorded, using SHA1 as vCenter does
+		// unless the client provided one to verify.
+		verify := doChecksum && hasChecksum(cs)
+		alg := "SHA1"
+		if verify {
+			alg = cs.Algorithm
+		}
+		newHash, ok := checksum[alg]
+		if !ok {
+			return library.File{}, fmt.Errorf("unsupported checksum algorithm %q", alg)
 		}
+		h := newHash()
+		src = io.TeeReader(src, h)
 
 		dst, err := openFile(src, dstFilePath, createOrCopyFlags, createOrCopyMode)
 		if err != nil {
@@ -2452,19 +2698,19 @@ func (s *handler) libraryItemFileCreate(
 			return library.File{}, err
 		}
 
-		if h != nil {
-			if sum := fmt.Sprintf("%x", h.Sum(nil)); sum != cs.Checksum {
-				return library.File{}, fmt.Errorf(
-					"checksum mismatch: file=%s, alg=%s, actual=%s, expected=%s",
-					fileName, cs.Algorithm, sum, cs.Checksum)
-			}
+		sum := fmt.Sprintf("%x", h.Sum(nil))
+		if verify && !strings.EqualFold(sum, cs.Checksum) {
+			return library.File{}, fmt.Errorf(
+				"checksum mismatch: file=%s, alg=%s, actual=%s, expected=%s",
+				fileName, cs.Algorithm, sum, cs.Checksum)
 		}
 
 		return library.File{
-			Cached:  types.NewBool(true),
-			Name:    fileName,
-			Size:    &n,
-			Version: "1",
+			Cached:   types.NewBool(true),
+			Checksum: &library.Checksum{Algorithm: alg, Checksum: sum},
+			Name:     fileName,
+			Size:     &n,
+			Version:  "1",
 		}, nil
 	}
 
@@ -2478,8 +2724,16 @@ func (s *handler) libraryItemFileCreate(
 			return err
 		}
 
-		// Update the library item with the uploaded file.
+		// Update the library item with the uploaded file, replacing any
+		// existing file of the same name.
 		i := s.Library[up.Library.ID].Item[up.Session.LibraryItemID]
+		for j := range i.File {
+			if i.File[j].Name == f.Name {
+				f.Version = nextVersion(i.File[j].Version)
+				i.File[j] = f
+				return nil
+			}
+		}
 		i.File = append(i.File, f)
 		return nil
 	}
@@ -2540,6 +2794,43 @@ func (s *handler) libraryItemFileCreate(
 	return nil
 }
 
+// nextVersion increments the given version, as vCenter does for a replaced library item file.
+func nextVersion(version string) string {
+	n, _ := strconv.Atoi(version)
+	return strconv.Itoa(n + 1)
+}
+
+// removeFiles removes the files marked for removal by the given update session from the library item.
+func (s *handler) removeFiles(up update) {
+	if len(up.Remove) == 0 {
+		return
+	}
+
+	l, ok := s.Library[up.Library.ID]
+	if !ok {
+		return
+	}
+	i, ok := l.Item[up.Session.LibraryItemID]
+	if !ok {
+		return
+	}
+
+	dir := s.libraryPath(l.Library, i.ID)
+	files := i.File[:0]
+	for _, f := range i.File {
+		if up.Remove[f.Name] && isValidFileName(f.Name) {
+			name := path.Join(dir, f.Name)
+			if backing := simulator.VirtualDiskBackingFileName(name); backing != name {
+				_ = os.Remove(backing)
+			}
+			_ = os.Remove(name)
+			continue
+		}
+		files = append(files, f)
+	}
+	i.File = files
+}
+
 func (s *handler) libraryItemFileData(w http.ResponseWriter, r *http.Request) {
 	p := strings.Split(r.URL.Path, "/")
 	id, name := p[len(p)-2], p[len(p)-1]
@@ -2577,7 +2868,12 @@ func (s *handler) libraryItemFileData(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := s.libraryItemFileCreate(up, name, r.Body, nil)
+	var cs *library.Checksum
+	if info, ok := up.File[id]; ok {
+		cs = info.Checksum
+	}
+
+	err := s.libraryItemFileCreate(up, name, r.Body, cs)
 	if err != nil {
 		s.error(w, err)
 	}
@@ -3089,7 +3385,7 @@ func (s *handler) libraryItemTemplateID(w http.ResponseWriter, r *http.Request)
 			return
 		}
 
-		s.syncItem(item, nil, nil, true, nil)
+		s.syncItem(r.Context(), item, nil, nil, true, nil)
 		ref, err := s.cloneVM(item.Template.Value, spec.Name, p, spec.DiskStorage)
 		if err != nil {
 			BadRequest(w, err.Error())
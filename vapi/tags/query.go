@@ -0,0 +1,429 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package tags
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// Query is a boolean expression over tags, used to find the objects with matching tag associations.
+//
+// A term is a tag name or ID, optionally qualified by a category name or ID in the form CATEGORY:TAG.
+// A TAG of "*" matches any tag in the category. A term matches an object if any of the tags it resolves to
+// is attached to the object. Terms are combined using "!" (or "not"), "&&" (or "and"), "||" (or "or")
+// and parentheses, in order of precedence. Names that contain spaces, colons, parentheses or operator characters
+// must be double quoted, as must a category ID used to qualify a tag.
+// Negation is relative to the objects with at least one tag attached.
+//
+// Examples:
+//
+//	env:prod && !(env:test || deprecated)
+//	"k8s-region":* and not "k8s-zone":us-west-1a
+type Query struct {
+	Expr  string   // Expr is the boolean expression
+	Types []string // Types, if set, limits the results to objects of the given types, such as "VirtualMachine"
+}
+
+// queryNode is a node of a parsed Query expression.
+type queryNode interface {
+	match(tags map[string]bool) bool
+}
+
+type queryTerm struct {
+	category string
+	tag      string
+	ids      []string
+}
+
+type queryNot struct {
+	node queryNode
+}
+
+type queryAnd struct {
+	left, right queryNode
+}
+
+type queryOr struct {
+	left, right queryNode
+}
+
+func (t *queryTerm) match(tags map[string]bool) bool {
+	return slices.ContainsFunc(t.ids, func(id string) bool { return tags[id] })
+}
+
+func (n *queryNot) match(tags map[string]bool) bool {
+	return !n.node.match(tags)
+}
+
+func (n *queryAnd) match(tags map[string]bool) bool {
+	return n.left.match(tags) && n.right.match(tags)
+}
+
+func (n *queryOr) match(tags map[string]bool) bool {
+	return n.left.match(tags) || n.right.match(tags)
+}
+
+// queryToken is a lexical token of a Query expression.
+// Operators are normalized to "!", "&&" and "||", word tokens have the word field set.
+type queryToken struct {
+	op   string
+	word string
+	pos  int
+}
+
+func (t queryToken) String() string {
+	if t.op != "" {
+		return t.op
+	}
+	return fmt.Sprintf("%q", t.word)
+}
+
+// isQueryDelim returns true if the byte terminates an unquoted word.
+func isQueryDelim(c byte) bool {
+	return strings.IndexByte(" \t\r\n()!&|\":", c) != -1
+}
+
+func lexQuery(expr string) ([]queryToken, error) {
+	var tokens []queryToken
+
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '(' || c == ')' || c == '!' || c == ':':
+			tokens = append(tokens, queryToken{op: string(c), pos: i})
+			i++
+		case c == '&' || c == '|':
+			if i+1 == len(expr) || expr[i+1] != c {
+				return nil, fmt.Errorf("invalid operator %q at offset %d", c, i)
+			}
+			tokens = append(tokens, queryToken{op: expr[i : i+2], pos: i})
+			i += 2
+		case c == '"':
+			var word strings.Builder
+			start := i
+			for i++; ; i++ {
+				if i == len(expr) {
+					return nil, fmt.Errorf("unterminated quote at offset %d", start)
+				}
+				if expr[i] == '\\' && i+1 < len(expr) {
+					i++
+				} else if expr[i] == '"' {
+					i++
+					break
+				}
+				word.WriteByte(expr[i])
+			}
+			tokens = append(tokens, queryToken{word: word.String(), pos: start})
+		default:
+			start := i
+			for i < len(expr) && !isQueryDelim(expr[i]) {
+				i++
+			}
+			// IDs are URNs such as "urn:vmomi:InventoryServiceTag:$uuid:GLOBAL"
+			if expr[start:i] == "urn" {
+				for i < len(expr) && (expr[i] == ':' || !isQueryDelim(expr[i])) {
+					i++
+				}
+			}
+			word := expr[start:i]
+			switch strings.ToLower(word) {
+			case "not":
+				tokens = append(tokens, queryToken{op: "!", pos: start})
+			case "and":
+				tokens = append(tokens, queryToken{op: "&&", pos: start})
+			case "or":
+				tokens = append(tokens, queryToken{op: "||", pos: start})
+			default:
+				tokens = append(tokens, queryToken{word: word, pos: start})
+			}
+		}
+	}
+
+	return tokens, nil
+}
+
+// queryParser is a recursive descent parser for Query expressions.
+type queryParser struct {
+	tokens []queryToken
+	terms  []*queryTerm
+	not    bool
+}
+
+func (p *queryParser) peek(op string) bool {
+	return len(p.tokens) != 0 && p.tokens[0].op == op
+}
+
+func (p *queryParser) next() (queryToken, error) {
+	if len(p.tokens) == 0 {
+		return queryToken{}, fmt.Errorf("unexpected end of expression")
+	}
+	t := p.tokens[0]
+	p.tokens = p.tokens[1:]
+	return t, nil
+}
+
+func (p *queryParser) or() (queryNode, error) {
+	node, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek("||") {
+		_, _ = p.next()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		node = &queryOr{node, right}
+	}
+	return node, nil
+}
+
+func (p *queryParser) and() (queryNode, error) {
+	node, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek("&&") {
+		_, _ = p.next()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		node = &queryAnd{node, right}
+	}
+	return node, nil
+}
+
+func (p *queryParser) unary() (queryNode, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t.op {
+	case "!":
+		p.not = true
+		node, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &queryNot{node}, nil
+	case "(":
+		node, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if t, err = p.next(); err != nil {
+			return nil, err
+		}
+		if t.op != ")" {
+			return nil, fmt.Errorf("expected ')' at offset %d, found %s", t.pos, t)
+		}
+		return node, nil
+	case "":
+		term := &queryTerm{tag: t.word}
+		if p.peek(":") {
+			_, _ = p.next()
+			if t, err = p.next(); err != nil {
+				return nil, err
+			}
+			if t.op != "" {
+				return nil, fmt.Errorf("expected tag at offset %d, found %s", t.pos, t)
+			}
+			term.category, term.tag = term.tag, t.word
+		}
+		p.terms = append(p.terms, term)
+		return term, nil
+	default:
+		return nil, fmt.Errorf("unexpected %s at offset %d", t, t.pos)
+	}
+}
+
+// parseQuery parses the given expression, returning the root node and parser state.
+func parseQuery(expr string) (queryNode, *queryParser, error) {
+	tokens, err := lexQuery(expr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := &queryParser{tokens: tokens}
+	node, err := p.or()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(p.tokens) != 0 {
+		t := p.tokens[0]
+		return nil, nil, fmt.Errorf("unexpected %s at offset %d", t, t.pos)
+	}
+
+	return node, p, nil
+}
+
+// queryResolver resolves query terms to tag IDs, caching tags and categories to minimize round-trips.
+type queryResolver struct {
+	m          *Manager
+	tags       map[string]*Tag
+	all        []string
+	categories map[string][]string
+}
+
+func (r *queryResolver) getTag(ctx context.Context, id string) (*Tag, error) {
+	if tag, ok := r.tags[id]; ok {
+		return tag, nil
+	}
+	tag, err := r.m.GetTag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.tags[id] = tag
+	return tag, nil
+}
+
+func (r *queryResolver) allTags(ctx context.Context) ([]string, error) {
+	if r.all == nil {
+		ids, err := r.m.ListTags(ctx)
+		if err != nil {
+			return nil, err
+		}
+		r.all = append([]string{}, ids...)
+	}
+	return r.all, nil
+}
+
+func (r *queryResolver) categoryTags(ctx context.Context, category string) ([]string, error) {
+	if ids, ok := r.categories[category]; ok {
+		return ids, nil
+	}
+	ids, err := r.m.ListTagsForCategory(ctx, category)
+	if err != nil {
+		return nil, fmt.Errorf("category %q: %s", category, err)
+	}
+	r.categories[category] = ids
+	return ids, nil
+}
+
+func (r *queryResolver) resolve(ctx context.Context, term *queryTerm) error {
+	if term.category == "" && !isName(term.tag) {
+		term.ids = []string{term.tag}
+		return nil
+	}
+
+	var ids []string
+	var err error
+	if term.category == "" {
+		ids, err = r.allTags(ctx)
+	} else {
+		ids, err = r.categoryTags(ctx, term.category)
+		if term.tag == "*" {
+			term.ids = ids
+			return err
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if id == term.tag {
+			term.ids = append(term.ids, id)
+			continue
+		}
+		tag, err := r.getTag(ctx, id)
+		if err != nil {
+			return err
+		}
+		if tag.Name == term.tag {
+			term.ids = append(term.ids, id)
+		}
+	}
+
+	if len(term.ids) == 0 {
+		if term.category == "" {
+			return fmt.Errorf("tag %q not found", term.tag)
+		}
+		return fmt.Errorf("tag %q not found in category %q", term.tag, term.category)
+	}
+
+	return nil
+}
+
+// Query returns the objects that match the given Query, sorted by type and ID.
+// The objects attached to the tags of all terms are fetched using a single ListAttachedObjectsOnTags call.
+func (c *Manager) Query(ctx context.Context, q Query) ([]types.ManagedObjectReference, error) {
+	root, p, err := parseQuery(q.Expr)
+	if err != nil {
+		return nil, fmt.Errorf("tags query %q: %s", q.Expr, err)
+	}
+
+	r := &queryResolver{
+		m:          c,
+		tags:       make(map[string]*Tag),
+		categories: make(map[string][]string),
+	}
+
+	var ids []string
+	for _, term := range p.terms {
+		if err := r.resolve(ctx, term); err != nil {
+			return nil, fmt.Errorf("tags query %q: %s", q.Expr, err)
+		}
+		ids = append(ids, term.ids...)
+	}
+
+	if p.not {
+		// negation is relative to all tagged objects
+		if ids, err = r.allTags(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	attached, err := c.ListAttachedObjectsOnTags(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make(map[types.ManagedObjectReference]map[string]bool)
+	for _, a := range attached {
+		for _, obj := range a.ObjectIDs {
+			ref := obj.Reference()
+			if len(q.Types) != 0 && !slices.Contains(q.Types, ref.Type) {
+				continue
+			}
+			if objects[ref] == nil {
+				objects[ref] = make(map[string]bool)
+			}
+			objects[ref][a.TagID] = true
+		}
+	}
+
+	var res []types.ManagedObjectReference
+	for ref, tags := range objects {
+		if root.match(tags) {
+			res = append(res, ref)
+		}
+	}
+
+	slices.SortFunc(res, func(a, b types.ManagedObjectReference) int {
+		if n := strings.Compare(a.Type, b.Type); n != 0 {
+			return n
+		}
+		return strings.Compare(a.Value, b.Value)
+	})
+
+	return res, nil
+}
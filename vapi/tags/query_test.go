@@ -0,0 +1,127 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package tags_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestManager_Query(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		require.NoError(t, c.Login(ctx, simulator.DefaultLogin))
+
+		m := tags.NewManager(c)
+
+		vms, err := find.NewFinder(vc).VirtualMachineList(ctx, "*")
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, len(vms), 4)
+
+		ids := map[string]string{}
+		for cat, names := range map[string][]string{
+			"env":  {"prod", "test"},
+			"tier": {"web", "db", "test"},
+		} {
+			catID, err := m.CreateCategory(ctx, &tags.Category{Name: cat, Cardinality: "MULTIPLE"})
+			require.NoError(t, err)
+			for _, name := range names {
+				id, err := m.CreateTag(ctx, &tags.Tag{Name: name, CategoryID: catID})
+				require.NoError(t, err)
+				ids[cat+":"+name] = id
+			}
+		}
+
+		ds, err := find.NewFinder(vc).DefaultDatastore(ctx)
+		require.NoError(t, err)
+
+		attach := map[string][]mo.Reference{
+			"env:prod":  {vms[0], vms[1], ds},
+			"env:test":  {vms[2]},
+			"tier:web":  {vms[0], vms[2]},
+			"tier:db":   {vms[1]},
+			"tier:test": {vms[3]},
+		}
+		for tag, refs := range attach {
+			require.NoError(t, m.AttachTagToMultipleObjects(ctx, ids[tag], refs))
+		}
+
+		refs := func(objs ...mo.Reference) []types.ManagedObjectReference {
+			var res []types.ManagedObjectReference
+			for _, obj := range objs {
+				res = append(res, obj.Reference())
+			}
+			return res
+		}
+
+		vm := []string{"VirtualMachine"}
+
+		tests := []struct {
+			expr  string
+			types []string
+			want  []types.ManagedObjectReference
+		}{
+			{"env:prod", nil, refs(ds, vms[0], vms[1])},
+			{"env:prod", vm, refs(vms[0], vms[1])},
+			{"env:prod && !tier:db", vm, refs(vms[0])},
+			{"env:prod and not tier:db", vm, refs(vms[0])},
+			{"env:* && tier:web", nil, refs(vms[0], vms[2])},
+			{"test", nil, refs(vms[2], vms[3])}, // tag name in any category
+			{"tier:test || (env:prod && tier:db)", nil, refs(vms[1], vms[3])},
+			{"!env:*", nil, refs(vms[3])},
+			{`"env":"test"`, nil, refs(vms[2])},
+			{ids["tier:db"], nil, refs(vms[1])},
+			{"env:" + ids["env:test"], nil, refs(vms[2])},
+			{"env:prod && env:test", nil, nil},
+		}
+
+		for _, test := range tests {
+			res, err := m.Query(ctx, tags.Query{Expr: test.expr, Types: test.types})
+			require.NoError(t, err, test.expr)
+			assert.Equal(t, test.want, res, test.expr)
+		}
+
+		// detach using a query result
+		res, err := m.Query(ctx, tags.Query{Expr: "env:prod", Types: vm})
+		require.NoError(t, err)
+		var objs []mo.Reference
+		for i := range res {
+			objs = append(objs, res[i])
+		}
+		require.NoError(t, m.DetachTagFromMultipleObjects(ctx, ids["env:prod"], objs))
+		res, err = m.Query(ctx, tags.Query{Expr: "env:prod"})
+		require.NoError(t, err)
+		assert.Equal(t, refs(ds), res)
+
+		for _, expr := range []string{
+			"",
+			"env:",
+			"env:prod &&",
+			"env:prod & tier:db",
+			"(env:prod",
+			"env:prod)",
+			`"env`,
+			"enoent",
+			"env:enoent",
+			"enoent:prod",
+		} {
+			_, err = m.Query(ctx, tags.Query{Expr: expr})
+			assert.Error(t, err, expr)
+		}
+	})
+}
@@ -84,6 +84,42 @@ func (c *Manager) AttachTagToMultipleObjects(ctx context.Context, tagID string,
 	return c.Do(ctx, url.Request(http.MethodPost, spec), nil)
 }
 
+// DetachTagFromMultipleObjects detaches a tag ID from multiple managed objects.
+// If the tag is already removed from an object, then the individual operation is a no-op and an error will not be thrown.
+//
+// This operation was added in vSphere API 6.5.
+func (c *Manager) DetachTagFromMultipleObjects(ctx context.Context, tagID string, refs []mo.Reference) error {
+	id, err := c.tagID(ctx, tagID)
+	if err != nil {
+		return err
+	}
+
+	var ids []internal.AssociatedObject
+	for i := range refs {
+		ref := refs[i].Reference()
+		ids = append(ids, internal.AssociatedObject{
+			Type:  ref.Type,
+			Value: ref.Value,
+		})
+	}
+
+	spec := struct {
+		ObjectIDs []internal.AssociatedObject `json:"object_ids"`
+	}{ids}
+
+	url := c.Resource(internal.AssociationPath).WithID(id).WithAction("detach-tag-from-multiple-objects")
+	var res batchResponse
+	if err := c.Do(ctx, url.Request(http.MethodPost, spec), &res); err != nil {
+		return err
+	}
+
+	if !res.Success {
+		return res.Errors
+	}
+
+	return nil
+}
+
 // AttachMultipleTagsToObject attaches multiple tag IDs to a managed object.
 // This operation is idempotent. If a tag is already attached to the object,
 // then the individual operation is a no-op and no error will be thrown. This
@@ -306,8 +342,9 @@ func (c *Manager) GetAttachedObjectsOnTags(ctx context.Context, tagID []string)
 			if err != nil {
 				return nil, fmt.Errorf("get tag %s: %s", id, err)
 			}
-			objs[i].Tag = tag
+			tags[id] = tag
 		}
+		objs[i].Tag = tag
 	}
 
 	return objs, nil
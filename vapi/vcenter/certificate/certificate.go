@@ -0,0 +1,122 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package certificate
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// vCenter certificate management REST endpoints
+const (
+	Path                   = "/api/vcenter/certificate-management/vcenter"
+	TLSPath                = Path + "/tls"
+	TLSCSRPath             = Path + "/tls-csr"
+	TrustedRootChainsPath  = Path + "/trusted-root-chains"
+	SigningCertificatePath = Path + "/signing-certificate"
+	VMCARootPath           = Path + "/vmca-root"
+)
+
+// Manager extends rest.Client, adding vCenter certificate management methods.
+//
+// Certificates, certificate chains, private keys and CSRs are PEM encoded strings.
+//
+// See https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/certificate_management/
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// TLSInfo contains information about the vCenter machine SSL certificate.
+type TLSInfo struct {
+	Version                       int64     `json:"version"`
+	SerialNumber                  string    `json:"serial_number"`
+	SignatureAlgorithm            string    `json:"signature_algorithm"`
+	IssuerDN                      string    `json:"issuer_dn"`
+	ValidFrom                     time.Time `json:"valid_from"`
+	ValidTo                       time.Time `json:"valid_to"`
+	SubjectDN                     string    `json:"subject_dn"`
+	Thumbprint                    string    `json:"thumbprint"`
+	IsCA                          bool      `json:"is_CA"`
+	PathLengthConstraint          int64     `json:"path_length_constraint"`
+	KeyUsage                      []string  `json:"key_usage,omitempty"`
+	ExtendedKeyUsage              []string  `json:"extended_key_usage,omitempty"`
+	SubjectAlternativeName        []string  `json:"subject_alternative_name,omitempty"`
+	AuthorityInformationAccessURI []string  `json:"authority_information_access_uri,omitempty"`
+	Cert                          string    `json:"cert"`
+}
+
+// TLSSpec is used to replace the vCenter machine SSL certificate.
+// If Key is empty, the private key generated by the most recent CreateTLSCSR call is used.
+// If RootCert is not empty, it is added to the trusted root chains.
+type TLSSpec struct {
+	Cert     string `json:"cert"`
+	Key      string `json:"key,omitempty"`
+	RootCert string `json:"root_cert,omitempty"`
+}
+
+// GetTLS returns the vCenter machine SSL certificate.
+func (c *Manager) GetTLS(ctx context.Context) (*TLSInfo, error) {
+	url := c.Resource(TLSPath)
+	var res TLSInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// SetTLS replaces the vCenter machine SSL certificate.
+// vCenter services are restarted once the certificate is replaced.
+func (c *Manager) SetTLS(ctx context.Context, spec TLSSpec) error {
+	url := c.Resource(TLSPath)
+	return c.Do(ctx, url.Request(http.MethodPut, spec), nil)
+}
+
+// RenewTLS renews the vCenter machine SSL certificate using the VMCA signing certificate.
+// The duration is specified in days, a value of zero uses the vCenter default.
+func (c *Manager) RenewTLS(ctx context.Context, duration int64) error {
+	url := c.Resource(TLSPath).WithParam("action", "renew")
+	var spec struct {
+		Duration int64 `json:"duration,omitempty"`
+	}
+	spec.Duration = duration
+	return c.Do(ctx, url.Request(http.MethodPost, spec), nil)
+}
+
+// CSRSpec contains the subject fields used to generate a certificate signing request or a VMCA root certificate.
+type CSRSpec struct {
+	KeySize          int64    `json:"key_size,omitempty"`
+	CommonName       string   `json:"common_name,omitempty"`
+	Organization     string   `json:"organization"`
+	OrganizationUnit string   `json:"organization_unit"`
+	Locality         string   `json:"locality"`
+	StateOrProvince  string   `json:"state_or_province"`
+	Country          string   `json:"country"`
+	EmailAddress     string   `json:"email_address"`
+	SubjectAltName   []string `json:"subject_alt_name,omitempty"`
+}
+
+// CreateTLSCSR generates a certificate signing request for the vCenter machine SSL certificate.
+// The private key is kept by vCenter and used by SetTLS when no key is specified.
+func (c *Manager) CreateTLSCSR(ctx context.Context, spec CSRSpec) (string, error) {
+	url := c.Resource(TLSCSRPath)
+	var res struct {
+		CSR string `json:"csr"`
+	}
+	err := c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+	return res.CSR, err
+}
+
+// CreateVMCARoot replaces the VMCA root certificate with a newly generated self-signed certificate.
+func (c *Manager) CreateVMCARoot(ctx context.Context, spec CSRSpec) error {
+	url := c.Resource(VMCARootPath)
+	return c.Do(ctx, url.Request(http.MethodPost, spec), nil)
+}
@@ -0,0 +1,163 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package certificate_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter/certificate"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+	_ "github.com/vmware/govmomi/vapi/vcenter/certificate/simulator"
+)
+
+func encode(kind string, der []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der}))
+}
+
+// newCA returns a self-signed CA certificate and a function to sign a CSR with it.
+func newCA(t *testing.T) (string, func(string) string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	now := time.Now()
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA", Organization: []string{"govmomi"}},
+		NotBefore:             now,
+		NotAfter:              now.Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, ca, ca, key.Public(), key)
+	require.NoError(t, err)
+
+	sign := func(csr string) string {
+		block, _ := pem.Decode([]byte(csr))
+		req, err := x509.ParseCertificateRequest(block.Bytes)
+		require.NoError(t, err)
+
+		cert := &x509.Certificate{
+			SerialNumber: big.NewInt(2),
+			Subject:      req.Subject,
+			DNSNames:     req.DNSNames,
+			NotBefore:    now,
+			NotAfter:     now.Add(time.Hour),
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, cert, ca, req.PublicKey, key)
+		require.NoError(t, err)
+		return encode("CERTIFICATE", der)
+	}
+
+	return encode("CERTIFICATE", der), sign
+}
+
+func TestCertificate(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		require.NoError(t, c.Login(ctx, simulator.DefaultLogin))
+
+		m := certificate.NewManager(c)
+
+		info, err := m.GetTLS(ctx)
+		require.NoError(t, err)
+		assert.NotEmpty(t, info.Thumbprint)
+		assert.NotEmpty(t, info.Cert)
+
+		chains, err := m.ListTrustedRootChains(ctx)
+		require.NoError(t, err)
+		require.Len(t, chains, 1)
+		vmca := chains[0].Chain
+
+		signing, err := m.GetSigningCertificate(ctx)
+		require.NoError(t, err)
+		assert.Len(t, signing.ActiveCertChain.CertChain, 1)
+
+		_, err = m.GetTrustedRootChain(ctx, "enoent")
+		require.Error(t, err)
+
+		require.Error(t, m.DeleteTrustedRootChain(ctx, vmca)) // active signing certificate
+
+		// replace the machine SSL certificate with one signed by an external CA
+		root, sign := newCA(t)
+		csr, err := m.CreateTLSCSR(ctx, certificate.CSRSpec{
+			CommonName:     "vcsa.example.com",
+			Organization:   "govmomi",
+			SubjectAltName: []string{"vcsa.example.com"},
+		})
+		require.NoError(t, err)
+		cert := sign(csr)
+
+		require.Error(t, m.SetTLS(ctx, certificate.TLSSpec{Cert: cert})) // root is not trusted
+
+		other, _ := newCA(t)
+		require.Error(t, m.SetTLS(ctx, certificate.TLSSpec{Cert: cert, RootCert: other})) // wrong root
+		chains, err = m.ListTrustedRootChains(ctx)
+		require.NoError(t, err)
+		assert.Len(t, chains, 1) // not added to the trust store
+
+		_, err = m.CreateTLSCSR(ctx, certificate.CSRSpec{CommonName: "vcsa.example.com", KeySize: 16384})
+		require.Error(t, err) // exceeds the simulator key size limit
+
+		id, err := m.CreateTrustedRootChain(ctx, certificate.TrustedRootChainCreateSpec{
+			CertChain: certificate.X509CertChain{CertChain: []string{root}},
+		})
+		require.NoError(t, err)
+
+		_, err = m.CreateTrustedRootChain(ctx, certificate.TrustedRootChainCreateSpec{
+			CertChain: certificate.X509CertChain{CertChain: []string{root}},
+			Chain:     id,
+		})
+		require.Error(t, err)
+
+		chain, err := m.GetTrustedRootChain(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, []string{root}, chain.CertChain.CertChain)
+
+		require.NoError(t, m.SetTLS(ctx, certificate.TLSSpec{Cert: cert}))
+		info, err = m.GetTLS(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, info.SubjectDN, "CN=vcsa.example.com")
+		assert.Contains(t, info.IssuerDN, "CN=Test CA")
+		assert.Equal(t, []string{"vcsa.example.com"}, info.SubjectAlternativeName)
+
+		require.Error(t, m.SetTLS(ctx, certificate.TLSSpec{Cert: cert})) // CSR key was consumed
+
+		// replace the VMCA root and renew the machine SSL certificate
+		require.NoError(t, m.CreateVMCARoot(ctx, certificate.CSRSpec{CommonName: "New VMCA"}))
+		signing, err = m.GetSigningCertificate(ctx)
+		require.NoError(t, err)
+		assert.Len(t, signing.SigningCertChains, 2)
+		require.NoError(t, m.DeleteTrustedRootChain(ctx, vmca))
+
+		require.NoError(t, m.RenewTLS(ctx, 30))
+		info, err = m.GetTLS(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, info.SubjectDN, "CN=vcsa.example.com")
+		assert.Contains(t, info.IssuerDN, "CN=New VMCA")
+		assert.WithinDuration(t, time.Now().AddDate(0, 0, 30), info.ValidTo, time.Hour)
+
+		require.NoError(t, m.DeleteTrustedRootChain(ctx, id))
+		chains, err = m.ListTrustedRootChains(ctx)
+		require.NoError(t, err)
+		assert.Len(t, chains, 1)
+	})
+}
@@ -0,0 +1,669 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/simulator"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vapi/vcenter/certificate"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+func init() {
+	simulator.RegisterEndpoint(func(s *simulator.Service, r *simulator.Registry) {
+		New(s.Listen).Register(s, r)
+	})
+}
+
+// Handler implements the vCenter certificate management API simulator.
+// The trust store, VMCA signing certificate and machine SSL certificate are kept in memory,
+// replacing the machine SSL certificate does not change the certificate served by the simulator.
+type Handler struct {
+	URL      *url.URL
+	registry *simulator.Registry
+
+	mu      sync.Mutex
+	machine *tls.Certificate
+	signer  *tls.Certificate
+	signers []certificate.X509CertChain
+	roots   map[string]certificate.X509CertChain
+	csrKey  crypto.Signer
+}
+
+// New creates a Handler instance
+func New(u *url.URL) *Handler {
+	return &Handler{
+		URL:   u,
+		roots: make(map[string]certificate.X509CertChain),
+	}
+}
+
+// Register vCenter certificate management API paths with the simulator's http.ServeMux
+func (h *Handler) Register(s *simulator.Service, r *simulator.Registry) {
+	if r.IsVPX() {
+		h.registry = r
+
+		s.HandleFunc(certificate.TLSPath, h.tls)
+		s.HandleFunc(certificate.TLSCSRPath, h.tlsCSR)
+		s.HandleFunc(certificate.TrustedRootChainsPath, h.trustedRootChains)
+		s.HandleFunc(certificate.TrustedRootChainsPath+"/", h.trustedRootChain)
+		s.HandleFunc(certificate.SigningCertificatePath, h.signingCertificate)
+		s.HandleFunc(certificate.VMCARootPath, h.vmcaRoot)
+	}
+}
+
+// init lazily seeds the certificate state using the simulator's TLS certificate,
+// which is self-signed and used as both the machine SSL and VMCA signing certificate.
+// If the simulator is not using TLS, a new self-signed certificate is generated.
+// Must be called with h.mu held.
+func (h *Handler) init() error {
+	if h.signer != nil {
+		return nil
+	}
+
+	var cert *tls.Certificate
+
+	if h.registry.SessionManager().TLS != nil {
+		if config := h.registry.SessionManager().TLS(); config != nil && len(config.Certificates) != 0 {
+			c := config.Certificates[0] // copy, the Leaf field may be set below
+			cert = &c
+			if cert.Leaf == nil {
+				leaf, err := x509.ParseCertificate(cert.Certificate[0])
+				if err != nil {
+					return err
+				}
+				cert.Leaf = leaf
+			}
+		}
+	}
+
+	if cert == nil {
+		var err error
+		cert, err = newRoot(pkix.Name{CommonName: "CA", Organization: []string{"VMware"}}, 2048)
+		if err != nil {
+			return err
+		}
+	}
+
+	h.machine = cert
+	h.setSigner(cert)
+
+	return nil
+}
+
+// setSigner makes cert the active VMCA signing certificate and adds its root to the trust store.
+func (h *Handler) setSigner(cert *tls.Certificate) {
+	chain := encodeChain(cert.Certificate)
+	h.signer = cert
+	h.signers = append(h.signers, certificate.X509CertChain{CertChain: chain})
+	h.addRoot(certificate.X509CertChain{CertChain: chain[len(chain)-1:]}, "")
+}
+
+// addRoot adds the chain to the trust store, using the root certificate thumbprint as the ID if none is given.
+func (h *Handler) addRoot(chain certificate.X509CertChain, id string) string {
+	if id == "" {
+		certs, _ := parseChain(chain.CertChain...)
+		id = strings.ReplaceAll(soap.ThumbprintSHA1(certs[len(certs)-1]), ":", "")
+	}
+	h.roots[id] = chain
+	return id
+}
+
+func encodeCert(der []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func encodeChain(chain [][]byte) []string {
+	res := make([]string, len(chain))
+	for i := range chain {
+		res[i] = encodeCert(chain[i])
+	}
+	return res
+}
+
+// parseChain decodes the PEM encoded certificates, each value may contain multiple certificates.
+func parseChain(values ...string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+
+	for _, val := range values {
+		rest := []byte(val)
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			certs = append(certs, cert)
+		}
+	}
+
+	if len(certs) == 0 {
+		return nil, errors.New("no certificate found")
+	}
+
+	return certs, nil
+}
+
+// keyPair parses the PEM encoded certificate chain, using the given PEM encoded key or signer.
+func keyPair(chain string, key string, signer crypto.Signer) (*tls.Certificate, error) {
+	var cert tls.Certificate
+	var err error
+
+	if key != "" {
+		cert, err = tls.X509KeyPair([]byte(chain), []byte(key))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if signer == nil {
+			return nil, errors.New("no private key")
+		}
+		certs, err := parseChain(chain)
+		if err != nil {
+			return nil, err
+		}
+		pub, ok := certs[0].PublicKey.(interface{ Equal(crypto.PublicKey) bool })
+		if !ok || !pub.Equal(signer.Public()) {
+			return nil, errors.New("private key does not match certificate")
+		}
+		for _, c := range certs {
+			cert.Certificate = append(cert.Certificate, c.Raw)
+		}
+		cert.PrivateKey = signer
+	}
+
+	cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0])
+	return &cert, err
+}
+
+// maxKeySize limits the cost of generating a key, vCenter supports sizes up to 16384.
+const maxKeySize = 4096
+
+func newKey(size int64) (*rsa.PrivateKey, error) {
+	if size == 0 {
+		size = 2048
+	}
+	if size < 2048 || size > maxKeySize {
+		return nil, errors.New("invalid key size")
+	}
+	return rsa.GenerateKey(rand.Reader, int(size))
+}
+
+func newSerialNumber() *big.Int {
+	n, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	return n
+}
+
+// newRoot generates a self-signed CA certificate.
+func newRoot(subject pkix.Name, size int64) (*tls.Certificate, error) {
+	key, err := newKey(size)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          newSerialNumber(),
+		Subject:               subject,
+		NotBefore:             now,
+		NotAfter:              now.AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, nil
+}
+
+func subject(spec certificate.CSRSpec) pkix.Name {
+	name := pkix.Name{CommonName: spec.CommonName}
+
+	add := func(dst *[]string, val string) {
+		if val != "" {
+			*dst = append(*dst, val)
+		}
+	}
+
+	add(&name.Organization, spec.Organization)
+	add(&name.OrganizationalUnit, spec.OrganizationUnit)
+	add(&name.Locality, spec.Locality)
+	add(&name.Province, spec.StateOrProvince)
+	add(&name.Country, spec.Country)
+
+	return name
+}
+
+// verify returns an error if the certificate chain cannot be verified using the trust store
+// and the given additional roots.
+// Must be called with h.mu held.
+func (h *Handler) verify(cert *tls.Certificate, extra ...*x509.Certificate) error {
+	roots := x509.NewCertPool()
+	for _, c := range extra {
+		roots.AddCert(c)
+	}
+	for _, chain := range h.roots {
+		certs, err := parseChain(chain.CertChain...)
+		if err != nil {
+			continue
+		}
+		for _, c := range certs {
+			roots.AddCert(c)
+		}
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, der := range cert.Certificate[1:] {
+		c, err := x509.ParseCertificate(der)
+		if err != nil {
+			return err
+		}
+		intermediates.AddCert(c)
+	}
+
+	_, err := cert.Leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err
+}
+
+var keyUsages = []struct {
+	bit  x509.KeyUsage
+	name string
+}{
+	{x509.KeyUsageDigitalSignature, "digitalSignature"},
+	{x509.KeyUsageContentCommitment, "nonRepudiation"},
+	{x509.KeyUsageKeyEncipherment, "keyEncipherment"},
+	{x509.KeyUsageDataEncipherment, "dataEncipherment"},
+	{x509.KeyUsageKeyAgreement, "keyAgreement"},
+	{x509.KeyUsageCertSign, "keyCertSign"},
+	{x509.KeyUsageCRLSign, "cRLSign"},
+	{x509.KeyUsageEncipherOnly, "encipherOnly"},
+	{x509.KeyUsageDecipherOnly, "decipherOnly"},
+}
+
+var extKeyUsages = map[x509.ExtKeyUsage]string{
+	x509.ExtKeyUsageAny:             "any",
+	x509.ExtKeyUsageServerAuth:      "serverAuth",
+	x509.ExtKeyUsageClientAuth:      "clientAuth",
+	x509.ExtKeyUsageCodeSigning:     "codeSigning",
+	x509.ExtKeyUsageEmailProtection: "emailProtection",
+	x509.ExtKeyUsageTimeStamping:    "timeStamping",
+	x509.ExtKeyUsageOCSPSigning:     "OCSPSigning",
+}
+
+func tlsInfo(cert *x509.Certificate) certificate.TLSInfo {
+	info := certificate.TLSInfo{
+		Version:                       int64(cert.Version),
+		SerialNumber:                  cert.SerialNumber.String(),
+		SignatureAlgorithm:            cert.SignatureAlgorithm.String(),
+		IssuerDN:                      cert.Issuer.String(),
+		ValidFrom:                     cert.NotBefore,
+		ValidTo:                       cert.NotAfter,
+		SubjectDN:                     cert.Subject.String(),
+		Thumbprint:                    soap.ThumbprintSHA1(cert),
+		IsCA:                          cert.IsCA,
+		PathLengthConstraint:          int64(cert.MaxPathLen),
+		SubjectAlternativeName:        cert.DNSNames,
+		AuthorityInformationAccessURI: cert.IssuingCertificateURL,
+		Cert:                          encodeCert(cert.Raw),
+	}
+
+	for _, u := range keyUsages {
+		if cert.KeyUsage&u.bit != 0 {
+			info.KeyUsage = append(info.KeyUsage, u.name)
+		}
+	}
+
+	for _, u := range cert.ExtKeyUsage {
+		if name, ok := extKeyUsages[u]; ok {
+			info.ExtendedKeyUsage = append(info.ExtendedKeyUsage, name)
+		}
+	}
+
+	for _, ip := range cert.IPAddresses {
+		info.SubjectAlternativeName = append(info.SubjectAlternativeName, ip.String())
+	}
+	info.SubjectAlternativeName = append(info.SubjectAlternativeName, cert.EmailAddresses...)
+
+	return info
+}
+
+func (h *Handler) tls(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.init(); err != nil {
+		vapi.ApiErrorGeneral(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, tlsInfo(h.machine.Leaf))
+	case http.MethodPut:
+		var spec certificate.TLSSpec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+
+		cert, err := keyPair(spec.Cert, spec.Key, h.csrKey)
+		if err != nil {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+
+		var root []*x509.Certificate
+		if spec.RootCert != "" {
+			if root, err = parseChain(spec.RootCert); err != nil {
+				vapi.ApiErrorInvalidArgument(w)
+				return
+			}
+		}
+
+		if err = h.verify(cert, root...); err != nil {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+
+		// the root is trusted only once the certificate is accepted
+		if spec.RootCert != "" {
+			h.addRoot(certificate.X509CertChain{CertChain: []string{spec.RootCert}}, "")
+		}
+
+		h.machine = cert
+		h.csrKey = nil
+		vapi.StatusOK(w)
+	case http.MethodPost:
+		if r.URL.Query().Get("action") != "renew" {
+			http.NotFound(w, r)
+			return
+		}
+
+		var spec struct {
+			Duration int64 `json:"duration,omitempty"`
+		}
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		if spec.Duration == 0 {
+			spec.Duration = 730
+		}
+
+		cert, err := h.sign(h.machine.Leaf, h.machine.PrivateKey.(crypto.Signer), spec.Duration)
+		if err != nil {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+
+		h.machine = cert
+		vapi.StatusOK(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// sign issues a new certificate for the given template and key using the VMCA signing certificate.
+// Must be called with h.mu held.
+func (h *Handler) sign(template *x509.Certificate, key crypto.Signer, days int64) (*tls.Certificate, error) {
+	now := time.Now()
+	cert := &x509.Certificate{
+		SerialNumber:   newSerialNumber(),
+		Subject:        template.Subject,
+		DNSNames:       template.DNSNames,
+		IPAddresses:    template.IPAddresses,
+		EmailAddresses: template.EmailAddresses,
+		NotBefore:      now,
+		NotAfter:       now.Add(time.Hour * 24 * time.Duration(days)),
+		KeyUsage:       x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		AuthorityKeyId: h.signer.Leaf.SubjectKeyId,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, cert, h.signer.Leaf, key.Public(), h.signer.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := append([][]byte{der}, h.signer.Certificate...)
+
+	return &tls.Certificate{Certificate: chain, PrivateKey: key, Leaf: leaf}, nil
+}
+
+func (h *Handler) tlsCSR(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.NotFound(w, r)
+		return
+	}
+
+	var spec certificate.CSRSpec
+	if !vapi.Decode(r, w, &spec) {
+		return
+	}
+
+	key, err := newKey(spec.KeySize)
+	if err != nil {
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	template := &x509.CertificateRequest{
+		Subject: subject(spec),
+	}
+	if spec.EmailAddress != "" {
+		template.EmailAddresses = []string{spec.EmailAddress}
+	}
+	for _, name := range spec.SubjectAltName {
+		if ip := net.ParseIP(name); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, name)
+		}
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	var csr bytes.Buffer
+	_ = pem.Encode(&csr, &pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+
+	h.mu.Lock()
+	h.csrKey = key
+	h.mu.Unlock()
+
+	vapi.StatusOK(w, struct {
+		CSR string `json:"csr"`
+	}{csr.String()})
+}
+
+func (h *Handler) trustedRootChains(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.init(); err != nil {
+		vapi.ApiErrorGeneral(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		res := []certificate.TrustedRootChainSummary{}
+		for id := range h.roots {
+			res = append(res, certificate.TrustedRootChainSummary{Chain: id})
+		}
+		slices.SortFunc(res, func(a, b certificate.TrustedRootChainSummary) int {
+			return strings.Compare(a.Chain, b.Chain)
+		})
+		vapi.StatusOK(w, res)
+	case http.MethodPost:
+		var spec certificate.TrustedRootChainCreateSpec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+
+		if _, err := parseChain(spec.CertChain.CertChain...); err != nil {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+
+		if spec.Chain != "" {
+			if _, ok := h.roots[spec.Chain]; ok {
+				vapi.ApiErrorAlreadyExists(w)
+				return
+			}
+		}
+
+		vapi.StatusOK(w, h.addRoot(spec.CertChain, spec.Chain))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) trustedRootChain(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.init(); err != nil {
+		vapi.ApiErrorGeneral(w)
+		return
+	}
+
+	id := path.Base(r.URL.Path)
+	chain, ok := h.roots[id]
+	if !ok {
+		vapi.ApiErrorNotFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, certificate.TrustedRootChainInfo{CertChain: chain})
+	case http.MethodDelete:
+		certs, _ := parseChain(chain.CertChain...)
+		for _, c := range certs {
+			if c.Equal(h.signer.Leaf) {
+				vapi.ApiErrorResourceInUse(w) // the active VMCA signing certificate
+				return
+			}
+		}
+		delete(h.roots, id)
+		vapi.StatusOK(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) signingCertificate(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.init(); err != nil {
+		vapi.ApiErrorGeneral(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, certificate.SigningCertificateInfo{
+			ActiveCertChain:   certificate.X509CertChain{CertChain: encodeChain(h.signer.Certificate)},
+			SigningCertChains: h.signers,
+		})
+	case http.MethodPut:
+		var spec certificate.SigningCertificateSetSpec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+
+		cert, err := keyPair(strings.Join(spec.SigningCertChain.CertChain, "\n"), spec.PrivateKey, nil)
+		if err != nil || !cert.Leaf.IsCA {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+
+		h.setSigner(cert)
+		vapi.StatusOK(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) vmcaRoot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.NotFound(w, r)
+		return
+	}
+
+	var spec certificate.CSRSpec
+	if !vapi.Decode(r, w, &spec) {
+		return
+	}
+
+	name := subject(spec)
+	if name.CommonName == "" {
+		name.CommonName = "CA"
+	}
+
+	cert, err := newRoot(name, spec.KeySize)
+	if err != nil {
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.init(); err != nil {
+		vapi.ApiErrorGeneral(w)
+		return
+	}
+
+	h.setSigner(cert)
+	vapi.StatusOK(w)
+}
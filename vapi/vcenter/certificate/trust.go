@@ -0,0 +1,85 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package certificate
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// X509CertChain is a certificate chain, ordered from leaf to root.
+type X509CertChain struct {
+	CertChain []string `json:"cert_chain"`
+}
+
+// TrustedRootChainSummary identifies a trusted root chain.
+type TrustedRootChainSummary struct {
+	Chain string `json:"chain"`
+}
+
+// TrustedRootChainInfo contains a trusted root chain.
+type TrustedRootChainInfo struct {
+	CertChain X509CertChain `json:"cert_chain"`
+}
+
+// TrustedRootChainCreateSpec is used to add a trusted root chain.
+// If Chain is empty, vCenter generates the chain identifier.
+type TrustedRootChainCreateSpec struct {
+	CertChain X509CertChain `json:"cert_chain"`
+	Chain     string        `json:"chain,omitempty"`
+}
+
+// ListTrustedRootChains returns the identifiers of the vCenter trusted root chains.
+func (c *Manager) ListTrustedRootChains(ctx context.Context) ([]TrustedRootChainSummary, error) {
+	url := c.Resource(TrustedRootChainsPath)
+	var res []TrustedRootChainSummary
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// GetTrustedRootChain returns the certificate chain with the given identifier.
+func (c *Manager) GetTrustedRootChain(ctx context.Context, chain string) (*TrustedRootChainInfo, error) {
+	url := c.Resource(TrustedRootChainsPath).WithSubpath(url.PathEscape(chain))
+	var res TrustedRootChainInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// CreateTrustedRootChain adds a certificate chain to the vCenter trust store, returning its identifier.
+func (c *Manager) CreateTrustedRootChain(ctx context.Context, spec TrustedRootChainCreateSpec) (string, error) {
+	url := c.Resource(TrustedRootChainsPath)
+	var res string
+	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+}
+
+// DeleteTrustedRootChain removes the certificate chain with the given identifier from the vCenter trust store.
+func (c *Manager) DeleteTrustedRootChain(ctx context.Context, chain string) error {
+	url := c.Resource(TrustedRootChainsPath).WithSubpath(url.PathEscape(chain))
+	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+}
+
+// SigningCertificateInfo contains the VMCA signing certificate chains.
+type SigningCertificateInfo struct {
+	ActiveCertChain   X509CertChain   `json:"active_cert_chain"`
+	SigningCertChains []X509CertChain `json:"signing_cert_chains"`
+}
+
+// SigningCertificateSetSpec is used to replace the VMCA signing certificate.
+type SigningCertificateSetSpec struct {
+	SigningCertChain X509CertChain `json:"signing_cert_chain"`
+	PrivateKey       string        `json:"private_key"`
+}
+
+// GetSigningCertificate returns the VMCA signing certificate chains.
+func (c *Manager) GetSigningCertificate(ctx context.Context) (*SigningCertificateInfo, error) {
+	url := c.Resource(SigningCertificatePath)
+	var res SigningCertificateInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// SetSigningCertificate replaces the VMCA signing certificate.
+func (c *Manager) SetSigningCertificate(ctx context.Context, spec SigningCertificateSetSpec) error {
+	url := c.Resource(SigningCertificatePath)
+	return c.Do(ctx, url.Request(http.MethodPut, spec), nil)
+}
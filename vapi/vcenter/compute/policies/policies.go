@@ -0,0 +1,148 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package policies
+
+import (
+	"context"
+	"net/http"
+	"path"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// vCenter compute policies REST endpoints
+const (
+	Path             = "/api/vcenter/compute/policies"
+	CapabilitiesPath = Path + "/capabilities"
+	VMPath           = "/api/vcenter/vm"
+)
+
+// Capabilities of compute policies.
+// Each capability constrains the placement of the VMs with the policy's VM tag attached.
+const (
+	// CapabilityVMHostAffinity keeps VMs on hosts with the policy's host tag attached.
+	CapabilityVMHostAffinity = "com.vmware.vcenter.compute.policies.capabilities.vm_host_affinity"
+	// CapabilityVMHostAntiAffinity keeps VMs off hosts with the policy's host tag attached.
+	CapabilityVMHostAntiAffinity = "com.vmware.vcenter.compute.policies.capabilities.vm_host_anti_affinity"
+	// CapabilityVMVMAffinity keeps VMs on the same host.
+	CapabilityVMVMAffinity = "com.vmware.vcenter.compute.policies.capabilities.vm_vm_affinity"
+	// CapabilityVMVMAntiAffinity keeps VMs on different hosts.
+	CapabilityVMVMAntiAffinity = "com.vmware.vcenter.compute.policies.capabilities.vm_vm_anti_affinity"
+	// CapabilityDisableDRSVMotion prevents DRS from migrating VMs for load balancing.
+	CapabilityDisableDRSVMotion = "com.vmware.vcenter.compute.policies.capabilities.disable_drs_vmotion"
+	// CapabilityVMEvacuation evacuates VMs from hosts that enter maintenance mode.
+	CapabilityVMEvacuation = "com.vmware.vcenter.compute.policies.capabilities.vm_evacuation"
+)
+
+// Manager extends rest.Client, adding vCenter compute policy related methods.
+//
+// See https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/compute/policies/
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// CreateSpec is the specification used to create a compute policy.
+// HostTag is required by the VM-host affinity and anti-affinity capabilities, other capabilities only use VMTag.
+type CreateSpec struct {
+	Capability  string `json:"capability"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	VMTag       string `json:"vm_tag"`
+	HostTag     string `json:"host_tag,omitempty"`
+}
+
+// Info contains information about a compute policy.
+type Info struct {
+	Capability  string `json:"capability"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	VMTag       string `json:"vm_tag,omitempty"`
+	HostTag     string `json:"host_tag,omitempty"`
+}
+
+// Summary contains commonly used information about a compute policy.
+type Summary struct {
+	Policy      string `json:"policy"`
+	Capability  string `json:"capability"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// CapabilitySummary contains commonly used information about a compute policy capability.
+type CapabilitySummary struct {
+	Capability  string `json:"capability"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ComplianceStatus of a VM with respect to a compute policy.
+type ComplianceStatus string
+
+const (
+	ComplianceStatusCompliant     = ComplianceStatus("COMPLIANT")
+	ComplianceStatusNotCompliant  = ComplianceStatus("NOT_COMPLIANT")
+	ComplianceStatusUnknown       = ComplianceStatus("UNKNOWN")
+	ComplianceStatusNotApplicable = ComplianceStatus("NOT_APPLICABLE")
+)
+
+// VMComplianceInfo contains information about the compliance of a VM with a compute policy.
+type VMComplianceInfo struct {
+	Status ComplianceStatus `json:"status"`
+}
+
+// List returns the compute policies.
+func (c *Manager) List(ctx context.Context) ([]Summary, error) {
+	url := c.Resource(Path)
+	var res []Summary
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// Get returns information about the given compute policy.
+func (c *Manager) Get(ctx context.Context, policy string) (*Info, error) {
+	url := c.Resource(Path).WithSubpath(policy)
+	var res Info
+	if err := c.Do(ctx, url.Request(http.MethodGet), &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Create creates a compute policy, returning the ID of the new policy.
+func (c *Manager) Create(ctx context.Context, spec CreateSpec) (string, error) {
+	url := c.Resource(Path)
+	var res string
+	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+}
+
+// Delete deletes the given compute policy.
+func (c *Manager) Delete(ctx context.Context, policy string) error {
+	url := c.Resource(Path).WithSubpath(policy)
+	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+}
+
+// ListCapabilities returns the capabilities supported by compute policies.
+func (c *Manager) ListCapabilities(ctx context.Context) ([]CapabilitySummary, error) {
+	url := c.Resource(CapabilitiesPath)
+	var res []CapabilitySummary
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// GetVMCompliance returns the compliance of the given VM, such as "vm-42", with the given compute policy.
+// The status is NOT_APPLICABLE if the policy's VM tag is not attached to the VM.
+func (c *Manager) GetVMCompliance(ctx context.Context, vm, policy string) (*VMComplianceInfo, error) {
+	url := c.Resource(path.Join(VMPath, vm, "compute", "policies", policy))
+	var res VMComplianceInfo
+	if err := c.Do(ctx, url.Request(http.MethodGet), &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
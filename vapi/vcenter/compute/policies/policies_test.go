@@ -0,0 +1,134 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package policies_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vapi/vcenter/compute/policies"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestPolicies(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		require.NoError(t, c.Login(ctx, simulator.DefaultLogin))
+
+		m := policies.NewManager(c)
+		tm := tags.NewManager(c)
+		finder := find.NewFinder(vc)
+
+		caps, err := m.ListCapabilities(ctx)
+		require.NoError(t, err)
+		assert.Len(t, caps, 6)
+
+		cluster, err := finder.ClusterComputeResource(ctx, "DC0_C0")
+		require.NoError(t, err)
+		hosts, err := cluster.Hosts(ctx)
+		require.NoError(t, err)
+		vms, err := finder.VirtualMachineList(ctx, "DC0_C0_RP0_VM*")
+		require.NoError(t, err)
+		require.Len(t, vms, 2)
+		vm := vms[0]
+
+		current, err := vm.HostSystem(ctx)
+		require.NoError(t, err)
+		var target types.ManagedObjectReference
+		for _, host := range hosts {
+			if host.Reference() != current.Reference() {
+				target = host.Reference()
+				break
+			}
+		}
+
+		category, err := tm.CreateCategory(ctx, &tags.Category{Name: "placement", Cardinality: "MULTIPLE"})
+		require.NoError(t, err)
+		hostTag, err := tm.CreateTag(ctx, &tags.Tag{Name: "gold-hosts", CategoryID: category})
+		require.NoError(t, err)
+		vmTag, err := tm.CreateTag(ctx, &tags.Tag{Name: "gold-vms", CategoryID: category})
+		require.NoError(t, err)
+		require.NoError(t, tm.AttachTag(ctx, hostTag, target))
+		require.NoError(t, tm.AttachTag(ctx, vmTag, vm.Reference()))
+
+		spec := policies.CreateSpec{
+			Capability: policies.CapabilityVMHostAffinity,
+			Name:       "gold",
+			VMTag:      vmTag,
+		}
+		_, err = m.Create(ctx, spec)
+		assert.Error(t, err, "host tag is required")
+
+		spec.HostTag = hostTag
+		id, err := m.Create(ctx, spec)
+		require.NoError(t, err)
+
+		_, err = m.Create(ctx, spec)
+		assert.Error(t, err, "duplicate name")
+
+		list, err := m.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, id, list[0].Policy)
+
+		info, err := m.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, hostTag, info.HostTag)
+
+		status, err := m.GetVMCompliance(ctx, vm.Reference().Value, id)
+		require.NoError(t, err)
+		assert.Equal(t, policies.ComplianceStatusNotCompliant, status.Status)
+
+		status, err = m.GetVMCompliance(ctx, vms[1].Reference().Value, id)
+		require.NoError(t, err)
+		assert.Equal(t, policies.ComplianceStatusNotApplicable, status.Status)
+
+		// DRS placement moves the VM to a host with the host tag
+		res, err := cluster.PlaceVm(ctx, types.PlacementSpec{
+			Vm:            types.NewReference(vm.Reference()),
+			PlacementType: string(types.PlacementSpecPlacementTypeRelocate),
+		})
+		require.NoError(t, err)
+		action := res.Recommendations[0].Action[0].(*types.PlacementAction)
+		assert.Equal(t, target, *action.TargetHost)
+
+		task, err := vm.Relocate(ctx, *action.RelocateSpec, types.VirtualMachineMovePriorityDefaultPriority)
+		require.NoError(t, err)
+		require.NoError(t, task.Wait(ctx))
+
+		status, err = m.GetVMCompliance(ctx, vm.Reference().Value, id)
+		require.NoError(t, err)
+		assert.Equal(t, policies.ComplianceStatusCompliant, status.Status)
+
+		// no host satisfies both affinity and anti-affinity
+		_, err = m.Create(ctx, policies.CreateSpec{
+			Capability: policies.CapabilityVMHostAntiAffinity,
+			Name:       "not-gold",
+			VMTag:      vmTag,
+			HostTag:    hostTag,
+		})
+		require.NoError(t, err)
+
+		_, err = cluster.PlaceVm(ctx, types.PlacementSpec{
+			Vm:            types.NewReference(vm.Reference()),
+			PlacementType: string(types.PlacementSpecPlacementTypeRelocate),
+		})
+		assert.Error(t, err)
+
+		require.NoError(t, m.Delete(ctx, id))
+		_, err = m.Get(ctx, id)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,346 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// vCenter inventory REST endpoints
+const (
+	ClusterPath      = "/api/vcenter/cluster"
+	DatacenterPath   = "/api/vcenter/datacenter"
+	DatastorePath    = "/api/vcenter/datastore"
+	FolderPath       = "/api/vcenter/folder"
+	HostPath         = "/api/vcenter/host"
+	NetworkPath      = "/api/vcenter/network"
+	ResourcePoolPath = "/api/vcenter/resource-pool"
+)
+
+// Manager extends rest.Client, adding vCenter inventory list methods.
+//
+// Inventory objects are identified by their managed object ID, for example "host-21".
+// Each filter field that is not empty must match, within a field any of the values may match.
+// vCenter limits the number of objects returned by the list methods, see the API reference for details.
+//
+// See https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// query is used to encode a filter spec as URL query parameters
+type query url.Values
+
+func (q query) add(name string, values ...string) {
+	for _, v := range values {
+		q[name] = append(q[name], v)
+	}
+}
+
+func (q query) bool(name string, value *bool) {
+	if value != nil {
+		q.add(name, strconv.FormatBool(*value))
+	}
+}
+
+func (c *Manager) list(ctx context.Context, path string, q query, res any) error {
+	url := c.Resource(path)
+	for name, values := range q {
+		for _, v := range values {
+			url.WithParam(name, v)
+		}
+	}
+	return c.Do(ctx, url.Request(http.MethodGet), res)
+}
+
+// HostConnectionState of a host.
+type HostConnectionState string
+
+const (
+	HostConnectionStateConnected     = HostConnectionState("CONNECTED")
+	HostConnectionStateDisconnected  = HostConnectionState("DISCONNECTED")
+	HostConnectionStateNotResponding = HostConnectionState("NOT_RESPONDING")
+)
+
+// HostPowerState of a host.
+type HostPowerState string
+
+const (
+	HostPowerStatePoweredOn  = HostPowerState("POWERED_ON")
+	HostPowerStatePoweredOff = HostPowerState("POWERED_OFF")
+	HostPowerStateStandby    = HostPowerState("STANDBY")
+)
+
+// HostFilterSpec contains properties used to filter the results when listing hosts.
+type HostFilterSpec struct {
+	Hosts            []string              `json:"hosts,omitempty"`
+	Names            []string              `json:"names,omitempty"`
+	Folders          []string              `json:"folders,omitempty"`
+	Datacenters      []string              `json:"datacenters,omitempty"`
+	Standalone       *bool                 `json:"standalone,omitempty"`
+	Clusters         []string              `json:"clusters,omitempty"`
+	ConnectionStates []HostConnectionState `json:"connection_states,omitempty"`
+}
+
+// HostSummary contains commonly used information about a host.
+type HostSummary struct {
+	Host            string              `json:"host"`
+	Name            string              `json:"name"`
+	ConnectionState HostConnectionState `json:"connection_state"`
+	PowerState      HostPowerState      `json:"power_state,omitempty"`
+}
+
+// ListHosts returns information about the hosts matching the filter, or all hosts if filter is nil.
+func (c *Manager) ListHosts(ctx context.Context, filter *HostFilterSpec) ([]HostSummary, error) {
+	q := query{}
+	if filter != nil {
+		q.add("hosts", filter.Hosts...)
+		q.add("names", filter.Names...)
+		q.add("folders", filter.Folders...)
+		q.add("datacenters", filter.Datacenters...)
+		q.bool("standalone", filter.Standalone)
+		q.add("clusters", filter.Clusters...)
+		for _, s := range filter.ConnectionStates {
+			q.add("connection_states", string(s))
+		}
+	}
+
+	var res []HostSummary
+	return res, c.list(ctx, HostPath, q, &res)
+}
+
+// DatastoreType is the type of a datastore.
+type DatastoreType string
+
+const (
+	DatastoreTypeVMFS  = DatastoreType("VMFS")
+	DatastoreTypeNFS   = DatastoreType("NFS")
+	DatastoreTypeNFS41 = DatastoreType("NFS41")
+	DatastoreTypeCIFS  = DatastoreType("CIFS")
+	DatastoreTypeVSAN  = DatastoreType("VSAN")
+	DatastoreTypeVFFS  = DatastoreType("VFFS")
+	DatastoreTypeVVOL  = DatastoreType("VVOL")
+)
+
+// DatastoreFilterSpec contains properties used to filter the results when listing datastores.
+type DatastoreFilterSpec struct {
+	Datastores  []string        `json:"datastores,omitempty"`
+	Names       []string        `json:"names,omitempty"`
+	Types       []DatastoreType `json:"types,omitempty"`
+	Folders     []string        `json:"folders,omitempty"`
+	Datacenters []string        `json:"datacenters,omitempty"`
+}
+
+// DatastoreSummary contains commonly used information about a datastore.
+type DatastoreSummary struct {
+	Datastore string        `json:"datastore"`
+	Name      string        `json:"name"`
+	Type      DatastoreType `json:"type"`
+	FreeSpace int64         `json:"free_space,omitempty"`
+	Capacity  int64         `json:"capacity,omitempty"`
+}
+
+// ListDatastores returns information about the datastores matching the filter, or all datastores if filter is nil.
+func (c *Manager) ListDatastores(ctx context.Context, filter *DatastoreFilterSpec) ([]DatastoreSummary, error) {
+	q := query{}
+	if filter != nil {
+		q.add("datastores", filter.Datastores...)
+		q.add("names", filter.Names...)
+		for _, t := range filter.Types {
+			q.add("types", string(t))
+		}
+		q.add("folders", filter.Folders...)
+		q.add("datacenters", filter.Datacenters...)
+	}
+
+	var res []DatastoreSummary
+	return res, c.list(ctx, DatastorePath, q, &res)
+}
+
+// NetworkType is the type of a network.
+type NetworkType string
+
+const (
+	NetworkTypeStandardPortgroup    = NetworkType("STANDARD_PORTGROUP")
+	NetworkTypeDistributedPortgroup = NetworkType("DISTRIBUTED_PORTGROUP")
+	NetworkTypeOpaqueNetwork        = NetworkType("OPAQUE_NETWORK")
+)
+
+// NetworkFilterSpec contains properties used to filter the results when listing networks.
+type NetworkFilterSpec struct {
+	Networks    []string      `json:"networks,omitempty"`
+	Names       []string      `json:"names,omitempty"`
+	Types       []NetworkType `json:"types,omitempty"`
+	Folders     []string      `json:"folders,omitempty"`
+	Datacenters []string      `json:"datacenters,omitempty"`
+}
+
+// NetworkSummary contains commonly used information about a network.
+type NetworkSummary struct {
+	Network string      `json:"network"`
+	Name    string      `json:"name"`
+	Type    NetworkType `json:"type"`
+}
+
+// ListNetworks returns information about the networks matching the filter, or all networks if filter is nil.
+func (c *Manager) ListNetworks(ctx context.Context, filter *NetworkFilterSpec) ([]NetworkSummary, error) {
+	q := query{}
+	if filter != nil {
+		q.add("networks", filter.Networks...)
+		q.add("names", filter.Names...)
+		for _, t := range filter.Types {
+			q.add("types", string(t))
+		}
+		q.add("folders", filter.Folders...)
+		q.add("datacenters", filter.Datacenters...)
+	}
+
+	var res []NetworkSummary
+	return res, c.list(ctx, NetworkPath, q, &res)
+}
+
+// ClusterFilterSpec contains properties used to filter the results when listing clusters.
+type ClusterFilterSpec struct {
+	Clusters    []string `json:"clusters,omitempty"`
+	Names       []string `json:"names,omitempty"`
+	Folders     []string `json:"folders,omitempty"`
+	Datacenters []string `json:"datacenters,omitempty"`
+}
+
+// ClusterSummary contains commonly used information about a cluster.
+type ClusterSummary struct {
+	Cluster    string `json:"cluster"`
+	Name       string `json:"name"`
+	HAEnabled  bool   `json:"ha_enabled"`
+	DRSEnabled bool   `json:"drs_enabled"`
+}
+
+// ListClusters returns information about the clusters matching the filter, or all clusters if filter is nil.
+func (c *Manager) ListClusters(ctx context.Context, filter *ClusterFilterSpec) ([]ClusterSummary, error) {
+	q := query{}
+	if filter != nil {
+		q.add("clusters", filter.Clusters...)
+		q.add("names", filter.Names...)
+		q.add("folders", filter.Folders...)
+		q.add("datacenters", filter.Datacenters...)
+	}
+
+	var res []ClusterSummary
+	return res, c.list(ctx, ClusterPath, q, &res)
+}
+
+// DatacenterFilterSpec contains properties used to filter the results when listing datacenters.
+type DatacenterFilterSpec struct {
+	Datacenters []string `json:"datacenters,omitempty"`
+	Names       []string `json:"names,omitempty"`
+	Folders     []string `json:"folders,omitempty"`
+}
+
+// DatacenterSummary contains commonly used information about a datacenter.
+type DatacenterSummary struct {
+	Datacenter string `json:"datacenter"`
+	Name       string `json:"name"`
+}
+
+// ListDatacenters returns information about the datacenters matching the filter, or all datacenters if filter is nil.
+func (c *Manager) ListDatacenters(ctx context.Context, filter *DatacenterFilterSpec) ([]DatacenterSummary, error) {
+	q := query{}
+	if filter != nil {
+		q.add("datacenters", filter.Datacenters...)
+		q.add("names", filter.Names...)
+		q.add("folders", filter.Folders...)
+	}
+
+	var res []DatacenterSummary
+	return res, c.list(ctx, DatacenterPath, q, &res)
+}
+
+// FolderType is the type of objects a folder can contain.
+type FolderType string
+
+const (
+	FolderTypeDatacenter     = FolderType("DATACENTER")
+	FolderTypeDatastore      = FolderType("DATASTORE")
+	FolderTypeHost           = FolderType("HOST")
+	FolderTypeNetwork        = FolderType("NETWORK")
+	FolderTypeVirtualMachine = FolderType("VIRTUAL_MACHINE")
+)
+
+// FolderFilterSpec contains properties used to filter the results when listing folders.
+type FolderFilterSpec struct {
+	Folders       []string   `json:"folders,omitempty"`
+	Names         []string   `json:"names,omitempty"`
+	Type          FolderType `json:"type,omitempty"`
+	ParentFolders []string   `json:"parent_folders,omitempty"`
+	Datacenters   []string   `json:"datacenters,omitempty"`
+}
+
+// FolderSummary contains commonly used information about a folder.
+type FolderSummary struct {
+	Folder string     `json:"folder"`
+	Name   string     `json:"name"`
+	Type   FolderType `json:"type"`
+}
+
+// ListFolders returns information about the folders matching the filter, or all folders if filter is nil.
+func (c *Manager) ListFolders(ctx context.Context, filter *FolderFilterSpec) ([]FolderSummary, error) {
+	q := query{}
+	if filter != nil {
+		q.add("folders", filter.Folders...)
+		q.add("names", filter.Names...)
+		if filter.Type != "" {
+			q.add("type", string(filter.Type))
+		}
+		q.add("parent_folders", filter.ParentFolders...)
+		q.add("datacenters", filter.Datacenters...)
+	}
+
+	var res []FolderSummary
+	return res, c.list(ctx, FolderPath, q, &res)
+}
+
+// ResourcePoolFilterSpec contains properties used to filter the results when listing resource pools.
+type ResourcePoolFilterSpec struct {
+	ResourcePools       []string `json:"resource_pools,omitempty"`
+	Names               []string `json:"names,omitempty"`
+	ParentResourcePools []string `json:"parent_resource_pools,omitempty"`
+	Datacenters         []string `json:"datacenters,omitempty"`
+	Hosts               []string `json:"hosts,omitempty"`
+	Clusters            []string `json:"clusters,omitempty"`
+}
+
+// ResourcePoolSummary contains commonly used information about a resource pool.
+type ResourcePoolSummary struct {
+	ResourcePool string `json:"resource_pool"`
+	Name         string `json:"name"`
+}
+
+// ListResourcePools returns information about the resource pools matching the filter, or all resource pools if filter is nil.
+func (c *Manager) ListResourcePools(ctx context.Context, filter *ResourcePoolFilterSpec) ([]ResourcePoolSummary, error) {
+	q := query{}
+	if filter != nil {
+		q.add("resource_pools", filter.ResourcePools...)
+		q.add("names", filter.Names...)
+		q.add("parent_resource_pools", filter.ParentResourcePools...)
+		q.add("datacenters", filter.Datacenters...)
+		q.add("hosts", filter.Hosts...)
+		q.add("clusters", filter.Clusters...)
+	}
+
+	var res []ResourcePoolSummary
+	return res, c.list(ctx, ResourcePoolPath, q, &res)
+}
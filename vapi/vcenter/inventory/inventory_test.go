@@ -0,0 +1,90 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter/inventory"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+	_ "github.com/vmware/govmomi/vapi/vcenter/inventory/simulator"
+)
+
+func TestInventory(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		require.NoError(t, c.Login(ctx, simulator.DefaultLogin))
+
+		m := inventory.NewManager(c)
+		finder := find.NewFinder(vc)
+
+		dcs, err := m.ListDatacenters(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, dcs, 1)
+		dc := dcs[0].Datacenter
+		assert.Equal(t, "DC0", dcs[0].Name)
+
+		hosts, err := m.ListHosts(ctx, &inventory.HostFilterSpec{Datacenters: []string{dc}})
+		require.NoError(t, err)
+		assert.Len(t, hosts, 4)
+
+		hosts, err = m.ListHosts(ctx, &inventory.HostFilterSpec{Standalone: new(bool)})
+		require.NoError(t, err)
+		assert.Len(t, hosts, 3)
+		assert.Equal(t, inventory.HostConnectionStateConnected, hosts[0].ConnectionState)
+
+		clusters, err := m.ListClusters(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, clusters, 1)
+		assert.Equal(t, "DC0_C0", clusters[0].Name)
+
+		hosts, err = m.ListHosts(ctx, &inventory.HostFilterSpec{Clusters: []string{clusters[0].Cluster}})
+		require.NoError(t, err)
+		assert.Len(t, hosts, 3)
+
+		pools, err := m.ListResourcePools(ctx, &inventory.ResourcePoolFilterSpec{Clusters: []string{clusters[0].Cluster}})
+		require.NoError(t, err)
+		require.Len(t, pools, 1)
+		assert.Equal(t, "Resources", pools[0].Name)
+
+		pools, err = m.ListResourcePools(ctx, &inventory.ResourcePoolFilterSpec{Hosts: []string{hosts[0].Host}})
+		require.NoError(t, err)
+		assert.Len(t, pools, 1)
+
+		datastores, err := m.ListDatastores(ctx, &inventory.DatastoreFilterSpec{Names: []string{"LocalDS_0"}})
+		require.NoError(t, err)
+		require.Len(t, datastores, 1)
+		assert.NotZero(t, datastores[0].Capacity)
+
+		networks, err := m.ListNetworks(ctx, &inventory.NetworkFilterSpec{Types: []inventory.NetworkType{inventory.NetworkTypeDistributedPortgroup}})
+		require.NoError(t, err)
+		names := make([]string, len(networks))
+		for i := range networks {
+			names[i] = networks[i].Name
+		}
+		assert.Contains(t, names, "DC0_DVPG0")
+		assert.NotContains(t, names, "VM Network")
+
+		folders, err := m.ListFolders(ctx, &inventory.FolderFilterSpec{Type: inventory.FolderTypeVirtualMachine})
+		require.NoError(t, err)
+		require.Len(t, folders, 1)
+		vmFolder, err := finder.Folder(ctx, "/DC0/vm")
+		require.NoError(t, err)
+		assert.Equal(t, vmFolder.Reference().Value, folders[0].Folder)
+
+		folders, err = m.ListFolders(ctx, &inventory.FolderFilterSpec{Names: []string{"enoent"}})
+		require.NoError(t, err)
+		assert.Empty(t, folders)
+	})
+}
@@ -0,0 +1,367 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/vmware/govmomi/simulator"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vapi/vcenter/inventory"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func init() {
+	simulator.RegisterEndpoint(func(s *simulator.Service, r *simulator.Registry) {
+		New(s.Listen).Register(s, r)
+	})
+}
+
+// Handler implements the vCenter inventory list API simulator,
+// answering from the objects in the simulator.Registry.
+type Handler struct {
+	URL      *url.URL
+	registry *simulator.Registry
+}
+
+// New creates a Handler instance
+func New(u *url.URL) *Handler {
+	return &Handler{
+		URL: u,
+	}
+}
+
+// Register vCenter inventory API paths with the simulator's http.ServeMux
+func (h *Handler) Register(s *simulator.Service, r *simulator.Registry) {
+	if r.IsVPX() {
+		h.registry = r
+
+		handlers := []struct {
+			p string
+			m http.HandlerFunc
+		}{
+			{inventory.ClusterPath, h.clusters},
+			{inventory.DatacenterPath, h.datacenters},
+			{inventory.DatastorePath, h.datastores},
+			{inventory.FolderPath, h.folders},
+			{inventory.HostPath, h.hosts},
+			{inventory.NetworkPath, h.networks},
+			{inventory.ResourcePoolPath, h.resourcePools},
+		}
+
+		for i := range handlers {
+			handler := handlers[i].m
+			s.HandleFunc(handlers[i].p, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					http.NotFound(w, r)
+					return
+				}
+				handler(w, r)
+			})
+		}
+	}
+}
+
+// filter is the list API filter spec, encoded as URL query parameters.
+type filter url.Values
+
+// match returns true if the filter param is not specified, or if any of the given values are specified in the filter.
+func (f filter) match(param string, values ...string) bool {
+	spec := f[param]
+	if len(spec) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if slices.Contains(spec, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// each invokes f with each entity of the given kind, sorted by ID, while holding the entity lock.
+func (h *Handler) each(kind string, f func(mo.Entity)) {
+	ctx := &simulator.Context{
+		Context: context.Background(),
+		Session: &simulator.Session{
+			UserSession: types.UserSession{
+				Key: uuid.New().String(),
+			},
+			Registry: h.registry,
+		},
+		Map: h.registry,
+	}
+
+	entities := h.registry.All(kind)
+	slices.SortFunc(entities, func(a, b mo.Entity) int {
+		return strings.Compare(a.Reference().Value, b.Reference().Value)
+	})
+
+	for _, e := range entities {
+		h.registry.WithLock(ctx, e, func() {
+			f(e)
+		})
+	}
+}
+
+func (h *Handler) hosts(w http.ResponseWriter, r *http.Request) {
+	f := filter(r.URL.Query())
+	res := []inventory.HostSummary{}
+
+	h.each("HostSystem", func(e mo.Entity) {
+		host := e.(*simulator.HostSystem)
+		parents := vapi.Ancestors(h.registry, host)
+		standalone := host.Parent.Type == "ComputeResource"
+
+		var cluster string
+		if !standalone {
+			cluster = host.Parent.Value
+		}
+
+		state := inventory.HostConnectionStateConnected
+		switch host.Runtime.ConnectionState {
+		case types.HostSystemConnectionStateDisconnected:
+			state = inventory.HostConnectionStateDisconnected
+		case types.HostSystemConnectionStateNotResponding:
+			state = inventory.HostConnectionStateNotResponding
+		}
+
+		power := inventory.HostPowerStatePoweredOn
+		switch host.Runtime.PowerState {
+		case types.HostSystemPowerStatePoweredOff:
+			power = inventory.HostPowerStatePoweredOff
+		case types.HostSystemPowerStateStandBy:
+			power = inventory.HostPowerStateStandby
+		}
+
+		if f.match("hosts", host.Self.Value) &&
+			f.match("names", host.Name) &&
+			f.match("folders", parents...) &&
+			f.match("datacenters", parents...) &&
+			f.match("standalone", strconv.FormatBool(standalone)) &&
+			f.match("clusters", cluster) &&
+			f.match("connection_states", string(state)) {
+			res = append(res, inventory.HostSummary{
+				Host:            host.Self.Value,
+				Name:            host.Name,
+				ConnectionState: state,
+				PowerState:      power,
+			})
+		}
+	})
+
+	vapi.StatusOK(w, res)
+}
+
+func (h *Handler) datastores(w http.ResponseWriter, r *http.Request) {
+	f := filter(r.URL.Query())
+	res := []inventory.DatastoreSummary{}
+
+	h.each("Datastore", func(e mo.Entity) {
+		ds := e.(*simulator.Datastore)
+		parents := vapi.Ancestors(h.registry, ds)
+		kind := inventory.DatastoreType(strings.ToUpper(ds.Summary.Type))
+
+		if f.match("datastores", ds.Self.Value) &&
+			f.match("names", ds.Name) &&
+			f.match("types", string(kind)) &&
+			f.match("folders", parents...) &&
+			f.match("datacenters", parents...) {
+			res = append(res, inventory.DatastoreSummary{
+				Datastore: ds.Self.Value,
+				Name:      ds.Name,
+				Type:      kind,
+				FreeSpace: ds.Summary.FreeSpace,
+				Capacity:  ds.Summary.Capacity,
+			})
+		}
+	})
+
+	vapi.StatusOK(w, res)
+}
+
+func (h *Handler) networks(w http.ResponseWriter, r *http.Request) {
+	f := filter(r.URL.Query())
+	res := []inventory.NetworkSummary{}
+
+	kinds := []struct {
+		kind string
+		typ  inventory.NetworkType
+	}{
+		{"Network", inventory.NetworkTypeStandardPortgroup},
+		{"DistributedVirtualPortgroup", inventory.NetworkTypeDistributedPortgroup},
+		{"OpaqueNetwork", inventory.NetworkTypeOpaqueNetwork},
+	}
+
+	for _, k := range kinds {
+		h.each(k.kind, func(e mo.Entity) {
+			net := e.Entity()
+			parents := vapi.Ancestors(h.registry, e)
+
+			if f.match("networks", net.Self.Value) &&
+				f.match("names", net.Name) &&
+				f.match("types", string(k.typ)) &&
+				f.match("folders", parents...) &&
+				f.match("datacenters", parents...) {
+				res = append(res, inventory.NetworkSummary{
+					Network: net.Self.Value,
+					Name:    net.Name,
+					Type:    k.typ,
+				})
+			}
+		})
+	}
+
+	vapi.StatusOK(w, res)
+}
+
+func (h *Handler) clusters(w http.ResponseWriter, r *http.Request) {
+	f := filter(r.URL.Query())
+	res := []inventory.ClusterSummary{}
+
+	h.each("ClusterComputeResource", func(e mo.Entity) {
+		cluster := e.(*simulator.ClusterComputeResource)
+		parents := vapi.Ancestors(h.registry, cluster)
+
+		if f.match("clusters", cluster.Self.Value) &&
+			f.match("names", cluster.Name) &&
+			f.match("folders", parents...) &&
+			f.match("datacenters", parents...) {
+			summary := inventory.ClusterSummary{
+				Cluster: cluster.Self.Value,
+				Name:    cluster.Name,
+			}
+			if config, ok := cluster.ConfigurationEx.(*types.ClusterConfigInfoEx); ok {
+				summary.HAEnabled = config.DasConfig.Enabled != nil && *config.DasConfig.Enabled
+				summary.DRSEnabled = config.DrsConfig.Enabled != nil && *config.DrsConfig.Enabled
+			}
+			res = append(res, summary)
+		}
+	})
+
+	vapi.StatusOK(w, res)
+}
+
+func (h *Handler) datacenters(w http.ResponseWriter, r *http.Request) {
+	f := filter(r.URL.Query())
+	res := []inventory.DatacenterSummary{}
+
+	h.each("Datacenter", func(e mo.Entity) {
+		dc := e.Entity()
+
+		if f.match("datacenters", dc.Self.Value) &&
+			f.match("names", dc.Name) &&
+			f.match("folders", vapi.Ancestors(h.registry, e)...) {
+			res = append(res, inventory.DatacenterSummary{
+				Datacenter: dc.Self.Value,
+				Name:       dc.Name,
+			})
+		}
+	})
+
+	vapi.StatusOK(w, res)
+}
+
+func folderType(childType []string) inventory.FolderType {
+	for _, kind := range childType {
+		switch kind {
+		case "Datacenter":
+			return inventory.FolderTypeDatacenter
+		case "ComputeResource":
+			return inventory.FolderTypeHost
+		case "Datastore":
+			return inventory.FolderTypeDatastore
+		case "Network":
+			return inventory.FolderTypeNetwork
+		case "VirtualMachine":
+			return inventory.FolderTypeVirtualMachine
+		}
+	}
+	return ""
+}
+
+func (h *Handler) folders(w http.ResponseWriter, r *http.Request) {
+	f := filter(r.URL.Query())
+	res := []inventory.FolderSummary{}
+
+	h.each("Folder", func(e mo.Entity) {
+		folder := e.(*simulator.Folder)
+		parents := vapi.Ancestors(h.registry, folder)
+		kind := folderType(folder.ChildType)
+
+		var parent string
+		if folder.Parent != nil && folder.Parent.Type == "Folder" {
+			parent = folder.Parent.Value
+		}
+
+		if f.match("folders", folder.Self.Value) &&
+			f.match("names", folder.Name) &&
+			f.match("type", string(kind)) &&
+			f.match("parent_folders", parent) &&
+			f.match("datacenters", parents...) {
+			res = append(res, inventory.FolderSummary{
+				Folder: folder.Self.Value,
+				Name:   folder.Name,
+				Type:   kind,
+			})
+		}
+	})
+
+	vapi.StatusOK(w, res)
+}
+
+func (h *Handler) resourcePools(w http.ResponseWriter, r *http.Request) {
+	f := filter(r.URL.Query())
+	res := []inventory.ResourcePoolSummary{}
+
+	h.each("ResourcePool", func(e mo.Entity) {
+		pool := e.(*simulator.ResourcePool)
+		parents := vapi.Ancestors(h.registry, pool)
+
+		var parent, cluster string
+		if pool.Parent != nil && pool.Parent.Type == "ResourcePool" {
+			parent = pool.Parent.Value
+		}
+		if pool.Owner.Type == "ClusterComputeResource" {
+			cluster = pool.Owner.Value
+		}
+
+		var hosts []string
+		if owner, ok := h.registry.Get(pool.Owner).(mo.Entity); ok {
+			switch cr := owner.(type) {
+			case *simulator.ClusterComputeResource:
+				for _, host := range cr.Host {
+					hosts = append(hosts, host.Value)
+				}
+			case *mo.ComputeResource:
+				for _, host := range cr.Host {
+					hosts = append(hosts, host.Value)
+				}
+			}
+		}
+
+		if f.match("resource_pools", pool.Self.Value) &&
+			f.match("names", pool.Name) &&
+			f.match("parent_resource_pools", parent) &&
+			f.match("datacenters", parents...) &&
+			f.match("hosts", hosts...) &&
+			f.match("clusters", cluster) {
+			res = append(res, inventory.ResourcePoolSummary{
+				ResourcePool: pool.Self.Value,
+				Name:         pool.Name,
+			})
+		}
+	})
+
+	vapi.StatusOK(w, res)
+}
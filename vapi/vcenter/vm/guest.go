@@ -0,0 +1,195 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package vm
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// LocalizableMessage is a message with an identifier and arguments for localization.
+type LocalizableMessage struct {
+	ID             string   `json:"id"`
+	DefaultMessage string   `json:"default_message"`
+	Args           []string `json:"args"`
+}
+
+// GuestIdentity contains information about the guest operating system, as reported by VMware Tools.
+type GuestIdentity struct {
+	OS        string             `json:"os"`
+	Family    string             `json:"family"`
+	Name      string             `json:"name"`
+	FullName  LocalizableMessage `json:"full_name"`
+	HostName  string             `json:"host_name"`
+	IPAddress string             `json:"ip_address,omitempty"`
+}
+
+// GetGuestIdentity returns the guest identity of the given virtual machine.
+// Requires VMware Tools to be running in the guest.
+func (c *Manager) GetGuestIdentity(ctx context.Context, vm string) (*GuestIdentity, error) {
+	url := c.resource(vm, "guest", "identity")
+	var res GuestIdentity
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// DNSValues of the guest operating system.
+type DNSValues struct {
+	DomainName string `json:"domain_name,omitempty"`
+	HostName   string `json:"host_name,omitempty"`
+}
+
+// DNSConfig of the guest operating system.
+type DNSConfig struct {
+	IPAddresses   []string `json:"ip_addresses"`
+	SearchDomains []string `json:"search_domains"`
+}
+
+// GuestNetworking contains the network configuration of the guest operating system.
+type GuestNetworking struct {
+	DNSValues *DNSValues `json:"dns_values,omitempty"`
+	DNS       *DNSConfig `json:"dns,omitempty"`
+}
+
+// IPAddressInfo of a guest network interface.
+type IPAddressInfo struct {
+	IPAddress    string `json:"ip_address"`
+	PrefixLength int32  `json:"prefix_length"`
+	Origin       string `json:"origin,omitempty"`
+	State        string `json:"state"`
+}
+
+// IPConfig of a guest network interface.
+type IPConfig struct {
+	IPAddresses []IPAddressInfo `json:"ip_addresses"`
+}
+
+// GuestInterface contains information about a guest network interface.
+// Nic is the identifier of the virtual Ethernet adapter, if the interface is backed by one.
+type GuestInterface struct {
+	Nic        string     `json:"nic,omitempty"`
+	MacAddress string     `json:"mac_address,omitempty"`
+	DNS        *DNSConfig `json:"dns,omitempty"`
+	IP         *IPConfig  `json:"ip,omitempty"`
+}
+
+// GetGuestNetworking returns the network configuration of the guest operating system.
+// Requires VMware Tools to be running in the guest.
+func (c *Manager) GetGuestNetworking(ctx context.Context, vm string) (*GuestNetworking, error) {
+	url := c.resource(vm, "guest", "networking")
+	var res GuestNetworking
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// ListGuestInterfaces returns the network interfaces of the guest operating system.
+// Requires VMware Tools to be running in the guest.
+func (c *Manager) ListGuestInterfaces(ctx context.Context, vm string) ([]GuestInterface, error) {
+	url := c.resource(vm, "guest", "networking", "interfaces")
+	var res []GuestInterface
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// HostnameGenerator types.
+const (
+	HostnameFixed          = "FIXED"
+	HostnamePrefix         = "PREFIX"
+	HostnameVirtualMachine = "VIRTUAL_MACHINE"
+)
+
+// HostnameGenerator describes how the guest host name is generated.
+type HostnameGenerator struct {
+	Type      string `json:"type"`
+	FixedName string `json:"fixed_name,omitempty"`
+	Prefix    string `json:"prefix,omitempty"`
+}
+
+// LinuxConfiguration contains the Linux guest customization settings.
+type LinuxConfiguration struct {
+	Hostname *HostnameGenerator `json:"hostname,omitempty"`
+	Domain   string             `json:"domain"`
+	TimeZone string             `json:"time_zone,omitempty"`
+}
+
+// ConfigurationSpec contains the guest operating system specific customization settings.
+type ConfigurationSpec struct {
+	LinuxConfig *LinuxConfiguration `json:"linux_config,omitempty"`
+}
+
+// GlobalDNSSettings are applied to all network interfaces of the guest.
+type GlobalDNSSettings struct {
+	DNSSuffixList []string `json:"dns_suffix_list,omitempty"`
+	DNSServers    []string `json:"dns_servers,omitempty"`
+}
+
+// IPv4 address types.
+const (
+	IPv4DHCP   = "DHCP"
+	IPv4Static = "STATIC"
+)
+
+// IPv4 settings of a guest network interface.
+type IPv4 struct {
+	Type      string   `json:"type"`
+	IPAddress string   `json:"ip_address,omitempty"`
+	Prefix    int32    `json:"prefix,omitempty"`
+	Gateways  []string `json:"gateways,omitempty"`
+}
+
+// IPSettings of a guest network interface.
+type IPSettings struct {
+	IPv4 *IPv4 `json:"ipv4,omitempty"`
+}
+
+// InterfaceSettings of a guest network interface,
+// applied in the order of the virtual Ethernet adapters of the virtual machine.
+type InterfaceSettings struct {
+	MacAddress string     `json:"mac_address,omitempty"`
+	Adapter    IPSettings `json:"adapter"`
+}
+
+// CustomizationSpec contains the guest customization settings.
+type CustomizationSpec struct {
+	ConfigurationSpec ConfigurationSpec   `json:"configuration_spec"`
+	GlobalDNSSettings GlobalDNSSettings   `json:"global_DNS_settings"`
+	Interfaces        []InterfaceSettings `json:"interfaces"`
+}
+
+// CustomizationSetSpec describes the customization to apply at the next power on of the virtual machine.
+// Name refers to an existing customization specification, otherwise Spec is used.
+type CustomizationSetSpec struct {
+	Name string             `json:"name,omitempty"`
+	Spec *CustomizationSpec `json:"spec,omitempty"`
+}
+
+// Customization status values.
+const (
+	CustomizationIdle      = "IDLE"
+	CustomizationPending   = "PENDING"
+	CustomizationRunning   = "RUNNING"
+	CustomizationSucceeded = "SUCCEEDED"
+	CustomizationFailed    = "FAILED"
+)
+
+// CustomizationInfo contains the status of the guest customization of a virtual machine.
+type CustomizationInfo struct {
+	Status    string              `json:"status"`
+	Error     *LocalizableMessage `json:"error,omitempty"`
+	StartTime *time.Time          `json:"start_time,omitempty"`
+	EndTime   *time.Time          `json:"end_time,omitempty"`
+}
+
+// SetGuestCustomization applies the customization spec to the given powered off virtual machine.
+// The customization is performed by VMware Tools at the next power on.
+func (c *Manager) SetGuestCustomization(ctx context.Context, vm string, spec CustomizationSetSpec) error {
+	url := c.resource(vm, "guest", "customization")
+	return c.Do(ctx, url.Request(http.MethodPut, spec), nil)
+}
+
+// GetGuestCustomization returns the status of the guest customization of the given virtual machine.
+func (c *Manager) GetGuestCustomization(ctx context.Context, vm string) (*CustomizationInfo, error) {
+	url := c.resource(vm, "guest", "customization")
+	var res CustomizationInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
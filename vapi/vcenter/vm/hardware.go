@@ -0,0 +1,341 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package vm
+
+import (
+	"context"
+	"net/http"
+)
+
+// HardwareInfo contains the virtual hardware settings of a virtual machine.
+type HardwareInfo struct {
+	Version       string `json:"version"`
+	UpgradePolicy string `json:"upgrade_policy"`
+	UpgradeStatus string `json:"upgrade_status"`
+}
+
+// GetHardware returns the virtual hardware settings of the given virtual machine.
+func (c *Manager) GetHardware(ctx context.Context, vm string) (*HardwareInfo, error) {
+	url := c.resource(vm, "hardware")
+	var res HardwareInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// CPUInfo contains the CPU settings of a virtual machine.
+type CPUInfo struct {
+	Count            int32 `json:"count"`
+	CoresPerSocket   int32 `json:"cores_per_socket"`
+	HotAddEnabled    bool  `json:"hot_add_enabled"`
+	HotRemoveEnabled bool  `json:"hot_remove_enabled"`
+}
+
+// CPUUpdateSpec describes the updates to the CPU settings of a virtual machine.
+// The count can only be changed while the VM is powered on if the matching hot add or remove setting is enabled.
+type CPUUpdateSpec struct {
+	Count            *int32 `json:"count,omitempty"`
+	CoresPerSocket   *int32 `json:"cores_per_socket,omitempty"`
+	HotAddEnabled    *bool  `json:"hot_add_enabled,omitempty"`
+	HotRemoveEnabled *bool  `json:"hot_remove_enabled,omitempty"`
+}
+
+// GetCPU returns the CPU settings of the given virtual machine.
+func (c *Manager) GetCPU(ctx context.Context, vm string) (*CPUInfo, error) {
+	url := c.resource(vm, "hardware", "cpu")
+	var res CPUInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// UpdateCPU updates the CPU settings of the given virtual machine.
+func (c *Manager) UpdateCPU(ctx context.Context, vm string, spec CPUUpdateSpec) error {
+	url := c.resource(vm, "hardware", "cpu")
+	return c.Do(ctx, url.Request(http.MethodPatch, spec), nil)
+}
+
+// MemoryInfo contains the memory settings of a virtual machine.
+type MemoryInfo struct {
+	SizeMiB                int64  `json:"size_MiB"`
+	HotAddEnabled          bool   `json:"hot_add_enabled"`
+	HotAddIncrementSizeMiB *int64 `json:"hot_add_increment_size_MiB,omitempty"`
+	HotAddLimitMiB         *int64 `json:"hot_add_limit_MiB,omitempty"`
+}
+
+// MemoryUpdateSpec describes the updates to the memory settings of a virtual machine.
+// The size can only be increased while the VM is powered on if hot add is enabled.
+type MemoryUpdateSpec struct {
+	SizeMiB       *int64 `json:"size_MiB,omitempty"`
+	HotAddEnabled *bool  `json:"hot_add_enabled,omitempty"`
+}
+
+// GetMemory returns the memory settings of the given virtual machine.
+func (c *Manager) GetMemory(ctx context.Context, vm string) (*MemoryInfo, error) {
+	url := c.resource(vm, "hardware", "memory")
+	var res MemoryInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// UpdateMemory updates the memory settings of the given virtual machine.
+func (c *Manager) UpdateMemory(ctx context.Context, vm string, spec MemoryUpdateSpec) error {
+	url := c.resource(vm, "hardware", "memory")
+	return c.Do(ctx, url.Request(http.MethodPatch, spec), nil)
+}
+
+// HostBusAdapterType is the type of host bus adapter a virtual disk is attached to.
+type HostBusAdapterType string
+
+const (
+	HostBusAdapterTypeIDE  = HostBusAdapterType("IDE")
+	HostBusAdapterTypeSCSI = HostBusAdapterType("SCSI")
+	HostBusAdapterTypeSATA = HostBusAdapterType("SATA")
+	HostBusAdapterTypeNVME = HostBusAdapterType("NVME")
+)
+
+// IDEAddress of a device attached to an IDE adapter.
+type IDEAddress struct {
+	Primary bool `json:"primary"`
+	Master  bool `json:"master"`
+}
+
+// BusAddress of a device attached to a SCSI, SATA or NVMe adapter.
+type BusAddress struct {
+	Bus  int32 `json:"bus"`
+	Unit int32 `json:"unit"`
+}
+
+// BusAddressSpec is the address of a device to be attached to a SCSI, SATA or NVMe adapter.
+// If Unit is not specified, the first available unit number on the bus is used.
+type BusAddressSpec struct {
+	Bus  int32  `json:"bus"`
+	Unit *int32 `json:"unit,omitempty"`
+}
+
+// DiskBackingType of a virtual disk.
+type DiskBackingType string
+
+const (
+	DiskBackingTypeVMDKFile = DiskBackingType("VMDK_FILE")
+)
+
+// DiskBacking describes the backing of a virtual disk.
+type DiskBacking struct {
+	Type     DiskBackingType `json:"type"`
+	VMDKFile string          `json:"vmdk_file,omitempty"`
+}
+
+// DiskInfo contains information about a virtual disk.
+type DiskInfo struct {
+	Label    string             `json:"label"`
+	Type     HostBusAdapterType `json:"type"`
+	IDE      *IDEAddress        `json:"ide,omitempty"`
+	SCSI     *BusAddress        `json:"scsi,omitempty"`
+	SATA     *BusAddress        `json:"sata,omitempty"`
+	NVME     *BusAddress        `json:"nvme,omitempty"`
+	Backing  DiskBacking        `json:"backing"`
+	Capacity int64              `json:"capacity,omitempty"`
+}
+
+// DiskSummary identifies a virtual disk.
+type DiskSummary struct {
+	Disk string `json:"disk"`
+}
+
+// VMDKCreateSpec describes a new VMDK file to be created for a virtual disk.
+type VMDKCreateSpec struct {
+	Name     string `json:"name,omitempty"`
+	Capacity int64  `json:"capacity,omitempty"`
+}
+
+// DiskCreateSpec describes a virtual disk to be added to a virtual machine.
+// Either Backing, an existing VMDK file, or NewVMDK must be specified.
+type DiskCreateSpec struct {
+	Type    HostBusAdapterType `json:"type,omitempty"`
+	SCSI    *BusAddressSpec    `json:"scsi,omitempty"`
+	SATA    *BusAddressSpec    `json:"sata,omitempty"`
+	NVME    *BusAddressSpec    `json:"nvme,omitempty"`
+	Backing *DiskBacking       `json:"backing,omitempty"`
+	NewVMDK *VMDKCreateSpec    `json:"new_vmdk,omitempty"`
+}
+
+// DiskUpdateSpec describes the updates to a virtual disk.
+type DiskUpdateSpec struct {
+	Backing *DiskBacking `json:"backing,omitempty"`
+}
+
+// ListDisks returns the virtual disks of the given virtual machine.
+func (c *Manager) ListDisks(ctx context.Context, vm string) ([]DiskSummary, error) {
+	url := c.resource(vm, "hardware", "disk")
+	var res []DiskSummary
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// GetDisk returns information about the given virtual disk.
+func (c *Manager) GetDisk(ctx context.Context, vm string, disk string) (*DiskInfo, error) {
+	url := c.resource(vm, "hardware", "disk", disk)
+	var res DiskInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// CreateDisk adds a virtual disk to the given virtual machine, returning the disk identifier.
+func (c *Manager) CreateDisk(ctx context.Context, vm string, spec DiskCreateSpec) (string, error) {
+	url := c.resource(vm, "hardware", "disk")
+	var res string
+	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+}
+
+// UpdateDisk updates the given virtual disk.
+func (c *Manager) UpdateDisk(ctx context.Context, vm string, disk string, spec DiskUpdateSpec) error {
+	url := c.resource(vm, "hardware", "disk", disk)
+	return c.Do(ctx, url.Request(http.MethodPatch, spec), nil)
+}
+
+// DeleteDisk removes the given virtual disk from the virtual machine.
+// The backing VMDK file is not deleted.
+func (c *Manager) DeleteDisk(ctx context.Context, vm string, disk string) error {
+	url := c.resource(vm, "hardware", "disk", disk)
+	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+}
+
+// EthernetType is the emulation type of a virtual Ethernet adapter.
+type EthernetType string
+
+const (
+	EthernetTypeE1000   = EthernetType("E1000")
+	EthernetTypeE1000E  = EthernetType("E1000E")
+	EthernetTypePCNET32 = EthernetType("PCNET32")
+	EthernetTypeVMXNET  = EthernetType("VMXNET")
+	EthernetTypeVMXNET2 = EthernetType("VMXNET2")
+	EthernetTypeVMXNET3 = EthernetType("VMXNET3")
+)
+
+// MacAddressType of a virtual Ethernet adapter.
+type MacAddressType string
+
+const (
+	MacAddressTypeManual    = MacAddressType("MANUAL")
+	MacAddressTypeGenerated = MacAddressType("GENERATED")
+	MacAddressTypeAssigned  = MacAddressType("ASSIGNED")
+)
+
+// EthernetBackingType of a virtual Ethernet adapter.
+type EthernetBackingType string
+
+const (
+	EthernetBackingTypeStandardPortgroup    = EthernetBackingType("STANDARD_PORTGROUP")
+	EthernetBackingTypeDistributedPortgroup = EthernetBackingType("DISTRIBUTED_PORTGROUP")
+	EthernetBackingTypeOpaqueNetwork        = EthernetBackingType("OPAQUE_NETWORK")
+	EthernetBackingTypeHostDevice           = EthernetBackingType("HOST_DEVICE")
+)
+
+// ConnectionState of a removable device.
+type ConnectionState string
+
+const (
+	ConnectionStateConnected    = ConnectionState("CONNECTED")
+	ConnectionStateNotConnected = ConnectionState("NOT_CONNECTED")
+)
+
+// EthernetBackingInfo describes the backing of a virtual Ethernet adapter.
+type EthernetBackingInfo struct {
+	Type                  EthernetBackingType `json:"type"`
+	Network               string              `json:"network,omitempty"`
+	NetworkName           string              `json:"network_name,omitempty"`
+	DistributedSwitchUUID string              `json:"distributed_switch_uuid,omitempty"`
+	DistributedPort       string              `json:"distributed_port,omitempty"`
+	OpaqueNetworkType     string              `json:"opaque_network_type,omitempty"`
+	OpaqueNetworkID       string              `json:"opaque_network_id,omitempty"`
+	HostDevice            string              `json:"host_device,omitempty"`
+}
+
+// EthernetBackingSpec describes the backing of a virtual Ethernet adapter to be created or updated.
+// Network is the identifier of a standard portgroup, distributed portgroup or opaque network.
+type EthernetBackingSpec struct {
+	Type            EthernetBackingType `json:"type"`
+	Network         string              `json:"network,omitempty"`
+	DistributedPort string              `json:"distributed_port,omitempty"`
+}
+
+// EthernetInfo contains information about a virtual Ethernet adapter.
+type EthernetInfo struct {
+	Label             string              `json:"label"`
+	Type              EthernetType        `json:"type"`
+	MacType           MacAddressType      `json:"mac_type"`
+	MacAddress        string              `json:"mac_address,omitempty"`
+	PCISlotNumber     int32               `json:"pci_slot_number,omitempty"`
+	WakeOnLANEnabled  bool                `json:"wake_on_lan_enabled"`
+	Backing           EthernetBackingInfo `json:"backing"`
+	State             ConnectionState     `json:"state"`
+	StartConnected    bool                `json:"start_connected"`
+	AllowGuestControl bool                `json:"allow_guest_control"`
+}
+
+// EthernetSummary identifies a virtual Ethernet adapter.
+type EthernetSummary struct {
+	Nic string `json:"nic"`
+}
+
+// EthernetCreateSpec describes a virtual Ethernet adapter to be added to a virtual machine.
+type EthernetCreateSpec struct {
+	Type              EthernetType         `json:"type,omitempty"`
+	MacType           MacAddressType       `json:"mac_type,omitempty"`
+	MacAddress        string               `json:"mac_address,omitempty"`
+	WakeOnLANEnabled  *bool                `json:"wake_on_lan_enabled,omitempty"`
+	Backing           *EthernetBackingSpec `json:"backing,omitempty"`
+	StartConnected    *bool                `json:"start_connected,omitempty"`
+	AllowGuestControl *bool                `json:"allow_guest_control,omitempty"`
+}
+
+// EthernetUpdateSpec describes the updates to a virtual Ethernet adapter.
+type EthernetUpdateSpec struct {
+	MacType           MacAddressType       `json:"mac_type,omitempty"`
+	MacAddress        string               `json:"mac_address,omitempty"`
+	WakeOnLANEnabled  *bool                `json:"wake_on_lan_enabled,omitempty"`
+	Backing           *EthernetBackingSpec `json:"backing,omitempty"`
+	StartConnected    *bool                `json:"start_connected,omitempty"`
+	AllowGuestControl *bool                `json:"allow_guest_control,omitempty"`
+}
+
+// ListEthernet returns the virtual Ethernet adapters of the given virtual machine.
+func (c *Manager) ListEthernet(ctx context.Context, vm string) ([]EthernetSummary, error) {
+	url := c.resource(vm, "hardware", "ethernet")
+	var res []EthernetSummary
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// GetEthernet returns information about the given virtual Ethernet adapter.
+func (c *Manager) GetEthernet(ctx context.Context, vm string, nic string) (*EthernetInfo, error) {
+	url := c.resource(vm, "hardware", "ethernet", nic)
+	var res EthernetInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// CreateEthernet adds a virtual Ethernet adapter to the given virtual machine, returning the adapter identifier.
+func (c *Manager) CreateEthernet(ctx context.Context, vm string, spec EthernetCreateSpec) (string, error) {
+	url := c.resource(vm, "hardware", "ethernet")
+	var res string
+	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+}
+
+// UpdateEthernet updates the given virtual Ethernet adapter.
+func (c *Manager) UpdateEthernet(ctx context.Context, vm string, nic string, spec EthernetUpdateSpec) error {
+	url := c.resource(vm, "hardware", "ethernet", nic)
+	return c.Do(ctx, url.Request(http.MethodPatch, spec), nil)
+}
+
+// DeleteEthernet removes the given virtual Ethernet adapter from the virtual machine.
+func (c *Manager) DeleteEthernet(ctx context.Context, vm string, nic string) error {
+	url := c.resource(vm, "hardware", "ethernet", nic)
+	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+}
+
+// ConnectEthernet connects the given virtual Ethernet adapter of a powered on virtual machine.
+func (c *Manager) ConnectEthernet(ctx context.Context, vm string, nic string) error {
+	url := c.resource(vm, "hardware", "ethernet", nic).WithParam("action", "connect")
+	return c.Do(ctx, url.Request(http.MethodPost), nil)
+}
+
+// DisconnectEthernet disconnects the given virtual Ethernet adapter of a powered on virtual machine.
+func (c *Manager) DisconnectEthernet(ctx context.Context, vm string, nic string) error {
+	url := c.resource(vm, "hardware", "ethernet", nic).WithParam("action", "disconnect")
+	return c.Do(ctx, url.Request(http.MethodPost), nil)
+}
@@ -0,0 +1,176 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package vm
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+const (
+	// Path is the REST endpoint for the virtual machine API
+	Path = "/api/vcenter/vm"
+)
+
+// Manager extends rest.Client, adding virtual machine related methods.
+//
+// VMs are identified by their managed object ID, for example "vm-42".
+// Disks and network adapters are identified by their virtual device key, for example "2000" or "4000".
+//
+// See https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/vm/
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+func (c *Manager) resource(vm string, subpath ...string) *rest.Resource {
+	p := []string{Path}
+	if vm != "" {
+		p = append(p, url.PathEscape(vm))
+	}
+	for _, s := range subpath {
+		p = append(p, url.PathEscape(s))
+	}
+	return c.Resource(path.Join(p...))
+}
+
+// PowerState of a virtual machine.
+type PowerState string
+
+const (
+	PowerStatePoweredOff = PowerState("POWERED_OFF")
+	PowerStatePoweredOn  = PowerState("POWERED_ON")
+	PowerStateSuspended  = PowerState("SUSPENDED")
+)
+
+// FilterSpec contains properties used to filter the results when listing virtual machines.
+// Each non-empty field must match, within a field any of the values may match.
+type FilterSpec struct {
+	VMs           []string     `json:"vms,omitempty"`
+	Names         []string     `json:"names,omitempty"`
+	Folders       []string     `json:"folders,omitempty"`
+	Datacenters   []string     `json:"datacenters,omitempty"`
+	Hosts         []string     `json:"hosts,omitempty"`
+	Clusters      []string     `json:"clusters,omitempty"`
+	ResourcePools []string     `json:"resource_pools,omitempty"`
+	PowerStates   []PowerState `json:"power_states,omitempty"`
+}
+
+// Summary contains commonly used information about a virtual machine.
+type Summary struct {
+	VM            string     `json:"vm"`
+	Name          string     `json:"name"`
+	PowerState    PowerState `json:"power_state"`
+	CPUCount      int32      `json:"cpu_count,omitempty"`
+	MemorySizeMiB int64      `json:"memory_size_MiB,omitempty"`
+}
+
+// Identity of a virtual machine.
+type Identity struct {
+	Name         string `json:"name"`
+	BiosUUID     string `json:"bios_uuid"`
+	InstanceUUID string `json:"instance_uuid"`
+}
+
+// Info contains information about a virtual machine.
+type Info struct {
+	Name       string                  `json:"name"`
+	Identity   *Identity               `json:"identity,omitempty"`
+	GuestOS    string                  `json:"guest_OS"`
+	PowerState PowerState              `json:"power_state"`
+	Hardware   HardwareInfo            `json:"hardware"`
+	CPU        CPUInfo                 `json:"cpu"`
+	Memory     MemoryInfo              `json:"memory"`
+	Disks      map[string]DiskInfo     `json:"disks"`
+	Nics       map[string]EthernetInfo `json:"nics"`
+}
+
+// List returns information about the virtual machines matching the FilterSpec, or all VMs if filter is nil.
+func (c *Manager) List(ctx context.Context, filter *FilterSpec) ([]Summary, error) {
+	url := c.resource("")
+
+	if filter != nil {
+		params := []struct {
+			name   string
+			values []string
+		}{
+			{"vms", filter.VMs},
+			{"names", filter.Names},
+			{"folders", filter.Folders},
+			{"datacenters", filter.Datacenters},
+			{"hosts", filter.Hosts},
+			{"clusters", filter.Clusters},
+			{"resource_pools", filter.ResourcePools},
+		}
+
+		for _, p := range params {
+			for _, v := range p.values {
+				url.WithParam(p.name, v)
+			}
+		}
+
+		for _, state := range filter.PowerStates {
+			url.WithParam("power_states", string(state))
+		}
+	}
+
+	var res []Summary
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// Get returns information about the given virtual machine.
+func (c *Manager) Get(ctx context.Context, vm string) (*Info, error) {
+	url := c.resource(vm)
+	var res Info
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// PowerInfo contains information about the power state of a virtual machine.
+type PowerInfo struct {
+	State         PowerState `json:"state"`
+	CleanPowerOff *bool      `json:"clean_power_off,omitempty"`
+}
+
+// GetPower returns the power state information of the given virtual machine.
+func (c *Manager) GetPower(ctx context.Context, vm string) (*PowerInfo, error) {
+	url := c.resource(vm, "power")
+	var res PowerInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+func (c *Manager) power(ctx context.Context, vm string, action string) error {
+	url := c.resource(vm, "power").WithParam("action", action)
+	return c.Do(ctx, url.Request(http.MethodPost), nil)
+}
+
+// Start powers on the given virtual machine.
+func (c *Manager) Start(ctx context.Context, vm string) error {
+	return c.power(ctx, vm, "start")
+}
+
+// Stop powers off the given virtual machine.
+func (c *Manager) Stop(ctx context.Context, vm string) error {
+	return c.power(ctx, vm, "stop")
+}
+
+// Suspend suspends the given powered on virtual machine.
+func (c *Manager) Suspend(ctx context.Context, vm string) error {
+	return c.power(ctx, vm, "suspend")
+}
+
+// Reset resets the given powered on virtual machine.
+func (c *Manager) Reset(ctx context.Context, vm string) error {
+	return c.power(ctx, vm, "reset")
+}
@@ -0,0 +1,165 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package vm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter/vm"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+	_ "github.com/vmware/govmomi/vapi/vm/simulator"
+)
+
+func TestVM(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		require.NoError(t, c.Login(ctx, simulator.DefaultLogin))
+
+		m := vm.NewManager(c)
+
+		all, err := m.List(ctx, nil)
+		require.NoError(t, err)
+		assert.NotEmpty(t, all)
+
+		res, err := m.List(ctx, &vm.FilterSpec{Names: []string{"DC0_H0_VM0"}})
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		id := res[0].VM
+		assert.Equal(t, vm.PowerStatePoweredOn, res[0].PowerState)
+
+		finder := find.NewFinder(vc)
+		obj, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		require.NoError(t, err)
+		assert.Equal(t, obj.Reference().Value, id)
+
+		host, err := obj.HostSystem(ctx)
+		require.NoError(t, err)
+		res, err = m.List(ctx, &vm.FilterSpec{Hosts: []string{host.Reference().Value}})
+		require.NoError(t, err)
+		assert.Len(t, res, 2)
+
+		res, err = m.List(ctx, &vm.FilterSpec{Names: []string{"DC0_H0_VM0"}, PowerStates: []vm.PowerState{vm.PowerStatePoweredOff}})
+		require.NoError(t, err)
+		assert.Empty(t, res)
+
+		info, err := m.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "DC0_H0_VM0", info.Name)
+		assert.Len(t, info.Disks, 1)
+		assert.Len(t, info.Nics, 1)
+
+		// power
+		require.Error(t, m.Start(ctx, id))
+		require.NoError(t, m.Suspend(ctx, id))
+		power, err := m.GetPower(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, vm.PowerStateSuspended, power.State)
+		require.NoError(t, m.Start(ctx, id))
+		require.NoError(t, m.Reset(ctx, id))
+
+		// guest
+		identity, err := m.GetGuestIdentity(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "LINUX", identity.Family)
+
+		interfaces, err := m.ListGuestInterfaces(ctx, id)
+		require.NoError(t, err)
+		require.Len(t, interfaces, 1)
+		assert.Contains(t, info.Nics, interfaces[0].Nic)
+
+		// cpu and memory
+		n := int32(2)
+		require.Error(t, m.UpdateCPU(ctx, id, vm.CPUUpdateSpec{Count: &n}))
+		require.NoError(t, m.Stop(ctx, id))
+		require.NoError(t, m.UpdateCPU(ctx, id, vm.CPUUpdateSpec{Count: &n, HotAddEnabled: types.NewBool(true)}))
+		cpu, err := m.GetCPU(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, n, cpu.Count)
+		assert.True(t, cpu.HotAddEnabled)
+
+		size := int64(64)
+		require.NoError(t, m.UpdateMemory(ctx, id, vm.MemoryUpdateSpec{SizeMiB: &size}))
+		mem, err := m.GetMemory(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, size, mem.SizeMiB)
+
+		_, err = m.GetGuestIdentity(ctx, id)
+		require.Error(t, err)
+
+		// disks
+		disk, err := m.CreateDisk(ctx, id, vm.DiskCreateSpec{NewVMDK: &vm.VMDKCreateSpec{Name: "data", Capacity: 1 << 30}})
+		require.NoError(t, err)
+		disks, err := m.ListDisks(ctx, id)
+		require.NoError(t, err)
+		assert.Len(t, disks, 2)
+		dinfo, err := m.GetDisk(ctx, id, disk)
+		require.NoError(t, err)
+		assert.Equal(t, vm.HostBusAdapterTypeSCSI, dinfo.Type)
+		assert.Contains(t, dinfo.Backing.VMDKFile, "data.vmdk")
+		require.NoError(t, m.DeleteDisk(ctx, id, disk))
+		_, err = m.GetDisk(ctx, id, disk)
+		require.Error(t, err)
+
+		// ethernet
+		pg, err := finder.Network(ctx, "DC0_DVPG0")
+		require.NoError(t, err)
+		nic, err := m.CreateEthernet(ctx, id, vm.EthernetCreateSpec{
+			Type:    vm.EthernetTypeE1000,
+			Backing: &vm.EthernetBackingSpec{Type: vm.EthernetBackingTypeDistributedPortgroup, Network: pg.Reference().Value},
+		})
+		require.NoError(t, err)
+		einfo, err := m.GetEthernet(ctx, id, nic)
+		require.NoError(t, err)
+		assert.Equal(t, vm.EthernetTypeE1000, einfo.Type)
+		assert.Equal(t, "DC0_DVPG0", einfo.Backing.NetworkName)
+		assert.Equal(t, vm.ConnectionStateNotConnected, einfo.State)
+		require.Error(t, m.ConnectEthernet(ctx, id, nic))
+		require.NoError(t, m.DeleteEthernet(ctx, id, nic))
+
+		// customization
+		custom, err := m.GetGuestCustomization(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, vm.CustomizationIdle, custom.Status)
+		spec := vm.CustomizationSetSpec{
+			Spec: &vm.CustomizationSpec{
+				ConfigurationSpec: vm.ConfigurationSpec{
+					LinuxConfig: &vm.LinuxConfiguration{
+						Hostname: &vm.HostnameGenerator{Type: vm.HostnameFixed, FixedName: "custom"},
+						Domain:   "example.com",
+					},
+				},
+				Interfaces: []vm.InterfaceSettings{{
+					Adapter: vm.IPSettings{IPv4: &vm.IPv4{Type: vm.IPv4Static, IPAddress: "10.0.0.42", Prefix: 24}},
+				}},
+			},
+		}
+		require.NoError(t, m.SetGuestCustomization(ctx, id, spec))
+		custom, err = m.GetGuestCustomization(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, vm.CustomizationPending, custom.Status)
+
+		require.NoError(t, m.Start(ctx, id))
+		custom, err = m.GetGuestCustomization(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, vm.CustomizationSucceeded, custom.Status)
+		identity, err = m.GetGuestIdentity(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "custom", identity.HostName)
+		assert.Equal(t, "10.0.0.42", identity.IPAddress)
+
+		_, err = m.Get(ctx, "vm-enoent")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,286 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vmware/govmomi/simulator"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+	vcenter "github.com/vmware/govmomi/vapi/vcenter/vm"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// path starts with "/api/vcenter/vm/{}/guest"
+func (h *Handler) handleVmGuest(w http.ResponseWriter, r *http.Request, tail []string, ctx *simulator.Context, vm *simulator.VirtualMachine) {
+	p := strings.Join(tail, "/")
+
+	if p == "customization" {
+		switch r.Method {
+		case http.MethodGet:
+			h.getCustomization(w, vm)
+		case http.MethodPut:
+			h.setCustomization(w, r, ctx, vm)
+		default:
+			http.NotFound(w, r)
+		}
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	// The guest info APIs require VMware Tools, which is assumed to be running while the VM is powered on.
+	if vm.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOn {
+		vapi.ApiErrorServiceUnavailable(w)
+		return
+	}
+
+	switch p {
+	case "identity":
+		vapi.StatusOK(w, guestIdentity(vm))
+	case "networking":
+		vapi.StatusOK(w, guestNetworking(vm))
+	case "networking/interfaces":
+		vapi.StatusOK(w, guestInterfaces(vm))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func guestFamily(family string) string {
+	switch types.VirtualMachineGuestOsFamily(family) {
+	case types.VirtualMachineGuestOsFamilyLinuxGuest:
+		return "LINUX"
+	case types.VirtualMachineGuestOsFamilyWindowsGuest:
+		return "WINDOWS"
+	case types.VirtualMachineGuestOsFamilySolarisGuest:
+		return "SOLARIS"
+	case types.VirtualMachineGuestOsFamilyDarwinGuestFamily:
+		return "DARWIN"
+	default:
+		return "OTHER"
+	}
+}
+
+func guestIdentity(vm *simulator.VirtualMachine) vcenter.GuestIdentity {
+	id := vm.Guest.GuestId
+	if id == "" {
+		id = vm.Config.GuestId
+	}
+
+	name := vm.Guest.GuestFullName
+	if name == "" {
+		name = vm.Config.GuestFullName
+	}
+
+	return vcenter.GuestIdentity{
+		OS:     id,
+		Family: guestFamily(vm.Guest.GuestFamily),
+		Name:   id,
+		FullName: vcenter.LocalizableMessage{
+			ID:             "vmsg.guestos." + id + ".label",
+			DefaultMessage: name,
+			Args:           []string{},
+		},
+		HostName:  vm.Guest.HostName,
+		IPAddress: vm.Guest.IpAddress,
+	}
+}
+
+func dnsConfig(dns *types.NetDnsConfigInfo) *vcenter.DNSConfig {
+	if dns == nil {
+		return nil
+	}
+	return &vcenter.DNSConfig{
+		IPAddresses:   dns.IpAddress,
+		SearchDomains: dns.SearchDomain,
+	}
+}
+
+func guestNetworking(vm *simulator.VirtualMachine) vcenter.GuestNetworking {
+	info := vcenter.GuestNetworking{
+		DNSValues: &vcenter.DNSValues{HostName: vm.Guest.HostName},
+	}
+
+	for _, nic := range vm.Guest.Net {
+		if nic.DnsConfig != nil {
+			info.DNSValues.DomainName = nic.DnsConfig.DomainName
+			info.DNS = dnsConfig(nic.DnsConfig)
+			break
+		}
+	}
+
+	return info
+}
+
+func guestInterfaces(vm *simulator.VirtualMachine) []vcenter.GuestInterface {
+	res := []vcenter.GuestInterface{}
+
+	for _, nic := range vm.Guest.Net {
+		info := vcenter.GuestInterface{
+			MacAddress: nic.MacAddress,
+			DNS:        dnsConfig(nic.DnsConfig),
+			IP:         &vcenter.IPConfig{IPAddresses: []vcenter.IPAddressInfo{}},
+		}
+
+		if nic.DeviceConfigId >= 0 {
+			info.Nic = strconv.Itoa(int(nic.DeviceConfigId))
+		}
+
+		if nic.IpConfig != nil && len(nic.IpConfig.IpAddress) != 0 {
+			for _, ip := range nic.IpConfig.IpAddress {
+				state := ip.State
+				if state == "" {
+					state = string(types.NetIpConfigInfoIpAddressStatusPreferred)
+				}
+				info.IP.IPAddresses = append(info.IP.IPAddresses, vcenter.IPAddressInfo{
+					IPAddress:    ip.IpAddress,
+					PrefixLength: ip.PrefixLength,
+					Origin:       strings.ToUpper(ip.Origin),
+					State:        strings.ToUpper(state),
+				})
+			}
+		} else {
+			for _, ip := range nic.IpAddress {
+				info.IP.IPAddresses = append(info.IP.IPAddresses, vcenter.IPAddressInfo{
+					IPAddress: ip,
+					State:     strings.ToUpper(string(types.NetIpConfigInfoIpAddressStatusPreferred)),
+				})
+			}
+		}
+
+		res = append(res, info)
+	}
+
+	return res
+}
+
+func (h *Handler) getCustomization(w http.ResponseWriter, vm *simulator.VirtualMachine) {
+	h.mu.Lock()
+	customized := h.customized[vm.Self]
+	h.mu.Unlock()
+
+	info := vcenter.CustomizationInfo{Status: vcenter.CustomizationIdle}
+
+	switch {
+	case vm.Config.Tools != nil && vm.Config.Tools.PendingCustomization != "":
+		info.Status = vcenter.CustomizationPending
+	case customized:
+		info.Status = vcenter.CustomizationSucceeded
+	}
+
+	vapi.StatusOK(w, info)
+}
+
+// customizationSpec converts the given spec to a vim25 CustomizationSpec, returning nil if the spec is not supported.
+// Only the Linux configuration is supported by the simulator.
+func customizationSpec(spec *vcenter.CustomizationSpec) *types.CustomizationSpec {
+	linux := spec.ConfigurationSpec.LinuxConfig
+	if linux == nil {
+		return nil
+	}
+
+	var hostname types.BaseCustomizationName = &types.CustomizationVirtualMachineName{}
+	if linux.Hostname != nil {
+		switch linux.Hostname.Type {
+		case vcenter.HostnameFixed:
+			hostname = &types.CustomizationFixedName{Name: linux.Hostname.FixedName}
+		case vcenter.HostnamePrefix:
+			hostname = &types.CustomizationPrefixName{Base: linux.Hostname.Prefix}
+		case vcenter.HostnameVirtualMachine:
+		default:
+			return nil
+		}
+	}
+
+	res := &types.CustomizationSpec{
+		Identity: &types.CustomizationLinuxPrep{
+			HostName: hostname,
+			Domain:   linux.Domain,
+			TimeZone: linux.TimeZone,
+		},
+		GlobalIPSettings: types.CustomizationGlobalIPSettings{
+			DnsSuffixList: spec.GlobalDNSSettings.DNSSuffixList,
+			DnsServerList: spec.GlobalDNSSettings.DNSServers,
+		},
+	}
+
+	for _, nic := range spec.Interfaces {
+		var settings types.CustomizationIPSettings
+
+		ipv4 := nic.Adapter.IPv4
+		switch {
+		case ipv4 == nil || ipv4.Type == vcenter.IPv4DHCP:
+			settings.Ip = &types.CustomizationDhcpIpGenerator{}
+		case ipv4.Type == vcenter.IPv4Static:
+			if net.ParseIP(ipv4.IPAddress) == nil || ipv4.Prefix < 0 || ipv4.Prefix > 32 {
+				return nil
+			}
+			settings.Ip = &types.CustomizationFixedIp{IpAddress: ipv4.IPAddress}
+			settings.SubnetMask = net.IP(net.CIDRMask(int(ipv4.Prefix), 32)).String()
+			settings.Gateway = ipv4.Gateways
+		default:
+			return nil
+		}
+
+		res.NicSettingMap = append(res.NicSettingMap, types.CustomizationAdapterMapping{
+			MacAddress: nic.MacAddress,
+			Adapter:    settings,
+		})
+	}
+
+	return res
+}
+
+func (h *Handler) setCustomization(w http.ResponseWriter, r *http.Request, ctx *simulator.Context, vm *simulator.VirtualMachine) {
+	var set vcenter.CustomizationSetSpec
+	if !vapi.Decode(r, w, &set) {
+		return
+	}
+
+	if vm.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOff {
+		vapi.ApiErrorNotAllowedInCurrentState(w)
+		return
+	}
+
+	var spec *types.CustomizationSpec
+
+	switch {
+	case set.Name != "":
+		m := h.registry.AllReference("CustomizationSpecManager")[0].(*simulator.CustomizationSpecManager)
+		res := m.GetCustomizationSpec(ctx, &types.GetCustomizationSpec{Name: set.Name})
+		if res.Fault() != nil {
+			vapi.ApiErrorNotFound(w)
+			return
+		}
+		spec = &res.(*methods.GetCustomizationSpecBody).Res.Returnval.Spec
+	case set.Spec != nil:
+		spec = customizationSpec(set.Spec)
+	}
+
+	if spec == nil {
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	req := &types.CustomizeVM_Task{This: vm.Self, Spec: *spec}
+	task := vm.CustomizeVMTask(ctx, req).(*methods.CustomizeVM_TaskBody).Res.Returnval
+	if !h.wait(w, r, ctx, task) {
+		return
+	}
+
+	h.mu.Lock()
+	h.customized[vm.Self] = true
+	h.mu.Unlock()
+
+	vapi.StatusOK(w)
+}
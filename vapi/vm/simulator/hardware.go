@@ -0,0 +1,755 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+	vcenter "github.com/vmware/govmomi/vapi/vcenter/vm"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func hardwareInfo(vm *simulator.VirtualMachine) vcenter.HardwareInfo {
+	info := vcenter.HardwareInfo{
+		Version:       strings.ToUpper(strings.ReplaceAll(vm.Config.Version, "-", "_")),
+		UpgradePolicy: "NEVER",
+		UpgradeStatus: "NONE",
+	}
+
+	if upgrade := vm.Config.ScheduledHardwareUpgradeInfo; upgrade != nil {
+		switch types.ScheduledHardwareUpgradeInfoHardwareUpgradePolicy(upgrade.UpgradePolicy) {
+		case types.ScheduledHardwareUpgradeInfoHardwareUpgradePolicyOnSoftPowerOff:
+			info.UpgradePolicy = "AFTER_CLEAN_SHUTDOWN"
+		case types.ScheduledHardwareUpgradeInfoHardwareUpgradePolicyAlways:
+			info.UpgradePolicy = "ALWAYS"
+		}
+	}
+
+	return info
+}
+
+func cpuInfo(vm *simulator.VirtualMachine) vcenter.CPUInfo {
+	return vcenter.CPUInfo{
+		Count:            vm.Config.Hardware.NumCPU,
+		CoresPerSocket:   vm.Config.Hardware.NumCoresPerSocket,
+		HotAddEnabled:    getOrDefault(vm.Config.CpuHotAddEnabled, false),
+		HotRemoveEnabled: getOrDefault(vm.Config.CpuHotRemoveEnabled, false),
+	}
+}
+
+func memoryInfo(vm *simulator.VirtualMachine) vcenter.MemoryInfo {
+	return vcenter.MemoryInfo{
+		SizeMiB:       int64(vm.Config.Hardware.MemoryMB),
+		HotAddEnabled: getOrDefault(vm.Config.MemoryHotAddEnabled, false),
+	}
+}
+
+// path starts with "/api/vcenter/vm/{}/hardware"
+func (h *Handler) handleVmHardware(w http.ResponseWriter, r *http.Request, tail []string, ctx *simulator.Context, vm *simulator.VirtualMachine) {
+	if len(tail) == 0 {
+		// "/api/vcenter/vm/{}/hardware"
+		switch r.Method {
+		case http.MethodGet:
+			vapi.StatusOK(w, hardwareInfo(vm))
+		default:
+			http.NotFound(w, r)
+		}
+		return
+	}
+
+	// "/api/vcenter/vm/{}/hardware/..."
+	switch tail[0] {
+	case "cpu":
+		h.handleVmCPU(w, r, tail[1:], ctx, vm)
+	case "memory":
+		h.handleVmMemory(w, r, tail[1:], ctx, vm)
+	case "disk":
+		h.handleVmDisks(w, r, tail[1:], ctx, vm)
+	case "ethernet":
+		h.handleVmEthernets(w, r, tail[1:], ctx, vm)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) reconfigure(w http.ResponseWriter, r *http.Request, ctx *simulator.Context, vm *simulator.VirtualMachine, spec types.VirtualMachineConfigSpec) bool {
+	req := &types.ReconfigVM_Task{This: vm.Self, Spec: spec}
+	task := vm.ReconfigVMTask(ctx, req).(*methods.ReconfigVM_TaskBody).Res.Returnval
+	return h.wait(w, r, ctx, task)
+}
+
+// path starts with "/api/vcenter/vm/{}/hardware/cpu"
+func (h *Handler) handleVmCPU(w http.ResponseWriter, r *http.Request, tail []string, ctx *simulator.Context, vm *simulator.VirtualMachine) {
+	if len(tail) > 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, cpuInfo(vm))
+	case http.MethodPatch:
+		h.updateCPU(w, r, ctx, vm)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) updateCPU(w http.ResponseWriter, r *http.Request, ctx *simulator.Context, vm *simulator.VirtualMachine) {
+	var update vcenter.CPUUpdateSpec
+	if !vapi.Decode(r, w, &update) {
+		return
+	}
+
+	info := cpuInfo(vm)
+	var spec types.VirtualMachineConfigSpec
+
+	if update.Count != nil {
+		if *update.Count < 1 {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+		spec.NumCPUs = *update.Count
+	}
+	if update.CoresPerSocket != nil {
+		if *update.CoresPerSocket < 1 {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+		spec.NumCoresPerSocket = *update.CoresPerSocket
+	}
+	spec.CpuHotAddEnabled = update.HotAddEnabled
+	spec.CpuHotRemoveEnabled = update.HotRemoveEnabled
+
+	if vm.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOff {
+		allowed := spec.CpuHotAddEnabled == nil && spec.CpuHotRemoveEnabled == nil &&
+			(spec.NumCoresPerSocket == 0 || spec.NumCoresPerSocket == info.CoresPerSocket)
+		switch {
+		case spec.NumCPUs > info.Count:
+			allowed = allowed && info.HotAddEnabled
+		case spec.NumCPUs != 0 && spec.NumCPUs < info.Count:
+			allowed = allowed && info.HotRemoveEnabled
+		}
+		if !allowed {
+			vapi.ApiErrorNotAllowedInCurrentState(w)
+			return
+		}
+	}
+
+	if h.reconfigure(w, r, ctx, vm, spec) {
+		vapi.StatusOK(w)
+	}
+}
+
+// path starts with "/api/vcenter/vm/{}/hardware/memory"
+func (h *Handler) handleVmMemory(w http.ResponseWriter, r *http.Request, tail []string, ctx *simulator.Context, vm *simulator.VirtualMachine) {
+	if len(tail) > 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, memoryInfo(vm))
+	case http.MethodPatch:
+		h.updateMemory(w, r, ctx, vm)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) updateMemory(w http.ResponseWriter, r *http.Request, ctx *simulator.Context, vm *simulator.VirtualMachine) {
+	var update vcenter.MemoryUpdateSpec
+	if !vapi.Decode(r, w, &update) {
+		return
+	}
+
+	info := memoryInfo(vm)
+	var spec types.VirtualMachineConfigSpec
+
+	if update.SizeMiB != nil {
+		if *update.SizeMiB < 4 {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+		spec.MemoryMB = *update.SizeMiB
+	}
+	spec.MemoryHotAddEnabled = update.HotAddEnabled
+
+	if vm.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOff {
+		allowed := spec.MemoryHotAddEnabled == nil
+		switch {
+		case spec.MemoryMB > info.SizeMiB:
+			allowed = allowed && info.HotAddEnabled
+		case spec.MemoryMB != 0 && spec.MemoryMB < info.SizeMiB:
+			allowed = false
+		}
+		if !allowed {
+			vapi.ApiErrorNotAllowedInCurrentState(w)
+			return
+		}
+	}
+
+	if h.reconfigure(w, r, ctx, vm, spec) {
+		vapi.StatusOK(w)
+	}
+}
+
+// deviceList returns a copy of the VM's devices, which can be modified for use in a ReconfigVM spec.
+func deviceList(vm *simulator.VirtualMachine) object.VirtualDeviceList {
+	devices := types.ArrayOfVirtualDevice{VirtualDevice: vm.Config.Hardware.Device}
+	return types.MustDeepCopy(devices).VirtualDevice
+}
+
+func deviceKey(id string) int32 {
+	key, err := strconv.ParseInt(id, 10, 32)
+	if err != nil {
+		return -1
+	}
+	return int32(key)
+}
+
+// newDeviceKey returns the key of the device that was added to the VM, given the device list before the change.
+func newDeviceKey(vm *simulator.VirtualMachine, before object.VirtualDeviceList) string {
+	for _, d := range vm.Config.Hardware.Device {
+		key := d.GetVirtualDevice().Key
+		if before.FindByKey(key) == nil {
+			return strconv.Itoa(int(key))
+		}
+	}
+	return ""
+}
+
+func controllerType(d types.BaseVirtualDevice) vcenter.HostBusAdapterType {
+	switch d.(type) {
+	case *types.VirtualIDEController:
+		return vcenter.HostBusAdapterTypeIDE
+	case types.BaseVirtualSCSIController:
+		return vcenter.HostBusAdapterTypeSCSI
+	case types.BaseVirtualSATAController:
+		return vcenter.HostBusAdapterTypeSATA
+	case *types.VirtualNVMEController:
+		return vcenter.HostBusAdapterTypeNVME
+	}
+	return ""
+}
+
+func diskInfo(devices object.VirtualDeviceList, disk *types.VirtualDisk) vcenter.DiskInfo {
+	info := vcenter.DiskInfo{
+		Capacity: disk.CapacityInBytes,
+	}
+
+	if d := disk.DeviceInfo; d != nil {
+		info.Label = d.GetDescription().Label
+	}
+
+	if b, ok := disk.Backing.(types.BaseVirtualDeviceFileBackingInfo); ok {
+		info.Backing = vcenter.DiskBacking{
+			Type:     vcenter.DiskBackingTypeVMDKFile,
+			VMDKFile: b.GetVirtualDeviceFileBackingInfo().FileName,
+		}
+	}
+
+	c := devices.FindByKey(disk.ControllerKey)
+	if c == nil || disk.UnitNumber == nil {
+		return info
+	}
+
+	bus := c.(types.BaseVirtualController).GetVirtualController().BusNumber
+	address := &vcenter.BusAddress{Bus: bus, Unit: *disk.UnitNumber}
+
+	info.Type = controllerType(c)
+	switch info.Type {
+	case vcenter.HostBusAdapterTypeIDE:
+		info.IDE = &vcenter.IDEAddress{Primary: bus == 0, Master: *disk.UnitNumber == 0}
+	case vcenter.HostBusAdapterTypeSCSI:
+		info.SCSI = address
+	case vcenter.HostBusAdapterTypeSATA:
+		info.SATA = address
+	case vcenter.HostBusAdapterTypeNVME:
+		info.NVME = address
+	}
+
+	return info
+}
+
+// path starts with "/api/vcenter/vm/{}/hardware/disk"
+func (h *Handler) handleVmDisks(w http.ResponseWriter, r *http.Request, tail []string, ctx *simulator.Context, vm *simulator.VirtualMachine) {
+	devices := deviceList(vm)
+
+	if len(tail) == 0 {
+		// "/api/vcenter/vm/{}/hardware/disk"
+		switch r.Method {
+		case http.MethodGet:
+			res := []vcenter.DiskSummary{}
+			for _, d := range devices.SelectByType((*types.VirtualDisk)(nil)) {
+				res = append(res, vcenter.DiskSummary{Disk: strconv.Itoa(int(d.GetVirtualDevice().Key))})
+			}
+			vapi.StatusOK(w, res)
+		case http.MethodPost:
+			h.createDisk(w, r, ctx, vm, devices)
+		default:
+			http.NotFound(w, r)
+		}
+		return
+	}
+
+	// "/api/vcenter/vm/{}/hardware/disk/{}"
+	disk, ok := devices.FindByKey(deviceKey(tail[0])).(*types.VirtualDisk)
+	if !ok || len(tail) > 1 {
+		vapi.ApiErrorNotFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, diskInfo(devices, disk))
+	case http.MethodPatch:
+		h.updateDisk(w, r, ctx, vm, disk)
+	case http.MethodDelete:
+		spec := types.VirtualMachineConfigSpec{
+			DeviceChange: []types.BaseVirtualDeviceConfigSpec{
+				&types.VirtualDeviceConfigSpec{
+					Operation: types.VirtualDeviceConfigSpecOperationRemove,
+					Device:    disk,
+				},
+			},
+		}
+		if h.reconfigure(w, r, ctx, vm, spec) {
+			vapi.StatusOK(w)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) createDisk(w http.ResponseWriter, r *http.Request, ctx *simulator.Context, vm *simulator.VirtualMachine, devices object.VirtualDeviceList) {
+	var create vcenter.DiskCreateSpec
+	if !vapi.Decode(r, w, &create) {
+		return
+	}
+
+	kind := create.Type
+	address := create.SCSI
+	switch {
+	case create.SATA != nil:
+		address = create.SATA
+		if kind == "" {
+			kind = vcenter.HostBusAdapterTypeSATA
+		}
+	case create.NVME != nil:
+		address = create.NVME
+		if kind == "" {
+			kind = vcenter.HostBusAdapterTypeNVME
+		}
+	}
+	if kind == "" {
+		kind = vcenter.HostBusAdapterTypeSCSI
+	}
+	if address == nil {
+		address = new(vcenter.BusAddressSpec)
+	}
+
+	var controller types.BaseVirtualController
+	for _, d := range devices {
+		c, ok := d.(types.BaseVirtualController)
+		if ok && controllerType(d) == kind && c.GetVirtualController().BusNumber == address.Bus {
+			controller = c
+			break
+		}
+	}
+	if controller == nil || (create.Backing == nil) == (create.NewVMDK == nil) {
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	var ds types.ManagedObjectReference
+	var name string
+	op := types.VirtualDeviceConfigSpecFileOperation("")
+
+	if create.Backing != nil {
+		name = create.Backing.VMDKFile
+	} else {
+		op = types.VirtualDeviceConfigSpecFileOperationCreate
+		if len(vm.Datastore) != 0 {
+			ds = vm.Datastore[0]
+		}
+		if create.NewVMDK.Name != "" {
+			var p object.DatastorePath
+			p.FromString(vm.Config.Files.VmPathName)
+			p.Path = path.Join(path.Dir(p.Path), create.NewVMDK.Name)
+			name = p.String()
+		}
+	}
+
+	disk := devices.CreateDisk(controller, ds, name)
+	if address.Unit != nil {
+		disk.UnitNumber = address.Unit
+	}
+	if create.NewVMDK != nil {
+		disk.CapacityInBytes = create.NewVMDK.Capacity
+		disk.CapacityInKB = create.NewVMDK.Capacity / 1024
+	}
+
+	spec := types.VirtualMachineConfigSpec{
+		DeviceChange: []types.BaseVirtualDeviceConfigSpec{
+			&types.VirtualDeviceConfigSpec{
+				Operation:     types.VirtualDeviceConfigSpecOperationAdd,
+				FileOperation: op,
+				Device:        disk,
+			},
+		},
+	}
+
+	if h.reconfigure(w, r, ctx, vm, spec) {
+		vapi.StatusOK(w, newDeviceKey(vm, devices))
+	}
+}
+
+func (h *Handler) updateDisk(w http.ResponseWriter, r *http.Request, ctx *simulator.Context, vm *simulator.VirtualMachine, disk *types.VirtualDisk) {
+	var update vcenter.DiskUpdateSpec
+	if !vapi.Decode(r, w, &update) {
+		return
+	}
+
+	if update.Backing == nil {
+		vapi.StatusOK(w)
+		return
+	}
+
+	b, ok := disk.Backing.(types.BaseVirtualDeviceFileBackingInfo)
+	if !ok || update.Backing.VMDKFile == "" {
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	b.GetVirtualDeviceFileBackingInfo().FileName = update.Backing.VMDKFile
+
+	spec := types.VirtualMachineConfigSpec{
+		DeviceChange: []types.BaseVirtualDeviceConfigSpec{
+			&types.VirtualDeviceConfigSpec{
+				Operation: types.VirtualDeviceConfigSpecOperationEdit,
+				Device:    disk,
+			},
+		},
+	}
+
+	if h.reconfigure(w, r, ctx, vm, spec) {
+		vapi.StatusOK(w)
+	}
+}
+
+func ethernetType(card types.BaseVirtualEthernetCard) vcenter.EthernetType {
+	switch card.(type) {
+	case *types.VirtualE1000:
+		return vcenter.EthernetTypeE1000
+	case *types.VirtualE1000e:
+		return vcenter.EthernetTypeE1000E
+	case *types.VirtualPCNet32:
+		return vcenter.EthernetTypePCNET32
+	case *types.VirtualVmxnet2:
+		return vcenter.EthernetTypeVMXNET2
+	case *types.VirtualVmxnet3:
+		return vcenter.EthernetTypeVMXNET3
+	default:
+		return vcenter.EthernetTypeVMXNET
+	}
+}
+
+func (h *Handler) entityName(ref types.ManagedObjectReference) string {
+	if e, ok := h.registry.Get(ref).(mo.Entity); ok {
+		return e.Entity().Name
+	}
+	return ""
+}
+
+func (h *Handler) ethernetInfo(card types.BaseVirtualEthernetCard) vcenter.EthernetInfo {
+	c := card.GetVirtualEthernetCard()
+
+	info := vcenter.EthernetInfo{
+		Type:             ethernetType(card),
+		MacType:          vcenter.MacAddressType(strings.ToUpper(c.AddressType)),
+		MacAddress:       c.MacAddress,
+		WakeOnLANEnabled: getOrDefault(c.WakeOnLanEnabled, false),
+		State:            vcenter.ConnectionStateNotConnected,
+	}
+
+	if d := c.DeviceInfo; d != nil {
+		info.Label = d.GetDescription().Label
+	}
+
+	if slot, ok := c.SlotInfo.(*types.VirtualDevicePciBusSlotInfo); ok {
+		info.PCISlotNumber = slot.PciSlotNumber
+	}
+
+	if conn := c.Connectable; conn != nil {
+		if conn.Connected {
+			info.State = vcenter.ConnectionStateConnected
+		}
+		info.StartConnected = conn.StartConnected
+		info.AllowGuestControl = conn.AllowGuestControl
+	}
+
+	switch b := c.Backing.(type) {
+	case *types.VirtualEthernetCardNetworkBackingInfo:
+		info.Backing.Type = vcenter.EthernetBackingTypeStandardPortgroup
+		info.Backing.NetworkName = b.DeviceName
+		if b.Network != nil {
+			info.Backing.Network = b.Network.Value
+		}
+	case *types.VirtualEthernetCardDistributedVirtualPortBackingInfo:
+		info.Backing.Type = vcenter.EthernetBackingTypeDistributedPortgroup
+		info.Backing.Network = b.Port.PortgroupKey
+		info.Backing.NetworkName = h.entityName(types.ManagedObjectReference{Type: "DistributedVirtualPortgroup", Value: b.Port.PortgroupKey})
+		info.Backing.DistributedSwitchUUID = b.Port.SwitchUuid
+		info.Backing.DistributedPort = b.Port.PortKey
+	case *types.VirtualEthernetCardOpaqueNetworkBackingInfo:
+		info.Backing.Type = vcenter.EthernetBackingTypeOpaqueNetwork
+		info.Backing.OpaqueNetworkType = b.OpaqueNetworkType
+		info.Backing.OpaqueNetworkID = b.OpaqueNetworkId
+	case *types.VirtualEthernetCardLegacyNetworkBackingInfo:
+		info.Backing.Type = vcenter.EthernetBackingTypeHostDevice
+		info.Backing.HostDevice = b.DeviceName
+	}
+
+	return info
+}
+
+// ethernetBacking returns the device backing for the given spec, or nil if the spec is invalid.
+func (h *Handler) ethernetBacking(spec *vcenter.EthernetBackingSpec) types.BaseVirtualDeviceBackingInfo {
+	switch spec.Type {
+	case vcenter.EthernetBackingTypeStandardPortgroup:
+		ref := types.ManagedObjectReference{Type: "Network", Value: spec.Network}
+		name := h.entityName(ref)
+		if name == "" {
+			return nil
+		}
+		return &types.VirtualEthernetCardNetworkBackingInfo{
+			VirtualDeviceDeviceBackingInfo: types.VirtualDeviceDeviceBackingInfo{
+				DeviceName: name,
+			},
+			Network: &ref,
+		}
+	case vcenter.EthernetBackingTypeDistributedPortgroup:
+		ref := types.ManagedObjectReference{Type: "DistributedVirtualPortgroup", Value: spec.Network}
+		pg, ok := h.registry.Get(ref).(*simulator.DistributedVirtualPortgroup)
+		if !ok || pg.Config.DistributedVirtualSwitch == nil {
+			return nil
+		}
+		dvs, ok := h.registry.Get(*pg.Config.DistributedVirtualSwitch).(*simulator.DistributedVirtualSwitch)
+		if !ok {
+			return nil
+		}
+		return &types.VirtualEthernetCardDistributedVirtualPortBackingInfo{
+			Port: types.DistributedVirtualSwitchPortConnection{
+				SwitchUuid:   dvs.Uuid,
+				PortgroupKey: pg.Key,
+				PortKey:      spec.DistributedPort,
+			},
+		}
+	}
+	return nil
+}
+
+// path starts with "/api/vcenter/vm/{}/hardware/ethernet"
+func (h *Handler) handleVmEthernets(w http.ResponseWriter, r *http.Request, tail []string, ctx *simulator.Context, vm *simulator.VirtualMachine) {
+	devices := deviceList(vm)
+
+	if len(tail) == 0 {
+		// "/api/vcenter/vm/{}/hardware/ethernet"
+		switch r.Method {
+		case http.MethodGet:
+			res := []vcenter.EthernetSummary{}
+			for _, d := range devices.SelectByType((*types.VirtualEthernetCard)(nil)) {
+				res = append(res, vcenter.EthernetSummary{Nic: strconv.Itoa(int(d.GetVirtualDevice().Key))})
+			}
+			vapi.StatusOK(w, res)
+		case http.MethodPost:
+			h.createEthernet(w, r, ctx, vm, devices)
+		default:
+			http.NotFound(w, r)
+		}
+		return
+	}
+
+	// "/api/vcenter/vm/{}/hardware/ethernet/{}"
+	card, ok := devices.FindByKey(deviceKey(tail[0])).(types.BaseVirtualEthernetCard)
+	if !ok || len(tail) > 1 {
+		vapi.ApiErrorNotFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, h.ethernetInfo(card))
+	case http.MethodPatch:
+		h.updateEthernet(w, r, ctx, vm, card)
+	case http.MethodPost:
+		h.connectEthernet(w, r, ctx, vm, card, r.URL.Query().Get("action"))
+	case http.MethodDelete:
+		h.editEthernet(w, r, ctx, vm, card, types.VirtualDeviceConfigSpecOperationRemove)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) editEthernet(w http.ResponseWriter, r *http.Request, ctx *simulator.Context, vm *simulator.VirtualMachine, card types.BaseVirtualEthernetCard, op types.VirtualDeviceConfigSpecOperation) {
+	spec := types.VirtualMachineConfigSpec{
+		DeviceChange: []types.BaseVirtualDeviceConfigSpec{
+			&types.VirtualDeviceConfigSpec{
+				Operation: op,
+				Device:    card.(types.BaseVirtualDevice),
+			},
+		},
+	}
+
+	if h.reconfigure(w, r, ctx, vm, spec) {
+		vapi.StatusOK(w)
+	}
+}
+
+// applyEthernet applies the settings common to create and update specs.
+func applyEthernet(c *types.VirtualEthernetCard, macType vcenter.MacAddressType, mac string, wol, start, guest *bool) bool {
+	switch macType {
+	case "":
+	case vcenter.MacAddressTypeManual:
+		if mac == "" {
+			return false
+		}
+		c.AddressType = string(types.VirtualEthernetCardMacTypeManual)
+		c.MacAddress = mac
+	case vcenter.MacAddressTypeGenerated, vcenter.MacAddressTypeAssigned:
+		c.AddressType = strings.ToLower(string(macType))
+		c.MacAddress = ""
+	default:
+		return false
+	}
+
+	if wol != nil {
+		c.WakeOnLanEnabled = wol
+	}
+
+	if c.Connectable == nil {
+		c.Connectable = &types.VirtualDeviceConnectInfo{StartConnected: true}
+	}
+	if start != nil {
+		c.Connectable.StartConnected = *start
+	}
+	if guest != nil {
+		c.Connectable.AllowGuestControl = *guest
+	}
+
+	return true
+}
+
+func (h *Handler) createEthernet(w http.ResponseWriter, r *http.Request, ctx *simulator.Context, vm *simulator.VirtualMachine, devices object.VirtualDeviceList) {
+	var create vcenter.EthernetCreateSpec
+	if !vapi.Decode(r, w, &create) {
+		return
+	}
+
+	kind := create.Type
+	if kind == "" {
+		kind = vcenter.EthernetTypeVMXNET3
+	}
+
+	if create.Backing == nil {
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+	backing := h.ethernetBacking(create.Backing)
+	if backing == nil {
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	device, err := devices.CreateEthernetCard(strings.ToLower(string(kind)), backing)
+	if err != nil {
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	card := device.(types.BaseVirtualEthernetCard).GetVirtualEthernetCard()
+	if !applyEthernet(card, create.MacType, create.MacAddress, create.WakeOnLANEnabled, create.StartConnected, create.AllowGuestControl) {
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	spec := types.VirtualMachineConfigSpec{
+		DeviceChange: []types.BaseVirtualDeviceConfigSpec{
+			&types.VirtualDeviceConfigSpec{
+				Operation: types.VirtualDeviceConfigSpecOperationAdd,
+				Device:    device,
+			},
+		},
+	}
+
+	if h.reconfigure(w, r, ctx, vm, spec) {
+		vapi.StatusOK(w, newDeviceKey(vm, devices))
+	}
+}
+
+func (h *Handler) updateEthernet(w http.ResponseWriter, r *http.Request, ctx *simulator.Context, vm *simulator.VirtualMachine, card types.BaseVirtualEthernetCard) {
+	var update vcenter.EthernetUpdateSpec
+	if !vapi.Decode(r, w, &update) {
+		return
+	}
+
+	c := card.GetVirtualEthernetCard()
+
+	if update.Backing != nil {
+		backing := h.ethernetBacking(update.Backing)
+		if backing == nil {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+		c.Backing = backing
+	}
+
+	if !applyEthernet(c, update.MacType, update.MacAddress, update.WakeOnLANEnabled, update.StartConnected, update.AllowGuestControl) {
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	h.editEthernet(w, r, ctx, vm, card, types.VirtualDeviceConfigSpecOperationEdit)
+}
+
+func (h *Handler) connectEthernet(w http.ResponseWriter, r *http.Request, ctx *simulator.Context, vm *simulator.VirtualMachine, card types.BaseVirtualEthernetCard, action string) {
+	var connect bool
+
+	switch action {
+	case "connect":
+		connect = true
+	case "disconnect":
+	default:
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	if vm.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOn {
+		vapi.ApiErrorNotAllowedInCurrentState(w)
+		return
+	}
+
+	c := card.GetVirtualEthernetCard()
+	if c.Connectable == nil {
+		c.Connectable = new(types.VirtualDeviceConnectInfo)
+	}
+	if c.Connectable.Connected == connect {
+		vapi.ApiErrorAlreadyInDesiredState(w)
+		return
+	}
+	c.Connectable.Connected = connect
+
+	h.editEthernet(w, r, ctx, vm, card, types.VirtualDeviceConfigSpecOperationEdit)
+}
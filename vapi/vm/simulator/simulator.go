@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/google/uuid"
 
@@ -38,11 +39,15 @@ func init() {
 type Handler struct {
 	u        *url.URL
 	registry *simulator.Registry
+
+	mu         sync.Mutex
+	customized map[types.ManagedObjectReference]bool
 }
 
 func New(u *url.URL) *Handler {
 	h := &Handler{
-		u: u,
+		u:          u,
+		customized: make(map[types.ManagedObjectReference]bool),
 	}
 	return h
 }
@@ -57,6 +62,7 @@ func (h *Handler) Register(s *simulator.Service, r *simulator.Registry) {
 		h.registry = r
 		s.HandleFunc(restPathPrefix, h.handle)
 		s.HandleFunc(apiPathPrefix, h.handle)
+		s.HandleFunc(internal.VCenterVMPath, h.handleVms)
 	}
 }
 
@@ -90,20 +96,13 @@ func (h *Handler) handleVm(w http.ResponseWriter, r *http.Request, tail []string
 	if vm == nil {
 		return
 	}
-	ctx := &simulator.Context{
-		Context: context.Background(),
-		Session: &simulator.Session{
-			UserSession: types.UserSession{
-				Key: uuid.New().String(),
-			},
-			Registry: h.registry,
-		},
-		Map: h.registry,
-	}
+	ctx := h.context()
 	h.registry.WithLock(ctx, vm.Reference(), func() {
 		if len(tail) == 0 {
 			// "/api/vcenter/vm/{}"
 			switch r.Method {
+			case http.MethodGet:
+				vapi.StatusOK(w, h.vmInfo(vm))
 			case http.MethodDelete:
 				h.deleteVM(w, r, ctx, vm)
 			default:
@@ -114,6 +113,12 @@ func (h *Handler) handleVm(w http.ResponseWriter, r *http.Request, tail []string
 			switch tail[0] {
 			case "data-sets":
 				h.handleVmDataSets(w, r, tail[1:], vm)
+			case "power":
+				h.handleVmPower(w, r, tail[1:], ctx, vm)
+			case "hardware":
+				h.handleVmHardware(w, r, tail[1:], ctx, vm)
+			case "guest":
+				h.handleVmGuest(w, r, tail[1:], ctx, vm)
 			default:
 				http.NotFound(w, r)
 			}
@@ -220,16 +225,48 @@ func (h *Handler) handleVmDataSetEntry(w http.ResponseWriter, r *http.Request, t
 	}
 }
 
-func (h *Handler) deleteVM(w http.ResponseWriter, r *http.Request, ctx *simulator.Context, vm *simulator.VirtualMachine) {
-	taskRef := vm.DestroyTask(ctx, &types.Destroy_Task{This: vm.Self}).(*methods.Destroy_TaskBody).Res.Returnval
-	task := ctx.Map.Get(taskRef).(*simulator.Task)
+func (h *Handler) context() *simulator.Context {
+	return &simulator.Context{
+		Context: context.Background(),
+		Session: &simulator.Session{
+			UserSession: types.UserSession{
+				Key: uuid.New().String(),
+			},
+			Registry: h.registry,
+		},
+		Map: h.registry,
+	}
+}
+
+// wait for the given task to complete, responding with an error if the task failed.
+func (h *Handler) wait(w http.ResponseWriter, r *http.Request, ctx *simulator.Context, ref types.ManagedObjectReference) bool {
+	task := ctx.Map.Get(ref).(*simulator.Task)
 	task.Wait()
-	if task.Info.Error != nil {
-		log.Printf("%s %s: %v", r.Method, r.RequestURI, task.Info.Error)
+	if task.Info.Error == nil {
+		return true
+	}
+
+	log.Printf("%s %s: %v", r.Method, r.RequestURI, task.Info.Error.LocalizedMessage)
+
+	switch task.Info.Error.Fault.(type) {
+	case *types.InvalidPowerState, *types.InvalidState, *types.CustomizationPending:
+		vapi.ApiErrorNotAllowedInCurrentState(w)
+	case *types.InvalidArgument, *types.NicSettingMismatch, *types.FileNotFound:
+		vapi.ApiErrorInvalidArgument(w)
+	case *types.NotSupported:
+		vapi.ApiErrorUnsupported(w)
+	default:
 		vapi.ApiErrorGeneral(w)
-		return
 	}
-	vapi.StatusOK(w)
+
+	return false
+}
+
+func (h *Handler) deleteVM(w http.ResponseWriter, r *http.Request, ctx *simulator.Context, vm *simulator.VirtualMachine) {
+	taskRef := vm.DestroyTask(ctx, &types.Destroy_Task{This: vm.Self}).(*methods.Destroy_TaskBody).Res.Returnval
+	if h.wait(w, r, ctx, taskRef) {
+		vapi.StatusOK(w)
+	}
 }
 
 func (h *Handler) createDataSet(w http.ResponseWriter, r *http.Request, vm *simulator.VirtualMachine) {
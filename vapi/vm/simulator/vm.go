@@ -0,0 +1,203 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+	vcenter "github.com/vmware/govmomi/vapi/vcenter/vm"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func powerState(s types.VirtualMachinePowerState) vcenter.PowerState {
+	switch s {
+	case types.VirtualMachinePowerStatePoweredOn:
+		return vcenter.PowerStatePoweredOn
+	case types.VirtualMachinePowerStateSuspended:
+		return vcenter.PowerStateSuspended
+	default:
+		return vcenter.PowerStatePoweredOff
+	}
+}
+
+// path is "/api/vcenter/vm"
+func (h *Handler) handleVms(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listVMs(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) listVMs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	match := func(name string, ids ...string) bool {
+		filter := query[name]
+		if len(filter) == 0 {
+			return true
+		}
+		for _, id := range ids {
+			if slices.Contains(filter, id) {
+				return true
+			}
+		}
+		return false
+	}
+
+	ctx := h.context()
+	res := []vcenter.Summary{}
+
+	for _, e := range h.registry.All(typeVM) {
+		vm := e.(*simulator.VirtualMachine)
+
+		h.registry.WithLock(ctx, vm.Reference(), func() {
+			if vm.Config == nil || vm.Config.Template {
+				return
+			}
+
+			var host, cluster string
+			if ref := vm.Runtime.Host; ref != nil {
+				host = ref.Value
+				if hs, ok := h.registry.Get(*ref).(*simulator.HostSystem); ok && hs.Parent.Type == "ClusterComputeResource" {
+					cluster = hs.Parent.Value
+				}
+			}
+
+			var pool string
+			if vm.ResourcePool != nil {
+				pool = vm.ResourcePool.Value
+			}
+
+			state := powerState(vm.Runtime.PowerState)
+			parents := vapi.Ancestors(h.registry, vm)
+
+			if !match("vms", vm.Self.Value) ||
+				!match("names", vm.Name) ||
+				!match("folders", parents...) ||
+				!match("datacenters", parents...) ||
+				!match("hosts", host) ||
+				!match("clusters", cluster) ||
+				!match("resource_pools", pool) ||
+				!match("power_states", string(state)) {
+				return
+			}
+
+			res = append(res, vcenter.Summary{
+				VM:            vm.Self.Value,
+				Name:          vm.Name,
+				PowerState:    state,
+				CPUCount:      vm.Config.Hardware.NumCPU,
+				MemorySizeMiB: int64(vm.Config.Hardware.MemoryMB),
+			})
+		})
+	}
+
+	slices.SortFunc(res, func(a, b vcenter.Summary) int {
+		return strings.Compare(a.VM, b.VM)
+	})
+
+	vapi.StatusOK(w, res)
+}
+
+func (h *Handler) vmInfo(vm *simulator.VirtualMachine) vcenter.Info {
+	info := vcenter.Info{
+		Name: vm.Name,
+		Identity: &vcenter.Identity{
+			Name:         vm.Name,
+			BiosUUID:     vm.Config.Uuid,
+			InstanceUUID: vm.Config.InstanceUuid,
+		},
+		GuestOS:    vm.Config.GuestId,
+		PowerState: powerState(vm.Runtime.PowerState),
+		Hardware:   hardwareInfo(vm),
+		CPU:        cpuInfo(vm),
+		Memory:     memoryInfo(vm),
+		Disks:      make(map[string]vcenter.DiskInfo),
+		Nics:       make(map[string]vcenter.EthernetInfo),
+	}
+
+	devices := object.VirtualDeviceList(vm.Config.Hardware.Device)
+
+	for _, d := range devices {
+		key := strconv.Itoa(int(d.GetVirtualDevice().Key))
+
+		switch device := d.(type) {
+		case *types.VirtualDisk:
+			info.Disks[key] = diskInfo(devices, device)
+		case types.BaseVirtualEthernetCard:
+			info.Nics[key] = h.ethernetInfo(device)
+		}
+	}
+
+	return info
+}
+
+// path starts with "/api/vcenter/vm/{}/power"
+func (h *Handler) handleVmPower(w http.ResponseWriter, r *http.Request, tail []string, ctx *simulator.Context, vm *simulator.VirtualMachine) {
+	if len(tail) > 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, vcenter.PowerInfo{State: powerState(vm.Runtime.PowerState)})
+	case http.MethodPost:
+		h.power(w, r, ctx, vm, r.URL.Query().Get("action"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) power(w http.ResponseWriter, r *http.Request, ctx *simulator.Context, vm *simulator.VirtualMachine, action string) {
+	state := vm.Runtime.PowerState
+	var task types.ManagedObjectReference
+
+	switch action {
+	case "start":
+		if state == types.VirtualMachinePowerStatePoweredOn {
+			vapi.ApiErrorAlreadyInDesiredState(w)
+			return
+		}
+		task = vm.PowerOnVMTask(ctx, &types.PowerOnVM_Task{This: vm.Self}).(*methods.PowerOnVM_TaskBody).Res.Returnval
+	case "stop":
+		if state == types.VirtualMachinePowerStatePoweredOff {
+			vapi.ApiErrorAlreadyInDesiredState(w)
+			return
+		}
+		task = vm.PowerOffVMTask(ctx, &types.PowerOffVM_Task{This: vm.Self}).(*methods.PowerOffVM_TaskBody).Res.Returnval
+	case "suspend":
+		switch state {
+		case types.VirtualMachinePowerStateSuspended:
+			vapi.ApiErrorAlreadyInDesiredState(w)
+			return
+		case types.VirtualMachinePowerStatePoweredOff:
+			vapi.ApiErrorNotAllowedInCurrentState(w)
+			return
+		}
+		task = vm.SuspendVMTask(ctx, &types.SuspendVM_Task{This: vm.Self}).(*methods.SuspendVM_TaskBody).Res.Returnval
+	case "reset":
+		if state != types.VirtualMachinePowerStatePoweredOn {
+			vapi.ApiErrorNotAllowedInCurrentState(w)
+			return
+		}
+		task = vm.ResetVMTask(ctx, &types.ResetVM_Task{This: vm.Self}).(*methods.ResetVM_TaskBody).Res.Returnval
+	default:
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	if h.wait(w, r, ctx, task) {
+		vapi.StatusOK(w)
+	}
+}
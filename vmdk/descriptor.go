@@ -18,13 +18,14 @@ import (
 )
 
 type Descriptor struct {
-	Encoding  string            `json:"encoding"`
-	Version   int               `json:"version"`
-	CID       DiskContentID     `json:"cid"`
-	ParentCID DiskContentID     `json:"parentCID"`
-	Type      string            `json:"type"`
-	Extent    []Extent          `json:"extent"`
-	DDB       map[string]string `json:"ddb"`
+	Encoding           string            `json:"encoding"`
+	Version            int               `json:"version"`
+	CID                DiskContentID     `json:"cid"`
+	ParentCID          DiskContentID     `json:"parentCID"`
+	Type               string            `json:"type"`
+	ParentFileNameHint string            `json:"parentFileNameHint,omitempty"`
+	Extent             []Extent          `json:"extent"`
+	DDB                map[string]string `json:"ddb"`
 }
 
 type DiskContentID uint32
@@ -97,8 +98,10 @@ func ParseDescriptor(r io.Reader) (*Descriptor, error) {
 			_, _ = fmt.Sscanf(val, "%x", &d.CID)
 		case "parentcid":
 			_, _ = fmt.Sscanf(val, "%x", &d.ParentCID)
-		case "createType":
+		case "createtype":
 			d.Type = val
+		case "parentfilenamehint":
+			d.ParentFileNameHint = val
 		}
 	}
 
@@ -146,7 +149,8 @@ version={{ .Version }}
 encoding="{{ .Encoding }}"
 CID={{ .CID }}
 parentCID={{ .ParentCID }}
-createType="{{ .Type }}"
+createType="{{ .Type }}"{{ if .ParentFileNameHint }}
+parentFileNameHint="{{ .ParentFileNameHint }}"{{ end }}
 
 # Extent description ({{ cap }} capacity){{range .Extent }}
 {{ .Permission }} {{ .Size }} {{ .Type }} "{{ .Info }}"{{end}}
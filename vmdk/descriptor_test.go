@@ -46,3 +46,35 @@ func TestDescriptor(t *testing.T) {
 		t.Error("not equal")
 	}
 }
+
+func TestDescriptorParent(t *testing.T) {
+	desc := vmdk.NewDescriptor(vmdk.Extent{
+		Type: "VMFSSPARSE",
+		Size: 1024,
+		Info: "test-000001-delta.vmdk",
+	})
+	desc.CID = 0xfffffffe
+	desc.ParentCID = 123
+	desc.Type = "vmfsSparse"
+	desc.ParentFileNameHint = "test.vmdk"
+
+	var buf bytes.Buffer
+
+	err := desc.Write(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Contains(buf.Bytes(), []byte(`parentFileNameHint="test.vmdk"`)) {
+		t.Errorf("missing parentFileNameHint: %s", buf.String())
+	}
+
+	parsed, err := vmdk.ParseDescriptor(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(desc, parsed) {
+		t.Errorf("not equal: %#v", parsed)
+	}
+}